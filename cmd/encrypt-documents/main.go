@@ -0,0 +1,84 @@
+// Package main provides a command that encrypts document files stored before encryption at rest was enabled
+//
+// Usage:
+//
+//	go run ./cmd/encrypt-documents [-dry-run]
+//
+// The configured storage backend (STORAGE_BACKEND, UPLOAD_PATH or S3_*) is opened with ENCRYPTION_KEY, and
+// every stored file that is not encrypted yet is encrypted in place: the encrypted copy is written beside
+// the file and then moved over it, so a failure leaves the original intact. Files that are already
+// encrypted are skipped, so the command can be re-run. Set ENCRYPTION_KEY for the server first, so new
+// uploads are encrypted while existing files are converted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/config"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	dryRun := flag.Bool("dry-run", false, "report which files would be encrypted without changing anything")
+	flag.Parse()
+
+	if cfg.EncryptionKey == "" {
+		log.Fatalf("ENCRYPTION_KEY is not set")
+	}
+	store, err := blobstore.Open(cfg.BlobStore())
+	if err != nil {
+		log.Fatalf("Failed to open document storage: %v", err)
+	}
+	blobs := store.(*blobstore.Encrypted)
+
+	// Collect the keys first, as encrypting writes temporary objects next to the files
+	var keys []string
+	if err := blobs.List(func(key string, size int64) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		log.Fatalf("Failed to list stored files: %v", err)
+	}
+
+	fmt.Printf("Encrypting %d files in %s\n", len(keys), blobs.Name())
+
+	var encrypted, skipped, failed int
+	for _, key := range keys {
+		if *dryRun {
+			keyID, err := blobs.KeyID(key)
+			switch {
+			case err != nil:
+				fmt.Printf("FAILED     %s: %v\n", key, err)
+				failed++
+			case keyID != "":
+				skipped++
+			default:
+				fmt.Printf("ENCRYPT    %s\n", key)
+				encrypted++
+			}
+			continue
+		}
+
+		done, err := blobs.EncryptInPlace(key)
+		switch {
+		case err != nil:
+			fmt.Printf("FAILED     %s: %v\n", key, err)
+			failed++
+		case done:
+			fmt.Printf("ENCRYPTED  %s\n", key)
+			encrypted++
+		default:
+			skipped++
+		}
+	}
+
+	fmt.Printf("Done: %d encrypted, %d already encrypted, %d failed\n", encrypted, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+// Package main provides a command that copies stored documents from one storage backend to another
+//
+// Usage:
+//
+//	go run ./cmd/migrate-storage -from filesystem -to s3 [-dry-run]
+//
+// Both backends are configured from the same environment variables as the server (UPLOAD_PATH for the
+// filesystem, S3_* for S3). Every file referenced by expert_documents or document_versions is copied to the destination under
+// a key relative to the upload directory, and document paths are rewritten to that key. Files already
+// present at the destination with the same size are not copied again, so the command can be re-run after
+// a partial failure. Source files are left in place; switch STORAGE_BACKEND once the copy succeeds.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/config"
+	"expertdb/internal/storage/sqlite"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	from := flag.String("from", blobstore.BackendFileSystem, "backend to copy documents from (filesystem or s3)")
+	to := flag.String("to", cfg.StorageBackend, "backend to copy documents to (filesystem or s3)")
+	dryRun := flag.Bool("dry-run", false, "report what would be copied without changing anything")
+	flag.Parse()
+
+	if *from == *to {
+		log.Fatalf("source and destination backends are both %q", *from)
+	}
+
+	srcConfig := cfg.BlobStore()
+	srcConfig.Backend = *from
+	src, err := blobstore.Open(srcConfig)
+	if err != nil {
+		log.Fatalf("Failed to open source storage: %v", err)
+	}
+
+	dstConfig := cfg.BlobStore()
+	dstConfig.Backend = *to
+	dst, err := blobstore.Open(dstConfig)
+	if err != nil {
+		log.Fatalf("Failed to open destination storage: %v", err)
+	}
+
+	store, err := sqlite.New(cfg.DBPath)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer store.Close()
+
+	docs, err := store.ListAllDocuments()
+	if err != nil {
+		log.Fatalf("Failed to list documents: %v", err)
+	}
+
+	// Deduplicated documents and their versions share files, which only need copying once
+	contentTypes := make(map[string]string)
+	var paths []string
+	addPath := func(filePath, contentType string) {
+		if _, seen := contentTypes[filePath]; !seen {
+			paths = append(paths, filePath)
+			contentTypes[filePath] = contentType
+		}
+	}
+	for _, doc := range docs {
+		addPath(doc.FilePath, doc.ContentType)
+		versions, err := store.ListDocumentVersions(doc.ID)
+		if err != nil {
+			log.Fatalf("Failed to list versions of document %d: %v", doc.ID, err)
+		}
+		for _, v := range versions {
+			addPath(v.FilePath, v.ContentType)
+		}
+	}
+
+	fmt.Printf("Copying %d files for %d documents from %s to %s\n", len(paths), len(docs), src.Name(), dst.Name())
+
+	var copied, skipped, failed int
+	for _, filePath := range paths {
+		key := blobstore.NormalizeKey(cfg.UploadPath, filePath)
+		// A file already at the destination counts as copied, even if an earlier run removed the source
+		size, srcErr := src.Stat(filePath)
+		existing, dstErr := dst.Stat(key)
+		if dstErr == nil && (srcErr != nil || existing == size) {
+			fmt.Printf("EXISTS  %s\n", key)
+			skipped++
+		} else if srcErr != nil {
+			fmt.Printf("FAILED  %s: %v\n", filePath, srcErr)
+			failed++
+			continue
+		} else if *dryRun {
+			fmt.Printf("COPY    %s -> %s (%d bytes)\n", filePath, key, size)
+			copied++
+		} else {
+			if err := blobstore.Copy(dst, src, filePath, key, contentTypes[filePath]); err != nil {
+				fmt.Printf("FAILED  %s: %v\n", filePath, err)
+				failed++
+				continue
+			}
+			fmt.Printf("COPIED  %s -> %s (%d bytes)\n", filePath, key, size)
+			copied++
+		}
+
+		if key != filePath && !*dryRun {
+			if _, err := store.UpdateDocumentFilePath(filePath, key); err != nil {
+				fmt.Printf("FAILED  updating path of %s: %v\n", filePath, err)
+				failed++
+			}
+		}
+	}
+
+	fmt.Printf("Done: %d copied, %d already present, %d failed\n", copied, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
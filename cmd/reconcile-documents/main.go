@@ -0,0 +1,107 @@
+// Package main provides a command that reconciles stored document files with the database
+//
+// Usage:
+//
+//	go run ./cmd/reconcile-documents [-repair] [-quarantine]
+//
+// The configured storage backend (STORAGE_BACKEND, UPLOAD_PATH or S3_*) is compared with expert_documents,
+// document_versions and the cv_document_id/approval_document_id references of experts and expert requests.
+// Without flags the discrepancies are only reported. -repair points records whose file is missing at an
+// orphaned file with the same checksum and clears references to documents that no longer exist.
+// -quarantine moves the remaining orphaned files under quarantine/, where they are listed with the other
+// quarantined uploads. The command exits with status 1 while any discrepancy remains.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/config"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/storage/sqlite"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	repair := flag.Bool("repair", false, "relink records to orphaned files with the same checksum and clear dangling references")
+	quarantine := flag.Bool("quarantine", false, "move orphaned files under quarantine/")
+	flag.Parse()
+
+	blobs, err := blobstore.Open(cfg.BlobStore())
+	if err != nil {
+		log.Fatalf("Failed to open document storage: %v", err)
+	}
+
+	store, err := sqlite.New(cfg.DBPath)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer store.Close()
+	store.SetBlobStore(blobs)
+
+	docService, err := documents.New(store, blobs)
+	if err != nil {
+		log.Fatalf("Failed to create document service: %v", err)
+	}
+
+	fmt.Printf("Reconciling documents in %s\n", blobs.Name())
+	report, err := docService.ReconcileDocuments(*repair, *quarantine)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	unresolved := 0
+	for _, f := range report.OrphanedFiles {
+		switch f.Action {
+		case domain.ReconcileQuarantined:
+			fmt.Printf("QUARANTINED  %s -> %s\n", f.FilePath, f.QuarantinedAs)
+		case domain.ReconcileFailed:
+			fmt.Printf("ORPHANED     %s (%d bytes): quarantine failed: %s\n", f.FilePath, f.FileSize, f.Error)
+			unresolved++
+		default:
+			fmt.Printf("ORPHANED     %s (%d bytes)\n", f.FilePath, f.FileSize)
+			unresolved++
+		}
+	}
+	for _, m := range report.MissingFiles {
+		record := fmt.Sprintf("document %d", m.DocumentID)
+		if m.Version > 0 {
+			record += fmt.Sprintf(" version %d", m.Version)
+		}
+		switch m.Action {
+		case domain.ReconcileRelinked:
+			fmt.Printf("RELINKED     %s: %s -> %s\n", record, m.FilePath, m.RelinkedTo)
+		case domain.ReconcileFailed:
+			fmt.Printf("MISSING      %s: %s: relink failed: %s\n", record, m.FilePath, m.Error)
+			unresolved++
+		default:
+			fmt.Printf("MISSING      %s: %s\n", record, m.FilePath)
+			unresolved++
+		}
+	}
+	for _, ref := range report.DanglingReferences {
+		reference := fmt.Sprintf("%s %d %s = %d", ref.Owner, ref.OwnerID, ref.Field, ref.DocumentID)
+		switch ref.Action {
+		case domain.ReconcileCleared:
+			fmt.Printf("CLEARED      %s\n", reference)
+		case domain.ReconcileFailed:
+			fmt.Printf("DANGLING     %s: clear failed: %s\n", reference, ref.Error)
+			unresolved++
+		default:
+			fmt.Printf("DANGLING     %s\n", reference)
+			unresolved++
+		}
+	}
+
+	fmt.Printf("Done: %d files and %d documents checked, %d orphaned files, %d missing files, %d dangling references, %d unresolved\n",
+		report.FilesScanned, report.DocumentsChecked, len(report.OrphanedFiles), len(report.MissingFiles),
+		len(report.DanglingReferences), unresolved)
+	if unresolved > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+// Package main provides a command that rewraps the data keys of encrypted document files after a master key rotation
+//
+// Usage:
+//
+//	go run ./cmd/rotate-document-keys [-dry-run]
+//
+// To rotate the master key, set ENCRYPTION_KEY to the new key and add the old one to ENCRYPTION_PREVIOUS_KEYS,
+// restart the server, then run this command with the same environment. Every file whose data key is wrapped by
+// a previous key gets it wrapped by the new key instead. Only the file header is rewritten; the content is not
+// re-encrypted. Files under keys that are not configured are reported as failures. Once the command reports
+// no failures the old key can be removed from ENCRYPTION_PREVIOUS_KEYS.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/config"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+
+	dryRun := flag.Bool("dry-run", false, "report which files would be rewrapped without changing anything")
+	flag.Parse()
+
+	if cfg.EncryptionKey == "" {
+		log.Fatalf("ENCRYPTION_KEY is not set to the new master key")
+	}
+	store, err := blobstore.Open(cfg.BlobStore())
+	if err != nil {
+		log.Fatalf("Failed to open document storage: %v", err)
+	}
+	blobs := store.(*blobstore.Encrypted)
+
+	// Collect the keys first, as rewrapping writes temporary objects next to the files
+	var keys []string
+	if err := blobs.List(func(key string, size int64) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		log.Fatalf("Failed to list stored files: %v", err)
+	}
+
+	fmt.Printf("Rewrapping data keys of %d files in %s\n", len(keys), blobs.Name())
+
+	var rewrapped, current, unencrypted, failed int
+	for _, key := range keys {
+		keyID, err := blobs.KeyID(key)
+		if err != nil {
+			fmt.Printf("FAILED     %s: %v\n", key, err)
+			failed++
+			continue
+		}
+		switch keyID {
+		case "":
+			unencrypted++
+			continue
+		case blobs.CurrentKeyID():
+			current++
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("REWRAP     %s (key %s)\n", key, keyID)
+			rewrapped++
+			continue
+		}
+		if _, err := blobs.Rewrap(key); err != nil {
+			fmt.Printf("FAILED     %s: %v\n", key, err)
+			failed++
+			continue
+		}
+		fmt.Printf("REWRAPPED  %s (key %s)\n", key, keyID)
+		rewrapped++
+	}
+
+	fmt.Printf("Done: %d rewrapped, %d already under the current key, %d failed\n", rewrapped, current, failed)
+	if unencrypted > 0 {
+		fmt.Printf("%d files are not encrypted; run cmd/encrypt-documents to encrypt them\n", unencrypted)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
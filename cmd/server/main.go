@@ -2,6 +2,8 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
 	"os"
 	"path/filepath"
@@ -10,8 +12,13 @@ import (
 	
 	"expertdb/internal/api"
 	"expertdb/internal/auth"
+	"expertdb/internal/blobstore"
 	"expertdb/internal/config"
 	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/email"
+	"expertdb/internal/filescan"
+	"expertdb/internal/jobs"
 	"expertdb/internal/logger"
 	"expertdb/internal/storage/sqlite"
 )
@@ -61,10 +68,12 @@ func main() {
 	l.Info("Database directory created: %s", dbDir)
 	
 	// Create the upload directory if it doesn't exist
-	if err := os.MkdirAll(cfg.UploadPath, 0755); err != nil {
-		l.Fatal("Failed to create upload directory: %v", err)
+	if cfg.StorageBackend == blobstore.BackendFileSystem {
+		if err := os.MkdirAll(cfg.UploadPath, 0755); err != nil {
+			l.Fatal("Failed to create upload directory: %v", err)
+		}
+		l.Info("Upload directory created: %s", cfg.UploadPath)
 	}
-	l.Info("Upload directory created: %s", cfg.UploadPath)
 	
 	// Initialize database connection
 	l.Info("Connecting to database at %s", cfg.DBPath)
@@ -82,6 +91,23 @@ func main() {
 		l.Fatal("Failed to initialize database: %v", err)
 	}
 	
+	// Apply the approval quorum for expert requests
+	store.SetExpertRequestApprovalPolicy(domain.ExpertRequestApprovalPolicy{
+		RequiredApprovals:    cfg.ApprovalQuorum,
+		RequireDistinctRoles: cfg.ApprovalRequireDistinctRoles,
+	})
+	
+	// Apply review targets for expert requests; configured statuses override the defaults
+	// and a target of 0 disables the SLA for that status
+	requestSLA := domain.ExpertRequestSLA{}
+	for status, target := range domain.DefaultExpertRequestSLA {
+		requestSLA[status] = target
+	}
+	for status, hours := range cfg.RequestSLAHours {
+		requestSLA[status] = time.Duration(hours) * time.Hour
+	}
+	store.SetExpertRequestSLA(requestSLA)
+	
 	// Initialize JWT secret
 	l.Info("Initializing JWT secret...")
 	if err := auth.InitJWTSecret(); err != nil {
@@ -89,12 +115,68 @@ func main() {
 	}
 	l.Info("JWT secret initialized successfully")
 	
+	// Open the document storage backend, shared by the document service and the store
+	blobs, err := blobstore.Open(cfg.BlobStore())
+	if err != nil {
+		l.Fatal("Failed to open document storage: %v", err)
+	}
+	store.SetBlobStore(blobs)
+	
 	// Create document service
-	docService, err := documents.New(store, cfg.UploadPath)
+	docService, err := documents.New(store, blobs)
 	if err != nil {
 		l.Fatal("Failed to create document service: %v", err)
 	}
 	
+	// Scan uploads for malware before they are stored; flagged files go to quarantine
+	scanner, err := filescan.Open(cfg.FileScanner())
+	if err != nil {
+		l.Fatal("Failed to configure malware scanner: %v", err)
+	}
+	if clamav, ok := scanner.(*filescan.ClamAV); ok {
+		if err := clamav.Ping(); err != nil {
+			l.Warn("ClamAV is not reachable, uploads will fail until it is: %v", err)
+		}
+	}
+	docService.SetScanner(scanner)
+	
+	// Sign document download links; without a configured secret they stop working on restart
+	linkSecret := []byte(cfg.DownloadLinkSecret)
+	if len(linkSecret) == 0 {
+		l.Warn("DOWNLOAD_LINK_SECRET is not set, document download links will stop working when the server restarts")
+		linkSecret = make([]byte, 32)
+		if _, err := rand.Read(linkSecret); err != nil {
+			l.Fatal("Failed to generate download link secret: %v", err)
+		}
+	}
+	docService.SetLinkSecret(linkSecret)
+	
+	// Start delivering queued emails; without an SMTP server they are written to the log
+	var sender email.Sender = email.LogSender{}
+	if cfg.SMTPHost != "" {
+		sender = &email.SMTPSender{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}
+	}
+	go email.NewDispatcher(store, sender, time.Minute).Run(context.Background())
+	
+	// Escalate expert requests that are past their review target to the admin notification feed
+	go jobs.NewSLAEscalator(store, time.Duration(cfg.SLACheckIntervalMins)*time.Minute).Run(context.Background())
+	
+	// Start and complete engagements by date, and flag active ones running without an end date
+	engagementOverdueAfter := time.Duration(cfg.EngagementOverdueDays) * 24 * time.Hour
+	go jobs.NewEngagementScheduler(store, time.Hour, engagementOverdueAfter).Run(context.Background())
+	
+	// Extract searchable text from documents stored before extraction ran on upload
+	go jobs.NewTextExtractionBackfill(docService, time.Hour).Run(context.Background())
+	
+	// Discard resumable uploads that were abandoned or never used
+	go jobs.NewUploadCleanup(docService, time.Hour).Run(context.Background())
+	
 	// Create API server
 	l.Info("Creating API server on port %s", cfg.Port)
 	server, err := api.NewServer(":"+cfg.Port, store, docService, cfg)
@@ -122,9 +204,16 @@ func main() {
 	l.Info("- Port: %s", cfg.Port)
 	l.Info("- Database: %s", cfg.DBPath)
 	l.Info("- Upload Path: %s", cfg.UploadPath)
+	l.Info("- Document Storage: %s", blobs.Name())
+	l.Info("- Malware Scanner: %s", scanner.Name())
 	l.Info("- CORS: %s", cfg.CORSAllowOrigins)
 	l.Info("- Log Level: %s", logLevel.String())
 	l.Info("- Log Directory: %s", cfg.LogDir)
+	l.Info("- Approval Quorum: %d (distinct roles: %v)", cfg.ApprovalQuorum, cfg.ApprovalRequireDistinctRoles)
+	l.Info("- Request SLA Check Interval: %d minutes", cfg.SLACheckIntervalMins)
+	l.Info("- Engagements Overdue After: %d days without an end date", cfg.EngagementOverdueDays)
+	l.Info("- SMTP Host: %s", cfg.SMTPHost)
+	l.Info("- Nomination Rate Limit: %d per hour", cfg.NominationRateLimit)
 	
 	// For mock data generation, run the populate_mock_data.sh script
 	// This keeps the server code clean and focused on its primary responsibility
@@ -0,0 +1,66 @@
+-- +goose Up
+-- Training programs offered to experts (e.g. "Validator Induction")
+CREATE TABLE IF NOT EXISTS "training_programs" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,                     -- Program name
+    description TEXT,                              -- Optional description of the program
+    validity_months INTEGER NOT NULL DEFAULT 0,    -- Months a completion stays valid (0 = never expires)
+    is_active BOOLEAN NOT NULL DEFAULT 1,          -- Inactive programs cannot receive new sessions
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Individual deliveries of a training program
+CREATE TABLE IF NOT EXISTS "training_sessions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    program_id INTEGER NOT NULL,                   -- References training_programs(id)
+    session_date DATE NOT NULL,                    -- Date the session took place
+    location TEXT,                                 -- Venue or "online"
+    trainer TEXT,                                  -- Name of the trainer
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (program_id) REFERENCES training_programs(id) ON DELETE CASCADE
+);
+
+-- Attendance of an expert at a training session
+CREATE TABLE IF NOT EXISTS "training_attendance" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,                   -- References training_sessions(id)
+    expert_id INTEGER NOT NULL,                    -- References experts(id)
+    status TEXT NOT NULL DEFAULT 'attended' CHECK (status IN ('registered', 'attended', 'absent')),
+    completed_at DATE,                             -- Date the training was completed
+    expires_at DATE,                               -- Date the completion lapses (NULL = never)
+    certificate_document_id INTEGER,               -- Optional reference to expert_documents(id)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (session_id, expert_id),
+    FOREIGN KEY (session_id) REFERENCES training_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (expert_id) REFERENCES experts(id) ON DELETE CASCADE,
+    FOREIGN KEY (certificate_document_id) REFERENCES expert_documents(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_training_sessions_program_id ON training_sessions(program_id);
+CREATE INDEX idx_training_attendance_expert_id ON training_attendance(expert_id);
+CREATE INDEX idx_training_attendance_session_id ON training_attendance(session_id);
+CREATE INDEX idx_training_attendance_expires_at ON training_attendance(expires_at);
+
+-- Carry the legacy is_trained flag over as attendance of a non-expiring legacy program
+INSERT INTO training_programs (name, description, validity_months)
+VALUES ('Legacy Training', 'Training recorded through the former is_trained flag', 0);
+
+INSERT INTO training_sessions (program_id, session_date, notes)
+SELECT id, date('now'), 'Imported from the is_trained flag'
+FROM training_programs WHERE name = 'Legacy Training';
+
+INSERT INTO training_attendance (session_id, expert_id, status, completed_at)
+SELECT s.id, e.id, 'attended', date(e.created_at)
+FROM experts e
+JOIN training_sessions s ON s.program_id = (SELECT id FROM training_programs WHERE name = 'Legacy Training')
+WHERE e.is_trained = 1;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_training_attendance_expires_at;
+DROP INDEX IF EXISTS idx_training_attendance_session_id;
+DROP INDEX IF EXISTS idx_training_attendance_expert_id;
+DROP INDEX IF EXISTS idx_training_sessions_program_id;
+DROP TABLE IF EXISTS "training_attendance";
+DROP TABLE IF EXISTS "training_sessions";
+DROP TABLE IF EXISTS "training_programs";
@@ -0,0 +1,69 @@
+-- +goose Up
+-- Parent/child relationships between expert areas, replacing "Parent - Child" names
+-- parent_id references expert_areas(id) and is NULL for top-level areas
+ALTER TABLE expert_areas ADD COLUMN parent_id INTEGER;
+CREATE INDEX idx_expert_areas_parent_id ON expert_areas(parent_id);
+
+-- Specialized areas belong to a general area
+-- general_area_id references expert_areas(id) and is NULL while unmapped
+ALTER TABLE specialized_areas ADD COLUMN general_area_id INTEGER;
+CREATE INDEX idx_specialized_areas_general_area_id ON specialized_areas(general_area_id);
+
+-- Create any parent that only existed as a name prefix (e.g. "Finance")
+INSERT OR IGNORE INTO expert_areas (name)
+SELECT DISTINCT trim(substr(name, 1, instr(name, ' - ') - 1))
+FROM expert_areas
+WHERE instr(name, ' - ') > 0;
+
+-- Link "Parent - Child" areas to their parent
+UPDATE expert_areas
+SET parent_id = (
+    SELECT p.id FROM expert_areas p
+    WHERE p.name = trim(substr(expert_areas.name, 1, instr(expert_areas.name, ' - ') - 1))
+)
+WHERE instr(name, ' - ') > 0;
+
+-- Strip the parent prefix from child names where the short name is still unique
+UPDATE expert_areas
+SET name = trim(substr(name, instr(name, ' - ') + 3))
+WHERE parent_id IS NOT NULL
+  AND instr(name, ' - ') > 0
+  AND NOT EXISTS (
+      SELECT 1 FROM expert_areas o
+      WHERE o.name = trim(substr(expert_areas.name, instr(expert_areas.name, ' - ') + 3))
+  );
+
+-- Full display path of every area reachable from a top-level area (e.g. "Business - Banking & Finance")
+CREATE VIEW IF NOT EXISTS expert_area_paths AS
+WITH RECURSIVE area_path(id, root_id, path, depth) AS (
+    SELECT id, id, name, 0 FROM expert_areas WHERE parent_id IS NULL
+    UNION ALL
+    SELECT a.id, ap.root_id, ap.path || ' - ' || a.name, ap.depth + 1
+    FROM expert_areas a
+    JOIN area_path ap ON a.parent_id = ap.id
+)
+SELECT id, root_id, path, depth FROM area_path;
+
+-- Map each specialized area to the general area most of its experts belong to
+UPDATE specialized_areas
+SET general_area_id = (
+    SELECT e.general_area
+    FROM experts e
+    WHERE ',' || e.specialized_area || ',' LIKE '%,' || specialized_areas.id || ',%'
+    GROUP BY e.general_area
+    ORDER BY COUNT(*) DESC
+    LIMIT 1
+);
+
+-- +goose Down
+-- Restore "Parent - Child" names before dropping the hierarchy
+UPDATE expert_areas
+SET name = (SELECT path FROM expert_area_paths p WHERE p.id = expert_areas.id)
+WHERE parent_id IS NOT NULL
+  AND EXISTS (SELECT 1 FROM expert_area_paths p WHERE p.id = expert_areas.id);
+
+DROP VIEW IF EXISTS expert_area_paths;
+DROP INDEX IF EXISTS idx_specialized_areas_general_area_id;
+DROP INDEX IF EXISTS idx_expert_areas_parent_id;
+ALTER TABLE specialized_areas DROP COLUMN general_area_id;
+ALTER TABLE expert_areas DROP COLUMN parent_id;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Retired areas stay on existing records but are hidden from new selections
+ALTER TABLE expert_areas ADD COLUMN retired_at TIMESTAMP;
+ALTER TABLE expert_areas ADD COLUMN merged_into_id INTEGER;
+ALTER TABLE specialized_areas ADD COLUMN retired_at TIMESTAMP;
+ALTER TABLE specialized_areas ADD COLUMN merged_into_id INTEGER;
+
+-- Audit trail for area maintenance operations (merge, retire, restore)
+CREATE TABLE IF NOT EXISTS "area_audit_log" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    area_type TEXT NOT NULL CHECK (area_type IN ('general', 'specialized')),
+    area_id INTEGER NOT NULL,                      -- References expert_areas(id) or specialized_areas(id)
+    action TEXT NOT NULL CHECK (action IN ('merge', 'retire', 'restore')),
+    target_area_id INTEGER,                        -- Area merged into (merge only)
+    details TEXT,                                  -- JSON object with counts and reason
+    performed_by INTEGER NOT NULL,                 -- References users(id)
+    performed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (performed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_area_audit_log_area ON area_audit_log(area_type, area_id);
+CREATE INDEX idx_area_audit_log_performed_at ON area_audit_log(performed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_area_audit_log_performed_at;
+DROP INDEX IF EXISTS idx_area_audit_log_area;
+DROP TABLE IF EXISTS "area_audit_log";
+ALTER TABLE specialized_areas DROP COLUMN merged_into_id;
+ALTER TABLE specialized_areas DROP COLUMN retired_at;
+ALTER TABLE expert_areas DROP COLUMN merged_into_id;
+ALTER TABLE expert_areas DROP COLUMN retired_at;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Moderation decisions on user-suggested specialized areas (expert_requests.suggested_specialized_areas)
+CREATE TABLE IF NOT EXISTS "specialized_area_suggestion_resolutions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    suggested_name TEXT NOT NULL,                  -- Suggested name as submitted
+    resolution TEXT NOT NULL CHECK (resolution IN ('accepted', 'mapped')),
+    specialized_area_id INTEGER NOT NULL,          -- Area created (accepted) or mapped to
+    requests_updated INTEGER NOT NULL DEFAULT 0,   -- Expert requests that carried the suggestion
+    experts_updated INTEGER NOT NULL DEFAULT 0,    -- Experts created from those requests
+    resolved_by INTEGER,                           -- References users(id)
+    resolved_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (specialized_area_id) REFERENCES specialized_areas(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_suggestion_resolutions_resolved_at ON specialized_area_suggestion_resolutions(resolved_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_suggestion_resolutions_resolved_at;
+DROP TABLE IF EXISTS "specialized_area_suggestion_resolutions";
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Expert requests follow a workflow: draft, submitted, under_review, needs_changes, approved, rejected, withdrawn
+-- Former 'pending' requests are waiting for a reviewer, i.e. submitted
+UPDATE expert_requests SET status = 'submitted' WHERE status = 'pending' OR status IS NULL;
+
+-- Every status change of an expert request
+CREATE TABLE IF NOT EXISTS "expert_request_status_history" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,                   -- References expert_requests(id)
+    from_status TEXT,                              -- NULL for the initial status
+    to_status TEXT NOT NULL,
+    note TEXT,                                     -- Reason given with the change (e.g. requested changes)
+    changed_by INTEGER,                            -- References users(id)
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (request_id) REFERENCES expert_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_expert_request_status_history_request ON expert_request_status_history(request_id);
+
+-- Backfill history from the existing request columns
+INSERT INTO expert_request_status_history (request_id, from_status, to_status, changed_by, changed_at)
+SELECT id, NULL, 'submitted', created_by, created_at FROM expert_requests;
+
+INSERT INTO expert_request_status_history (request_id, from_status, to_status, note, changed_by, changed_at)
+SELECT id, 'submitted', status, rejection_reason, reviewed_by, COALESCE(reviewed_at, created_at)
+FROM expert_requests
+WHERE status IN ('approved', 'rejected');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_expert_request_status_history_request;
+DROP TABLE IF EXISTS "expert_request_status_history";
+UPDATE expert_requests SET status = 'pending' WHERE status IN ('draft', 'submitted', 'under_review', 'needs_changes');
+UPDATE expert_requests SET status = 'rejected' WHERE status = 'withdrawn';
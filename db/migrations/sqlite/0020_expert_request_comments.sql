@@ -0,0 +1,31 @@
+-- +goose Up
+-- Threaded discussion between requesters and reviewers on an expert request
+CREATE TABLE IF NOT EXISTS "expert_request_comments" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,                   -- References expert_requests(id)
+    parent_id INTEGER,                             -- Comment being replied to (NULL starts a thread)
+    author_id INTEGER,                             -- References users(id)
+    body TEXT NOT NULL,
+    is_internal BOOLEAN NOT NULL DEFAULT 0,        -- Admin-only note, never shown to the requester
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (request_id) REFERENCES expert_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES expert_request_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- When each user last read the comments on a request, for unread counts
+CREATE TABLE IF NOT EXISTS "expert_request_comment_reads" (
+    request_id INTEGER NOT NULL,                   -- References expert_requests(id)
+    user_id INTEGER NOT NULL,                      -- References users(id)
+    last_read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (request_id, user_id),
+    FOREIGN KEY (request_id) REFERENCES expert_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_expert_request_comments_request ON expert_request_comments(request_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_expert_request_comments_request;
+DROP TABLE IF EXISTS "expert_request_comment_reads";
+DROP TABLE IF EXISTS "expert_request_comments";
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Approval votes on expert requests; the expert is created once enough reviewers have approved
+CREATE TABLE IF NOT EXISTS "expert_request_approvals" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id INTEGER NOT NULL,                   -- References expert_requests(id)
+    approver_id INTEGER NOT NULL,                  -- References users(id)
+    approver_role TEXT NOT NULL,                   -- Role of the approver when the vote was cast
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    invalidated_at TIMESTAMP,                      -- Set when the request is sent back, so the vote no longer counts
+    FOREIGN KEY (request_id) REFERENCES expert_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (approver_id) REFERENCES users(id)
+);
+
+-- A reviewer has at most one standing vote per request
+CREATE UNIQUE INDEX idx_expert_request_approvals_active ON expert_request_approvals(request_id, approver_id) WHERE invalidated_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_expert_request_approvals_active;
+DROP TABLE IF EXISTS "expert_request_approvals";
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Possible duplicates found when the request was submitted, and whether the submitter confirmed it is not one
+ALTER TABLE expert_requests ADD COLUMN duplicate_matches TEXT;                    -- JSON array of DuplicateMatch
+ALTER TABLE expert_requests ADD COLUMN duplicate_confirmed BOOLEAN NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE expert_requests DROP COLUMN duplicate_confirmed;
+ALTER TABLE expert_requests DROP COLUMN duplicate_matches;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Invitations that let prospective experts nominate themselves without an account
+-- Only a SHA-256 hash of the token is stored; the token itself is shown once when the invite is created
+CREATE TABLE IF NOT EXISTS "nomination_invites" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash TEXT NOT NULL UNIQUE,
+    email TEXT,                                    -- Person the invite was sent to (optional)
+    note TEXT,
+    max_uses INTEGER NOT NULL DEFAULT 1,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_by INTEGER,                            -- References users(id)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Requests submitted through the public nomination portal
+ALTER TABLE expert_requests ADD COLUMN is_self_nominated BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE expert_requests ADD COLUMN nomination_invite_id INTEGER; -- References nomination_invites(id)
+
+-- Outgoing emails, delivered by a background dispatcher so failures can be retried
+CREATE TABLE IF NOT EXISTS "email_outbox" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',        -- pending, sent, failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX idx_email_outbox_status ON email_outbox(status, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_email_outbox_status;
+DROP TABLE IF EXISTS "email_outbox";
+ALTER TABLE expert_requests DROP COLUMN nomination_invite_id;
+ALTER TABLE expert_requests DROP COLUMN is_self_nominated;
+DROP TABLE IF EXISTS "nomination_invites";
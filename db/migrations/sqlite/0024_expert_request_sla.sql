@@ -0,0 +1,38 @@
+-- +goose Up
+-- When each request entered its current status, for aging and review SLAs
+ALTER TABLE expert_requests ADD COLUMN status_changed_at TIMESTAMP;
+
+UPDATE expert_requests
+SET status_changed_at = COALESCE(
+    (SELECT MAX(h.changed_at) FROM expert_request_status_history h
+     WHERE h.request_id = expert_requests.id AND h.to_status = expert_requests.status),
+    created_at
+);
+
+CREATE INDEX idx_expert_requests_status_changed ON expert_requests(status, status_changed_at);
+
+-- Notifications for administrators, e.g. requests escalated for breaching their review SLA
+CREATE TABLE IF NOT EXISTS "admin_notifications" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,                            -- sla_breach
+    request_id INTEGER,                            -- References expert_requests(id)
+    status TEXT,                                   -- Request status the notification is about
+    status_changed_at TIMESTAMP,                   -- When the request entered that status
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMP,
+    acknowledged_by INTEGER,                       -- References users(id)
+    FOREIGN KEY (request_id) REFERENCES expert_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (acknowledged_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- A request is escalated at most once for each time it enters a status
+CREATE UNIQUE INDEX idx_admin_notifications_escalation ON admin_notifications(kind, request_id, status, status_changed_at);
+CREATE INDEX idx_admin_notifications_created ON admin_notifications(acknowledged_at, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_admin_notifications_created;
+DROP INDEX IF EXISTS idx_admin_notifications_escalation;
+DROP TABLE IF EXISTS "admin_notifications";
+DROP INDEX IF EXISTS idx_expert_requests_status_changed;
+ALTER TABLE expert_requests DROP COLUMN status_changed_at;
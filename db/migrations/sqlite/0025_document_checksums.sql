@@ -0,0 +1,13 @@
+-- +goose Up
+-- SHA-256 of each stored file, used to detect corruption and to share identical uploads
+-- Rows uploaded before this migration keep a NULL checksum until the next integrity scan fills it in
+ALTER TABLE expert_documents ADD COLUMN sha256 TEXT;
+
+CREATE INDEX idx_documents_sha256 ON expert_documents(sha256);
+CREATE INDEX idx_documents_file_path ON expert_documents(file_path);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_file_path;
+DROP INDEX IF EXISTS idx_documents_sha256;
+
+ALTER TABLE expert_documents DROP COLUMN sha256;
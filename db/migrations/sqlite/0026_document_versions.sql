@@ -0,0 +1,33 @@
+-- +goose Up
+-- Every file uploaded for a document is kept as a numbered version; expert_documents mirrors the current one
+ALTER TABLE expert_documents ADD COLUMN current_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE expert_documents ADD COLUMN uploaded_by INTEGER; -- References users(id), uploader of the current version
+
+CREATE TABLE IF NOT EXISTS "document_versions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,              -- References expert_documents(id)
+    version INTEGER NOT NULL,                  -- 1 for the original upload, increasing with each replacement
+    filename TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    sha256 TEXT,
+    uploaded_by INTEGER,                       -- References users(id); NULL when unknown
+    uploaded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    note TEXT,
+    UNIQUE (document_id, version)
+);
+
+CREATE INDEX idx_document_versions_file_path ON document_versions(file_path);
+
+-- Existing documents become version 1 of themselves
+INSERT INTO document_versions (document_id, version, filename, file_path, content_type, file_size, sha256, uploaded_at)
+SELECT id, 1, filename, file_path, content_type, file_size, sha256, COALESCE(upload_date, CURRENT_TIMESTAMP)
+FROM expert_documents;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_document_versions_file_path;
+DROP TABLE IF EXISTS document_versions;
+
+ALTER TABLE expert_documents DROP COLUMN uploaded_by;
+ALTER TABLE expert_documents DROP COLUMN current_version;
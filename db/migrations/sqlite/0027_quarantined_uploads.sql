@@ -0,0 +1,21 @@
+-- +goose Up
+-- Uploads flagged by the malware scanner are kept aside for review instead of being stored as documents
+CREATE TABLE IF NOT EXISTS "quarantined_uploads" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    filename TEXT NOT NULL,                    -- Name given by the uploader
+    file_path TEXT NOT NULL,                   -- Storage key under quarantine/
+    content_type TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    scanner TEXT NOT NULL,                     -- Scanner that flagged the file
+    signature TEXT NOT NULL,                   -- Signature reported by the scanner
+    document_type TEXT NOT NULL,               -- Document type the upload was intended as
+    source TEXT NOT NULL,                      -- What the upload was for, e.g. "expert 12" or "expert request 5"
+    quarantined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_quarantined_uploads_quarantined_at ON quarantined_uploads(quarantined_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_quarantined_uploads_quarantined_at;
+DROP TABLE IF EXISTS quarantined_uploads;
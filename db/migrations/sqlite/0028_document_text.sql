@@ -0,0 +1,8 @@
+-- +goose Up
+-- Plain text extracted from PDF and DOCX documents, searchable from the expert list
+ALTER TABLE expert_documents ADD COLUMN extracted_text TEXT;          -- NULL when no text could be extracted
+ALTER TABLE expert_documents ADD COLUMN text_extracted_at TIMESTAMP;  -- NULL until extraction has been attempted
+
+-- +goose Down
+ALTER TABLE expert_documents DROP COLUMN text_extracted_at;
+ALTER TABLE expert_documents DROP COLUMN extracted_text;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- Signed links that let someone without an account download one document
+-- The signature is derived from DOWNLOAD_LINK_SECRET when the link is created and is not stored
+CREATE TABLE IF NOT EXISTS "document_download_links" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,                  -- References expert_documents(id)
+    recipient TEXT NOT NULL,                       -- Who the link was made for, e.g. an external panel member
+    single_use BOOLEAN NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    download_count INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_by INTEGER,                            -- References users(id)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_document_download_links_document ON document_download_links(document_id);
+
+-- Every download made through a link, with who created the link and where it was used from
+CREATE TABLE IF NOT EXISTS "document_download_log" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    link_id INTEGER NOT NULL,                      -- References document_download_links(id)
+    document_id INTEGER NOT NULL,
+    recipient TEXT NOT NULL,
+    link_created_by INTEGER,
+    ip_address TEXT,
+    user_agent TEXT,
+    downloaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_document_download_log_link ON document_download_log(link_id, downloaded_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_document_download_log_link;
+DROP TABLE IF EXISTS "document_download_log";
+DROP INDEX IF EXISTS idx_document_download_links_document;
+DROP TABLE IF EXISTS "document_download_links";
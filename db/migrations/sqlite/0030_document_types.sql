@@ -0,0 +1,42 @@
+-- +goose Up
+-- Registry of document types, managed by admins
+-- expert_documents.document_type and quarantined_uploads.document_type hold a code from this table
+CREATE TABLE IF NOT EXISTS "document_types" (
+    code TEXT PRIMARY KEY,                         -- e.g. 'cv'; used in file names, so lowercase letters, digits and underscores
+    name TEXT NOT NULL,
+    description TEXT,
+    allowed_mime_types TEXT NOT NULL,              -- Comma-separated content types accepted for uploads
+    max_size INTEGER NOT NULL,                     -- Largest accepted upload in bytes
+    directory TEXT NOT NULL,                       -- Storage directory of expert documents of this type
+    expert_requirement TEXT NOT NULL DEFAULT 'optional' CHECK (expert_requirement IN ('required', 'optional', 'none')),
+    request_requirement TEXT NOT NULL DEFAULT 'none' CHECK (request_requirement IN ('required', 'optional', 'none')),
+    multiple BOOLEAN NOT NULL DEFAULT 0,           -- Whether an expert may hold several documents of this type
+    retired_at TIMESTAMP,                          -- Retired types accept no new uploads
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The types previously built into the document service, with its 10 MB limit and accepted formats
+INSERT OR IGNORE INTO document_types (code, name, description, allowed_mime_types, max_size, directory, expert_requirement, request_requirement, multiple) VALUES
+    ('cv', 'CV', 'Curriculum vitae',
+     'application/pdf,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,image/jpeg,image/png',
+     10485760, 'experts', 'required', 'required', 0),
+    ('approval', 'Approval', 'Signed approval of the expert''s nomination',
+     'application/pdf,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,image/jpeg,image/png',
+     10485760, 'approvals', 'required', 'required', 0),
+    ('certificate', 'Certificate', 'Training and qualification certificates',
+     'application/pdf,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,image/jpeg,image/png',
+     10485760, 'certificates', 'optional', 'none', 1),
+    ('publication', 'Publication', 'Papers, reports and other published work',
+     'application/pdf,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,image/jpeg,image/png',
+     10485760, 'publications', 'optional', 'none', 1);
+
+-- Any other type already in use is registered with the same defaults, so its documents stay valid
+INSERT OR IGNORE INTO document_types (code, name, allowed_mime_types, max_size, directory, multiple)
+SELECT DISTINCT document_type, document_type,
+    'application/pdf,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,image/jpeg,image/png',
+    10485760, document_type, 1
+FROM expert_documents;
+
+-- +goose Down
+DROP TABLE IF EXISTS "document_types";
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Resumable uploads: a file is sent in chunks, verified against its checksum once complete and then
+-- used in place of a multipart file by the endpoints that accept uploads
+CREATE TABLE IF NOT EXISTS "upload_sessions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_type TEXT NOT NULL,                   -- Code of the document type the file is uploaded as
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    file_size INTEGER NOT NULL,                    -- Declared size of the whole file in bytes
+    sha256 TEXT NOT NULL,                          -- Declared checksum, verified on completion
+    received INTEGER NOT NULL DEFAULT 0,           -- Bytes received so far; the offset of the next chunk
+    status TEXT NOT NULL DEFAULT 'uploading' CHECK (status IN ('uploading', 'complete')),
+    created_by INTEGER NOT NULL,                   -- References users(id); only this user may use the upload
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL                  -- Unused uploads are discarded after this time
+);
+
+CREATE INDEX idx_upload_sessions_expires ON upload_sessions(expires_at);
+
+-- Chunks received for an upload, each stored as a separate file under uploads/
+CREATE TABLE IF NOT EXISTS "upload_chunks" (
+    upload_id INTEGER NOT NULL,                    -- References upload_sessions(id)
+    chunk_offset INTEGER NOT NULL,                 -- Position of the chunk's first byte in the file
+    size INTEGER NOT NULL,
+    file_path TEXT NOT NULL,
+    PRIMARY KEY (upload_id, chunk_offset)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "upload_chunks";
+DROP INDEX IF EXISTS idx_upload_sessions_expires;
+DROP TABLE IF EXISTS "upload_sessions";
@@ -0,0 +1,10 @@
+-- +goose Up
+-- When an active engagement without an end date was flagged as overdue by the engagement scheduler
+ALTER TABLE expert_engagements ADD COLUMN overdue_since TIMESTAMP;
+
+-- Notifications about engagements, such as overdue ones
+ALTER TABLE admin_notifications ADD COLUMN engagement_id INTEGER REFERENCES expert_engagements(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE admin_notifications DROP COLUMN engagement_id;
+ALTER TABLE expert_engagements DROP COLUMN overdue_since;
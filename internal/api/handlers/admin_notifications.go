@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+)
+
+// AdminNotificationHandler handles the administrators' notification feed
+type AdminNotificationHandler struct {
+	store storage.Storage
+}
+
+// NewAdminNotificationHandler creates a new admin notification handler
+func NewAdminNotificationHandler(store storage.Storage) *AdminNotificationHandler {
+	return &AdminNotificationHandler{
+		store: store,
+	}
+}
+
+// HandleListAdminNotifications handles GET /api/notifications requests
+// Pass unacknowledged=true to only return notifications nobody has handled yet
+func (h *AdminNotificationHandler) HandleListAdminNotifications(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	pagination := utils.ParsePaginationParams(r, 20)
+	unacknowledgedOnly := r.URL.Query().Get("unacknowledged") == "true"
+
+	notifications, err := h.store.ListAdminNotifications(unacknowledgedOnly, pagination.Limit, pagination.Offset)
+	if err != nil {
+		log.Error("Failed to list admin notifications: %v", err)
+		return fmt.Errorf("failed to retrieve notifications: %w", err)
+	}
+
+	total, err := h.store.CountAdminNotifications(unacknowledgedOnly)
+	if err != nil {
+		return fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	unacknowledged, err := h.store.CountAdminNotifications(true)
+	if err != nil {
+		return fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	response := utils.BuildPaginationResponse(notifications, total, pagination)
+	response["unacknowledged"] = unacknowledged
+	return utils.RespondWithSuccess(w, "", response)
+}
+
+// HandleAcknowledgeAdminNotification handles POST /api/notifications/{id}/acknowledge requests
+func (h *AdminNotificationHandler) HandleAcknowledgeAdminNotification(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "notification")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.AcknowledgeAdminNotification(id, userID); err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to acknowledge notification: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "Notification acknowledged", nil)
+}
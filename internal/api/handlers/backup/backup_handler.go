@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"expertdb/internal/domain"
@@ -82,6 +83,12 @@ func (h *Handler) HandleBackupCSV(w http.ResponseWriter, r *http.Request) error
 		return fmt.Errorf("failed to backup documents: %w", err)
 	}
 
+	// Generate and add document type registry CSV
+	if err := h.addDocumentTypesToZip(zipWriter, tempDir); err != nil {
+		log.Error("Failed to add document types to backup: %v", err)
+		return fmt.Errorf("failed to backup document types: %w", err)
+	}
+
 	// Generate and add expert areas CSV
 	if err := h.addAreasToZip(zipWriter, tempDir); err != nil {
 		log.Error("Failed to add areas to backup: %v", err)
@@ -194,7 +201,7 @@ func (h *Handler) addExpertsToZip(zipWriter *zip.Writer, tempDir string) error {
 // addExpertRequestsToZip creates a CSV file with expert requests data and adds it to the ZIP archive
 func (h *Handler) addExpertRequestsToZip(zipWriter *zip.Writer, tempDir string) error {
 	// Get all expert requests (both pending, approved, and rejected)
-	requests, err := h.store.ListExpertRequests("all", 0, 0)
+	requests, err := h.store.ListExpertRequests(map[string]interface{}{"status": "all"}, 0, 0)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve expert requests: %w", err)
 	}
@@ -435,6 +442,63 @@ func (h *Handler) addDocumentsToZip(zipWriter *zip.Writer, tempDir string) error
 	return addFileToZip(zipWriter, csvPath, "documents.csv")
 }
 
+// addDocumentTypesToZip creates a CSV file with the document type registry and adds it to the ZIP archive
+func (h *Handler) addDocumentTypesToZip(zipWriter *zip.Writer, tempDir string) error {
+	// Get all document types, including retired ones that existing documents may still use
+	types, err := h.store.ListDocumentTypes()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve document types: %w", err)
+	}
+
+	// Create CSV file
+	csvPath := filepath.Join(tempDir, "document_types.csv")
+	file, err := os.Create(csvPath)
+	if err != nil {
+		return fmt.Errorf("failed to create document types CSV file: %w", err)
+	}
+	defer file.Close()
+
+	// Create CSV writer
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header row
+	header := []string{
+		"Code", "Name", "Description", "AllowedMimeTypes", "MaxSize", "Directory",
+		"ExpertRequirement", "RequestRequirement", "Multiple", "RetiredAt",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write data rows
+	for _, t := range types {
+		retiredAt := ""
+		if t.RetiredAt != nil {
+			retiredAt = t.RetiredAt.Format(time.RFC3339)
+		}
+		row := []string{
+			t.Code,
+			t.Name,
+			t.Description,
+			strings.Join(t.AllowedMimeTypes, ","),
+			strconv.FormatInt(t.MaxSize, 10),
+			t.Directory,
+			t.ExpertRequirement,
+			t.RequestRequirement,
+			strconv.FormatBool(t.Multiple),
+			retiredAt,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write document type row: %w", err)
+		}
+	}
+	writer.Flush()
+
+	// Add file to ZIP
+	return addFileToZip(zipWriter, csvPath, "document_types.csv")
+}
+
 // addAreasToZip creates a CSV file with expert areas data and adds it to the ZIP archive
 func (h *Handler) addAreasToZip(zipWriter *zip.Writer, tempDir string) error {
 	// Get all areas
@@ -456,16 +520,22 @@ func (h *Handler) addAreasToZip(zipWriter *zip.Writer, tempDir string) error {
 	defer writer.Flush()
 
 	// Write header row
-	header := []string{"ID", "Name"}
+	header := []string{"ID", "Name", "ParentID", "Path"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	// Write data rows
 	for _, area := range areas {
+		parentID := ""
+		if area.ParentID != nil {
+			parentID = strconv.FormatInt(*area.ParentID, 10)
+		}
 		row := []string{
 			strconv.FormatInt(area.ID, 10),
 			area.Name,
+			parentID,
+			area.Path,
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write area row: %w", err)
@@ -0,0 +1,63 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// HandleDownloadPhaseDocuments handles GET /api/phases/{id}/documents/archive requests
+// Streams a ZIP with a folder per application of the phase holding the CVs and approvals of its
+// assigned experts, and a manifest.csv. The types parameter selects other document types
+func (h *Handler) HandleDownloadPhaseDocuments(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := utils.ExtractIDFromPath(r, "id", "phase")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	docTypes, err := h.documentService.ArchiveTypes(r.URL.Query().Get("types"))
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		return err
+	}
+
+	phase, err := h.store.GetPhase(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Phase not found")
+		}
+		return fmt.Errorf("failed to retrieve phase: %w", err)
+	}
+	folders, err := h.documentService.ApplicationFolders(phase)
+	if err != nil {
+		return err
+	}
+	if len(folders) == 0 {
+		return utils.RespondWithNotFound(w, "No application of the phase has experts assigned")
+	}
+
+	filename := fmt.Sprintf("%s_documents_%s.zip", phase.PhaseID, time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	summary, err := h.documentService.WriteArchive(w, folders, docTypes)
+	if err != nil {
+		// The response has started, so the client is left with an incomplete archive
+		log.Error("Failed to stream document archive of phase %d: %v", id, err)
+		return nil
+	}
+
+	log.Info("Document archive of phase %s sent: %d applications, %d documents included, %d missing, %d unreadable",
+		phase.PhaseID, summary.Folders, summary.Included, summary.Missing, summary.Unreadable)
+	return nil
+}
@@ -2,14 +2,17 @@
 package documents
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 	
 	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
 	"expertdb/internal/documents"
+	"expertdb/internal/domain"
 	"expertdb/internal/logger"
 	"expertdb/internal/storage"
 )
@@ -18,13 +21,16 @@ import (
 type Handler struct {
 	store           storage.Storage
 	documentService *documents.Service
+	publicBaseURL   string
 }
 
 // NewHandler creates a new document handler
-func NewHandler(store storage.Storage, documentService *documents.Service) *Handler {
+// publicBaseURL prefixes the download links it creates; without it only their path is returned
+func NewHandler(store storage.Storage, documentService *documents.Service, publicBaseURL string) *Handler {
 	return &Handler{
 		store:           store,
 		documentService: documentService,
+		publicBaseURL:   strings.TrimRight(publicBaseURL, "/"),
 	}
 }
 
@@ -53,6 +59,11 @@ func (h *Handler) HandleUploadDocument(w http.ResponseWriter, r *http.Request) e
 		return fmt.Errorf("invalid expert ID: %w", err)
 	}
 	
+	// A file sent with the resumable upload endpoints is passed by its upload ID instead of as a file
+	if uploadIDStr := r.FormValue("uploadId"); uploadIDStr != "" {
+		return h.uploadDocumentFromUpload(w, r, expertID, uploadIDStr)
+	}
+	
 	// Get document type or use default
 	docType := r.FormValue("documentType")
 	if docType == "" {
@@ -73,6 +84,12 @@ func (h *Handler) HandleUploadDocument(w http.ResponseWriter, r *http.Request) e
 		expertID, docType, header.Filename)
 	doc, err := h.documentService.CreateDocument(expertID, file, header, docType)
 	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return utils.RespondWithNotFound(w, "Expert not found")
+		}
 		log.Error("Failed to upload document: %v", err)
 		return fmt.Errorf("failed to upload document: %w", err)
 	}
@@ -82,6 +99,43 @@ func (h *Handler) HandleUploadDocument(w http.ResponseWriter, r *http.Request) e
 	return utils.RespondWithSuccess(w, "Document uploaded successfully", doc)
 }
 
+// uploadDocumentFromUpload stores a complete resumable upload as a document of an expert
+// The document gets the type the upload was made as; a documentType given as well must match it
+func (h *Handler) uploadDocumentFromUpload(w http.ResponseWriter, r *http.Request, expertID int64, uploadIDStr string) error {
+	log := logger.Get()
+	
+	uploadID, err := strconv.ParseInt(uploadIDStr, 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid upload ID")
+	}
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	if docType := r.FormValue("documentType"); docType != "" {
+		upload, err := h.documentService.GetUpload(uploadID, userID)
+		if err != nil {
+			return respondWithUploadError(w, err)
+		}
+		if upload.DocumentType != docType {
+			return utils.RespondWithBadRequest(w, fmt.Sprintf("upload %d was made for %s documents, not %s", uploadID, upload.DocumentType, docType))
+		}
+	}
+	
+	doc, err := h.documentService.CreateDocumentFromUpload(expertID, uploadID, userID)
+	if err != nil {
+		if err == domain.ErrNotFound || errors.Is(err, domain.ErrValidation) {
+			return respondWithUploadError(w, err)
+		}
+		log.Error("Failed to store upload %d as a document: %v", uploadID, err)
+		return fmt.Errorf("failed to upload document: %w", err)
+	}
+	
+	log.Info("Document uploaded successfully from upload %d: ID: %d, Type: %s, Expert: %d", uploadID, doc.ID, doc.DocumentType, doc.ExpertID)
+	return utils.RespondWithSuccess(w, "Document uploaded successfully", doc)
+}
+
 // HandleGetDocument handles GET /api/documents/{id} requests
 func (h *Handler) HandleGetDocument(w http.ResponseWriter, r *http.Request) error {
 	log := logger.Get()
@@ -102,6 +156,11 @@ func (h *Handler) HandleGetDocument(w http.ResponseWriter, r *http.Request) erro
 		return fmt.Errorf("document not found: %w", err)
 	}
 	
+	// Include the extracted text so reviewers can skim the document without downloading it
+	if doc.ExtractedText, err = h.documentService.GetDocumentText(id); err != nil {
+		log.Warn("Failed to load text of document %d: %v", id, err)
+	}
+	
 	// Return document information with standardized response
 	log.Debug("Returning document: ID: %d, Type: %s, Expert: %d", doc.ID, doc.DocumentType, doc.ExpertID)
 	return utils.RespondWithSuccess(w, "", doc)
@@ -150,13 +209,38 @@ func (h *Handler) HandleGetExpertDocuments(w http.ResponseWriter, r *http.Reques
 		log.Error("Failed to retrieve documents for expert %d: %v", id, err)
 		return fmt.Errorf("failed to retrieve documents: %w", err)
 	}
-	
+
+	// Required document types the expert has nothing of, according to the document type registry
+	missing, err := h.documentService.MissingExpertDocuments(id, docs)
+	if err != nil {
+		log.Error("Failed to check required documents for expert %d: %v", id, err)
+		return fmt.Errorf("failed to check required documents: %w", err)
+	}
+
+	// Optional: restrict to one registered document type
+	if docType := r.URL.Query().Get("type"); docType != "" {
+		if _, err := h.documentService.GetDocumentType(docType); err != nil {
+			if err == domain.ErrNotFound {
+				return utils.RespondWithBadRequest(w, fmt.Sprintf("Unknown document type '%s'", docType))
+			}
+			return fmt.Errorf("failed to get document type: %w", err)
+		}
+		filtered := []*domain.Document{}
+		for _, doc := range docs {
+			if doc.DocumentType == docType {
+				filtered = append(filtered, doc)
+			}
+		}
+		docs = filtered
+	}
+
 	// Return documents with standardized response
 	log.Debug("Returning %d documents for expert ID: %d", len(docs), id)
 	responseData := map[string]interface{}{
-		"documents": docs,
-		"count":     len(docs),
-		"expertId":  id,
+		"documents":       docs,
+		"count":           len(docs),
+		"expertId":        id,
+		"missingRequired": missing,
 	}
 	return utils.RespondWithSuccess(w, "", responseData)
 }
@@ -183,38 +267,104 @@ func (h *Handler) HandleDownloadDocument(w http.ResponseWriter, r *http.Request)
 	
 	// Open the file for reading
 	log.Debug("Opening file for download: %s", doc.FilePath)
-	file, err := os.Open(doc.FilePath)
+	file, fileSize, err := h.documentService.OpenDocument(doc)
 	if err != nil {
 		log.Error("Failed to open document file for download: %s - %v", doc.FilePath, err)
 		return fmt.Errorf("failed to open document file: %w", err)
 	}
 	defer file.Close()
 	
-	// Get file info for size verification
-	fileInfo, err := file.Stat()
+	// Stream the file content to the response
+	log.Debug("Streaming file content for download: %s (%d bytes)", doc.Filename, fileSize)
+	bytesWritten, err := writeDownload(w, doc.Filename, doc.ContentType, file, fileSize)
 	if err != nil {
-		log.Error("Failed to get file info for download: %s - %v", doc.FilePath, err)
-		return fmt.Errorf("failed to get file info: %w", err)
+		log.Error("Failed to stream file content for download: %v", err)
+		return fmt.Errorf("failed to stream file content: %w", err)
 	}
 	
+	log.Info("Document downloaded successfully: ID %d, Filename: %s, Bytes: %d", 
+		doc.ID, doc.Filename, bytesWritten)
+	
+	return nil
+}
+
+// writeDownload sets attachment headers and streams a file to the response
+// A negative size (unknown length) omits the Content-Length header
+func writeDownload(w http.ResponseWriter, filename, contentType string, file io.Reader, size int64) (int64, error) {
 	// Set appropriate headers for file download
-	w.Header().Set("Content-Type", doc.ContentType)
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", doc.Filename))
+	w.Header().Set("Content-Type", contentType)
+	if size >= 0 {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", size))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Set("Pragma", "no-cache")
 	w.Header().Set("Expires", "0")
 	
-	// Stream the file content to the response
-	log.Debug("Streaming file content for download: %s (%d bytes)", doc.Filename, fileInfo.Size())
-	bytesWritten, err := io.Copy(w, file)
+	return io.Copy(w, file)
+}
+
+// HandleVerifyDocument handles GET /api/documents/{id}/verify requests
+// The response reports whether the stored file still matches the checksum recorded at upload
+func (h *Handler) HandleVerifyDocument(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	// Extract and validate document ID from path
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		log.Error("Failed to stream file content for download: %v", err)
-		return fmt.Errorf("failed to stream file content: %w", err)
+		log.Warn("Invalid document ID provided for verification: %s", idStr)
+		return utils.RespondWithBadRequest(w, "invalid document ID")
 	}
 	
-	log.Info("Document downloaded successfully: ID %d, Filename: %s, Bytes: %d", 
-		doc.ID, doc.Filename, bytesWritten)
+	result, err := h.documentService.VerifyDocument(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document not found")
+		}
+		log.Error("Failed to verify document %d: %v", id, err)
+		return fmt.Errorf("failed to verify document: %w", err)
+	}
 	
-	return nil
-}
\ No newline at end of file
+	if !result.Intact && result.Status != domain.DocumentIntegrityUnhashed {
+		log.Warn("Document %d failed integrity check: %s (%s)", id, result.Status, result.FilePath)
+	}
+	return utils.RespondWithSuccess(w, "", result)
+}
+
+// HandleScanDocumentIntegrity handles POST /api/documents/integrity-scan requests
+// Every document is verified; checksums are recorded for documents uploaded before checksums
+// existed unless backfill=false is passed
+func (h *Handler) HandleScanDocumentIntegrity(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	backfill := true
+	if value := r.URL.Query().Get("backfill"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "backfill must be true or false")
+		}
+		backfill = parsed
+	}
+	
+	report, err := h.documentService.ScanIntegrity(backfill)
+	if err != nil {
+		log.Error("Document integrity scan failed: %v", err)
+		return fmt.Errorf("failed to scan documents: %w", err)
+	}
+	
+	return utils.RespondWithSuccess(w, "Document integrity scan completed", report)
+}
+
+// HandleExtractDocumentText handles POST /api/documents/extract-text requests
+// Text is extracted from documents that have none yet, or from every document with force=true
+func (h *Handler) HandleExtractDocumentText(w http.ResponseWriter, r *http.Request) error {
+	force := r.URL.Query().Get("force") == "true"
+
+	report, err := h.documentService.ExtractDocumentText(force)
+	if err != nil {
+		return fmt.Errorf("failed to extract document text: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "Document text extraction completed", report)
+}
@@ -0,0 +1,186 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Lifetime limits of download links, in hours
+const (
+	defaultLinkExpiryHours = 72
+	maxLinkExpiryHours     = 30 * 24
+)
+
+// CreateDownloadLinkRequest represents a new document download link
+type CreateDownloadLinkRequest struct {
+	Recipient      string `json:"recipient"`      // Who the link is for, recorded with every download
+	ExpiresInHours int    `json:"expiresInHours"` // Hours until the link expires (default 72, at most 720)
+	SingleUse      bool   `json:"singleUse"`      // Stop working after the first download
+}
+
+// linkURL returns the absolute URL of a signed link path under the configured public base URL
+// The request's Host and forwarding headers are not used, as clients control them
+func (h *Handler) linkURL(path string) string {
+	return h.publicBaseURL + path
+}
+
+// HandleCreateDownloadLink handles POST /api/documents/{id}/links requests
+// The response contains the signed URL, which is not stored and cannot be retrieved again
+func (h *Handler) HandleCreateDownloadLink(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid document ID")
+	}
+
+	var req CreateDownloadLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	req.Recipient = strings.TrimSpace(req.Recipient)
+	if req.ExpiresInHours == 0 {
+		req.ExpiresInHours = defaultLinkExpiryHours
+	}
+
+	var validationErrors []string
+	if req.Recipient == "" {
+		validationErrors = append(validationErrors, "recipient is required")
+	}
+	if req.ExpiresInHours < 1 || req.ExpiresInHours > maxLinkExpiryHours {
+		validationErrors = append(validationErrors, fmt.Sprintf("expiresInHours must be between 1 and %d", maxLinkExpiryHours))
+	}
+	if len(validationErrors) > 0 {
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	link := &domain.DocumentDownloadLink{
+		DocumentID: id,
+		Recipient:  req.Recipient,
+		SingleUse:  req.SingleUse,
+		ExpiresAt:  time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour),
+		CreatedBy:  userID,
+	}
+	if err := h.documentService.CreateDownloadLink(link); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document not found")
+		}
+		return fmt.Errorf("failed to create download link: %w", err)
+	}
+
+	path := h.documentService.DownloadLinkPath(link)
+	return utils.RespondWithSuccess(w, "Download link created", map[string]interface{}{
+		"link": link,
+		"path": path,
+		"url":  h.linkURL(path),
+	})
+}
+
+// HandleListDownloadLinks handles GET /api/documents/{id}/links requests
+func (h *Handler) HandleListDownloadLinks(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid document ID")
+	}
+
+	links, err := h.documentService.ListDownloadLinks(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document not found")
+		}
+		return fmt.Errorf("failed to list download links: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"documentId": id,
+		"links":      links,
+		"count":      len(links),
+	})
+}
+
+// HandleRevokeDownloadLink handles DELETE /api/document-links/{id} requests
+func (h *Handler) HandleRevokeDownloadLink(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "download link")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	if err := h.documentService.RevokeDownloadLink(id); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Download link not found")
+		}
+		return fmt.Errorf("failed to revoke download link: %w", err)
+	}
+
+	logger.Get().Info("Download link %d revoked", id)
+	return utils.RespondWithSuccess(w, "Download link revoked", nil)
+}
+
+// HandleListLinkDownloads handles GET /api/document-links/{id}/downloads requests
+func (h *Handler) HandleListLinkDownloads(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "download link")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	downloads, err := h.documentService.ListLinkDownloads(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Download link not found")
+		}
+		return fmt.Errorf("failed to list downloads: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"linkId":    id,
+		"downloads": downloads,
+		"count":     len(downloads),
+	})
+}
+
+// HandleDownloadWithLink handles GET /api/document-links/{id}/download requests
+// Public access: the signed expires and signature query parameters stand in for a bearer token
+func (h *Handler) HandleDownloadWithLink(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithCustomError(w, http.StatusForbidden, "Invalid download link", nil)
+	}
+
+	query := r.URL.Query()
+	doc, file, size, err := h.documentService.OpenDownloadLink(id, query.Get("expires"), query.Get("signature"),
+		utils.ClientIP(r), r.UserAgent())
+	switch {
+	case err == documents.ErrLinkInvalid:
+		log.Warn("Rejected download link %d from %s: invalid signature or unknown link", id, utils.ClientIP(r))
+		return utils.RespondWithCustomError(w, http.StatusForbidden, "Invalid download link", nil)
+	case err == documents.ErrLinkUnavailable:
+		return utils.RespondWithCustomError(w, http.StatusGone, "This download link has expired, been revoked or already been used", nil)
+	case err == domain.ErrNotFound:
+		return utils.RespondWithNotFound(w, "Document not found")
+	case err != nil:
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := writeDownload(w, doc.Filename, doc.ContentType, file, size); err != nil {
+		log.Error("Failed to stream document %d through download link %d: %v", doc.ID, id, err)
+		return fmt.Errorf("failed to stream file content: %w", err)
+	}
+	return nil
+}
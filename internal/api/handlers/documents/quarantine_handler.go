@@ -0,0 +1,45 @@
+package documents
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// HandleListQuarantinedUploads handles GET /api/documents/quarantine requests
+func (h *Handler) HandleListQuarantinedUploads(w http.ResponseWriter, r *http.Request) error {
+	uploads, err := h.documentService.ListQuarantinedUploads()
+	if err != nil {
+		return fmt.Errorf("failed to list quarantined uploads: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"uploads": uploads,
+		"count":   len(uploads),
+	})
+}
+
+// HandleDeleteQuarantinedUpload handles DELETE /api/documents/quarantine/{id} requests
+// The quarantined file is removed permanently
+func (h *Handler) HandleDeleteQuarantinedUpload(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid quarantined upload ID")
+	}
+
+	if err := h.documentService.DeleteQuarantinedUpload(id); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Quarantined upload not found")
+		}
+		return fmt.Errorf("failed to delete quarantined upload: %w", err)
+	}
+
+	log.Info("Quarantined upload %d deleted", id)
+	return utils.RespondWithSuccess(w, "Quarantined upload deleted successfully", nil)
+}
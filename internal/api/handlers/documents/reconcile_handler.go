@@ -0,0 +1,37 @@
+package documents
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/logger"
+)
+
+// HandleReconcileDocuments handles POST /api/documents/reconcile requests
+// By default discrepancies are only reported; repair=true and quarantine=true fix them
+func (h *Handler) HandleReconcileDocuments(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	options := map[string]bool{"repair": false, "quarantine": false}
+	for name := range options {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, name+" must be true or false")
+		}
+		options[name] = parsed
+	}
+
+	report, err := h.documentService.ReconcileDocuments(options["repair"], options["quarantine"])
+	if err != nil {
+		log.Error("Document reconciliation failed: %v", err)
+		return fmt.Errorf("failed to reconcile documents: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "Document reconciliation completed", report)
+}
@@ -0,0 +1,129 @@
+package documents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/domain"
+)
+
+// DocumentTypeRequest represents the settings of a document type in create and update requests
+type DocumentTypeRequest struct {
+	Code               string   `json:"code"` // Only read on create; the code of an existing type cannot change
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	AllowedMimeTypes   []string `json:"allowedMimeTypes"`
+	MaxSize            int64    `json:"maxSize"`            // Bytes
+	Directory          string   `json:"directory"`          // Storage directory, e.g. "publications"
+	ExpertRequirement  string   `json:"expertRequirement"`  // "required", "optional" (default) or "none"
+	RequestRequirement string   `json:"requestRequirement"` // "required", "optional" or "none" (default)
+	Multiple           bool     `json:"multiple"`
+}
+
+// documentType converts the request into a document type with the given code
+func (req *DocumentTypeRequest) documentType(code string) *domain.DocumentType {
+	return &domain.DocumentType{
+		Code:               code,
+		Name:               req.Name,
+		Description:        req.Description,
+		AllowedMimeTypes:   req.AllowedMimeTypes,
+		MaxSize:            req.MaxSize,
+		Directory:          req.Directory,
+		ExpertRequirement:  req.ExpertRequirement,
+		RequestRequirement: req.RequestRequirement,
+		Multiple:           req.Multiple,
+	}
+}
+
+// respondWithDocumentTypeError maps a failed registry change to an HTTP response
+func respondWithDocumentTypeError(w http.ResponseWriter, err error) error {
+	switch {
+	case err == domain.ErrNotFound:
+		return utils.RespondWithNotFound(w, "Document type not found")
+	case errors.Is(err, domain.ErrValidation):
+		message := strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": ")
+		return utils.RespondWithValidationErrorStrings(w, strings.Split(message, "; "))
+	default:
+		return err
+	}
+}
+
+// HandleListDocumentTypes handles GET /api/document-types requests
+// Retired types are only listed with include_retired=true
+func (h *Handler) HandleListDocumentTypes(w http.ResponseWriter, r *http.Request) error {
+	types, err := h.documentService.ListDocumentTypes(r.URL.Query().Get("include_retired") == "true")
+	if err != nil {
+		return fmt.Errorf("failed to list document types: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"documentTypes": types,
+		"count":         len(types),
+	})
+}
+
+// HandleCreateDocumentType handles POST /api/document-types requests
+func (h *Handler) HandleCreateDocumentType(w http.ResponseWriter, r *http.Request) error {
+	var req DocumentTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	t := req.documentType(req.Code)
+	if err := h.documentService.CreateDocumentType(t); err != nil {
+		return respondWithDocumentTypeError(w, err)
+	}
+
+	return utils.RespondWithSuccess(w, "Document type created", t)
+}
+
+// HandleUpdateDocumentType handles PUT /api/document-types/{code} requests
+// All settings are replaced; they apply to later uploads only
+func (h *Handler) HandleUpdateDocumentType(w http.ResponseWriter, r *http.Request) error {
+	code := r.PathValue("code")
+
+	var req DocumentTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	t := req.documentType(code)
+	if err := h.documentService.UpdateDocumentType(t); err != nil {
+		return respondWithDocumentTypeError(w, err)
+	}
+
+	updated, err := h.documentService.GetDocumentType(code)
+	if err != nil {
+		return fmt.Errorf("failed to get updated document type: %w", err)
+	}
+	return utils.RespondWithSuccess(w, "Document type updated", updated)
+}
+
+// HandleRetireDocumentType handles PUT /api/document-types/{code}/retire requests
+func (h *Handler) HandleRetireDocumentType(w http.ResponseWriter, r *http.Request) error {
+	code := r.PathValue("code")
+
+	var req struct {
+		Retired bool `json:"retired"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	if err := h.documentService.SetDocumentTypeRetired(code, req.Retired); err != nil {
+		return respondWithDocumentTypeError(w, err)
+	}
+
+	message := "Document type restored"
+	if req.Retired {
+		message = "Document type retired"
+	}
+	return utils.RespondWithSuccess(w, message, map[string]interface{}{
+		"code":    code,
+		"retired": req.Retired,
+	})
+}
@@ -0,0 +1,153 @@
+package documents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+)
+
+// StartUploadRequest describes a file to be sent in chunks
+type StartUploadRequest struct {
+	DocumentType string `json:"documentType"` // Code of a registered document type
+	Filename     string `json:"filename"`
+	ContentType  string `json:"contentType"`
+	FileSize     int64  `json:"fileSize"` // Size of the whole file in bytes
+	SHA256       string `json:"sha256"`   // Hex SHA-256 of the whole file, verified on completion
+}
+
+// respondWithUploadError maps a failed upload operation to an HTTP response
+func respondWithUploadError(w http.ResponseWriter, err error) error {
+	switch {
+	case err == domain.ErrNotFound:
+		return utils.RespondWithNotFound(w, "Upload not found")
+	case errors.Is(err, domain.ErrValidation):
+		return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+	default:
+		return err
+	}
+}
+
+// HandleStartUpload handles POST /api/uploads requests
+// The response holds the upload ID and the largest chunk the server accepts
+func (h *Handler) HandleStartUpload(w http.ResponseWriter, r *http.Request) error {
+	var req StartUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	upload := &domain.UploadSession{
+		DocumentType: req.DocumentType,
+		Filename:     req.Filename,
+		ContentType:  req.ContentType,
+		FileSize:     req.FileSize,
+		SHA256:       req.SHA256,
+		CreatedBy:    userID,
+	}
+	if err := h.documentService.StartUpload(upload); err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			message := strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": ")
+			return utils.RespondWithValidationErrorStrings(w, strings.Split(message, "; "))
+		}
+		return fmt.Errorf("failed to start upload: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "Upload started", upload)
+}
+
+// HandleGetUpload handles GET /api/uploads/{id} requests
+// Clients resuming an interrupted upload continue from the returned received count
+func (h *Handler) HandleGetUpload(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "upload")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	upload, err := h.documentService.GetUpload(id, userID)
+	if err != nil {
+		return respondWithUploadError(w, err)
+	}
+	return utils.RespondWithSuccess(w, "", upload)
+}
+
+// HandleUploadChunk handles PUT /api/uploads/{id}/chunks?offset={offset} requests
+// The request body is the raw chunk. A chunk at the wrong offset is rejected with 409 and the
+// offset the upload continues from
+func (h *Handler) HandleUploadChunk(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "upload")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return utils.RespondWithBadRequest(w, "offset must be the number of bytes sent before this chunk")
+	}
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	upload, err := h.documentService.AddUploadChunk(id, userID, offset, r.Body)
+	if err == documents.ErrUploadOffset {
+		return utils.RespondWithCustomError(w, http.StatusConflict,
+			fmt.Sprintf("Chunk offset %d does not match the %d bytes received", offset, upload.Received),
+			map[string]interface{}{"received": upload.Received})
+	}
+	if err != nil {
+		return respondWithUploadError(w, err)
+	}
+	return utils.RespondWithSuccess(w, "", upload)
+}
+
+// HandleCompleteUpload handles POST /api/uploads/{id}/complete requests
+// The upload is checked against its declared size and checksum; a complete upload can then be passed
+// as uploadId to POST /api/documents, or as approvalUploadId to batch approval
+func (h *Handler) HandleCompleteUpload(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "upload")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	upload, err := h.documentService.CompleteUpload(id, userID)
+	if err != nil {
+		return respondWithUploadError(w, err)
+	}
+	return utils.RespondWithSuccess(w, "Upload complete", upload)
+}
+
+// HandleCancelUpload handles DELETE /api/uploads/{id} requests
+func (h *Handler) HandleCancelUpload(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "upload")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.documentService.CancelUpload(id, userID); err != nil {
+		return respondWithUploadError(w, err)
+	}
+	return utils.RespondWithSuccess(w, "Upload cancelled", nil)
+}
@@ -0,0 +1,149 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// HandleListDocumentVersions handles GET /api/documents/{id}/versions requests
+func (h *Handler) HandleListDocumentVersions(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid document ID")
+	}
+
+	versions, err := h.documentService.ListDocumentVersions(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document not found")
+		}
+		return fmt.Errorf("failed to list document versions: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"documentId": id,
+		"versions":   versions,
+		"count":      len(versions),
+	})
+}
+
+// HandleUploadDocumentVersion handles POST /api/documents/{id}/versions requests
+// The multipart form carries the new "file" and an optional "note"
+func (h *Handler) HandleUploadDocumentVersion(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "invalid document ID")
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		return utils.RespondWithBadRequest(w, "failed to parse form - file may be too large")
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "no file provided")
+	}
+	defer file.Close()
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	doc, err := h.documentService.AddDocumentVersion(id, file, header, userID, strings.TrimSpace(r.FormValue("note")))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document not found")
+		}
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		log.Error("Failed to upload version of document %d: %v", id, err)
+		return fmt.Errorf("failed to upload document version: %w", err)
+	}
+
+	log.Info("Document %d replaced with version %d by user %d", id, doc.CurrentVersion, userID)
+	return utils.RespondWithSuccess(w, "Document version uploaded successfully", doc)
+}
+
+// HandleDownloadDocumentVersion handles GET /api/documents/{id}/versions/{version}/download requests
+func (h *Handler) HandleDownloadDocumentVersion(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, version, ok := parseVersionPath(w, r)
+	if !ok {
+		return nil
+	}
+
+	v, err := h.documentService.GetDocumentVersion(id, version)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document version not found")
+		}
+		return fmt.Errorf("failed to get document version: %w", err)
+	}
+
+	file, fileSize, err := h.documentService.OpenDocumentVersion(v)
+	if err != nil {
+		log.Error("Failed to open file of document %d version %d: %s - %v", id, version, v.FilePath, err)
+		return fmt.Errorf("failed to open document file: %w", err)
+	}
+	defer file.Close()
+
+	bytesWritten, err := writeDownload(w, v.Filename, v.ContentType, file, fileSize)
+	if err != nil {
+		log.Error("Failed to stream file content for download: %v", err)
+		return fmt.Errorf("failed to stream file content: %w", err)
+	}
+
+	log.Info("Document version downloaded: ID %d, version %d, Bytes: %d", id, version, bytesWritten)
+	return nil
+}
+
+// HandlePromoteDocumentVersion handles POST /api/documents/{id}/versions/{version}/promote requests
+func (h *Handler) HandlePromoteDocumentVersion(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, version, ok := parseVersionPath(w, r)
+	if !ok {
+		return nil
+	}
+
+	doc, err := h.documentService.PromoteDocumentVersion(id, version)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Document version not found")
+		}
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		log.Error("Failed to promote document %d to version %d: %v", id, version, err)
+		return fmt.Errorf("failed to promote document version: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, fmt.Sprintf("Version %d is now the current version", version), doc)
+}
+
+// parseVersionPath reads the document ID and version number from the path, responding with 400 when invalid
+func parseVersionPath(w http.ResponseWriter, r *http.Request) (int64, int, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		utils.RespondWithBadRequest(w, "invalid document ID")
+		return 0, 0, false
+	}
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		utils.RespondWithBadRequest(w, "invalid version number")
+		return 0, 0, false
+	}
+	return id, version, true
+}
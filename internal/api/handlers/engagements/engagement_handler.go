@@ -4,6 +4,7 @@ package engagements
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -62,13 +63,16 @@ func (h *Handler) HandleCreateEngagement(w http.ResponseWriter, r *http.Request)
 	// Set default status if not provided
 	if engagement.Status == "" {
 		log.Debug("No status specified, using default status: pending")
-		engagement.Status = "pending" // Default status
+		engagement.Status = domain.EngagementStatusPending // Default status
 	}
 
 	// Create the engagement in database
 	log.Debug("Creating engagement for expert ID: %d, type: %s",
 		engagement.ExpertID, engagement.EngagementType)
 	id, err := h.store.CreateEngagement(&engagement)
+	if errors.Is(err, domain.ErrValidation) {
+		return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+	}
 	if err != nil {
 		log.Error("Failed to create engagement in database: %v", err)
 		return fmt.Errorf("failed to create engagement: %w", err)
@@ -163,9 +167,12 @@ func (h *Handler) HandleUpdateEngagement(w http.ResponseWriter, r *http.Request)
 		updateEngagement.Notes = existing.Notes
 	}
 
-	// Update the engagement in database
+	// Update the engagement in database; status changes must follow the engagement transition rules
 	log.Debug("Updating engagement ID: %d, Type: %s", id, updateEngagement.EngagementType)
 	if err := h.store.UpdateEngagement(&updateEngagement); err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
 		log.Error("Failed to update engagement in database: %v", err)
 		return fmt.Errorf("failed to update engagement: %w", err)
 	}
@@ -481,7 +488,7 @@ func parseCSVEngagements(file io.Reader) ([]*domain.Engagement, error) {
 		}
 
 		if idx, ok := headerIndex["status"]; ok && idx < len(row) && row[idx] != "" {
-			engagement.Status = strings.TrimSpace(row[idx])
+			engagement.Status = strings.ToLower(strings.TrimSpace(row[idx]))
 		}
 
 		if idx, ok := headerIndex["feedback_score"]; ok && idx < len(row) && row[idx] != "" {
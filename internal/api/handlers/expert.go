@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -38,43 +40,9 @@ func (h *ExpertHandler) HandleGetExperts(w http.ResponseWriter, r *http.Request)
 
 	// Parse query parameters for filtering - Clean standardized approach
 	queryParams := r.URL.Query()
-	filters := make(map[string]interface{})
-
-	// Multi-value filters for the four key fields
-	if generalArea := queryParams.Get("general_area"); generalArea != "" {
-		filters["general_area"] = generalArea
-	}
-	
-	if institution := queryParams.Get("institution"); institution != "" {
-		filters["institution"] = institution
-	}
-	
-	if role := queryParams.Get("role"); role != "" {
-		filters["role"] = role
-	}
-	
-	if employmentType := queryParams.Get("employment_type"); employmentType != "" {
-		filters["employment_type"] = employmentType
-	}
-
-	// Boolean filters (single value only)
-	if available := queryParams.Get("is_available"); available != "" {
-		filters["is_available"] = available == "true"
-	}
-
-	// Filter by nationality (Bahraini/non-Bahraini)
-	if nationality := queryParams.Get("is_bahraini"); nationality != "" {
-		filters["is_bahraini"] = nationality == "true"
-	}
-	
-	// Filter by specialized area (supports multiple values)
-	if specializedArea := queryParams.Get("specialized_area"); specializedArea != "" {
-		filters["specialized_area"] = specializedArea
-	}
-
-	// Published status filter
-	if published := queryParams.Get("is_published"); published != "" {
-		filters["is_published"] = published == "true"
+	filters, err := parseExpertFilters(queryParams)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
 	}
 
 	// Process sorting parameters
@@ -97,6 +65,7 @@ func (h *ExpertHandler) HandleGetExperts(w http.ResponseWriter, r *http.Request)
 			"is_bahraini": true,
 			"is_available": true,
 			"is_published": true,
+			"is_trained": true,
 		}
 		
 		if allowedSortFields[sortParam] {
@@ -187,6 +156,80 @@ func (h *ExpertHandler) HandleGetExperts(w http.ResponseWriter, r *http.Request)
 	return utils.RespondWithSuccess(w, "", responseData)
 }
 
+// parseExpertFilters reads the filters of GET /api/experts from query parameters
+// The returned error describes an invalid parameter
+func parseExpertFilters(queryParams url.Values) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	// Multi-value filters for the four key fields
+	if generalArea := queryParams.Get("general_area"); generalArea != "" {
+		filters["general_area"] = generalArea
+		
+		// Optionally match sub-areas of the selected general areas
+		if queryParams.Get("include_descendants") == "true" {
+			filters["general_area_descendants"] = true
+		}
+	}
+	
+	if institution := queryParams.Get("institution"); institution != "" {
+		filters["institution"] = institution
+	}
+	
+	if role := queryParams.Get("role"); role != "" {
+		filters["role"] = role
+	}
+	
+	if employmentType := queryParams.Get("employment_type"); employmentType != "" {
+		filters["employment_type"] = employmentType
+	}
+
+	// Boolean filters (single value only)
+	if available := queryParams.Get("is_available"); available != "" {
+		filters["is_available"] = available == "true"
+	}
+
+	// Filter by nationality (Bahraini/non-Bahraini)
+	if nationality := queryParams.Get("is_bahraini"); nationality != "" {
+		filters["is_bahraini"] = nationality == "true"
+	}
+	
+	// Filter by specialized area (supports multiple values)
+	if specializedArea := queryParams.Get("specialized_area"); specializedArea != "" {
+		filters["specialized_area"] = specializedArea
+	}
+
+	// Published status filter
+	if published := queryParams.Get("is_published"); published != "" {
+		filters["is_published"] = published == "true"
+	}
+
+	// Training filters - trained status is derived from valid attendance records
+	if trained := queryParams.Get("is_trained"); trained != "" {
+		filters["is_trained"] = trained == "true"
+	}
+
+	// Trained in one or more programs (comma-separated program IDs)
+	if trainedIn := queryParams.Get("trained_in"); trainedIn != "" {
+		filters["trained_in"] = trainedIn
+	}
+
+	// Search the text extracted from expert CVs; quoted phrases are matched as a whole
+	if cvText := strings.TrimSpace(queryParams.Get("cv_text")); cvText != "" {
+		filters["cv_text"] = cvText
+	}
+
+	// Training lapsing within N days
+	if expiring := queryParams.Get("training_expiring_within"); expiring != "" {
+		days, err := strconv.Atoi(expiring)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("training_expiring_within must be a non-negative number of days")
+		}
+		filters["training_expiring_within"] = days
+	}
+
+	return filters, nil
+}
+
 // HandleGetExpert handles GET /api/experts/{id} requests
 func (h *ExpertHandler) HandleGetExpert(w http.ResponseWriter, r *http.Request) error {
 	log := logger.Get()
@@ -281,6 +324,8 @@ func (h *ExpertHandler) HandleCreateExpert(w http.ResponseWriter, r *http.Reques
 		errors = append(errors, "specializedArea is required")
 	}
 	
+	errors = append(errors, retiredAreaErrors(h.store, expert.GeneralArea, expert.SpecializedArea)...)
+	
 	if expert.Phone == "" {
 		errors = append(errors, "phone is required")
 	}
@@ -413,19 +458,29 @@ func (h *ExpertHandler) HandleUpdateExpert(w http.ResponseWriter, r *http.Reques
 			return fmt.Errorf("invalid JSON data: %w", err)
 		}
 		
+		// Replaced documents keep their earlier files as versions, recording who uploaded the new one
+		uploaderID, err := auth.GetUserIDFromRequest(r)
+		if err != nil {
+			log.Warn("Failed to get user ID from request")
+			return err
+		}
+		
 		// Process CV file if provided
 		cvFile, cvFileHeader, err := r.FormFile("cvFile")
 		if err == nil {
 			// CV file was provided, replace existing document
 			defer cvFile.Close()
 			
-			cvDoc, err := h.documentService.ReplaceExpertDocument(id, cvFile, cvFileHeader, "cv")
+			cvDoc, err := h.documentService.ReplaceExpertDocument(id, cvFile, cvFileHeader, "cv", uploaderID, r.FormValue("cvNote"))
 			if err != nil {
+				if errors.Is(err, domain.ErrValidation) {
+					return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+				}
 				log.Error("Failed to replace CV file: %v", err)
 				return fmt.Errorf("failed to replace CV: %w", err)
 			}
 			
-			log.Debug("Replaced CV document for expert ID: %d with new document ID: %d", id, cvDoc.ID)
+			log.Debug("Replaced CV document for expert ID: %d (document ID: %d, version %d)", id, cvDoc.ID, cvDoc.CurrentVersion)
 		} else if err != http.ErrMissingFile {
 			log.Warn("Error accessing CV file: %v", err)
 			return fmt.Errorf("error processing CV file: %w", err)
@@ -437,13 +492,16 @@ func (h *ExpertHandler) HandleUpdateExpert(w http.ResponseWriter, r *http.Reques
 			// Approval document file was provided, replace existing document
 			defer approvalFile.Close()
 			
-			approvalDoc, err := h.documentService.ReplaceExpertDocument(id, approvalFile, approvalFileHeader, "approval")
+			approvalDoc, err := h.documentService.ReplaceExpertDocument(id, approvalFile, approvalFileHeader, "approval", uploaderID, r.FormValue("approvalNote"))
 			if err != nil {
+				if errors.Is(err, domain.ErrValidation) {
+					return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+				}
 				log.Error("Failed to replace approval document: %v", err)
 				return fmt.Errorf("failed to replace approval document: %w", err)
 			}
 			
-			log.Debug("Replaced approval document for expert ID: %d with new document ID: %d", id, approvalDoc.ID)
+			log.Debug("Replaced approval document for expert ID: %d (document ID: %d, version %d)", id, approvalDoc.ID, approvalDoc.CurrentVersion)
 		} else if err != http.ErrMissingFile {
 			log.Warn("Error accessing approval document file: %v", err)
 			return fmt.Errorf("error processing approval document file: %w", err)
@@ -504,6 +562,22 @@ func (h *ExpertHandler) HandleUpdateExpert(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	// Retired areas cannot be newly selected, but an expert may keep the ones they already have
+	if existingExpert != nil {
+		var generalArea int64
+		if updateExpert.GeneralArea != existingExpert.GeneralArea {
+			generalArea = updateExpert.GeneralArea
+		}
+		specializedArea := ""
+		if updateExpert.SpecializedArea != existingExpert.SpecializedArea {
+			specializedArea = updateExpert.SpecializedArea
+		}
+		if errors := retiredAreaErrors(h.store, generalArea, specializedArea); len(errors) > 0 {
+			log.Warn("Expert update validation failed: %v", errors)
+			return utils.RespondWithValidationErrorStrings(w, errors)
+		}
+	}
+
 	// Get user ID from JWT context for audit trail
 	userID, err := auth.GetUserIDFromRequest(r)
 	if err != nil {
@@ -558,6 +632,8 @@ func (h *ExpertHandler) HandleDeleteExpert(w http.ResponseWriter, r *http.Reques
 }
 
 // HandleGetExpertAreas handles GET /api/expert/areas requests
+// Areas are returned as a tree of top-level areas with nested children; ?flat=true returns the flat list
+// Retired areas are omitted unless ?include_retired=true
 func (h *ExpertHandler) HandleGetExpertAreas(w http.ResponseWriter, r *http.Request) error {
 	log := logger.Get()
 	log.Debug("Processing GET /api/expert/areas request")
@@ -569,16 +645,88 @@ func (h *ExpertHandler) HandleGetExpertAreas(w http.ResponseWriter, r *http.Requ
 		return fmt.Errorf("failed to fetch expert areas: %w", err)
 	}
 
-	// Return areas as JSON
+	// Retired areas stay on existing records but are hidden from selection lists by default
+	if r.URL.Query().Get("include_retired") != "true" {
+		active := []*domain.Area{}
+		for _, area := range areas {
+			if area.RetiredAt == nil {
+				active = append(active, area)
+			}
+		}
+		areas = active
+	}
+
 	log.Debug("Returning %d expert areas", len(areas))
 	
+	if r.URL.Query().Get("flat") == "true" {
+		return utils.RespondWithSuccess(w, "", areas)
+	}
+
 	// Use the standardized response format
-	return utils.RespondWithSuccess(w, "", areas)
+	return utils.RespondWithSuccess(w, "", buildAreaTree(areas))
+}
+
+// buildAreaTree nests areas under their parents, preserving the input order among siblings
+// Areas whose parent is missing are treated as top-level so nothing is dropped
+func buildAreaTree(areas []*domain.Area) []*domain.Area {
+	byID := make(map[int64]*domain.Area, len(areas))
+	for _, area := range areas {
+		byID[area.ID] = area
+	}
+
+	roots := []*domain.Area{}
+	for _, area := range areas {
+		if area.ParentID != nil {
+			if parent, ok := byID[*area.ParentID]; ok {
+				parent.Children = append(parent.Children, area)
+				continue
+			}
+		}
+		roots = append(roots, area)
+	}
+
+	return roots
+}
+
+// retiredAreaErrors returns validation errors for any retired general or specialized area in a selection
+// A zero general area or empty specialized area list is skipped
+func retiredAreaErrors(store storage.Storage, generalArea int64, specializedArea string) []string {
+	var errors []string
+
+	if generalArea > 0 {
+		if area, err := store.GetArea(generalArea); err == nil && area.RetiredAt != nil {
+			errors = append(errors, fmt.Sprintf("generalArea '%s' has been retired", area.Path))
+		}
+	}
+
+	var ids []int64
+	for _, value := range strings.Split(specializedArea, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		if areas, err := store.GetSpecializedAreasByIds(ids); err == nil {
+			for _, area := range areas {
+				if area.RetiredAt != nil {
+					errors = append(errors, fmt.Sprintf("specializedArea '%s' has been retired", area.Name))
+				}
+			}
+		}
+	}
+
+	return errors
 }
 
 // AreaRequest represents a request to create or update an area
 type AreaRequest struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	ParentID *int64 `json:"parentId,omitempty"` // Optional parent area when creating
+}
+
+// AreaParentRequest represents a request to move an area within the hierarchy
+type AreaParentRequest struct {
+	ParentID *int64 `json:"parentId"` // New parent area ID, or null to make the area top-level
 }
 
 // HandleCreateArea handles POST /api/expert/areas requests
@@ -604,7 +752,7 @@ func (h *ExpertHandler) HandleCreateArea(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Create area in database
-	id, err := h.store.CreateArea(req.Name)
+	id, err := h.store.CreateArea(req.Name, req.ParentID)
 	if err != nil {
 		log.Error("Failed to create area: %v", err)
 		
@@ -625,6 +773,7 @@ func (h *ExpertHandler) HandleCreateArea(w http.ResponseWriter, r *http.Request)
 	return utils.RespondWithSuccess(w, "Area created successfully", map[string]interface{}{
 		"id": id,
 		"name": req.Name,
+		"parentId": req.ParentID,
 	})
 }
 
@@ -686,6 +835,172 @@ func (h *ExpertHandler) HandleUpdateArea(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// HandleMoveArea handles PUT /api/expert/areas/{id}/parent requests
+func (h *ExpertHandler) HandleMoveArea(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	// Extract and validate area ID from path
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Warn("Invalid area ID provided: %s", idStr)
+		return fmt.Errorf("invalid area ID: %w", err)
+	}
+	
+	var req AreaParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse area move request: %v", err)
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Invalid JSON format", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	
+	if err := h.store.MoveArea(id, req.ParentID); err != nil {
+		log.Error("Failed to move area: %v", err)
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, fmt.Sprintf("No area exists with ID: %d", id))
+		}
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Failed to move area", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	
+	log.Info("Area moved successfully: ID: %d", id)
+	return utils.RespondWithSuccess(w, "Area moved successfully", map[string]interface{}{
+		"id": id,
+		"parentId": req.ParentID,
+	})
+}
+
+// AreaMergeRequest represents a request to merge one area into another
+type AreaMergeRequest struct {
+	TargetID int64 `json:"targetId"` // Area that absorbs the merged area's experts and requests
+}
+
+// AreaRetireRequest represents a request to retire or restore an area
+type AreaRetireRequest struct {
+	Retired bool   `json:"retired"`
+	Reason  string `json:"reason,omitempty"` // Optional reason recorded in the audit log
+}
+
+// HandleMergeArea handles POST /api/expert/areas/{id}/merge requests
+func (h *ExpertHandler) HandleMergeArea(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	// Extract and validate area ID from path
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Warn("Invalid area ID provided: %s", idStr)
+		return fmt.Errorf("invalid area ID: %w", err)
+	}
+	
+	var req AreaMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse area merge request: %v", err)
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Invalid JSON format", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	if req.TargetID <= 0 {
+		return utils.RespondWithValidationErrorStrings(w, []string{"targetId must be a positive number"})
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		log.Warn("Failed to get user ID from request for area merge")
+		return err
+	}
+	
+	result, err := h.store.MergeAreas(id, req.TargetID, userID)
+	if err != nil {
+		log.Error("Failed to merge area %d into %d: %v", id, req.TargetID, err)
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, fmt.Sprintf("No area exists with ID: %d", id))
+		}
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Failed to merge area", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	
+	log.Info("Area %d merged into %d by user %d", id, req.TargetID, userID)
+	return utils.RespondWithSuccess(w, "Area merged successfully", result)
+}
+
+// HandleRetireArea handles PUT /api/expert/areas/{id}/retire requests
+func (h *ExpertHandler) HandleRetireArea(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	// Extract and validate area ID from path
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Warn("Invalid area ID provided: %s", idStr)
+		return fmt.Errorf("invalid area ID: %w", err)
+	}
+	
+	var req AreaRetireRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse area retire request: %v", err)
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Invalid JSON format", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		log.Warn("Failed to get user ID from request for area retirement")
+		return err
+	}
+	
+	if err := h.store.SetAreaRetired(id, req.Retired, req.Reason, userID); err != nil {
+		log.Error("Failed to update retirement of area %d: %v", id, err)
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, fmt.Sprintf("No area exists with ID: %d", id))
+		}
+		return utils.RespondWithCustomError(w, http.StatusBadRequest, "Failed to update area", map[string]interface{}{
+			"details": err.Error(),
+		})
+	}
+	
+	message := "Area restored successfully"
+	if req.Retired {
+		message = "Area retired successfully"
+	}
+	return utils.RespondWithSuccess(w, message, map[string]interface{}{
+		"id":      id,
+		"retired": req.Retired,
+	})
+}
+
+// HandleGetAreaAuditLog handles GET /api/areas/audit requests
+// Optional query parameters: type (general|specialized) and area_id
+func (h *ExpertHandler) HandleGetAreaAuditLog(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	areaType := r.URL.Query().Get("type")
+	if areaType != "" && areaType != "general" && areaType != "specialized" {
+		return utils.RespondWithBadRequest(w, "type must be one of: general, specialized")
+	}
+	
+	var areaID int64
+	if areaIDStr := r.URL.Query().Get("area_id"); areaIDStr != "" {
+		parsed, err := strconv.ParseInt(areaIDStr, 10, 64)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "Invalid area ID")
+		}
+		areaID = parsed
+	}
+	
+	entries, err := h.store.ListAreaAuditLog(areaType, areaID)
+	if err != nil {
+		log.Error("Failed to retrieve area audit log: %v", err)
+		return fmt.Errorf("failed to retrieve area audit log: %w", err)
+	}
+	
+	return utils.RespondWithSuccess(w, "", entries)
+}
+
 // HandleGetExpertEditHistory handles GET /api/experts/{id}/edit-history requests
 func (h *ExpertHandler) HandleGetExpertEditHistory(w http.ResponseWriter, r *http.Request) error {
 	log := logger.Get()
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// HandleDownloadExpertDocuments handles GET /api/experts/documents/archive requests
+// Streams a ZIP with a folder of CVs and approvals per expert matching the GET /api/experts filters,
+// and a manifest.csv. The types parameter selects other document types
+func (h *ExpertHandler) HandleDownloadExpertDocuments(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	queryParams := r.URL.Query()
+
+	filters, err := parseExpertFilters(queryParams)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	docTypes, err := h.documentService.ArchiveTypes(queryParams.Get("types"))
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		return err
+	}
+
+	// ListExperts returns no rows for a zero limit, so the matching experts are counted first
+	count, err := h.store.CountExperts(filters)
+	if err != nil {
+		return fmt.Errorf("failed to count experts: %w", err)
+	}
+	if count == 0 {
+		return utils.RespondWithNotFound(w, "No experts match the filters")
+	}
+	filters["sort_by"] = "name"
+	experts, err := h.store.ListExperts(filters, count, 0)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve experts: %w", err)
+	}
+
+	filename := fmt.Sprintf("expert_documents_%s.zip", time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	summary, err := h.documentService.WriteArchive(w, documents.ExpertFolders(experts), docTypes)
+	if err != nil {
+		// The response has started, so the client is left with an incomplete archive
+		log.Error("Failed to stream document archive of %d experts: %v", len(experts), err)
+		return nil
+	}
+
+	log.Info("Document archive of %d experts sent: %d documents included, %d missing, %d unreadable",
+		summary.Folders, summary.Included, summary.Missing, summary.Unreadable)
+	return nil
+}
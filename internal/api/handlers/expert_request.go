@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
@@ -43,6 +45,97 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 		return fmt.Errorf("failed to parse form: %w", err)
 	}
 
+	// Read the request fields from the form
+	req, specializedAreaStr, err := parseExpertRequestForm(r)
+	if err != nil {
+		return err
+	}
+	
+	// Basic validation - required fields
+	if validationErrors := validateExpertRequestForm(h.store, req, specializedAreaStr); len(validationErrors) > 0 {
+		log.Warn("Expert request validation failed: %v", validationErrors)
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+
+	// Handle CV file - required
+	cvFile, cvFileHeader, err := r.FormFile("cv")
+	if err != nil {
+		log.Warn("Failed to get CV file: %v", err)
+		return fmt.Errorf("CV file is required: %w", err)
+	}
+	defer cvFile.Close()
+
+	// Get user ID from JWT context using the new utility
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		log.Warn("Failed to get user ID from request context")
+		return err
+	}
+
+	// Requests are submitted for review straight away unless saved as a draft
+	status := domain.RequestStatusSubmitted
+	if saveAsDraft, _ := strconv.ParseBool(r.FormValue("draft")); saveAsDraft {
+		status = domain.RequestStatusDraft
+	}
+
+	// Look for the same person among existing experts and open requests; submitting anyway
+	// requires an explicit confirmation that this is not a duplicate
+	duplicateMatches, err := h.store.FindDuplicateExperts(req.Name, req.Email, req.Phone, 0)
+	if err != nil {
+		log.Error("Failed to check for duplicate experts: %v", err)
+		return fmt.Errorf("failed to check for duplicate experts: %w", err)
+	}
+	confirmedNotDuplicate, _ := strconv.ParseBool(r.FormValue("confirmNotDuplicate"))
+	if len(duplicateMatches) > 0 && !confirmedNotDuplicate {
+		log.Info("Expert request for %s held back: %d possible duplicates", req.Name, len(duplicateMatches))
+		return respondWithDuplicates(w, duplicateMatches)
+	}
+	
+	// Create ExpertRequest for storage (without file paths initially)
+	expertRequest := &domain.ExpertRequest{
+		Name:                      req.Name,
+		Designation:               req.Designation,
+		Affiliation:               req.Affiliation,
+		Phone:                     req.Phone,
+		Email:                     req.Email,
+		IsBahraini:                req.IsBahraini,
+		IsAvailable:               req.IsAvailable,
+		Role:                      req.Role,
+		EmploymentType:            req.EmploymentType,
+		GeneralArea:               req.GeneralArea,
+		SpecializedArea:           specializedAreaStr,
+		SuggestedSpecializedAreas: req.SuggestedSpecializedAreas,
+		IsTrained:                 req.IsTrained,
+		IsPublished:               req.IsPublished,
+		ExperienceEntries:         req.ExperienceEntries,
+		EducationEntries:          req.EducationEntries,
+		Status:                    status,
+		CreatedAt:                 time.Now(),
+		CreatedBy:                 userID,
+		DuplicateMatches:          duplicateMatches,
+		DuplicateConfirmed:        len(duplicateMatches) > 0,
+	}
+
+	// Create the request and store its CV as one unit of work: if the upload fails, no request is left behind
+	requestID, cvDoc, err := h.documentService.CreateExpertRequestWithCV(expertRequest, cvFile, cvFileHeader)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		log.Error("Failed to create expert request with CV: %v", err)
+		userErr := errs.ParseSQLiteError(err, "expert request")
+		return utils.RespondWithError(w, userErr)
+	}
+	log.Debug("Expert request %d created successfully with CV document %d", requestID, cvDoc.ID)
+
+	// Return success response
+	return utils.RespondWithCreated(w, requestID, "Expert request created successfully")
+}
+
+// parseExpertRequestForm reads the expert request fields from a parsed multipart form
+// It returns the request and its specialized area IDs as a comma-separated string
+func parseExpertRequestForm(r *http.Request) (*domain.CreateExpertRequest, string, error) {
+	log := logger.Get()
 
 	// Create CreateExpertRequest from form data
 	var req domain.CreateExpertRequest
@@ -73,7 +166,7 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 	if experienceJSON != "" {
 		if err := json.Unmarshal([]byte(experienceJSON), &req.ExperienceEntries); err != nil {
 			log.Warn("Failed to parse experience entries JSON: %v", err)
-			return fmt.Errorf("invalid experience entries data: %w", err)
+			return nil, "", fmt.Errorf("invalid experience entries data: %w", err)
 		}
 	}
 	
@@ -82,7 +175,7 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 	if educationJSON != "" {
 		if err := json.Unmarshal([]byte(educationJSON), &req.EducationEntries); err != nil {
 			log.Warn("Failed to parse education entries JSON: %v", err)
-			return fmt.Errorf("invalid education entries data: %w", err)
+			return nil, "", fmt.Errorf("invalid education entries data: %w", err)
 		}
 	}
 	
@@ -90,7 +183,7 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 	if specAreaIdsJSON := r.FormValue("specializedAreaIds"); specAreaIdsJSON != "" {
 		if err := json.Unmarshal([]byte(specAreaIdsJSON), &req.SpecializedAreaIds); err != nil {
 			log.Warn("Failed to parse specialized area IDs JSON: %v", err)
-			return fmt.Errorf("invalid specialized area IDs data: %w", err)
+			return nil, "", fmt.Errorf("invalid specialized area IDs data: %w", err)
 		}
 	}
 	
@@ -98,41 +191,10 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 	if suggestedAreasJSON := r.FormValue("suggestedSpecializedAreas"); suggestedAreasJSON != "" {
 		if err := json.Unmarshal([]byte(suggestedAreasJSON), &req.SuggestedSpecializedAreas); err != nil {
 			log.Warn("Failed to parse suggested specialized areas JSON: %v", err)
-			return fmt.Errorf("invalid suggested specialized areas data: %w", err)
+			return nil, "", fmt.Errorf("invalid suggested specialized areas data: %w", err)
 		}
 	}
 
-	// Basic validation - required fields
-	validationErrors := []string{}
-	if req.Name == "" {
-		validationErrors = append(validationErrors, "name is required")
-	}
-	if req.Affiliation == "" {
-		validationErrors = append(validationErrors, "affiliation is required")
-	}
-	if req.Role == "" {
-		validationErrors = append(validationErrors, "role is required")
-	}
-	if len(validationErrors) > 0 {
-		log.Warn("Expert request validation failed: %v", validationErrors)
-		return utils.RespondWithValidationErrorStrings(w, validationErrors)
-	}
-
-	// Handle CV file - required
-	cvFile, cvFileHeader, err := r.FormFile("cv")
-	if err != nil {
-		log.Warn("Failed to get CV file: %v", err)
-		return fmt.Errorf("CV file is required: %w", err)
-	}
-	defer cvFile.Close()
-
-	// Get user ID from JWT context using the new utility
-	userID, err := auth.GetUserIDFromRequest(r)
-	if err != nil {
-		log.Warn("Failed to get user ID from request context")
-		return err
-	}
-
 	// Convert specialized area IDs to comma-separated string for storage compatibility
 	specializedAreaStr := ""
 	if len(req.SpecializedAreaIds) > 0 {
@@ -143,52 +205,23 @@ func (h *ExpertRequestHandler) HandleCreateExpertRequest(w http.ResponseWriter,
 		specializedAreaStr = strings.Join(idStrings, ",")
 	}
 
-	// Create ExpertRequest for storage (without file paths initially)
-	expertRequest := &domain.ExpertRequest{
-		Name:                      req.Name,
-		Designation:               req.Designation,
-		Affiliation:               req.Affiliation,
-		Phone:                     req.Phone,
-		Email:                     req.Email,
-		IsBahraini:                req.IsBahraini,
-		IsAvailable:               req.IsAvailable,
-		Role:                      req.Role,
-		EmploymentType:            req.EmploymentType,
-		GeneralArea:               req.GeneralArea,
-		SpecializedArea:           specializedAreaStr,
-		SuggestedSpecializedAreas: req.SuggestedSpecializedAreas,
-		IsTrained:                 req.IsTrained,
-		IsPublished:               req.IsPublished,
-		ExperienceEntries:         req.ExperienceEntries,
-		EducationEntries:          req.EducationEntries,
-		Status:                    "pending",
-		CreatedAt:                 time.Now(),
-		CreatedBy:                 userID,
-	}
+	return &req, specializedAreaStr, nil
+}
 
-	// Transaction-based approach: Create request -> Upload CV -> Update paths
-	
-	// Step 1: Create expert request record and get ID
-	requestID, err := h.store.CreateExpertRequest(expertRequest)
-	if err != nil {
-		log.Error("Failed to create expert request: %v", err)
-		userErr := errs.ParseSQLiteError(err, "expert request")
-		return utils.RespondWithError(w, userErr)
+// validateExpertRequestForm checks the required fields and rejects retired areas
+func validateExpertRequestForm(store storage.Storage, req *domain.CreateExpertRequest, specializedAreaStr string) []string {
+	validationErrors := []string{}
+	if req.Name == "" {
+		validationErrors = append(validationErrors, "name is required")
 	}
-
-	// Step 2: Upload CV file using the actual request ID
-	cvDoc, err := h.documentService.CreateDocumentForExpertRequest(requestID, cvFile, cvFileHeader)
-	if err != nil {
-		log.Error("Failed to upload CV for request %d: %v", requestID, err)
-		// TODO: Consider rolling back the request creation here
-		return fmt.Errorf("failed to upload CV: %w", err)
+	if req.Affiliation == "" {
+		validationErrors = append(validationErrors, "affiliation is required")
 	}
-
-	// Step 3: Document reference already updated by CreateDocumentForExpertRequest
-	log.Debug("Expert request %d created successfully with CV document %d", requestID, cvDoc.ID)
-
-	// Return success response
-	return utils.RespondWithCreated(w, requestID, "Expert request created successfully")
+	if req.Role == "" {
+		validationErrors = append(validationErrors, "role is required")
+	}
+	validationErrors = append(validationErrors, retiredAreaErrors(store, req.GeneralArea, specializedAreaStr)...)
+	return validationErrors
 }
 
 // HandleGetExpertRequests handles GET /api/expert-requests requests
@@ -212,22 +245,100 @@ func (h *ExpertRequestHandler) HandleGetExpertRequests(w http.ResponseWriter, r
 	isAdmin := userRole == "admin" || userRole == "super_user"
 	
 	// Parse query parameters for filtering
-	status := r.URL.Query().Get("status")
-	if status != "" {
-		log.Debug("Filtering expert requests by status: %s", status)
+	queryParams := r.URL.Query()
+	filters := make(map[string]interface{})
+	
+	// Multi-value filters (comma-separated)
+	for _, key := range []string{"status", "general_area", "role"} {
+		if val := queryParams.Get(key); val != "" {
+			filters[key] = val
+		}
+	}
+	
+	// General areas are matched by ID; an unusable value must not silently widen the results
+	if generalArea := queryParams.Get("general_area"); generalArea != "" {
+		for _, areaID := range strings.Split(generalArea, ",") {
+			if areaID = strings.TrimSpace(areaID); areaID == "" {
+				continue
+			}
+			if _, err := strconv.ParseInt(areaID, 10, 64); err != nil {
+				return utils.RespondWithBadRequest(w, "general_area must be one or more comma-separated area IDs")
+			}
+		}
+	}
+	
+	// Filter by submitter (only meaningful for admins; users always see their own requests)
+	if createdBy := queryParams.Get("created_by"); createdBy != "" {
+		submitterID, err := strconv.ParseInt(createdBy, 10, 64)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "created_by must be a user ID")
+		}
+		filters["created_by"] = submitterID
+	}
+	
+	// Filter by nationality (Bahraini/non-Bahraini)
+	if nationality := queryParams.Get("is_bahraini"); nationality != "" {
+		filters["is_bahraini"] = nationality == "true"
+	}
+	if selfNominated := queryParams.Get("is_self_nominated"); selfNominated != "" {
+		filters["is_self_nominated"] = selfNominated == "true"
+	}
+	
+	// Filter by review target breaches for the request's current status
+	if breached := queryParams.Get("sla_breached"); breached != "" {
+		filters["sla_breached"] = breached == "true"
+	}
+	
+	// Submission date range (inclusive)
+	for _, key := range []string{"created_from", "created_to"} {
+		if val := queryParams.Get(key); val != "" {
+			if _, err := time.Parse("2006-01-02", val); err != nil {
+				return utils.RespondWithBadRequest(w, fmt.Sprintf("%s must be a date in YYYY-MM-DD format", key))
+			}
+			filters[key] = val
+		}
+	}
+	
+	// Free-text search on name and affiliation
+	if search := queryParams.Get("search"); search != "" {
+		filters["search"] = search
+	}
+	
+	// Count before adding sorting, which doesn't affect the total
+	countFilters := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		countFilters[k] = v
+	}
+	if !isAdmin {
+		countFilters["created_by"] = userID
 	}
 	
+	// Sorting is validated by the store; unknown fields fall back to newest first
+	if sortBy := queryParams.Get("sort_by"); sortBy != "" {
+		filters["sort_by"] = sortBy
+	}
+	if sortOrder := queryParams.Get("sort_order"); sortOrder != "" {
+		filters["sort_order"] = sortOrder
+	}
+	log.Debug("Filtering expert requests with: %v", filters)
+	
 	// Parse pagination parameters using the new utility
 	pagination := utils.ParsePaginationParams(r, 100) // Default limit of 100 for requests
 	
+	totalCount, err := h.store.CountExpertRequests(countFilters)
+	if err != nil {
+		log.Error("Failed to count expert requests: %v", err)
+		return fmt.Errorf("failed to count expert requests: %w", err)
+	}
+	
 	var requests []*domain.ExpertRequest
 	
 	if isAdmin {
 		// Admin users can see all requests
-		requests, err = h.store.ListExpertRequests(status, pagination.Limit, pagination.Offset)
+		requests, err = h.store.ListExpertRequests(filters, pagination.Limit, pagination.Offset)
 	} else {
 		// Regular users can only see their own requests
-		requests, err = h.store.ListExpertRequestsByUser(userID, status, pagination.Limit, pagination.Offset)
+		requests, err = h.store.ListExpertRequestsByUser(userID, filters, pagination.Limit, pagination.Offset)
 	}
 	
 	if err != nil {
@@ -235,15 +346,27 @@ func (h *ExpertRequestHandler) HandleGetExpertRequests(w http.ResponseWriter, r
 		return fmt.Errorf("failed to retrieve expert requests: %w", err)
 	}
 	
+	// Admin listings flag requests with comments the admin has not read yet
+	// (user listings already carry their unread counts)
+	if isAdmin {
+		for _, request := range requests {
+			unread, err := h.store.CountUnreadExpertRequestComments(request.ID, userID, true)
+			if err != nil {
+				log.Error("Failed to count unread comments for request %d: %v", request.ID, err)
+				return fmt.Errorf("failed to retrieve expert requests: %w", err)
+			}
+			request.UnreadComments = unread
+		}
+	}
+	
 	// Create response with pagination metadata
+	paginated := utils.BuildPaginationResponse(requests, totalCount, pagination)
 	responseData := map[string]interface{}{
-		"requests": requests,
-		"pagination": map[string]interface{}{
-			"limit": pagination.Limit,
-			"offset": pagination.Offset,
-			"count": len(requests),
-		},
+		"requests":   requests,
+		"total":      totalCount,
+		"pagination": paginated["pagination"],
 	}
+	w.Header().Set("X-Total-Count", fmt.Sprintf("%d", totalCount))
 	
 	return utils.RespondWithSuccess(w, "", responseData)
 }
@@ -271,6 +394,26 @@ func (h *ExpertRequestHandler) HandleGetExpertRequest(w http.ResponseWriter, r *
 		return fmt.Errorf("failed to retrieve expert request: %w", err)
 	}
 	
+	// Include the full discussion, internal notes included (this endpoint is admin only)
+	request.Comments, err = h.store.ListExpertRequestComments(id, true)
+	if err != nil {
+		log.Error("Failed to get comments for expert request %d: %v", id, err)
+		return fmt.Errorf("failed to retrieve expert request comments: %w", err)
+	}
+	if userID, err := auth.GetUserIDFromRequest(r); err == nil {
+		if request.UnreadComments, err = h.store.CountUnreadExpertRequestComments(id, userID, true); err != nil {
+			return fmt.Errorf("failed to count unread comments: %w", err)
+		}
+	}
+	
+	// Include approval votes so reviewers can see how far the request is from its quorum
+	request.Approvals, err = h.store.ListExpertRequestApprovals(id)
+	if err != nil {
+		log.Error("Failed to get approvals for expert request %d: %v", id, err)
+		return fmt.Errorf("failed to retrieve expert request approvals: %w", err)
+	}
+	request.RequiredApprovals = h.store.ExpertRequestApprovalPolicy().RequiredApprovals
+	
 	// Return expert request data
 	log.Debug("Successfully retrieved expert request: ID: %d, Name: %s", request.ID, request.Name)
 	return utils.RespondWithSuccess(w, "", request)
@@ -316,15 +459,14 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 	}
 	
 	// Check permissions:
-	// 1. Admins and super users can edit any request
-	// 2. Regular users can edit only their own rejected requests
+	// 1. Admins and super users can update any request (status changes are checked by the workflow)
+	// 2. Regular users can update only their own requests while the workflow lets them edit
 	isAdmin := role == auth.RoleAdmin || role == auth.RoleSuperUser
 	isOwner := existingRequest.CreatedBy == userID
-	isRejected := existingRequest.Status == "rejected"
 	
-	if !isAdmin && !(isOwner && isRejected) {
-		log.Warn("User %d attempted to update request %d without permission. Admin: %v, Owner: %v, Rejected: %v", 
-			userID, id, isAdmin, isOwner, isRejected)
+	if !isAdmin && !(isOwner && domain.ExpertRequestEditable(existingRequest.Status, true, false)) {
+		log.Warn("User %d attempted to update request %d without permission. Admin: %v, Owner: %v, Status: %s", 
+			userID, id, isAdmin, isOwner, existingRequest.Status)
 		return domain.ErrForbidden
 	}
 	
@@ -332,6 +474,15 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 	contentType := r.Header.Get("Content-Type")
 	var updateRequest domain.ExpertRequest
 	
+	// Documents uploaded with the update are discarded unless the update goes through
+	var uploads []*domain.Document
+	updated := false
+	defer func() {
+		if !updated {
+			h.discardRequestUploads(existingRequest, uploads)
+		}
+	}()
+	
 	if strings.HasPrefix(contentType, "multipart/form-data") {
 		// This is a file upload with form data
 		
@@ -374,12 +525,18 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 			defer cvFile.Close()
 			
 			// Create CV document for the request (will be moved during approval)
-			_, err := h.documentService.CreateDocumentForExpertRequest(id, cvFile, cvFileHeader)
+			doc, err := h.documentService.CreateDocumentForExpertRequest(id, cvFile, cvFileHeader)
 			if err != nil {
+				if errors.Is(err, domain.ErrValidation) {
+					return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+				}
 				log.Error("Failed to upload updated CV: %v", err)
 				return fmt.Errorf("failed to upload CV: %w", err)
 			}
-			// Document reference already updated by CreateDocumentForExpertRequest
+			uploads = append(uploads, doc)
+			
+			// Keep the new CV when the edited fields are saved
+			updateRequest.CVDocumentID = &doc.ID
 		}
 		
 		// Process approval document if provided - store it for later use during approval
@@ -391,10 +548,15 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 			// Create approval document for the request
 			doc, err := h.documentService.CreateApprovalDocumentForExpertRequest(id, approvalFile, approvalFileHeader)
 			if err != nil {
+				if errors.Is(err, domain.ErrValidation) {
+					return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+				}
 				log.Error("Failed to upload approval document: %v", err)
 				return fmt.Errorf("failed to upload approval document: %w", err)
 			}
 			
+			uploads = append(uploads, doc)
+			
 			// Update the request with the document ID
 			updateRequest.ApprovalDocumentID = &doc.ID
 		}
@@ -409,51 +571,25 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 	// Ensure ID matches path parameter
 	updateRequest.ID = id
 	
-	// Validate status
-	if updateRequest.Status != "" && 
-	   updateRequest.Status != "approved" && 
-	   updateRequest.Status != "rejected" && 
-	   updateRequest.Status != "pending" {
-		log.Warn("Invalid status provided: %s", updateRequest.Status)
-		return fmt.Errorf("invalid status: %s", updateRequest.Status)
-	}
-	
-	// Perform status update if it's changing and user is admin
-	if isAdmin && updateRequest.Status != "" && updateRequest.Status != existingRequest.Status {
-		log.Info("Admin %d updating request %d status from '%s' to '%s'", userID, id, existingRequest.Status, updateRequest.Status)
+	// Status changes go through the request workflow
+	if updateRequest.Status != "" && updateRequest.Status != existingRequest.Status {
+		log.Info("User %d moving request %d from '%s' to '%s'", userID, id, existingRequest.Status, updateRequest.Status)
 		
-		// If approving the request, require an approval document
-		if updateRequest.Status == "approved" {
-			// Check if the request has an approval document
-			hasApprovalDoc := existingRequest.ApprovalDocumentID != nil || updateRequest.ApprovalDocumentID != nil
-			
-			if !hasApprovalDoc {
-				log.Warn("Approval rejected for request %d: no approval document", id)
-				return utils.RespondWithBadRequest(w, "Approval document is required before approving a request")
-			}
+		// An approval document uploaded with this update counts towards approval
+		request := *existingRequest
+		if updateRequest.ApprovalDocumentID != nil {
+			request.ApprovalDocumentID = updateRequest.ApprovalDocumentID
 		}
 		
-		
-		if updateRequest.Status == "approved" {
-			// Use the new method that handles approval document with proper expert ID
-			expertID, err := h.store.ApproveExpertRequestWithDocument(id, userID, h.documentService)
-			if err != nil {
-				log.Error("Failed to approve expert request %d: %v", id, err)
-				
-				// Use the new error parser for user-friendly errors
-				userErr := errs.ParseSQLiteError(err, "expert request")
-				return utils.RespondWithError(w, userErr)
-			}
-			log.Info("Expert request %d approved - created expert %d", id, expertID)
-		} else {
-			// For rejection, use the old method
-			if err := h.store.UpdateExpertRequestStatus(id, updateRequest.Status, updateRequest.RejectionReason, userID); err != nil {
-				log.Error("Failed to update expert request %d status: %v", id, err)
-				
-				// Use the new error parser for user-friendly errors
-				userErr := errs.ParseSQLiteError(err, "expert request")
-				return utils.RespondWithError(w, userErr)
-			}
+		confirmedNotDuplicate, _ := strconv.ParseBool(r.FormValue("confirmNotDuplicate"))
+		approval, err := h.transitionExpertRequest(&request, updateRequest.Status, updateRequest.RejectionReason, userID, isAdmin, confirmedNotDuplicate)
+		if err != nil {
+			log.Warn("Failed to move expert request %d to '%s': %v", id, updateRequest.Status, err)
+			return respondWithTransitionError(w, err)
+		}
+		updated = true
+		if approval != nil && !approval.Approved {
+			return utils.RespondWithSuccess(w, approvalPendingMessage(approval), approval)
 		}
 	} else {
 		// If not a status update or if user is not admin, update the request fields
@@ -478,20 +614,20 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 			updateRequest.ApprovalDocumentID = existingRequest.ApprovalDocumentID
 		}
 		
-		// Regular users shouldn't be able to change status
-		if !isAdmin {
-			updateRequest.Status = existingRequest.Status
-			updateRequest.ReviewedAt = existingRequest.ReviewedAt
-			updateRequest.ReviewedBy = existingRequest.ReviewedBy
-		}
+		// Status and review fields only change through the workflow
+		updateRequest.Status = existingRequest.Status
+		updateRequest.RejectionReason = existingRequest.RejectionReason
+		updateRequest.ReviewedAt = existingRequest.ReviewedAt
+		updateRequest.ReviewedBy = existingRequest.ReviewedBy
 		
-		if err := h.store.UpdateExpertRequest(&updateRequest); err != nil {
+		if err := h.saveExpertRequestEdit(existingRequest, &updateRequest, userID); err != nil {
 			log.Error("Failed to update expert request %d: %v", id, err)
 			
 			// Use the new error parser for user-friendly errors
 			userErr := errs.ParseSQLiteError(err, "expert request")
 			return utils.RespondWithError(w, userErr)
 		}
+		updated = true
 	}
 	
 	// Return success response
@@ -501,7 +637,8 @@ func (h *ExpertRequestHandler) HandleUpdateExpertRequest(w http.ResponseWriter,
 
 // BatchApprovalRequest represents a request to approve multiple expert requests at once
 type BatchApprovalRequest struct {
-	RequestIDs []int64 `json:"requestIds"` // Array of expert request IDs to approve
+	RequestIDs       []int64 `json:"requestIds"`                 // Array of expert request IDs to approve
+	ApprovalUploadID int64   `json:"approvalUploadId,omitempty"` // Complete resumable upload to use instead of the approval_document file
 }
 
 // HandleBatchApproveExpertRequests handles POST /api/expert-requests/batch-approve requests
@@ -510,14 +647,14 @@ func (h *ExpertRequestHandler) HandleBatchApproveExpertRequests(w http.ResponseW
 	log := logger.Get()
 	log.Debug("Processing POST /api/expert-requests/batch-approve request")
 	
-	// Get user role for authentication - only admins can perform batch approvals
+	// Get user role for authentication - only reviewers (admins and super users) can perform batch approvals
 	role, err := auth.GetUserRoleFromRequest(r)
 	if err != nil {
 		log.Warn("Failed to get user role from request")
 		return err
 	}
 	
-	if role != auth.RoleAdmin {
+	if role != auth.RoleAdmin && role != auth.RoleSuperUser {
 		log.Warn("Non-admin attempted to perform batch approval")
 		return domain.ErrForbidden
 	}
@@ -555,24 +692,50 @@ func (h *ExpertRequestHandler) HandleBatchApproveExpertRequests(w http.ResponseW
 		return fmt.Errorf("at least one request ID is required")
 	}
 	
-	// Process approval document (required)
-	approvalFile, approvalFileHeader, err := r.FormFile("approval_document")
-	if err != nil {
-		log.Warn("Failed to get approval document: %v", err)
-		return fmt.Errorf("approval document is required: %w", err)
+	// Process approval document (required), sent as a file or beforehand as a resumable upload
+	var approvalFile multipart.File
+	var approvalFileHeader *multipart.FileHeader
+	if batchRequest.ApprovalUploadID != 0 {
+		upload, err := h.documentService.OpenUpload(batchRequest.ApprovalUploadID, userID, domain.DocumentTypeApproval)
+		if err != nil {
+			if err == domain.ErrNotFound {
+				return utils.RespondWithNotFound(w, "Upload not found")
+			}
+			if errors.Is(err, domain.ErrValidation) {
+				return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+			}
+			return fmt.Errorf("failed to open approval upload: %w", err)
+		}
+		defer upload.Close()
+		approvalFile, approvalFileHeader = upload.File, upload.Header
+	} else {
+		approvalFile, approvalFileHeader, err = r.FormFile("approval_document")
+		if err != nil {
+			log.Warn("Failed to get approval document: %v", err)
+			return fmt.Errorf("approval document is required: %w", err)
+		}
+		defer approvalFile.Close()
 	}
-	defer approvalFile.Close()
 	
 	// Call the storage method for batch approval with file moving
 	log.Debug("Batch approving %d expert requests with file moving", len(batchRequest.RequestIDs))
 	log.Debug("DEBUG: Request IDs to approve: %v", batchRequest.RequestIDs)
 	log.Debug("DEBUG: Reviewer user ID: %d", userID)
-	approved, expertIDs, errors := h.store.BatchApproveExpertRequestsWithFileMove(batchRequest.RequestIDs, userID, h.documentService)
-	log.Debug("DEBUG: Batch approval completed - approved: %v, expertIDs: %v, errors: %v", approved, expertIDs, errors)
-	if err != nil {
-		log.Error("Failed during batch approval: %v", err)
-		return fmt.Errorf("failed during batch approval: %w", err)
+	results, errors := h.store.BatchApproveExpertRequestsWithFileMove(batchRequest.RequestIDs, userID, h.documentService)
+	
+	// Only requests that met their approval quorum produced experts; the rest await further votes
+	approved := []int64{}
+	expertIDs := []int64{}
+	pending := []*domain.ExpertRequestApprovalResult{}
+	for _, result := range results {
+		if result.Approved {
+			approved = append(approved, result.RequestID)
+			expertIDs = append(expertIDs, result.ExpertID)
+		} else {
+			pending = append(pending, result)
+		}
 	}
+	log.Debug("DEBUG: Batch approval completed - approved: %v, expertIDs: %v, pending: %d, errors: %v", approved, expertIDs, len(pending), errors)
 	
 	// Upload the approval document for the successfully approved experts
 	var approvalDoc *domain.Document
@@ -586,6 +749,11 @@ func (h *ExpertRequestHandler) HandleBatchApproveExpertRequests(w http.ResponseW
 			return fmt.Errorf("failed to upload approval document: %w", err)
 		}
 		log.Debug("Approval document created: %s", approvalDoc.FilePath)
+		if batchRequest.ApprovalUploadID != 0 {
+			if err := h.documentService.DiscardUpload(batchRequest.ApprovalUploadID); err != nil {
+				log.Warn("Failed to discard upload %d after storing it as approval document %d: %v", batchRequest.ApprovalUploadID, approvalDoc.ID, err)
+			}
+		}
 		
 		// Update all approved experts with the approval document path
 		log.Debug("DEBUG: Updating experts with approval document path: %s", approvalDoc.FilePath)
@@ -604,6 +772,11 @@ func (h *ExpertRequestHandler) HandleBatchApproveExpertRequests(w http.ResponseW
 		"approvedIds": approved,
 	}
 	
+	if len(pending) > 0 {
+		responseData["pending"] = pending
+		responseData["pendingCount"] = len(pending)
+	}
+	
 	if len(errors) > 0 {
 		errorMessages := make(map[int64]string)
 		for id, err := range errors {
@@ -655,23 +828,16 @@ func (h *ExpertRequestHandler) HandleEditExpertRequest(w http.ResponseWriter, r
 		return fmt.Errorf("failed to retrieve expert request: %w", err)
 	}
 	
-	// Check access permissions
-	if !isAdmin {
-		// Regular users can only edit their own rejected requests
-		if existingRequest.CreatedBy != userID {
-			log.Warn("User %d attempted to edit request %d not owned by them", userID, requestID)
-			return domain.ErrForbidden
-		}
-		if existingRequest.Status != "rejected" {
-			log.Warn("User %d attempted to edit request %d with status %s (only rejected allowed)", userID, requestID, existingRequest.Status)
-			return fmt.Errorf("only rejected requests can be edited by users")
-		}
-	} else {
-		// Admins can edit any pending request
-		if existingRequest.Status != "pending" {
-			log.Warn("Admin attempted to edit request %d with status %s (only pending allowed)", requestID, existingRequest.Status)
-			return fmt.Errorf("only pending requests can be edited by admins")
-		}
+	// Check access permissions: requesters edit their drafts and returned requests,
+	// admins edit requests awaiting a decision
+	isOwner := existingRequest.CreatedBy == userID
+	if !isOwner && !isAdmin {
+		log.Warn("User %d attempted to edit request %d not owned by them", userID, requestID)
+		return domain.ErrForbidden
+	}
+	if !domain.ExpertRequestEditable(existingRequest.Status, isOwner, isAdmin) {
+		log.Warn("User %d attempted to edit request %d with status %s", userID, requestID, existingRequest.Status)
+		return utils.RespondWithBadRequest(w, fmt.Sprintf("Requests with status '%s' cannot be edited", existingRequest.Status))
 	}
 	
 	// Parse multipart form
@@ -730,31 +896,43 @@ func (h *ExpertRequestHandler) HandleEditExpertRequest(w http.ResponseWriter, r
 		updatedRequest.IsTrained = isTrainedStr == "true"
 	}
 	
-	// Handle CV file upload if provided
+	// Retired areas cannot be newly selected, but a request may keep the ones it already has
+	var changedGeneralArea int64
+	if updatedRequest.GeneralArea != existingRequest.GeneralArea {
+		changedGeneralArea = updatedRequest.GeneralArea
+	}
+	changedSpecializedArea := ""
+	if updatedRequest.SpecializedArea != existingRequest.SpecializedArea {
+		changedSpecializedArea = updatedRequest.SpecializedArea
+	}
+	if validationErrors := retiredAreaErrors(h.store, changedGeneralArea, changedSpecializedArea); len(validationErrors) > 0 {
+		log.Warn("Expert request edit validation failed: %v", validationErrors)
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+	
+	// Handle CV file upload if provided; it is discarded if the edit is not saved
+	var uploads []*domain.Document
 	if cvFile, cvHeader, err := r.FormFile("cv"); err == nil {
 		defer cvFile.Close()
 		
 		// Upload new CV for expert request
-		_, err := h.documentService.CreateDocumentForExpertRequest(requestID, cvFile, cvHeader)
+		doc, err := h.documentService.CreateDocumentForExpertRequest(requestID, cvFile, cvHeader)
 		if err != nil {
+			if errors.Is(err, domain.ErrValidation) {
+				return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+			}
 			log.Error("Failed to upload CV: %v", err)
 			return fmt.Errorf("failed to upload CV: %w", err)
 		}
-		// Document reference already updated by CreateDocumentForExpertRequest
-	}
-	
-	// Reset status to pending if user edited a rejected request
-	if !isAdmin && existingRequest.Status == "rejected" {
-		updatedRequest.Status = "pending"
-		updatedRequest.RejectionReason = ""
-		updatedRequest.ReviewedAt = time.Time{}
-		updatedRequest.ReviewedBy = 0
+		uploads = append(uploads, doc)
+		updatedRequest.CVDocumentID = &doc.ID
 	}
 	
 	// Update the request in storage
-	err = h.store.UpdateExpertRequest(&updatedRequest)
+	err = h.saveExpertRequestEdit(existingRequest, &updatedRequest, userID)
 	if err != nil {
 		log.Error("Failed to update expert request: %v", err)
+		h.discardRequestUploads(existingRequest, uploads)
 		return fmt.Errorf("failed to update expert request: %w", err)
 	}
 	
@@ -762,4 +940,258 @@ func (h *ExpertRequestHandler) HandleEditExpertRequest(w http.ResponseWriter, r
 	return utils.RespondWithSuccess(w, "Expert request updated successfully", nil)
 }
 
-// Use containsString from expert.go
\ No newline at end of file
+// saveExpertRequestEdit stores the edited fields of an expert request. A returned request
+// (needs_changes or rejected) edited by its requester is resubmitted for review, whichever
+// endpoint the edit came through
+func (h *ExpertRequestHandler) saveExpertRequestEdit(existing, updated *domain.ExpertRequest, userID int64) error {
+	if existing.CreatedBy == userID &&
+		(existing.Status == domain.RequestStatusNeedsChanges || existing.Status == domain.RequestStatusRejected) {
+		// The edit and the resubmission are saved together, so neither applies without the other
+		err := h.store.UpdateAndResubmitExpertRequest(updated, existing.Status, "Resubmitted after changes", userID)
+		if err != nil {
+			return fmt.Errorf("failed to resubmit expert request: %w", err)
+		}
+		logger.Get().Info("Expert request %d resubmitted after changes by user %d", existing.ID, userID)
+		return nil
+	}
+	return h.store.UpdateExpertRequest(updated)
+}
+
+// discardRequestUploads removes documents uploaded with an expert request update that did not go
+// through, pointing the request back at the documents it had before
+func (h *ExpertRequestHandler) discardRequestUploads(existing *domain.ExpertRequest, uploads []*domain.Document) {
+	for _, doc := range uploads {
+		previousID := existing.CVDocumentID
+		if doc.DocumentType == domain.DocumentTypeApproval {
+			previousID = existing.ApprovalDocumentID
+		}
+		if err := h.documentService.DiscardRequestDocument(existing.ID, doc, previousID); err != nil {
+			logger.Get().Warn("Failed to discard document %d uploaded for expert request %d: %v", doc.ID, existing.ID, err)
+		}
+	}
+}
+
+// ExpertRequestTransitionRequest represents a request to move an expert request to a new workflow status
+type ExpertRequestTransitionRequest struct {
+	Status              string `json:"status"`                        // Target status, see domain.RequestStatus* constants
+	Note                string `json:"note,omitempty"`                // Reason shown to the requester (required when requesting changes)
+	ConfirmNotDuplicate bool   `json:"confirmNotDuplicate,omitempty"` // Submit a draft despite possible duplicates
+}
+
+// HandleTransitionExpertRequest handles POST /api/expert-requests/{id}/transition requests
+func (h *ExpertRequestHandler) HandleTransitionExpertRequest(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	
+	id, err := utils.ExtractIDFromPath(r, "id", "expert request")
+	if err != nil {
+		return fmt.Errorf("invalid request ID: %w", err)
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		log.Warn("Failed to get user ID from request")
+		return err
+	}
+	
+	role, err := auth.GetUserRoleFromRequest(r)
+	if err != nil {
+		log.Warn("Failed to get user role from request")
+		return err
+	}
+	isAdmin := role == auth.RoleAdmin || role == auth.RoleSuperUser
+	
+	var req ExpertRequestTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	if req.Status == "" {
+		return utils.RespondWithValidationErrorStrings(w, []string{"status is required"})
+	}
+	
+	existingRequest, err := h.store.GetExpertRequest(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to retrieve expert request: %w", err)
+	}
+	
+	if existingRequest.CreatedBy != userID && !isAdmin {
+		log.Warn("User %d attempted to change status of request %d not owned by them", userID, id)
+		return domain.ErrForbidden
+	}
+	
+	approval, err := h.transitionExpertRequest(existingRequest, req.Status, req.Note, userID, isAdmin, req.ConfirmNotDuplicate)
+	if err != nil {
+		log.Warn("Failed to move expert request %d to '%s': %v", id, req.Status, err)
+		return respondWithTransitionError(w, err)
+	}
+	
+	responseData := map[string]interface{}{
+		"id":         id,
+		"fromStatus": existingRequest.Status,
+		"status":     req.Status,
+	}
+	if approval != nil {
+		responseData["approval"] = approval
+		if !approval.Approved {
+			// The vote may have moved the request under review, but it is not approved yet
+			current, err := h.store.GetExpertRequest(id)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve expert request: %w", err)
+			}
+			responseData["status"] = current.Status
+			return utils.RespondWithSuccess(w, approvalPendingMessage(approval), responseData)
+		}
+	}
+	
+	return utils.RespondWithSuccess(w, "Expert request status updated successfully", responseData)
+}
+
+// HandleGetExpertRequestHistory handles GET /api/expert-requests/{id}/history requests
+// Returns the status history together with the transitions currently open to the user
+func (h *ExpertRequestHandler) HandleGetExpertRequestHistory(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "expert request")
+	if err != nil {
+		return fmt.Errorf("invalid request ID: %w", err)
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	role, err := auth.GetUserRoleFromRequest(r)
+	if err != nil {
+		return err
+	}
+	isAdmin := role == auth.RoleAdmin || role == auth.RoleSuperUser
+	
+	request, err := h.store.GetExpertRequest(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to retrieve expert request: %w", err)
+	}
+	
+	isOwner := request.CreatedBy == userID
+	if !isOwner && !isAdmin {
+		return domain.ErrForbidden
+	}
+	
+	history, err := h.store.ListExpertRequestHistory(id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve expert request history: %w", err)
+	}
+	
+	approvals, err := h.store.ListExpertRequestApprovals(id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve expert request approvals: %w", err)
+	}
+	
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"requestId":          id,
+		"status":             request.Status,
+		"allowedTransitions": domain.AllowedExpertRequestTransitions(request.Status, isOwner, isAdmin),
+		"history":            history,
+		"approvals":          approvals,
+		"requiredApprovals":  h.store.ExpertRequestApprovalPolicy().RequiredApprovals,
+	})
+}
+
+// transitionExpertRequest moves a request to a new workflow status after checking the transition
+// is open to the user. Approving records the user's vote and returns its outcome; the expert is
+// only created once the approval quorum is met. Submitting a draft checks it for duplicates as
+// creating a request does, unless confirmNotDuplicate is set
+func (h *ExpertRequestHandler) transitionExpertRequest(request *domain.ExpertRequest, toStatus, note string, userID int64, isAdmin, confirmNotDuplicate bool) (*domain.ExpertRequestApprovalResult, error) {
+	log := logger.Get()
+	
+	if err := domain.ValidateExpertRequestTransition(request.Status, toStatus, request.CreatedBy == userID, isAdmin); err != nil {
+		return nil, err
+	}
+	
+	switch toStatus {
+	case domain.RequestStatusApproved:
+		// The document type registry decides which documents a request needs before approval
+		if err := h.documentService.CheckRequestDocuments(request); err != nil {
+			return nil, err
+		}
+		approval, err := h.store.ApproveExpertRequestWithDocument(request.ID, userID, h.documentService)
+		if err != nil {
+			return nil, err
+		}
+		if approval.Approved {
+			log.Info("Expert request %d approved - created expert %d", request.ID, approval.ExpertID)
+		} else {
+			log.Info("Approval by user %d recorded for expert request %d (%d of %d)", userID, request.ID, approval.Approvals, approval.RequiredApprovals)
+		}
+		return approval, nil
+	case domain.RequestStatusNeedsChanges:
+		if strings.TrimSpace(note) == "" {
+			return nil, fmt.Errorf("%w: a note describing the required changes is needed", domain.ErrValidation)
+		}
+	case domain.RequestStatusSubmitted:
+		if request.Status == domain.RequestStatusDraft {
+			return nil, h.submitDraftExpertRequest(request, note, userID, confirmNotDuplicate)
+		}
+	}
+	
+	return nil, h.store.TransitionExpertRequest(request.ID, request.Status, toStatus, note, userID)
+}
+
+// submitDraftExpertRequest submits a draft after looking for the same person among existing experts
+// and open requests, as the details may have changed since the draft was saved. Possible duplicates
+// hold the submission back unless confirmNotDuplicate is set
+func (h *ExpertRequestHandler) submitDraftExpertRequest(request *domain.ExpertRequest, note string, userID int64, confirmNotDuplicate bool) error {
+	matches, err := h.store.FindDuplicateExperts(request.Name, request.Email, request.Phone, request.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate experts: %w", err)
+	}
+	if len(matches) > 0 && !confirmNotDuplicate {
+		logger.Get().Info("Submission of expert request %d held back: %d possible duplicates", request.ID, len(matches))
+		return &duplicatesFoundError{matches: matches}
+	}
+	
+	if err := h.store.TransitionExpertRequest(request.ID, request.Status, domain.RequestStatusSubmitted, note, userID); err != nil {
+		return err
+	}
+	return h.store.SetExpertRequestDuplicates(request.ID, matches, len(matches) > 0)
+}
+
+// duplicatesFoundError holds back a submission that may duplicate an existing expert or open request
+type duplicatesFoundError struct {
+	matches []*domain.DuplicateMatch
+}
+
+func (e *duplicatesFoundError) Error() string {
+	return fmt.Sprintf("%d possible duplicates found", len(e.matches))
+}
+
+// respondWithDuplicates asks the submitter to confirm a request is not a duplicate of the matches
+func respondWithDuplicates(w http.ResponseWriter, matches []*domain.DuplicateMatch) error {
+	return utils.RespondWithCustomError(w, http.StatusConflict,
+		"Possible duplicates found; resubmit with confirmNotDuplicate=true if this is a different person",
+		map[string]interface{}{"duplicateMatches": matches})
+}
+
+// approvalPendingMessage describes an approval vote that did not yet meet the quorum
+func approvalPendingMessage(approval *domain.ExpertRequestApprovalResult) string {
+	return fmt.Sprintf("Approval recorded (%d of %d); the expert will be created once the remaining reviewers approve",
+		approval.Approvals, approval.RequiredApprovals)
+}
+
+// respondWithTransitionError maps a failed workflow transition to an HTTP response
+func respondWithTransitionError(w http.ResponseWriter, err error) error {
+	var duplicates *duplicatesFoundError
+	switch {
+	case errors.As(err, &duplicates):
+		return respondWithDuplicates(w, duplicates.matches)
+	case err == domain.ErrForbidden, err == domain.ErrNotFound:
+		return err
+	case errors.Is(err, domain.ErrValidation):
+		return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+	default:
+		return utils.RespondWithError(w, errs.ParseSQLiteError(err, "expert request"))
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/logger"
+)
+
+// HandleGetExpertRequestAging handles GET /api/expert-requests/aging requests
+// Reports how long open requests have waited in their current status, by age and by reviewer
+func (h *ExpertRequestHandler) HandleGetExpertRequestAging(w http.ResponseWriter, r *http.Request) error {
+	report, err := h.store.GetExpertRequestAgingReport(time.Now())
+	if err != nil {
+		logger.Get().Error("Failed to build expert request aging report: %v", err)
+		return fmt.Errorf("failed to build aging report: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", report)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Batch transaction modes
+const (
+	batchModeBestEffort   = "best_effort"    // Apply each request on its own
+	batchModeAllOrNothing = "all_or_nothing" // Apply every request or none
+)
+
+// ExpertRequestBatchRequest represents one action applied to several expert requests
+// Requests are listed in requestIds (sharing reason) or in items with their own reasons
+type ExpertRequestBatchRequest struct {
+	Action     string                          `json:"action"`     // reject, withdraw or reassign
+	RequestIDs []int64                         `json:"requestIds"` // Requests using the shared reason
+	Items      []domain.ExpertRequestBatchItem `json:"items"`      // Requests with their own reason
+	Reason     string                          `json:"reason"`     // Shared reason, used for items without one
+	ReviewerID int64                           `json:"reviewerId"` // New reviewer (reassign only)
+	Mode       string                          `json:"mode"`       // best_effort (default) or all_or_nothing
+}
+
+// expertRequestBatchResult reports the outcome of a batch action for one request
+type expertRequestBatchResult struct {
+	Success    bool   `json:"success"`
+	Status     string `json:"status,omitempty"`     // Status after the action
+	ReviewerID int64  `json:"reviewerId,omitempty"` // Reviewer after a reassignment
+	Error      string `json:"error,omitempty"`
+}
+
+// HandleBatchUpdateExpertRequests handles POST /api/expert-requests/batch requests
+// Rejecting and reassigning are open to reviewers; requesters may withdraw their own requests
+func (h *ExpertRequestHandler) HandleBatchUpdateExpertRequests(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	role, err := auth.GetUserRoleFromRequest(r)
+	if err != nil {
+		return err
+	}
+	isAdmin := role == auth.RoleAdmin || role == auth.RoleSuperUser
+
+	var req ExpertRequestBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+
+	// Combine both ways of listing requests, applying the shared reason where none is given
+	items := make([]domain.ExpertRequestBatchItem, 0, len(req.RequestIDs)+len(req.Items))
+	for _, id := range req.RequestIDs {
+		items = append(items, domain.ExpertRequestBatchItem{ID: id})
+	}
+	items = append(items, req.Items...)
+	for i := range items {
+		items[i].Reason = strings.TrimSpace(items[i].Reason)
+		if items[i].Reason == "" {
+			items[i].Reason = strings.TrimSpace(req.Reason)
+		}
+	}
+
+	if req.Mode == "" {
+		req.Mode = batchModeBestEffort
+	}
+
+	validationErrors := []string{}
+	switch req.Action {
+	case domain.ExpertRequestBatchReject, domain.ExpertRequestBatchWithdraw, domain.ExpertRequestBatchReassign:
+	case "":
+		validationErrors = append(validationErrors, "action is required")
+	default:
+		validationErrors = append(validationErrors, fmt.Sprintf("unknown action '%s': must be reject, withdraw or reassign", req.Action))
+	}
+	if req.Mode != batchModeBestEffort && req.Mode != batchModeAllOrNothing {
+		validationErrors = append(validationErrors, "mode must be best_effort or all_or_nothing")
+	}
+	if len(items) == 0 {
+		validationErrors = append(validationErrors, "at least one request ID is required")
+	}
+	seen := make(map[int64]bool, len(items))
+	for _, item := range items {
+		if seen[item.ID] {
+			validationErrors = append(validationErrors, fmt.Sprintf("request %d is listed more than once", item.ID))
+		}
+		seen[item.ID] = true
+		if req.Action == domain.ExpertRequestBatchReject && item.Reason == "" {
+			validationErrors = append(validationErrors, fmt.Sprintf("a rejection reason is required for request %d", item.ID))
+		}
+	}
+	if req.Action == domain.ExpertRequestBatchReassign {
+		if req.ReviewerID == 0 {
+			validationErrors = append(validationErrors, "reviewerId is required to reassign requests")
+		} else {
+			reviewer, err := h.store.GetUser(req.ReviewerID)
+			if err != nil && err != domain.ErrNotFound {
+				return fmt.Errorf("failed to retrieve reviewer: %w", err)
+			}
+			if reviewer == nil || !reviewer.IsActive || (reviewer.Role != auth.RoleAdmin && reviewer.Role != auth.RoleSuperUser) {
+				validationErrors = append(validationErrors, "reviewerId must be an active admin or super user")
+			}
+		}
+	}
+	if len(validationErrors) > 0 {
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+
+	// Reviewer-only actions are refused up front rather than failing request by request
+	if req.Action != domain.ExpertRequestBatchWithdraw && !isAdmin {
+		log.Warn("User %d attempted batch %s without reviewer access", userID, req.Action)
+		return domain.ErrForbidden
+	}
+
+	batch := &domain.ExpertRequestBatch{
+		Action:       req.Action,
+		Items:        items,
+		ReviewerID:   req.ReviewerID,
+		AllOrNothing: req.Mode == batchModeAllOrNothing,
+	}
+	succeeded, failures := h.store.BatchUpdateExpertRequests(batch, userID, isAdmin)
+
+	resultStatus := map[string]string{
+		domain.ExpertRequestBatchReject:   domain.RequestStatusRejected,
+		domain.ExpertRequestBatchWithdraw: domain.RequestStatusWithdrawn,
+	}[req.Action]
+	results := make(map[int64]expertRequestBatchResult, len(items))
+	for _, id := range succeeded {
+		result := expertRequestBatchResult{Success: true, Status: resultStatus}
+		if req.Action == domain.ExpertRequestBatchReassign {
+			result.ReviewerID = req.ReviewerID
+		}
+		results[id] = result
+	}
+	errorMessages := make(map[int64]string, len(failures))
+	for id, err := range failures {
+		errorMessages[id] = batchErrorMessage(err)
+		results[id] = expertRequestBatchResult{Error: errorMessages[id]}
+	}
+
+	responseData := map[string]interface{}{
+		"action":         req.Action,
+		"mode":           req.Mode,
+		"totalRequests":  len(items),
+		"succeededCount": len(succeeded),
+		"succeededIds":   succeeded,
+		"results":        results,
+	}
+	if len(failures) > 0 {
+		responseData["errors"] = errorMessages
+		responseData["errorCount"] = len(failures)
+	}
+
+	return utils.RespondWithSuccess(w, fmt.Sprintf("Applied %s to %d of %d requests", req.Action, len(succeeded), len(items)), responseData)
+}
+
+// batchErrorMessage describes why a batch action failed for one request
+func batchErrorMessage(err error) string {
+	switch {
+	case err == domain.ErrNotFound:
+		return "expert request not found"
+	case err == domain.ErrForbidden:
+		return "you are not allowed to perform this action on this request"
+	case errors.Is(err, domain.ErrValidation):
+		return strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": ")
+	default:
+		return err.Error()
+	}
+}
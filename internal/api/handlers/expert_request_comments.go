@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// ExpertRequestCommentRequest represents a new comment on an expert request
+type ExpertRequestCommentRequest struct {
+	Body     string `json:"body"`               // Comment text
+	ParentID *int64 `json:"parentId,omitempty"` // Comment being replied to
+	Internal bool   `json:"internal"`           // Admin-only note (ignored for non-admins)
+}
+
+// commentAccess resolves the request and whether the user may see it as owner or admin
+func (h *ExpertRequestHandler) commentAccess(r *http.Request) (request *domain.ExpertRequest, userID int64, isAdmin bool, err error) {
+	id, err := utils.ExtractIDFromPath(r, "id", "expert request")
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("invalid request ID: %w", err)
+	}
+
+	userID, err = auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	role, err := auth.GetUserRoleFromRequest(r)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	isAdmin = role == auth.RoleAdmin || role == auth.RoleSuperUser
+
+	request, err = h.store.GetExpertRequest(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, 0, false, domain.ErrNotFound
+		}
+		return nil, 0, false, fmt.Errorf("failed to retrieve expert request: %w", err)
+	}
+
+	if request.CreatedBy != userID && !isAdmin {
+		return nil, 0, false, domain.ErrForbidden
+	}
+
+	return request, userID, isAdmin, nil
+}
+
+// HandleListExpertRequestComments handles GET /api/expert-requests/{id}/comments requests
+// Internal notes are only returned to admins; viewing the comments marks them as read
+func (h *ExpertRequestHandler) HandleListExpertRequestComments(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	request, userID, isAdmin, err := h.commentAccess(r)
+	if err != nil {
+		return err
+	}
+
+	comments, err := h.store.ListExpertRequestComments(request.ID, isAdmin)
+	if err != nil {
+		log.Error("Failed to list comments for request %d: %v", request.ID, err)
+		return fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+
+	if err := h.store.MarkExpertRequestCommentsRead(request.ID, userID); err != nil {
+		log.Warn("Failed to mark comments on request %d as read for user %d: %v", request.ID, userID, err)
+	}
+
+	return utils.RespondWithSuccess(w, "", comments)
+}
+
+// HandleCreateExpertRequestComment handles POST /api/expert-requests/{id}/comments requests
+func (h *ExpertRequestHandler) HandleCreateExpertRequestComment(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	request, userID, isAdmin, err := h.commentAccess(r)
+	if err != nil {
+		return err
+	}
+
+	var req ExpertRequestCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		return utils.RespondWithValidationErrorStrings(w, []string{"body is required"})
+	}
+	if req.Internal && !isAdmin {
+		return utils.RespondWithBadRequest(w, "Only admins can post internal notes")
+	}
+
+	comment := &domain.ExpertRequestComment{
+		RequestID:  request.ID,
+		ParentID:   req.ParentID,
+		AuthorID:   userID,
+		Body:       req.Body,
+		IsInternal: req.Internal,
+	}
+
+	// Requesters cannot see internal notes, so they cannot reply to them either
+	if !isAdmin && req.ParentID != nil {
+		visible, err := h.store.ListExpertRequestComments(request.ID, false)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve comments: %w", err)
+		}
+		if !containsComment(visible, *req.ParentID) {
+			return utils.RespondWithBadRequest(w, fmt.Sprintf("Comment %d is not on this request", *req.ParentID))
+		}
+	}
+
+	id, err := h.store.CreateExpertRequestComment(comment)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		log.Error("Failed to create comment on request %d: %v", request.ID, err)
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	log.Info("User %d commented on expert request %d (internal: %v)", userID, request.ID, comment.IsInternal)
+	return utils.RespondWithCreated(w, id, "Comment added successfully")
+}
+
+// containsComment reports whether a comment ID appears anywhere in the given threads
+func containsComment(threads []*domain.ExpertRequestComment, id int64) bool {
+	for _, comment := range threads {
+		if comment.ID == id || containsComment(comment.Replies, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/email"
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+)
+
+// NominationHandler handles nomination invites and public self-nominations
+type NominationHandler struct {
+	store           storage.Storage
+	documentService *documents.Service
+	limiter         *utils.RateLimiter
+	publicBaseURL   string
+}
+
+// NewNominationHandler creates a new nomination handler
+func NewNominationHandler(store storage.Storage, documentService *documents.Service, limiter *utils.RateLimiter, publicBaseURL string) *NominationHandler {
+	return &NominationHandler{
+		store:           store,
+		documentService: documentService,
+		limiter:         limiter,
+		publicBaseURL:   strings.TrimRight(publicBaseURL, "/"),
+	}
+}
+
+// CreateNominationInviteRequest represents a new nomination invite
+type CreateNominationInviteRequest struct {
+	Email         string `json:"email"`         // Send the invite link to this address (optional)
+	Note          string `json:"note"`          // Admin note
+	MaxUses       int    `json:"maxUses"`       // Nominations the invite accepts (default 1)
+	ExpiresInDays int    `json:"expiresInDays"` // Days until the invite expires (default 14)
+}
+
+// nominationLink returns the public link for an invite token
+func (h *NominationHandler) nominationLink(token string) string {
+	return h.publicBaseURL + "/nominate/" + token
+}
+
+// HandleCreateNominationInvite handles POST /api/nomination-invites requests
+// The token is only returned in this response; if an email is given the invite link is also sent there
+func (h *NominationHandler) HandleCreateNominationInvite(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	var req CreateNominationInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request body")
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	if req.MaxUses == 0 {
+		req.MaxUses = 1
+	}
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = 14
+	}
+
+	var validationErrors []string
+	if req.MaxUses < 1 {
+		validationErrors = append(validationErrors, "maxUses must be at least 1")
+	}
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > 365 {
+		validationErrors = append(validationErrors, "expiresInDays must be between 1 and 365")
+	}
+	if req.Email != "" {
+		if err := email.ValidateAddress(req.Email); err != nil {
+			validationErrors = append(validationErrors, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+	}
+	if len(validationErrors) > 0 {
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return fmt.Errorf("failed to generate invite token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	invite := &domain.NominationInvite{
+		Email:     req.Email,
+		Note:      req.Note,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: time.Now().AddDate(0, 0, req.ExpiresInDays),
+		CreatedBy: userID,
+	}
+	if _, err := h.store.CreateNominationInvite(invite, token); err != nil {
+		log.Error("Failed to create nomination invite: %v", err)
+		return fmt.Errorf("failed to create nomination invite: %w", err)
+	}
+	invite.Token = token
+	link := h.nominationLink(token)
+
+	if invite.Email != "" {
+		body := fmt.Sprintf("You have been invited to nominate yourself to the expert database.\n\n"+
+			"Submit your details and CV here:\n%s\n\nThis link expires on %s.",
+			link, invite.ExpiresAt.Format("2 January 2006"))
+		if err := email.Enqueue(h.store, invite.Email, "Invitation to join the expert database", body); err != nil {
+			log.Warn("Failed to queue invite email for invite %d: %v", invite.ID, err)
+		}
+	}
+
+	log.Info("Nomination invite %d created by user %d", invite.ID, userID)
+	return utils.RespondWithSuccess(w, "Nomination invite created", map[string]interface{}{
+		"invite": invite,
+		"link":   link,
+	})
+}
+
+// HandleListNominationInvites handles GET /api/nomination-invites requests
+func (h *NominationHandler) HandleListNominationInvites(w http.ResponseWriter, r *http.Request) error {
+	invites, err := h.store.ListNominationInvites()
+	if err != nil {
+		logger.Get().Error("Failed to list nomination invites: %v", err)
+		return fmt.Errorf("failed to retrieve nomination invites: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", invites)
+}
+
+// HandleRevokeNominationInvite handles DELETE /api/nomination-invites/{id} requests
+func (h *NominationHandler) HandleRevokeNominationInvite(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPath(r, "id", "nomination invite")
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	if err := h.store.RevokeNominationInvite(id); err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to revoke nomination invite: %w", err)
+	}
+
+	logger.Get().Info("Nomination invite %d revoked", id)
+	return utils.RespondWithSuccess(w, "Nomination invite revoked", nil)
+}
+
+// usableInvite resolves the invite in the request path, writing an error response when it can't be used
+// Returns nil when a response has already been written
+func (h *NominationHandler) usableInvite(w http.ResponseWriter, r *http.Request) (*domain.NominationInvite, error) {
+	if !h.limiter.Allow(utils.ClientIP(r)) {
+		logger.Get().Warn("Nomination rate limit exceeded for %s", utils.ClientIP(r))
+		return nil, utils.RespondWithCustomError(w, http.StatusTooManyRequests, "Too many requests, please try again later", nil)
+	}
+
+	invite, err := h.store.GetNominationInviteByToken(r.PathValue("token"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, utils.RespondWithNotFound(w, "Invitation not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve invitation: %w", err)
+	}
+
+	if !invite.Usable(time.Now()) {
+		return nil, utils.RespondWithCustomError(w, http.StatusGone, "This invitation has expired or has already been used", nil)
+	}
+
+	return invite, nil
+}
+
+// HandleGetNomination handles GET /api/nominations/{token} requests
+// Lets the nomination form check an invite before the expert fills it in
+func (h *NominationHandler) HandleGetNomination(w http.ResponseWriter, r *http.Request) error {
+	invite, err := h.usableInvite(w, r)
+	if invite == nil {
+		return err
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"email":     invite.Email,
+		"expiresAt": invite.ExpiresAt,
+	})
+}
+
+// HandleSubmitNomination handles POST /api/nominations/{token} requests
+// The nomination is stored as a submitted, self-nominated expert request with its CV,
+// and the nominee is sent a confirmation email
+func (h *NominationHandler) HandleSubmitNomination(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	invite, err := h.usableInvite(w, r)
+	if invite == nil {
+		return err
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		log.Warn("Failed to parse nomination form: %v", err)
+		return utils.RespondWithBadRequest(w, "Invalid form data")
+	}
+
+	req, specializedAreaStr, err := parseExpertRequestForm(r)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	// The nominee's address receives the confirmation email, so it must be deliverable
+	req.Email = strings.TrimSpace(req.Email)
+	validationErrors := validateExpertRequestForm(h.store, req, specializedAreaStr)
+	if err := email.ValidateAddress(req.Email); err != nil {
+		validationErrors = append(validationErrors, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+	}
+	if len(validationErrors) > 0 {
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+
+	cvFile, cvFileHeader, err := r.FormFile("cv")
+	if err != nil {
+		return utils.RespondWithValidationErrorStrings(w, []string{"cv file is required"})
+	}
+	defer cvFile.Close()
+
+	// Possible duplicates are kept for reviewers but not revealed to the public
+	duplicateMatches, err := h.store.FindDuplicateExperts(req.Name, req.Email, req.Phone, 0)
+	if err != nil {
+		log.Error("Failed to check nomination for duplicate experts: %v", err)
+		return fmt.Errorf("failed to check for duplicate experts: %w", err)
+	}
+
+	inviteID := invite.ID
+	expertRequest := &domain.ExpertRequest{
+		Name:                      req.Name,
+		Designation:               req.Designation,
+		Affiliation:               req.Affiliation,
+		Phone:                     req.Phone,
+		Email:                     req.Email,
+		IsBahraini:                req.IsBahraini,
+		IsAvailable:               req.IsAvailable,
+		Role:                      req.Role,
+		EmploymentType:            req.EmploymentType,
+		GeneralArea:               req.GeneralArea,
+		SpecializedArea:           specializedAreaStr,
+		SuggestedSpecializedAreas: req.SuggestedSpecializedAreas,
+		IsTrained:                 req.IsTrained,
+		IsPublished:               req.IsPublished,
+		ExperienceEntries:         req.ExperienceEntries,
+		EducationEntries:          req.EducationEntries,
+		Status:                    domain.RequestStatusSubmitted,
+		CreatedAt:                 time.Now(),
+		DuplicateMatches:          duplicateMatches,
+		IsSelfNominated:           true,
+		NominationInviteID:        &inviteID,
+	}
+
+	requestID, _, err := h.documentService.CreateExpertRequestWithCV(expertRequest, cvFile, cvFileHeader)
+	if err != nil {
+		// Rejected uploads, or an invite used up while the form was being submitted
+		if errors.Is(err, domain.ErrValidation) {
+			return utils.RespondWithBadRequest(w, strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+		}
+		log.Error("Failed to create self-nomination: %v", err)
+		return fmt.Errorf("failed to submit nomination: %w", err)
+	}
+
+	body := fmt.Sprintf("Dear %s,\n\nThank you for your nomination to the expert database. "+
+		"We have received your details and CV (reference %d) and will be in touch once they have been reviewed.",
+		req.Name, requestID)
+	if err := email.Enqueue(h.store, req.Email, "We received your nomination", body); err != nil {
+		log.Warn("Failed to queue confirmation email for nomination %d: %v", requestID, err)
+	}
+
+	log.Info("Self-nomination %d submitted with invite %d", requestID, invite.ID)
+	return utils.RespondWithCreated(w, requestID, "Nomination submitted successfully")
+}
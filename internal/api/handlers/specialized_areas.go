@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	
 	"expertdb/internal/api/utils"
+	"expertdb/internal/auth"
 	"expertdb/internal/domain"
 	"expertdb/internal/storage"
 )
@@ -25,6 +28,32 @@ func (h *SpecializedAreasHandler) HandleListSpecializedAreas(w http.ResponseWrit
 		return fmt.Errorf("failed to retrieve specialized areas: %w", err)
 	}
 	
+	// Retired specialized areas are hidden from selection lists unless explicitly requested
+	if r.URL.Query().Get("include_retired") != "true" {
+		active := []*domain.SpecializedArea{}
+		for _, area := range areas {
+			if area.RetiredAt == nil {
+				active = append(active, area)
+			}
+		}
+		areas = active
+	}
+	
+	// Optional: restrict to specialized areas mapped to a general area
+	if generalArea := r.URL.Query().Get("general_area"); generalArea != "" {
+		generalAreaID, err := strconv.ParseInt(generalArea, 10, 64)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "Invalid general area ID")
+		}
+		filtered := []*domain.SpecializedArea{}
+		for _, area := range areas {
+			if area.GeneralAreaID != nil && *area.GeneralAreaID == generalAreaID {
+				filtered = append(filtered, area)
+			}
+		}
+		areas = filtered
+	}
+	
 	// Optional: implement search filtering
 	search := r.URL.Query().Get("search")
 	if search != "" {
@@ -39,4 +68,188 @@ func (h *SpecializedAreasHandler) HandleListSpecializedAreas(w http.ResponseWrit
 	}
 	
 	return utils.RespondWithSuccess(w, "", areas)
-}
\ No newline at end of file
+}
+
+// HandleUpdateSpecializedAreaGeneralArea handles PUT /api/specialized-areas/{id}/general-area requests
+func (h *SpecializedAreasHandler) HandleUpdateSpecializedAreaGeneralArea(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid specialized area ID")
+	}
+	
+	var req struct {
+		GeneralAreaID *int64 `json:"generalAreaId"` // null clears the mapping
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	
+	if err := h.store.UpdateSpecializedAreaGeneralArea(id, req.GeneralAreaID); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Specialized area not found")
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	
+	return utils.RespondWithSuccess(w, "Specialized area updated successfully", map[string]interface{}{
+		"id":            id,
+		"generalAreaId": req.GeneralAreaID,
+	})
+}
+
+// HandleMergeSpecializedArea handles POST /api/specialized-areas/{id}/merge requests
+func (h *SpecializedAreasHandler) HandleMergeSpecializedArea(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid specialized area ID")
+	}
+	
+	var req AreaMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	if req.TargetID <= 0 {
+		return utils.RespondWithValidationErrorStrings(w, []string{"targetId must be a positive number"})
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	result, err := h.store.MergeSpecializedAreas(id, req.TargetID, userID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Specialized area not found")
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	
+	return utils.RespondWithSuccess(w, "Specialized area merged successfully", result)
+}
+
+// HandleRetireSpecializedArea handles PUT /api/specialized-areas/{id}/retire requests
+func (h *SpecializedAreasHandler) HandleRetireSpecializedArea(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid specialized area ID")
+	}
+	
+	var req AreaRetireRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	if err := h.store.SetSpecializedAreaRetired(id, req.Retired, req.Reason, userID); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Specialized area not found")
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	
+	message := "Specialized area restored successfully"
+	if req.Retired {
+		message = "Specialized area retired successfully"
+	}
+	return utils.RespondWithSuccess(w, message, map[string]interface{}{
+		"id":      id,
+		"retired": req.Retired,
+	})
+}
+
+// SuggestionResolutionRequest identifies a suggested specialized area and how to resolve it
+type SuggestionResolutionRequest struct {
+	Name              string `json:"name"`                        // Suggested name as listed in the queue
+	GeneralAreaID     *int64 `json:"generalAreaId,omitempty"`     // General area for the new area (accept only)
+	SpecializedAreaID int64  `json:"specializedAreaId,omitempty"` // Existing area to map to (map only)
+}
+
+// HandleListSuggestions handles GET /api/specialized-areas/suggestions requests
+func (h *SpecializedAreasHandler) HandleListSuggestions(w http.ResponseWriter, r *http.Request) error {
+	suggestions, err := h.store.ListSpecializedAreaSuggestions()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve specialized area suggestions: %w", err)
+	}
+	
+	return utils.RespondWithSuccess(w, "", suggestions)
+}
+
+// HandleListSuggestionResolutions handles GET /api/specialized-areas/suggestions/resolved requests
+func (h *SpecializedAreasHandler) HandleListSuggestionResolutions(w http.ResponseWriter, r *http.Request) error {
+	resolutions, err := h.store.ListSuggestionResolutions()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve suggestion resolutions: %w", err)
+	}
+	
+	return utils.RespondWithSuccess(w, "", resolutions)
+}
+
+// HandleAcceptSuggestion handles POST /api/specialized-areas/suggestions/accept requests
+func (h *SpecializedAreasHandler) HandleAcceptSuggestion(w http.ResponseWriter, r *http.Request) error {
+	var req SuggestionResolutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return utils.RespondWithValidationErrorStrings(w, []string{"name is required"})
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	resolution, err := h.store.AcceptSpecializedAreaSuggestion(req.Name, req.GeneralAreaID, userID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "No pending suggestion with that name")
+		}
+		if strings.Contains(err.Error(), "already exists") {
+			return utils.RespondWithCustomError(w, http.StatusConflict, err.Error(), map[string]interface{}{
+				"suggestion": "Map the suggestion to the existing specialized area instead",
+			})
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	
+	return utils.RespondWithSuccess(w, "Suggestion accepted successfully", resolution)
+}
+
+// HandleMapSuggestion handles POST /api/specialized-areas/suggestions/map requests
+func (h *SpecializedAreasHandler) HandleMapSuggestion(w http.ResponseWriter, r *http.Request) error {
+	var req SuggestionResolutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+	
+	var validationErrors []string
+	if strings.TrimSpace(req.Name) == "" {
+		validationErrors = append(validationErrors, "name is required")
+	}
+	if req.SpecializedAreaID <= 0 {
+		validationErrors = append(validationErrors, "specializedAreaId must be a positive number")
+	}
+	if len(validationErrors) > 0 {
+		return utils.RespondWithValidationErrorStrings(w, validationErrors)
+	}
+	
+	userID, err := auth.GetUserIDFromRequest(r)
+	if err != nil {
+		return err
+	}
+	
+	resolution, err := h.store.MapSpecializedAreaSuggestion(req.Name, req.SpecializedAreaID, userID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "No pending suggestion with that name")
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+	
+	return utils.RespondWithSuccess(w, "Suggestion mapped successfully", resolution)
+}
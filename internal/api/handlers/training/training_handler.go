@@ -0,0 +1,392 @@
+// Package training provides handlers for expert training records
+package training
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/api/utils"
+	"expertdb/internal/documents"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+)
+
+// dateLayout is the accepted format for training dates in requests
+const dateLayout = "2006-01-02"
+
+// Handler manages training-related HTTP endpoints
+type Handler struct {
+	store           storage.Storage
+	documentService *documents.Service
+}
+
+// NewHandler creates a new training handler
+func NewHandler(store storage.Storage, documentService *documents.Service) *Handler {
+	return &Handler{
+		store:           store,
+		documentService: documentService,
+	}
+}
+
+// programRequest is the payload for creating or updating a training program
+type programRequest struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	ValidityMonths *int   `json:"validityMonths"` // Months a completion stays valid, 0 for never expiring
+	IsActive       *bool  `json:"isActive"`
+}
+
+// sessionRequest is the payload for creating a training session
+type sessionRequest struct {
+	SessionDate string `json:"sessionDate"` // YYYY-MM-DD
+	Location    string `json:"location"`
+	Trainer     string `json:"trainer"`
+	Notes       string `json:"notes"`
+}
+
+// attendanceRequest is the payload for recording attendance
+type attendanceRequest struct {
+	ExpertID    int64  `json:"expertId"`
+	Status      string `json:"status"`      // registered, attended, absent
+	CompletedAt string `json:"completedAt"` // YYYY-MM-DD, defaults to the session date
+	ExpiresAt   string `json:"expiresAt"`   // YYYY-MM-DD, defaults to completion plus program validity
+}
+
+// HandleListPrograms handles GET /api/training/programs requests
+func (h *Handler) HandleListPrograms(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	log.Debug("Processing GET /api/training/programs request")
+
+	programs, err := h.store.ListTrainingPrograms()
+	if err != nil {
+		log.Error("Failed to list training programs: %v", err)
+		return fmt.Errorf("failed to list training programs: %w", err)
+	}
+
+	if programs == nil {
+		programs = []*domain.TrainingProgram{}
+	}
+
+	return utils.RespondWithSuccess(w, "", programs)
+}
+
+// HandleCreateProgram handles POST /api/training/programs requests
+func (h *Handler) HandleCreateProgram(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+	log.Debug("Processing POST /api/training/programs request")
+
+	var req programRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse training program request: %v", err)
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+
+	program := &domain.TrainingProgram{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if req.ValidityMonths != nil {
+		program.ValidityMonths = *req.ValidityMonths
+	}
+
+	id, err := h.store.CreateTrainingProgram(program)
+	if err != nil {
+		log.Warn("Failed to create training program: %v", err)
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	log.Info("Training program created: ID: %d, Name: %s", id, program.Name)
+	return utils.RespondWithCreated(w, id, "Training program created successfully")
+}
+
+// HandleUpdateProgram handles PUT /api/training/programs/{id} requests
+func (h *Handler) HandleUpdateProgram(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid training program ID")
+	}
+	log.Debug("Processing PUT /api/training/programs/%d request", id)
+
+	current, err := h.store.GetTrainingProgram(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Training program not found")
+		}
+		return fmt.Errorf("failed to get training program: %w", err)
+	}
+
+	var req programRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse training program update: %v", err)
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+
+	program := &domain.TrainingProgram{
+		ID:             id,
+		Name:           strings.TrimSpace(req.Name),
+		Description:    req.Description,
+		ValidityMonths: current.ValidityMonths,
+		IsActive:       current.IsActive,
+	}
+	if program.Description == "" {
+		program.Description = current.Description
+	}
+	if req.ValidityMonths != nil {
+		program.ValidityMonths = *req.ValidityMonths
+	}
+	if req.IsActive != nil {
+		program.IsActive = *req.IsActive
+	}
+
+	if err := h.store.UpdateTrainingProgram(program); err != nil {
+		log.Warn("Failed to update training program %d: %v", id, err)
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	log.Info("Training program updated: ID: %d", id)
+	return utils.RespondWithSuccess(w, "Training program updated successfully", map[string]interface{}{
+		"id": id,
+	})
+}
+
+// HandleListSessions handles GET /api/training/programs/{id}/sessions requests
+func (h *Handler) HandleListSessions(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	programID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid training program ID")
+	}
+	log.Debug("Processing GET /api/training/programs/%d/sessions request", programID)
+
+	if _, err := h.store.GetTrainingProgram(programID); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Training program not found")
+		}
+		return fmt.Errorf("failed to get training program: %w", err)
+	}
+
+	sessions, err := h.store.ListTrainingSessions(programID)
+	if err != nil {
+		log.Error("Failed to list training sessions: %v", err)
+		return fmt.Errorf("failed to list training sessions: %w", err)
+	}
+
+	if sessions == nil {
+		sessions = []*domain.TrainingSession{}
+	}
+
+	return utils.RespondWithSuccess(w, "", sessions)
+}
+
+// HandleCreateSession handles POST /api/training/programs/{id}/sessions requests
+func (h *Handler) HandleCreateSession(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	programID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid training program ID")
+	}
+	log.Debug("Processing POST /api/training/programs/%d/sessions request", programID)
+
+	var req sessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse training session request: %v", err)
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+
+	sessionDate, err := time.Parse(dateLayout, req.SessionDate)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "sessionDate is required in YYYY-MM-DD format")
+	}
+
+	session := &domain.TrainingSession{
+		ProgramID:   programID,
+		SessionDate: sessionDate,
+		Location:    req.Location,
+		Trainer:     req.Trainer,
+		Notes:       req.Notes,
+	}
+
+	id, err := h.store.CreateTrainingSession(session)
+	if err != nil {
+		log.Warn("Failed to create training session: %v", err)
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	log.Info("Training session created: ID: %d, Program: %d", id, programID)
+	return utils.RespondWithCreated(w, id, "Training session created successfully")
+}
+
+// HandleGetSession handles GET /api/training/sessions/{id} requests
+func (h *Handler) HandleGetSession(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid training session ID")
+	}
+	log.Debug("Processing GET /api/training/sessions/%d request", id)
+
+	session, err := h.store.GetTrainingSession(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Training session not found")
+		}
+		return fmt.Errorf("failed to get training session: %w", err)
+	}
+
+	return utils.RespondWithSuccess(w, "", session)
+}
+
+// HandleRecordAttendance handles POST /api/training/sessions/{id}/attendance requests
+// Accepts a JSON body, or multipart form data with an optional "certificate" file
+func (h *Handler) HandleRecordAttendance(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	sessionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid training session ID")
+	}
+	log.Debug("Processing POST /api/training/sessions/%d/attendance request", sessionID)
+
+	isMultipart := strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+
+	var req attendanceRequest
+	if isMultipart {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			log.Warn("Failed to parse multipart form: %v", err)
+			return utils.RespondWithBadRequest(w, "Failed to parse form - file may be too large")
+		}
+		req.ExpertID, _ = strconv.ParseInt(r.FormValue("expertId"), 10, 64)
+		req.Status = r.FormValue("status")
+		req.CompletedAt = r.FormValue("completedAt")
+		req.ExpiresAt = r.FormValue("expiresAt")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to parse attendance request: %v", err)
+		return utils.RespondWithBadRequest(w, "Invalid request payload")
+	}
+
+	if req.ExpertID <= 0 {
+		return utils.RespondWithBadRequest(w, "expertId is required")
+	}
+
+	attendance := &domain.TrainingAttendance{
+		SessionID: sessionID,
+		ExpertID:  req.ExpertID,
+		Status:    req.Status,
+	}
+
+	if req.CompletedAt != "" {
+		completedAt, err := time.Parse(dateLayout, req.CompletedAt)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "completedAt must be in YYYY-MM-DD format")
+		}
+		attendance.CompletedAt = &completedAt
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(dateLayout, req.ExpiresAt)
+		if err != nil {
+			return utils.RespondWithBadRequest(w, "expiresAt must be in YYYY-MM-DD format")
+		}
+		attendance.ExpiresAt = &expiresAt
+	}
+
+	// Upload the certificate first so the attendance record can reference it
+	if isMultipart {
+		file, header, err := r.FormFile("certificate")
+		if err == nil {
+			defer file.Close()
+			doc, err := h.documentService.CreateDocument(req.ExpertID, file, header, "certificate")
+			if err != nil {
+				log.Warn("Failed to upload training certificate: %v", err)
+				return utils.RespondWithBadRequest(w, fmt.Sprintf("Failed to upload certificate: %v", err))
+			}
+			attendance.CertificateDocumentID = &doc.ID
+		} else if err != http.ErrMissingFile {
+			return utils.RespondWithBadRequest(w, "Invalid certificate file")
+		}
+	}
+
+	id, err := h.store.RecordTrainingAttendance(attendance)
+	if err != nil {
+		log.Warn("Failed to record training attendance: %v", err)
+		if attendance.CertificateDocumentID != nil {
+			if delErr := h.documentService.DeleteDocument(*attendance.CertificateDocumentID); delErr != nil {
+				log.Error("Failed to clean up certificate document %d: %v", *attendance.CertificateDocumentID, delErr)
+			}
+		}
+		return utils.RespondWithBadRequest(w, err.Error())
+	}
+
+	record, err := h.store.GetTrainingAttendance(id)
+	if err != nil {
+		return fmt.Errorf("failed to get training attendance: %w", err)
+	}
+
+	log.Info("Training attendance recorded: ID: %d, Session: %d, Expert: %d", id, sessionID, req.ExpertID)
+	return utils.RespondWithSuccess(w, "Training attendance recorded successfully", record)
+}
+
+// HandleDeleteAttendance handles DELETE /api/training/attendance/{id} requests
+func (h *Handler) HandleDeleteAttendance(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid attendance ID")
+	}
+	log.Debug("Processing DELETE /api/training/attendance/%d request", id)
+
+	if err := h.store.DeleteTrainingAttendance(id); err != nil {
+		if err == domain.ErrNotFound {
+			return utils.RespondWithNotFound(w, "Attendance record not found")
+		}
+		return fmt.Errorf("failed to delete training attendance: %w", err)
+	}
+
+	log.Info("Training attendance deleted: ID: %d", id)
+	return utils.RespondWithSuccess(w, "Training attendance deleted successfully", nil)
+}
+
+// HandleGetExpertTraining handles GET /api/experts/{id}/training requests
+func (h *Handler) HandleGetExpertTraining(w http.ResponseWriter, r *http.Request) error {
+	log := logger.Get()
+
+	expertID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return utils.RespondWithBadRequest(w, "Invalid expert ID")
+	}
+	log.Debug("Processing GET /api/experts/%d/training request", expertID)
+
+	records, err := h.store.ListExpertTrainingRecords(expertID)
+	if err != nil {
+		return fmt.Errorf("failed to get expert training records: %w", err)
+	}
+
+	isTrained := false
+	for _, record := range records {
+		if record.IsValid {
+			isTrained = true
+			break
+		}
+	}
+
+	if records == nil {
+		records = []*domain.TrainingAttendance{}
+	}
+
+	return utils.RespondWithSuccess(w, "", map[string]interface{}{
+		"expertId":  expertID,
+		"isTrained": isTrained,
+		"records":   records,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 	
 	"expertdb/internal/api/handlers"
 	"expertdb/internal/api/handlers/backup"
@@ -12,6 +13,8 @@ import (
 	"expertdb/internal/api/handlers/engagements"
 	"expertdb/internal/api/handlers/phase"
 	"expertdb/internal/api/handlers/statistics"
+	"expertdb/internal/api/handlers/training"
+	"expertdb/internal/api/utils"
 	"expertdb/internal/auth"
 	"expertdb/internal/config"
 	docsvc "expertdb/internal/documents"
@@ -118,7 +121,7 @@ func (s *Server) registerRoutes() {
 	// Create handlers
 	expertHandler := handlers.NewExpertHandler(s.store, s.documentService)
 	expertRequestHandler := handlers.NewExpertRequestHandler(s.store, s.documentService)
-	documentHandler := documents.NewHandler(s.store, s.documentService)
+	documentHandler := documents.NewHandler(s.store, s.documentService, s.config.PublicBaseURL)
 	engagementHandler := engagements.NewHandler(s.store)
 	statisticsHandler := statistics.NewHandler(s.store)
 	backupHandler := backup.NewHandler(s.store)
@@ -127,6 +130,10 @@ func (s *Server) registerRoutes() {
 	phaseHandler := phase.NewHandler(s.store)
 	roleAssignmentHandler := handlers.NewRoleAssignmentHandler(s.store)
 	specializedAreasHandler := handlers.NewSpecializedAreasHandler(s.store)
+	trainingHandler := training.NewHandler(s.store, s.documentService)
+	adminNotificationHandler := handlers.NewAdminNotificationHandler(s.store)
+	nominationHandler := handlers.NewNominationHandler(s.store, s.documentService,
+		utils.NewRateLimiter(s.config.NominationRateLimit, time.Hour), s.config.PublicBaseURL)
 	
 	// Define a generic error handler wrapper for converting HandlerFunc to http.HandlerFunc
 	errorHandler := func(h auth.HandlerFunc) http.HandlerFunc {
@@ -156,6 +163,19 @@ func (s *Server) registerRoutes() {
 		return authHandler.HandleLogin(w, r)
 	})))
 	
+	// Self-nomination portal - public access with an invite token, rate limited per client IP
+	s.mux.Handle("GET /api/nominations/{token}", corsAndLogMiddleware(errorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return nominationHandler.HandleGetNomination(w, r)
+	})))
+	s.mux.Handle("POST /api/nominations/{token}", corsAndLogMiddleware(errorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return nominationHandler.HandleSubmitNomination(w, r)
+	})))
+	
+	// Signed document download links - public access; the URL signature stands in for a token
+	s.mux.Handle("GET /api/document-links/{id}/download", corsAndLogMiddleware(errorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleDownloadWithLink(w, r)
+	})))
+	
 	// Get expert areas - authenticated user access (Phase 8A: Area Access Extension)
 	s.mux.Handle("GET /api/expert/areas", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
 		return expertHandler.HandleGetExpertAreas(w, r)
@@ -176,6 +196,55 @@ func (s *Server) registerRoutes() {
 		return expertHandler.HandleUpdateArea(w, r)
 	}))))
 	
+	// Move expert area within the hierarchy - admin access
+	s.mux.Handle("PUT /api/expert/areas/{id}/parent", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return expertHandler.HandleMoveArea(w, r)
+	}))))
+	
+	// Map specialized area to a general area - admin access
+	s.mux.Handle("PUT /api/specialized-areas/{id}/general-area", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleUpdateSpecializedAreaGeneralArea(w, r)
+	}))))
+	
+	// Merge expert area into another area - admin access
+	s.mux.Handle("POST /api/expert/areas/{id}/merge", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return expertHandler.HandleMergeArea(w, r)
+	}))))
+	
+	// Retire or restore expert area - admin access
+	s.mux.Handle("PUT /api/expert/areas/{id}/retire", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return expertHandler.HandleRetireArea(w, r)
+	}))))
+	
+	// Merge specialized area into another specialized area - admin access
+	s.mux.Handle("POST /api/specialized-areas/{id}/merge", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleMergeSpecializedArea(w, r)
+	}))))
+	
+	// Retire or restore specialized area - admin access
+	s.mux.Handle("PUT /api/specialized-areas/{id}/retire", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleRetireSpecializedArea(w, r)
+	}))))
+	
+	// Specialized area suggestion moderation queue - admin access
+	s.mux.Handle("GET /api/specialized-areas/suggestions", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleListSuggestions(w, r)
+	}))))
+	s.mux.Handle("GET /api/specialized-areas/suggestions/resolved", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleListSuggestionResolutions(w, r)
+	}))))
+	s.mux.Handle("POST /api/specialized-areas/suggestions/accept", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleAcceptSuggestion(w, r)
+	}))))
+	s.mux.Handle("POST /api/specialized-areas/suggestions/map", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return specializedAreasHandler.HandleMapSuggestion(w, r)
+	}))))
+	
+	// Area merge and retirement audit log - admin access
+	s.mux.Handle("GET /api/areas/audit", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return expertHandler.HandleGetAreaAuditLog(w, r)
+	}))))
+	
 	//
 	// USER ACCESS (All authenticated users)
 	//
@@ -189,12 +258,21 @@ func (s *Server) registerRoutes() {
 		return expertHandler.HandleGetExpert(w, r)
 	}))))
 
+	// Document archive of the experts matching the expert list filters - authenticated users can download documents
+	s.mux.Handle("GET /api/experts/documents/archive", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertHandler.HandleDownloadExpertDocuments(w, r)
+	}))))
+
 	// Expert edit history endpoint - authenticated users can view edit history
 	s.mux.Handle("GET /api/experts/{id}/edit-history", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
 		return expertHandler.HandleGetExpertEditHistory(w, r)
 	}))))
 	
 	// Read-only document endpoints
+	s.mux.Handle("GET /api/document-types", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleListDocumentTypes(w, r)
+	}))))
+	
 	s.mux.Handle("GET /api/documents/{id}", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
 		return documentHandler.HandleGetDocument(w, r)
 	}))))
@@ -204,6 +282,20 @@ func (s *Server) registerRoutes() {
 		return documentHandler.HandleDownloadDocument(w, r)
 	}))))
 	
+	// Document integrity check - authenticated users can verify a file against its upload checksum
+	s.mux.Handle("GET /api/documents/{id}/verify", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleVerifyDocument(w, r)
+	}))))
+	
+	// Document version history - authenticated users can list and download earlier versions
+	s.mux.Handle("GET /api/documents/{id}/versions", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleListDocumentVersions(w, r)
+	}))))
+	
+	s.mux.Handle("GET /api/documents/{id}/versions/{version}/download", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleDownloadDocumentVersion(w, r)
+	}))))
+	
 	
 	// Read-only engagement endpoints
 	s.mux.Handle("GET /api/engagements/{id}", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
@@ -219,6 +311,23 @@ func (s *Server) registerRoutes() {
 		return engagementHandler.HandleGetExpertEngagements(w, r)
 	}))))
 	
+	// Read-only training endpoints
+	s.mux.Handle("GET /api/training/programs", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleListPrograms(w, r)
+	}))))
+	
+	s.mux.Handle("GET /api/training/programs/{id}/sessions", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleListSessions(w, r)
+	}))))
+	
+	s.mux.Handle("GET /api/training/sessions/{id}", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleGetSession(w, r)
+	}))))
+	
+	s.mux.Handle("GET /api/experts/{id}/training", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleGetExpertTraining(w, r)
+	}))))
+	
 	//
 	// PLANNER ACCESS
 	//
@@ -279,12 +388,80 @@ func (s *Server) registerRoutes() {
 		return expertRequestHandler.HandleEditExpertRequest(w, r)
 	}))))
 	
+	// Expert request workflow - requesters and admins (allowed transitions depend on role)
+	s.mux.Handle("POST /api/expert-requests/{id}/transition", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleTransitionExpertRequest(w, r)
+	}))))
+	
+	s.mux.Handle("GET /api/expert-requests/{id}/history", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleGetExpertRequestHistory(w, r)
+	}))))
+	
+	// Expert request comment threads - requesters and admins (internal notes are admin only)
+	s.mux.Handle("GET /api/expert-requests/{id}/comments", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleListExpertRequestComments(w, r)
+	}))))
+	
+	s.mux.Handle("POST /api/expert-requests/{id}/comments", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleCreateExpertRequestComment(w, r)
+	}))))
+
 	// Batch approval endpoint for multiple expert requests
 	s.mux.Handle("POST /api/expert-requests/batch-approve", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
 		return expertRequestHandler.HandleBatchApproveExpertRequests(w, r)
 	}))))
+	
+	// Batch reject, withdraw and reassign - reviewers, or requesters withdrawing their own requests
+	s.mux.Handle("POST /api/expert-requests/batch", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleBatchUpdateExpertRequests(w, r)
+	}))))
+	
+	// Expert request aging report - admin access
+	s.mux.Handle("GET /api/expert-requests/aging", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return expertRequestHandler.HandleGetExpertRequestAging(w, r)
+	}))))
+	
+	// Admin notification feed (e.g. expert requests escalated past their review target) - admin access
+	s.mux.Handle("GET /api/notifications", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return adminNotificationHandler.HandleListAdminNotifications(w, r)
+	}))))
+	s.mux.Handle("POST /api/notifications/{id}/acknowledge", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return adminNotificationHandler.HandleAcknowledgeAdminNotification(w, r)
+	}))))
+	
+	// Nomination invites for the self-nomination portal - admin access
+	s.mux.Handle("POST /api/nomination-invites", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return nominationHandler.HandleCreateNominationInvite(w, r)
+	}))))
+	s.mux.Handle("GET /api/nomination-invites", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return nominationHandler.HandleListNominationInvites(w, r)
+	}))))
+	s.mux.Handle("DELETE /api/nomination-invites/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return nominationHandler.HandleRevokeNominationInvite(w, r)
+	}))))
 
 	
+	// Training management (programs, sessions, attendance)
+	s.mux.Handle("POST /api/training/programs", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleCreateProgram(w, r)
+	}))))
+	
+	s.mux.Handle("PUT /api/training/programs/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleUpdateProgram(w, r)
+	}))))
+	
+	s.mux.Handle("POST /api/training/programs/{id}/sessions", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleCreateSession(w, r)
+	}))))
+	
+	s.mux.Handle("POST /api/training/sessions/{id}/attendance", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleRecordAttendance(w, r)
+	}))))
+	
+	s.mux.Handle("DELETE /api/training/attendance/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return trainingHandler.HandleDeleteAttendance(w, r)
+	}))))
+	
 	// Document management (upload, delete)
 	s.mux.Handle("POST /api/documents", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
 		return documentHandler.HandleUploadDocument(w, r)
@@ -294,6 +471,81 @@ func (s *Server) registerRoutes() {
 		return documentHandler.HandleDeleteDocument(w, r)
 	}))))
 	
+	// Bulk integrity scan over all stored documents
+	s.mux.Handle("POST /api/documents/integrity-scan", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleScanDocumentIntegrity(w, r)
+	}))))
+	
+	// Download links for people without an account - admin access
+	s.mux.Handle("POST /api/documents/{id}/links", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleCreateDownloadLink(w, r)
+	}))))
+	s.mux.Handle("GET /api/documents/{id}/links", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleListDownloadLinks(w, r)
+	}))))
+	s.mux.Handle("DELETE /api/document-links/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleRevokeDownloadLink(w, r)
+	}))))
+	s.mux.Handle("GET /api/document-links/{id}/downloads", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleListLinkDownloads(w, r)
+	}))))
+	
+	// Compare stored files with document records, optionally repairing or quarantining discrepancies
+	s.mux.Handle("POST /api/documents/reconcile", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleReconcileDocuments(w, r)
+	}))))
+	
+	// Extract searchable text from stored PDF and DOCX documents
+	s.mux.Handle("POST /api/documents/extract-text", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleExtractDocumentText(w, r)
+	}))))
+	
+	// Document versions - upload a replacement file or restore an earlier version
+	s.mux.Handle("POST /api/documents/{id}/versions", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleUploadDocumentVersion(w, r)
+	}))))
+	
+	s.mux.Handle("POST /api/documents/{id}/versions/{version}/promote", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandlePromoteDocumentVersion(w, r)
+	}))))
+	
+	// Uploads flagged by the malware scanner
+	s.mux.Handle("GET /api/documents/quarantine", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleListQuarantinedUploads(w, r)
+	}))))
+	
+	s.mux.Handle("DELETE /api/documents/quarantine/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleDeleteQuarantinedUpload(w, r)
+	}))))
+	
+	// Document type registry - admin management
+	s.mux.Handle("POST /api/document-types", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleCreateDocumentType(w, r)
+	}))))
+	s.mux.Handle("PUT /api/document-types/{code}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleUpdateDocumentType(w, r)
+	}))))
+	s.mux.Handle("PUT /api/document-types/{code}/retire", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleRetireDocumentType(w, r)
+	}))))
+	
+	// Resumable uploads - large files sent in chunks, then used by POST /api/documents or batch approval
+	s.mux.Handle("POST /api/uploads", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleStartUpload(w, r)
+	}))))
+	s.mux.Handle("GET /api/uploads/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleGetUpload(w, r)
+	}))))
+	s.mux.Handle("PUT /api/uploads/{id}/chunks", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleUploadChunk(w, r)
+	}))))
+	s.mux.Handle("POST /api/uploads/{id}/complete", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleCompleteUpload(w, r)
+	}))))
+	s.mux.Handle("DELETE /api/uploads/{id}", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleCancelUpload(w, r)
+	}))))
+	
 	//
 	// SUPER USER ACCESS
 	//
@@ -341,6 +593,11 @@ func (s *Server) registerRoutes() {
 		return phaseHandler.HandleGetPhase(w, r)
 	}))))
 	
+	// Document archive of a phase, with a folder per application - authenticated users can download documents
+	s.mux.Handle("GET /api/phases/{id}/documents/archive", corsAndLogMiddleware(errorHandler(auth.RequireAuth(func(w http.ResponseWriter, r *http.Request) error {
+		return documentHandler.HandleDownloadPhaseDocuments(w, r)
+	}))))
+	
 	// Create phase - admin access
 	s.mux.Handle("POST /api/phases", corsAndLogMiddleware(errorHandler(auth.RequireRole(auth.RoleAdmin, func(w http.ResponseWriter, r *http.Request) error {
 		return phaseHandler.HandleCreatePhase(w, r)
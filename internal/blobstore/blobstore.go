@@ -0,0 +1,110 @@
+// Package blobstore provides storage backends for uploaded document files
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Backend names accepted by Open
+const (
+	BackendFileSystem = "filesystem"
+	BackendS3         = "s3"
+)
+
+// Store holds document files addressed by slash-separated keys such as "experts/cv_12_20250101_090000.pdf"
+type Store interface {
+	// Put stores the content read from r under key, replacing any existing object
+	Put(key string, r io.Reader, contentType string) error
+	// Get opens the object stored under key along with its size in bytes
+	Get(key string) (io.ReadCloser, int64, error)
+	// Stat returns the size of the object stored under key
+	Stat(key string) (int64, error)
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(key string) error
+	// Move renames the object at src to dst
+	Move(src, dst string) error
+	// List calls fn with the key and size of every stored object, stopping at the first error fn returns
+	List(fn func(key string, size int64) error) error
+	// Key returns the key that a stored document path refers to, as List would report it
+	Key(stored string) string
+	// Name identifies the backend in logs
+	Name() string
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	Backend string   // BackendFileSystem or BackendS3
+	Root    string   // Base directory of the filesystem backend
+	S3      S3Config // Settings for the S3 backend
+
+	MasterKey          string   // Base64 master key wrapping the data keys of new files; files are stored unencrypted when empty
+	PreviousMasterKeys []string // Base64 master keys replaced by a rotation, still needed to read files not yet rewrapped
+}
+
+// Open creates the storage backend described by cfg
+// When master keys are configured the backend is wrapped so files are encrypted at rest (see Encrypted)
+func Open(cfg Config) (Store, error) {
+	var store Store
+	switch cfg.Backend {
+	case "", BackendFileSystem:
+		store = NewFileSystem(cfg.Root)
+	case BackendS3:
+		s3, err := NewS3(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		store = s3
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q; must be %q or %q", cfg.Backend, BackendFileSystem, BackendS3)
+	}
+
+	if cfg.MasterKey == "" && len(cfg.PreviousMasterKeys) == 0 {
+		return store, nil
+	}
+
+	var current *MasterKey
+	if cfg.MasterKey != "" {
+		k, err := ParseMasterKey(cfg.MasterKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key: %w", err)
+		}
+		current = k
+	}
+	var previous []*MasterKey
+	for i, encoded := range cfg.PreviousMasterKeys {
+		k, err := ParseMasterKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous encryption key %d: %w", i+1, err)
+		}
+		previous = append(previous, k)
+	}
+	return NewEncrypted(store, current, previous...), nil
+}
+
+// Copy copies the object stored under key from one store to another
+func Copy(dst, src Store, key, dstKey, contentType string) error {
+	r, _, err := src.Get(key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return dst.Put(dstKey, r, contentType)
+}
+
+// NormalizeKey turns a stored document path into a key relative to the filesystem root
+// Documents uploaded before storage backends existed recorded their full path under root
+func NormalizeKey(root, stored string) string {
+	cleaned := filepath.Clean(stored)
+	if rel, err := filepath.Rel(filepath.Clean(root), cleaned); err == nil && !strings.HasPrefix(rel, "..") {
+		cleaned = rel
+	}
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(cleaned)), "/")
+}
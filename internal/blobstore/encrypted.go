@@ -0,0 +1,459 @@
+package blobstore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Encrypted files start with a header holding the file's data key, wrapped by a master key:
+//
+//	magic (8) | master key ID (8) | wrap nonce (12) | wrapped data key (32 + 16)
+//
+// The content follows in chunks of up to 64 KiB, each sealed with AES-256-GCM under the data key.
+// A chunk's nonce is its index, with the last byte set on the final chunk so truncation is detected.
+// Rewrapping only replaces the header; the chunks stay as they are
+const (
+	encryptionMagic = "EXDBENC1"
+	keyIDSize       = 8
+	nonceSize       = 12
+	dataKeySize     = 32
+	tagSize         = 16
+	headerSize      = len(encryptionMagic) + keyIDSize + nonceSize + dataKeySize + tagSize
+	chunkSize       = 64 << 10
+
+	// rewriteSuffix names the temporary object a file is written to before it replaces the original
+	rewriteSuffix = ".rewrite"
+)
+
+// ErrDecrypt is returned when an encrypted file cannot be decrypted: its master key is not configured,
+// or the file is damaged
+var ErrDecrypt = errors.New("failed to decrypt file")
+
+// MasterKey wraps the data keys of encrypted files
+type MasterKey struct {
+	id   [keyIDSize]byte
+	aead cipher.AEAD
+}
+
+// ParseMasterKey decodes a base64-encoded 32-byte master key, as generated by `openssl rand -base64 32`
+func ParseMasterKey(encoded string) (*MasterKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+	if len(raw) != dataKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", dataKeySize, len(raw))
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &MasterKey{aead: aead}
+	sum := sha256.Sum256(raw)
+	copy(k.id[:], sum[:keyIDSize])
+	return k, nil
+}
+
+// ID identifies the key in file headers and reports without revealing it
+func (k *MasterKey) ID() string {
+	return hex.EncodeToString(k.id[:])
+}
+
+// newAEAD returns AES-256-GCM keyed with key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypted is a Store that encrypts objects written through it with a fresh data key per object,
+// wrapped by the current master key, and decrypts them transparently when they are read.
+// Objects stored before encryption was enabled are read as they are. Sizes reported by Get and Stat
+// are those of the decrypted content; List reports stored sizes
+type Encrypted struct {
+	Store
+	current *MasterKey                     // Wraps the data keys of new objects; nil writes plaintext
+	keys    map[[keyIDSize]byte]*MasterKey // Every configured key, for reading
+}
+
+// NewEncrypted wraps inner so objects are encrypted under current
+// Previous master keys are only used to read objects written before a rotation. Without a current
+// key new objects are stored unencrypted, while existing encrypted objects remain readable
+func NewEncrypted(inner Store, current *MasterKey, previous ...*MasterKey) *Encrypted {
+	e := &Encrypted{Store: inner, current: current, keys: make(map[[keyIDSize]byte]*MasterKey)}
+	for _, k := range previous {
+		e.keys[k.id] = k
+	}
+	if current != nil {
+		e.keys[current.id] = current
+	}
+	return e
+}
+
+// Name identifies the backend in logs
+func (e *Encrypted) Name() string {
+	if e.current == nil {
+		return e.Store.Name() + " (decrypt only)"
+	}
+	return fmt.Sprintf("%s (encrypted, key %s)", e.Store.Name(), e.current.ID())
+}
+
+// CurrentKeyID returns the ID of the master key new objects are encrypted with, empty when there is none
+func (e *Encrypted) CurrentKeyID() string {
+	if e.current == nil {
+		return ""
+	}
+	return e.current.ID()
+}
+
+// Put encrypts the content read from r and stores it under key
+func (e *Encrypted) Put(key string, r io.Reader, contentType string) error {
+	if e.current == nil {
+		return e.Store.Put(key, r, contentType)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
+	}
+	header, err := e.current.wrap(dataKey)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	// The stored object is opaque, so it no longer carries the content type of the document
+	sealed := &sealReader{src: r, aead: aead, buf: make([]byte, chunkSize+1)}
+	return e.Store.Put(key, io.MultiReader(bytes.NewReader(header), sealed), "application/octet-stream")
+}
+
+// Get opens the object stored under key, decrypting it if it is encrypted
+func (e *Encrypted) Get(key string) (io.ReadCloser, int64, error) {
+	raw, header, size, err := e.openRaw(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if header == nil {
+		return raw, size, nil
+	}
+
+	dataKey, err := e.unwrap(key, header)
+	if err != nil {
+		raw.Close()
+		return nil, 0, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		raw.Close()
+		return nil, 0, err
+	}
+	opened := &openReader{key: key, src: raw.Reader, aead: aead, buf: make([]byte, chunkSize+tagSize)}
+	return readCloser{Reader: opened, Closer: raw.Closer}, plaintextSize(size), nil
+}
+
+// Stat returns the size of the content stored under key, as Get would return it
+func (e *Encrypted) Stat(key string) (int64, error) {
+	raw, header, size, err := e.openRaw(key)
+	if err != nil {
+		return 0, err
+	}
+	raw.Close()
+	if header == nil {
+		return size, nil
+	}
+	return plaintextSize(size), nil
+}
+
+// KeyID returns the ID of the master key wrapping the object's data key, empty if it is not encrypted
+func (e *Encrypted) KeyID(key string) (string, error) {
+	raw, header, _, err := e.openRaw(key)
+	if err != nil {
+		return "", err
+	}
+	raw.Close()
+	if header == nil {
+		return "", nil
+	}
+	return hex.EncodeToString(headerKeyID(header)), nil
+}
+
+// EncryptInPlace encrypts an object stored before encryption was enabled, reporting whether it did
+// The encrypted copy is written next to the original and then moved over it
+func (e *Encrypted) EncryptInPlace(key string) (bool, error) {
+	if e.current == nil {
+		return false, fmt.Errorf("no current master key to encrypt with")
+	}
+
+	raw, header, _, err := e.openRaw(key)
+	if err != nil {
+		return false, err
+	}
+	defer raw.Close()
+	if header != nil {
+		return false, nil
+	}
+
+	return true, e.rewrite(key, func(tmp string) error {
+		return e.Put(tmp, raw, "")
+	})
+}
+
+// Rewrap wraps an object's data key with the current master key, reporting whether it was wrapped by
+// another key. The content is not decrypted or re-encrypted
+func (e *Encrypted) Rewrap(key string) (bool, error) {
+	if e.current == nil {
+		return false, fmt.Errorf("no current master key to rewrap with")
+	}
+
+	raw, header, _, err := e.openRaw(key)
+	if err != nil {
+		return false, err
+	}
+	defer raw.Close()
+	if header == nil || bytes.Equal(headerKeyID(header), e.current.id[:]) {
+		return false, nil
+	}
+
+	dataKey, err := e.unwrap(key, header)
+	if err != nil {
+		return false, err
+	}
+	rewrapped, err := e.current.wrap(dataKey)
+	if err != nil {
+		return false, err
+	}
+	return true, e.rewrite(key, func(tmp string) error {
+		return e.Store.Put(tmp, io.MultiReader(bytes.NewReader(rewrapped), raw), "application/octet-stream")
+	})
+}
+
+// rewrite replaces the object under key with what write stores under a temporary key
+// The original stays intact until the new object is complete
+func (e *Encrypted) rewrite(key string, write func(tmp string) error) error {
+	tmp := key + rewriteSuffix
+	if err := write(tmp); err != nil {
+		e.Store.Delete(tmp)
+		return err
+	}
+	if err := e.Store.Move(tmp, key); err != nil {
+		e.Store.Delete(tmp)
+		return fmt.Errorf("failed to replace %s: %w", key, err)
+	}
+	return nil
+}
+
+// bufferedObject is a stored object opened for reading through a buffer
+type bufferedObject struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// openRaw opens the stored object under key and reads its encryption header
+// The header is nil for objects that are not encrypted, whose reader then starts at the first byte
+func (e *Encrypted) openRaw(key string) (bufferedObject, []byte, int64, error) {
+	rc, size, err := e.Store.Get(key)
+	if err != nil {
+		return bufferedObject{}, nil, 0, err
+	}
+	raw := bufferedObject{Reader: bufio.NewReaderSize(rc, chunkSize+tagSize), Closer: rc}
+
+	magic, err := raw.Peek(len(encryptionMagic))
+	if err != nil && err != io.EOF {
+		rc.Close()
+		return bufferedObject{}, nil, 0, err
+	}
+	if string(magic) != encryptionMagic {
+		return raw, nil, size, nil
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(raw, header); err != nil {
+		rc.Close()
+		return bufferedObject{}, nil, 0, fmt.Errorf("%w: %s: incomplete header", ErrDecrypt, key)
+	}
+	return raw, header, size, nil
+}
+
+// wrap returns an encryption header holding dataKey sealed with k
+func (k *MasterKey) wrap(dataKey []byte) ([]byte, error) {
+	header := make([]byte, 0, headerSize)
+	header = append(header, encryptionMagic...)
+	header = append(header, k.id[:]...)
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+	return k.aead.Seal(header, nonce, dataKey, k.id[:]), nil
+}
+
+// unwrap returns the data key held in an object's header
+func (e *Encrypted) unwrap(key string, header []byte) ([]byte, error) {
+	var id [keyIDSize]byte
+	copy(id[:], headerKeyID(header))
+	k, ok := e.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is encrypted with master key %x, which is not configured", ErrDecrypt, key, id)
+	}
+
+	offset := len(encryptionMagic) + keyIDSize
+	nonce := header[offset : offset+nonceSize]
+	dataKey, err := k.aead.Open(nil, nonce, header[offset+nonceSize:], id[:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: data key does not match master key %x", ErrDecrypt, key, id)
+	}
+	return dataKey, nil
+}
+
+// headerKeyID returns the master key ID recorded in an encryption header
+func headerKeyID(header []byte) []byte {
+	return header[len(encryptionMagic) : len(encryptionMagic)+keyIDSize]
+}
+
+// chunkNonce returns the nonce of the chunk at index, marking the final chunk
+func chunkNonce(index uint64, last bool) []byte {
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce, index)
+	if last {
+		nonce[nonceSize-1] = 1
+	}
+	return nonce
+}
+
+// plaintextSize returns the content size of an encrypted object of the given stored size
+// Every chunk but the last holds chunkSize bytes, and each carries a tag
+func plaintextSize(stored int64) int64 {
+	if stored < 0 {
+		return stored
+	}
+	body := stored - int64(headerSize)
+	chunks := (body + chunkSize + tagSize - 1) / (chunkSize + tagSize)
+	return body - chunks*tagSize
+}
+
+// readCloser pairs a reader with the closer of the object it reads from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// sealReader encrypts src chunk by chunk as it is read
+type sealReader struct {
+	src     io.Reader
+	aead    cipher.AEAD
+	index   uint64
+	buf     []byte // One chunk plus a byte of lookahead, to tell whether more content follows
+	pending int    // Bytes already in buf, carried over from the previous chunk
+	sealed  []byte
+	out     []byte // Sealed bytes not yet returned
+	done    bool
+}
+
+func (r *sealReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// sealNext encrypts the next chunk; an empty source still produces one final chunk
+func (r *sealReader) sealNext() error {
+	n, err := io.ReadFull(r.src, r.buf[r.pending:])
+	n += r.pending
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	default:
+		return err
+	}
+
+	size := min(n, chunkSize)
+	r.sealed = r.aead.Seal(r.sealed[:0], chunkNonce(r.index, last), r.buf[:size], nil)
+	r.out = r.sealed
+	r.pending = copy(r.buf, r.buf[size:n])
+	r.index++
+	r.done = last
+	return nil
+}
+
+// openReader decrypts chunks read from src, failing if any was altered, reordered or cut off
+type openReader struct {
+	key   string
+	src   *bufio.Reader
+	aead  cipher.AEAD
+	index uint64
+	buf   []byte
+	plain []byte
+	out   []byte // Decrypted bytes not yet returned
+	done  bool
+}
+
+func (r *openReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.openNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// openNext decrypts the next chunk; a short chunk, or a full one at the end of the object, is the final one
+func (r *openReader) openNext() error {
+	n, err := io.ReadFull(r.src, r.buf)
+	last := false
+	switch err {
+	case nil:
+		if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return fmt.Errorf("%w: %s is truncated", ErrDecrypt, r.key)
+	default:
+		return err
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(r.index, last), r.buf[:n], nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s is damaged or truncated", ErrDecrypt, r.key)
+	}
+	r.plain = plain
+	r.out = plain
+	r.index++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,223 @@
+package blobstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testMasterKey returns a master key of 32 copies of b
+func testMasterKey(t *testing.T, b byte) *MasterKey {
+	t.Helper()
+	k, err := ParseMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, dataKeySize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// rawObject returns an object's bytes as stored, without decrypting them
+func rawObject(t *testing.T, root, key string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseMasterKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr string
+	}{
+		{"valid", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)), ""},
+		{"surrounding whitespace", " " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)) + "\n", ""},
+		{"not base64", "not a key!", "not valid base64"},
+		{"too short", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16)), "must be 32 bytes, got 16"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseMasterKey(tt.encoded)
+			if tt.wantErr == "" {
+				if err != nil || len(k.ID()) != 2*keyIDSize {
+					t.Errorf("ParseMasterKey = %v, %v", k, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseMasterKey error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+	if testMasterKey(t, 1).ID() == testMasterKey(t, 2).ID() {
+		t.Error("different keys share an ID")
+	}
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	store := NewEncrypted(NewFileSystem(root), testMasterKey(t, 1))
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 5} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			content := strings.Repeat("confidential ", size/13+1)[:size]
+			key := fmt.Sprintf("experts/cv_%d.pdf", size)
+			if err := store.Put(key, strings.NewReader(content), "application/pdf"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			raw := rawObject(t, root, key)
+			if !bytes.HasPrefix(raw, []byte(encryptionMagic)) || (size > 0 && bytes.Contains(raw, []byte(content))) {
+				t.Errorf("stored object is not encrypted")
+			}
+			if got := readObject(t, store, key); got != content {
+				t.Errorf("read %d bytes, want the %d written", len(got), size)
+			}
+			if stat, err := store.Stat(key); err != nil || stat != int64(size) {
+				t.Errorf("Stat = %d, %v, want %d", stat, err, size)
+			}
+		})
+	}
+}
+
+func TestEncryptedDamagedObjects(t *testing.T) {
+	content := strings.Repeat("x", 2*chunkSize+10)
+	tests := []struct {
+		name   string
+		damage func([]byte) []byte
+	}{
+		{"flipped content byte", func(raw []byte) []byte { raw[headerSize+5] ^= 1; return raw }},
+		{"flipped wrapped key", func(raw []byte) []byte { raw[headerSize-1] ^= 1; return raw }},
+		{"last chunk cut off", func(raw []byte) []byte { return raw[:headerSize+2*(chunkSize+tagSize)] }},
+		{"chunk cut short", func(raw []byte) []byte { return raw[:len(raw)-3] }},
+		{"header cut short", func(raw []byte) []byte { return raw[:headerSize-4] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			store := NewEncrypted(NewFileSystem(root), testMasterKey(t, 1))
+			if err := store.Put("doc.pdf", strings.NewReader(content), ""); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(root, "doc.pdf")
+			if err := os.WriteFile(path, tt.damage(rawObject(t, root, "doc.pdf")), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := func() error {
+				r, _, err := store.Get("doc.pdf")
+				if err != nil {
+					return err
+				}
+				defer r.Close()
+				_, err = io.ReadAll(r)
+				return err
+			}()
+			if !errors.Is(err, ErrDecrypt) {
+				t.Errorf("reading a damaged object: error = %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedKeyRotation(t *testing.T) {
+	root := t.TempDir()
+	oldKey, newKey := testMasterKey(t, 1), testMasterKey(t, 2)
+	if err := NewEncrypted(NewFileSystem(root), oldKey).Put("doc.pdf", strings.NewReader("content"), ""); err != nil {
+		t.Fatal(err)
+	}
+	contentBefore := rawObject(t, root, "doc.pdf")[headerSize:]
+
+	rotated := NewEncrypted(NewFileSystem(root), newKey, oldKey)
+	steps := []struct {
+		name        string
+		rewrap      bool // Whether Rewrap is expected to replace the header
+		wantKeyID   string
+		readableOld bool // Whether a store with only the old key can still read the object
+	}{
+		{"before rewrap", false, oldKey.ID(), true},
+		{"rewrapped", true, newKey.ID(), false},
+		{"already current", false, newKey.ID(), false},
+	}
+	for i, step := range steps {
+		if i > 0 {
+			rewrapped, err := rotated.Rewrap("doc.pdf")
+			if err != nil || rewrapped != step.rewrap {
+				t.Fatalf("%s: Rewrap = %v, %v, want %v", step.name, rewrapped, err, step.rewrap)
+			}
+		}
+		if id, err := rotated.KeyID("doc.pdf"); err != nil || id != step.wantKeyID {
+			t.Errorf("%s: KeyID = %s, %v, want %s", step.name, id, err, step.wantKeyID)
+		}
+		if got := readObject(t, rotated, "doc.pdf"); got != "content" {
+			t.Errorf("%s: read %q", step.name, got)
+		}
+		_, _, err := NewEncrypted(NewFileSystem(root), oldKey).Get("doc.pdf")
+		if step.readableOld != (err == nil) || (err != nil && !errors.Is(err, ErrDecrypt)) {
+			t.Errorf("%s: reading with the old key only: error = %v", step.name, err)
+		}
+	}
+
+	// Rewrapping replaces the header only
+	if !bytes.Equal(rawObject(t, root, "doc.pdf")[headerSize:], contentBefore) {
+		t.Error("Rewrap re-encrypted the content")
+	}
+	if _, err := os.Stat(filepath.Join(root, "doc.pdf"+rewriteSuffix)); !os.IsNotExist(err) {
+		t.Errorf("temporary object left behind: %v", err)
+	}
+	if _, err := NewEncrypted(NewFileSystem(root), nil).Rewrap("doc.pdf"); err == nil {
+		t.Error("Rewrap without a current key succeeded")
+	}
+}
+
+func TestEncryptedPlaintextObjects(t *testing.T) {
+	root := t.TempDir()
+	inner := NewFileSystem(root)
+	if err := inner.Put("legacy.pdf", strings.NewReader("legacy content"), ""); err != nil {
+		t.Fatal(err)
+	}
+	store := NewEncrypted(inner, testMasterKey(t, 1))
+
+	if got := readObject(t, store, "legacy.pdf"); got != "legacy content" {
+		t.Errorf("read %q from a plaintext object", got)
+	}
+	if id, err := store.KeyID("legacy.pdf"); err != nil || id != "" {
+		t.Errorf("KeyID = %q, %v, want none", id, err)
+	}
+
+	for _, want := range []bool{true, false} {
+		encrypted, err := store.EncryptInPlace("legacy.pdf")
+		if err != nil || encrypted != want {
+			t.Errorf("EncryptInPlace = %v, %v, want %v", encrypted, err, want)
+		}
+	}
+	if raw := rawObject(t, root, "legacy.pdf"); !bytes.HasPrefix(raw, []byte(encryptionMagic)) {
+		t.Error("object not encrypted in place")
+	}
+	if got := readObject(t, store, "legacy.pdf"); got != "legacy content" {
+		t.Errorf("read %q after encrypting in place", got)
+	}
+
+	// Without a current key, encrypted objects stay readable and new ones are written as they are
+	decryptOnly := NewEncrypted(inner, nil, testMasterKey(t, 1))
+	if got := readObject(t, decryptOnly, "legacy.pdf"); got != "legacy content" {
+		t.Errorf("decrypt-only store read %q", got)
+	}
+	if err := decryptOnly.Put("new.pdf", strings.NewReader("new content"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawObject(t, root, "new.pdf"); string(raw) != "new content" {
+		t.Errorf("decrypt-only store wrote %q, want the plaintext", raw)
+	}
+	if _, err := decryptOnly.EncryptInPlace("new.pdf"); err == nil {
+		t.Error("EncryptInPlace without a current key succeeded")
+	}
+}
@@ -0,0 +1,167 @@
+package blobstore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSystem stores objects as files below a root directory
+type FileSystem struct {
+	root string
+}
+
+// NewFileSystem creates a filesystem store rooted at root
+// Directories are created as objects are written
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{root: filepath.Clean(root)}
+}
+
+// Name identifies the backend in logs
+func (fs *FileSystem) Name() string {
+	return "filesystem:" + fs.root
+}
+
+// path resolves a key to a file path below the root
+// Documents uploaded before storage backends existed stored their full path, which is used as is
+func (fs *FileSystem) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if filepath.IsAbs(cleaned) || cleaned == fs.root || strings.HasPrefix(cleaned, fs.root+string(filepath.Separator)) {
+		return cleaned, nil
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key %q: escapes storage root", key)
+	}
+	return filepath.Join(fs.root, cleaned), nil
+}
+
+// Put writes r to the file for key, removing a partially written file on error
+func (fs *FileSystem) Put(key string, r io.Reader, contentType string) error {
+	p, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	dst, err := os.Create(p)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	_, err = io.Copy(dst, r)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(p) // Clean up on error
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	return nil
+}
+
+// Get opens the file for key
+func (fs *FileSystem) Get(key string) (io.ReadCloser, int64, error) {
+	p, err := fs.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, 0, fsError(key, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to get file info: %w", err)
+	}
+	return f, info.Size(), nil
+}
+
+// Stat returns the size of the file for key
+func (fs *FileSystem) Stat(key string) (int64, error) {
+	p, err := fs.path(key)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return 0, fsError(key, err)
+	}
+	return info.Size(), nil
+}
+
+// Delete removes the file for key
+func (fs *FileSystem) Delete(key string) error {
+	p, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// Move renames the file for src to the file for dst
+func (fs *FileSystem) Move(src, dst string) error {
+	srcPath, err := fs.path(src)
+	if err != nil {
+		return err
+	}
+	dstPath, err := fs.path(dst)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+	if err := os.Rename(srcPath, dstPath); err != nil {
+		return fsError(src, err)
+	}
+	return nil
+}
+
+// List walks the root directory, reporting every file with its path relative to the root
+// A root that does not exist yet holds no objects
+func (fs *FileSystem) List(fn func(key string, size int64) error) error {
+	err := filepath.WalkDir(fs.root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if p == fs.root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(fs.root, p)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), info.Size())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	return nil
+}
+
+// Key maps a stored path, including full paths recorded before storage backends existed, to a key relative to the root
+func (fs *FileSystem) Key(stored string) string {
+	return NormalizeKey(fs.root, stored)
+}
+
+// fsError maps a missing file to ErrNotFound
+func fsError(key string, err error) error {
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return err
+}
@@ -1,7 +1,15 @@
 // Package config provides configuration management for the ExpertDB application
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/filescan"
+)
 
 // Configuration represents application configuration
 type Configuration struct {
@@ -14,20 +22,88 @@ type Configuration struct {
 	AdminPassword    string `json:"-"`                // Default admin password
 	LogDir           string `json:"-"`                // Directory for log files
 	LogLevel         string `json:"-"`                // Log level (debug, info, warn, error)
+
+	ApprovalQuorum               int  `json:"approvalQuorum"`               // Reviewer approvals needed before an expert is created
+	ApprovalRequireDistinctRoles bool `json:"approvalRequireDistinctRoles"` // Approving reviewers must hold different roles
+
+	RequestSLAHours      map[string]int `json:"requestSlaHours"`      // Review target in hours per expert request status, overriding the defaults
+	SLACheckIntervalMins int            `json:"slaCheckIntervalMins"` // Minutes between checks for requests past their review target
+
+	EngagementOverdueDays int `json:"engagementOverdueDays"` // Days an active engagement may run without an end date before it is flagged
+
+	PublicBaseURL       string `json:"publicBaseUrl"`       // Base URL of the web app, used in links sent by email
+	NominationRateLimit int    `json:"nominationRateLimit"` // Public nomination requests allowed per client IP per hour
+	DownloadLinkSecret  string `json:"-"`                   // Key that signs document download links; random per start when empty
+
+	SMTPHost     string `json:"-"` // SMTP server host; emails are only logged when empty
+	SMTPPort     string `json:"-"` // SMTP server port
+	SMTPUsername string `json:"-"` // SMTP username
+	SMTPPassword string `json:"-"` // SMTP password
+	SMTPFrom     string `json:"-"` // Sender address for outgoing emails
+
+	StorageBackend    string `json:"storageBackend"` // Document storage backend: "filesystem" or "s3"
+	S3Endpoint        string `json:"s3Endpoint"`     // S3-compatible endpoint URL, e.g. http://localhost:9000
+	S3Region          string `json:"s3Region"`       // Region used to sign S3 requests
+	S3Bucket          string `json:"s3Bucket"`       // Bucket holding document objects
+	S3Prefix          string `json:"s3Prefix"`       // Optional key prefix inside the bucket
+	S3AccessKeyID     string `json:"-"`              // S3 access key ID
+	S3SecretAccessKey string `json:"-"`              // S3 secret access key
+	S3PathStyle       bool   `json:"s3PathStyle"`    // Address objects as endpoint/bucket/key instead of bucket.endpoint/key
+
+	EncryptionKey          string   `json:"-"` // Base64 master key that encrypts stored documents; documents are stored unencrypted when empty
+	EncryptionPreviousKeys []string `json:"-"` // Master keys replaced by a rotation, kept until their files are rewrapped
+
+	MalwareScanner    string `json:"malwareScanner"`    // Scanner applied to uploads: "none" or "clamav"
+	ClamAVAddress     string `json:"clamavAddress"`     // clamd socket, e.g. unix:///var/run/clamav/clamd.ctl or tcp://localhost:3310
+	ClamAVTimeoutSecs int    `json:"clamavTimeoutSecs"` // Seconds allowed for one clamd scan
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Configuration {
 	config := &Configuration{
-		Port:             os.Getenv("PORT"),
-		DBPath:           os.Getenv("DB_PATH"),
-		UploadPath:       os.Getenv("UPLOAD_PATH"),
-		CORSAllowOrigins: os.Getenv("CORS_ALLOWED_ORIGINS"),
-		AdminEmail:       os.Getenv("ADMIN_EMAIL"),
-		AdminName:        os.Getenv("ADMIN_NAME"),
-		AdminPassword:    os.Getenv("ADMIN_PASSWORD"),
-		LogDir:           os.Getenv("LOG_DIR"),
-		LogLevel:         os.Getenv("LOG_LEVEL"),
+		Port:               os.Getenv("PORT"),
+		DBPath:             os.Getenv("DB_PATH"),
+		UploadPath:         os.Getenv("UPLOAD_PATH"),
+		CORSAllowOrigins:   os.Getenv("CORS_ALLOWED_ORIGINS"),
+		AdminEmail:         os.Getenv("ADMIN_EMAIL"),
+		AdminName:          os.Getenv("ADMIN_NAME"),
+		AdminPassword:      os.Getenv("ADMIN_PASSWORD"),
+		LogDir:             os.Getenv("LOG_DIR"),
+		LogLevel:           os.Getenv("LOG_LEVEL"),
+		PublicBaseURL:      os.Getenv("PUBLIC_BASE_URL"),
+		DownloadLinkSecret: os.Getenv("DOWNLOAD_LINK_SECRET"),
+		SMTPHost:           os.Getenv("SMTP_HOST"),
+		SMTPPort:           os.Getenv("SMTP_PORT"),
+		SMTPUsername:       os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:       os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:           os.Getenv("SMTP_FROM"),
+		StorageBackend:     os.Getenv("STORAGE_BACKEND"),
+		S3Endpoint:         os.Getenv("S3_ENDPOINT"),
+		S3Region:           os.Getenv("S3_REGION"),
+		S3Bucket:           os.Getenv("S3_BUCKET"),
+		S3Prefix:           os.Getenv("S3_PREFIX"),
+		S3AccessKeyID:      os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:  os.Getenv("S3_SECRET_ACCESS_KEY"),
+		MalwareScanner:     os.Getenv("MALWARE_SCANNER"),
+		ClamAVAddress:      os.Getenv("CLAMAV_ADDRESS"),
+	}
+
+	config.ApprovalQuorum, _ = strconv.Atoi(os.Getenv("APPROVAL_QUORUM"))
+	config.ApprovalRequireDistinctRoles, _ = strconv.ParseBool(os.Getenv("APPROVAL_REQUIRE_DISTINCT_ROLES"))
+	config.NominationRateLimit, _ = strconv.Atoi(os.Getenv("NOMINATION_RATE_LIMIT"))
+	config.RequestSLAHours = parseStatusHours(os.Getenv("REQUEST_SLA_HOURS"))
+	config.SLACheckIntervalMins, _ = strconv.Atoi(os.Getenv("SLA_CHECK_INTERVAL_MINUTES"))
+	config.EngagementOverdueDays, _ = strconv.Atoi(os.Getenv("ENGAGEMENT_OVERDUE_DAYS"))
+	config.ClamAVTimeoutSecs, _ = strconv.Atoi(os.Getenv("CLAMAV_TIMEOUT_SECONDS"))
+	config.EncryptionKey = strings.TrimSpace(os.Getenv("ENCRYPTION_KEY"))
+	for _, key := range strings.Split(os.Getenv("ENCRYPTION_PREVIOUS_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			config.EncryptionPreviousKeys = append(config.EncryptionPreviousKeys, key)
+		}
+	}
+	config.S3PathStyle = true
+	if pathStyle, err := strconv.ParseBool(os.Getenv("S3_PATH_STYLE")); err == nil {
+		config.S3PathStyle = pathStyle
 	}
 
 	// Set defaults for empty values
@@ -58,6 +134,86 @@ func LoadConfig() *Configuration {
 	if config.LogLevel == "" {
 		config.LogLevel = "info"
 	}
+	if config.ApprovalQuorum < 1 {
+		config.ApprovalQuorum = 2
+	}
+	if config.PublicBaseURL == "" {
+		config.PublicBaseURL = "http://localhost:" + config.Port
+	}
+	if config.NominationRateLimit < 1 {
+		config.NominationRateLimit = 10
+	}
+	if config.SLACheckIntervalMins < 1 {
+		config.SLACheckIntervalMins = 60
+	}
+	if config.EngagementOverdueDays < 1 {
+		config.EngagementOverdueDays = 90
+	}
+	if config.SMTPPort == "" {
+		config.SMTPPort = "587"
+	}
+	if config.SMTPFrom == "" {
+		config.SMTPFrom = "no-reply@expertdb.com"
+	}
+	if config.StorageBackend == "" {
+		config.StorageBackend = "filesystem"
+	}
+	if config.S3Region == "" {
+		config.S3Region = "us-east-1"
+	}
+	if config.MalwareScanner == "" {
+		config.MalwareScanner = "none"
+	}
+	if config.ClamAVAddress == "" {
+		config.ClamAVAddress = "unix:///var/run/clamav/clamd.ctl"
+	}
+	if config.ClamAVTimeoutSecs < 1 {
+		config.ClamAVTimeoutSecs = 60
+	}
 
 	return config
-}
\ No newline at end of file
+}
+
+// parseStatusHours parses a list such as "submitted=72,under_review=168" into hours per status
+// Entries that are not in that form are ignored
+func parseStatusHours(value string) map[string]int {
+	hours := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		status, h, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(h)); err == nil {
+			hours[strings.TrimSpace(status)] = n
+		}
+	}
+	return hours
+}
+
+// BlobStore returns the document storage settings
+func (c *Configuration) BlobStore() blobstore.Config {
+	return blobstore.Config{
+		Backend: c.StorageBackend,
+		Root:    c.UploadPath,
+		S3: blobstore.S3Config{
+			Endpoint:        c.S3Endpoint,
+			Region:          c.S3Region,
+			Bucket:          c.S3Bucket,
+			Prefix:          c.S3Prefix,
+			AccessKeyID:     c.S3AccessKeyID,
+			SecretAccessKey: c.S3SecretAccessKey,
+			PathStyle:       c.S3PathStyle,
+		},
+		MasterKey:          c.EncryptionKey,
+		PreviousMasterKeys: c.EncryptionPreviousKeys,
+	}
+}
+
+// FileScanner returns the malware scanner settings for uploads
+func (c *Configuration) FileScanner() filescan.Config {
+	return filescan.Config{
+		Backend:       c.MalwareScanner,
+		ClamAVAddress: c.ClamAVAddress,
+		Timeout:       time.Duration(c.ClamAVTimeoutSecs) * time.Second,
+	}
+}
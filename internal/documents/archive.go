@@ -0,0 +1,313 @@
+package documents
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Document types archived when a request names none
+var defaultArchiveTypes = []string{domain.DocumentTypeCV, domain.DocumentTypeApproval}
+
+// Manifest statuses of the documents of an archive
+const (
+	ArchiveIncluded   = "included"   // The file is in the archive
+	ArchiveMissing    = "missing"    // The expert has no document of the type
+	ArchiveUnreadable = "unreadable" // The file could not be read from storage; see the manifest note
+)
+
+// ArchiveFolder is a folder of a document archive, holding the documents of one or more experts
+type ArchiveFolder struct {
+	Name        string
+	Application *domain.PhaseApplication // Application the folder is for, nil for a folder per expert
+	Experts     []*domain.Expert
+}
+
+// ApplicationFolders returns one archive folder per application of a phase that has experts assigned,
+// named after the application's ID, institution and qualification
+func (s *Service) ApplicationFolders(phase *domain.Phase) ([]ArchiveFolder, error) {
+	loaded := make(map[int64]*domain.Expert)
+	var folders []ArchiveFolder
+	for i := range phase.Applications {
+		app := &phase.Applications[i]
+		folder := ArchiveFolder{
+			Name:        archiveName(fmt.Sprintf("%d_%s_%s", app.ID, app.InstitutionName, app.QualificationName)),
+			Application: app,
+		}
+		for _, expertID := range []int64{app.Expert1, app.Expert2} {
+			if expertID == 0 {
+				continue
+			}
+			expert, ok := loaded[expertID]
+			if !ok {
+				var err error
+				expert, err = s.store.GetExpert(expertID)
+				if err != nil && err != domain.ErrNotFound {
+					return nil, fmt.Errorf("failed to load expert %d: %w", expertID, err)
+				}
+				loaded[expertID] = expert
+			}
+			if expert != nil {
+				folder.Experts = append(folder.Experts, expert)
+			}
+		}
+		if len(folder.Experts) > 0 {
+			folders = append(folders, folder)
+		}
+	}
+	return folders, nil
+}
+
+// ExpertFolders returns one archive folder per expert, named after the expert's ID and name
+func ExpertFolders(experts []*domain.Expert) []ArchiveFolder {
+	folders := make([]ArchiveFolder, 0, len(experts))
+	for _, expert := range experts {
+		folders = append(folders, ArchiveFolder{
+			Name:    archiveName(fmt.Sprintf("%d_%s", expert.ID, expert.Name)),
+			Experts: []*domain.Expert{expert},
+		})
+	}
+	return folders
+}
+
+// ArchiveSummary counts the documents written to an archive
+type ArchiveSummary struct {
+	Folders    int
+	Included   int
+	Missing    int
+	Unreadable int
+}
+
+// ArchiveTypes parses a comma-separated list of document type codes to archive, defaulting to CVs
+// and approvals. Retired types are accepted, as their documents remain
+func (s *Service) ArchiveTypes(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return defaultArchiveTypes, nil
+	}
+
+	var codes []string
+	for _, code := range strings.Split(list, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		if _, err := s.store.GetDocumentType(code); err != nil {
+			if err == domain.ErrNotFound {
+				return nil, fmt.Errorf("%w: document type '%s' does not exist", domain.ErrValidation, code)
+			}
+			return nil, fmt.Errorf("failed to look up document type: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return defaultArchiveTypes, nil
+	}
+	return codes, nil
+}
+
+// WriteArchive streams a ZIP archive with a folder per entry of folders, holding the documents of the
+// given types of the folder's experts, followed by manifest.csv, which lists every document with its
+// checksum and whether it was included. A document shared by experts of a folder, such as a batch
+// approval, is written once. Files that cannot be read are listed as unreadable instead of failing the
+// archive; an error is returned when documents cannot be looked up or writing to w fails
+func (s *Service) WriteArchive(w io.Writer, folders []ArchiveFolder, docTypes []string) (*ArchiveSummary, error) {
+	log := logger.Get()
+	archive := zip.NewWriter(w)
+	summary := &ArchiveSummary{}
+	manifest := [][]string{{
+		"Folder", "ApplicationID", "Institution", "Qualification", "ExpertID", "ExpertName",
+		"DocumentType", "DocumentID", "File", "Size", "SHA256", "Status", "Note",
+	}}
+
+	for _, folder := range folders {
+		summary.Folders++
+		written := make(map[int64]string) // Archive path of each document already in the folder
+		used := make(map[string]bool)
+
+		for _, expert := range folder.Experts {
+			docs, err := s.archiveDocuments(expert, docTypes)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, docType := range docTypes {
+				row := []string{folder.Name, "", "", "", strconv.FormatInt(expert.ID, 10), expert.Name, docType, "", "", "", "", "", ""}
+				if folder.Application != nil {
+					row[1] = strconv.FormatInt(folder.Application.ID, 10)
+					row[2] = folder.Application.InstitutionName
+					row[3] = folder.Application.QualificationName
+				}
+
+				if len(docs[docType]) == 0 {
+					row[11] = ArchiveMissing
+					manifest = append(manifest, row)
+					summary.Missing++
+					continue
+				}
+
+				for _, doc := range docs[docType] {
+					row := append([]string(nil), row...)
+					row[7] = strconv.FormatInt(doc.ID, 10)
+					row[10] = doc.SHA256
+
+					name, ok := written[doc.ID]
+					if !ok {
+						name = archiveFileName(folder.Name, docType, expert, doc, used)
+						size, err := s.writeArchiveFile(archive, name, doc)
+						if err != nil {
+							if _, failed := err.(archiveWriteError); failed {
+								return nil, err
+							}
+							log.Warn("Document %d could not be added to the archive: %v", doc.ID, err)
+							if size > 0 {
+								row[8] = name
+							}
+							row[11] = ArchiveUnreadable
+							row[12] = err.Error()
+							manifest = append(manifest, row)
+							summary.Unreadable++
+							continue
+						}
+						written[doc.ID] = name
+						row[9] = strconv.FormatInt(size, 10)
+					} else {
+						row[9] = strconv.FormatInt(doc.FileSize, 10)
+						row[12] = "shared with another expert of the folder"
+					}
+					row[8] = name
+					row[11] = ArchiveIncluded
+					manifest = append(manifest, row)
+					summary.Included++
+				}
+			}
+		}
+	}
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: "manifest.csv", Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(entry)
+	if err := writer.WriteAll(manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// archiveDocuments returns an expert's current documents of the given types by type. CVs and approvals
+// are taken from the expert's references, as an approval shared by several experts is only listed
+// under one of them
+func (s *Service) archiveDocuments(expert *domain.Expert, docTypes []string) (map[string][]*domain.Document, error) {
+	docs := make(map[string][]*domain.Document)
+	var listed []*domain.Document
+
+	for _, docType := range docTypes {
+		var ref *int64
+		switch docType {
+		case domain.DocumentTypeCV:
+			ref = expert.CVDocumentID
+		case domain.DocumentTypeApproval:
+			ref = expert.ApprovalDocumentID
+		default:
+			if listed == nil {
+				var err error
+				if listed, err = s.store.ListDocuments(expert.ID); err != nil {
+					return nil, fmt.Errorf("failed to list documents of expert %d: %w", expert.ID, err)
+				}
+			}
+			for _, doc := range listed {
+				if doc.DocumentType == docType {
+					docs[docType] = append(docs[docType], doc)
+				}
+			}
+			continue
+		}
+
+		if ref == nil {
+			continue
+		}
+		doc, err := s.store.GetDocument(*ref)
+		if err == domain.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load document %d: %w", *ref, err)
+		}
+		docs[docType] = append(docs[docType], doc)
+	}
+	return docs, nil
+}
+
+// archiveWriteError marks a failure to write the archive itself, as opposed to reading a document
+type archiveWriteError struct{ error }
+
+// writeArchiveFile copies a document's file into the archive under name, returning its size
+// A file that fails part way through is left truncated in the archive
+func (s *Service) writeArchiveFile(archive *zip.Writer, name string, doc *domain.Document) (int64, error) {
+	file, _, err := s.blobs.Get(doc.FilePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: doc.UploadDate})
+	if err != nil {
+		return 0, archiveWriteError{err}
+	}
+	var size int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := file.Read(buf)
+		if n > 0 {
+			if _, err := entry.Write(buf[:n]); err != nil {
+				return size, archiveWriteError{err}
+			}
+			size += int64(n)
+		}
+		if readErr == io.EOF {
+			return size, nil
+		}
+		if readErr != nil {
+			return size, fmt.Errorf("read failed after %d bytes, so the file in the archive is incomplete: %w", size, readErr)
+		}
+	}
+}
+
+// archiveFileName names a document within its folder after its type and expert, adding the document ID
+// when the name is already used in the folder
+func archiveFileName(folder, docType string, expert *domain.Expert, doc *domain.Document, used map[string]bool) string {
+	extension := strings.ToLower(filepath.Ext(doc.Filename))
+	base := archiveName(fmt.Sprintf("%s_%s", docType, expert.Name))
+	name := path.Join(folder, base+extension)
+	if used[name] {
+		name = path.Join(folder, fmt.Sprintf("%s_%d%s", base, doc.ID, extension))
+	}
+	used[name] = true
+	return name
+}
+
+// archiveName makes s safe to use as a file or folder name in an archive
+func archiveName(s string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(s))
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	return strings.TrimRight(name, ". ")
+}
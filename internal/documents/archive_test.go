@@ -0,0 +1,190 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"12_Amal Hasan", "12_Amal Hasan"},
+		{` a/b\c:d*e?f"g<h>i|j `, "a_b_c_d_e_f_g_h_i_j"},
+		{"tab\there", "tab_here"},
+		{"trailing dots...", "trailing dots"},
+		{strings.Repeat("é", 120), strings.Repeat("é", 100)},
+	}
+	for _, tt := range tests {
+		if got := archiveName(tt.in); got != tt.want {
+			t.Errorf("archiveName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveTypes(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	store.types["certificate"] = &domain.DocumentType{Code: "certificate"}
+	if err := store.SetDocumentTypeRetired("certificate", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		list    string
+		want    string
+		wantErr bool
+	}{
+		{"", "[cv approval]", false},
+		{" , ", "[cv approval]", false},
+		{" certificate, cv ", "[certificate cv]", false}, // Retired types remain archivable
+		{"cv,passport", "", true},
+	}
+	for _, tt := range tests {
+		codes, err := svc.ArchiveTypes(tt.list)
+		if tt.wantErr {
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("ArchiveTypes(%q) error = %v, want a validation error", tt.list, err)
+			}
+			continue
+		}
+		if err != nil || fmt.Sprint(codes) != tt.want {
+			t.Errorf("ArchiveTypes(%q) = %v, %v, want %s", tt.list, codes, err, tt.want)
+		}
+	}
+}
+
+func TestApplicationFolders(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	store.experts[1] = &domain.Expert{ID: 1, Name: "Amal Hasan"}
+	store.experts[2] = &domain.Expert{ID: 2, Name: "Badr Saleh"}
+	phase := &domain.Phase{Applications: []domain.PhaseApplication{
+		{ID: 7, InstitutionName: "University", QualificationName: "BSc/Physics", Expert1: 1, Expert2: 2},
+		{ID: 8, InstitutionName: "College", QualificationName: "Diploma", Expert1: 99}, // Deleted expert only
+		{ID: 9, InstitutionName: "Institute", QualificationName: "MSc", Expert2: 1},
+	}}
+
+	folders, err := svc.ApplicationFolders(phase)
+	if err != nil {
+		t.Fatalf("ApplicationFolders: %v", err)
+	}
+	var got []string
+	for _, folder := range folders {
+		got = append(got, fmt.Sprintf("%s:%d:%d", folder.Name, folder.Application.ID, len(folder.Experts)))
+	}
+	if want := "[7_University_BSc_Physics:7:2 9_Institute_MSc:9:1]"; fmt.Sprint(got) != want {
+		t.Errorf("folders = %v, want %s", got, want)
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	svc, store, blobs := newMemoryService(t)
+	files := map[string]string{
+		"experts/cv_1.pdf":       "cv of expert 1",
+		"approvals/shared.pdf":   "shared approval",
+		"certificates/first.pdf": "first certificate",
+		"certificates/other.pdf": "second certificate",
+	}
+	for key, content := range files {
+		if err := blobs.Put(key, strings.NewReader(content), "application/pdf"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, doc := range []*domain.Document{
+		{ID: 1, ExpertID: 1, DocumentType: domain.DocumentTypeCV, Filename: "cv.pdf", FilePath: "experts/cv_1.pdf"},
+		{ID: 2, ExpertID: 1, DocumentType: domain.DocumentTypeApproval, Filename: "approval.pdf", FilePath: "approvals/shared.pdf"},
+		{ID: 3, ExpertID: 1, DocumentType: "certificate", Filename: "first.PDF", FilePath: "certificates/first.pdf"},
+		{ID: 4, ExpertID: 1, DocumentType: "certificate", Filename: "other.pdf", FilePath: "certificates/other.pdf"},
+		{ID: 5, ExpertID: 2, DocumentType: domain.DocumentTypeCV, Filename: "cv.pdf", FilePath: "experts/cv_missing.pdf"},
+	} {
+		store.documents[doc.ID] = doc
+	}
+	ref := func(id int64) *int64 { return &id }
+	folder := ArchiveFolder{
+		Name:        "7_University_BSc",
+		Application: &domain.PhaseApplication{ID: 7, InstitutionName: "University", QualificationName: "BSc"},
+		Experts: []*domain.Expert{
+			{ID: 1, Name: "Amal Hasan", CVDocumentID: ref(1), ApprovalDocumentID: ref(2)},
+			{ID: 2, Name: "Badr/Saleh", CVDocumentID: ref(5), ApprovalDocumentID: ref(2)},
+		},
+	}
+
+	var buf bytes.Buffer
+	summary, err := svc.WriteArchive(&buf, []ArchiveFolder{folder}, []string{domain.DocumentTypeCV, domain.DocumentTypeApproval, "certificate"})
+	if err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	if *summary != (ArchiveSummary{Folders: 1, Included: 5, Missing: 1, Unreadable: 1}) {
+		t.Errorf("summary = %+v", *summary)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+		entries[f.Name] = string(content)
+	}
+	wantEntries := map[string]string{
+		"7_University_BSc/cv_Amal Hasan.pdf":            "cv of expert 1",
+		"7_University_BSc/approval_Amal Hasan.pdf":      "shared approval",
+		"7_University_BSc/certificate_Amal Hasan.pdf":   "first certificate",
+		"7_University_BSc/certificate_Amal Hasan_4.pdf": "second certificate",
+	}
+	for name, want := range wantEntries {
+		if entries[name] != want {
+			t.Errorf("%s holds %q, want %q", name, entries[name], want)
+		}
+	}
+	if len(entries) != len(wantEntries)+1 {
+		t.Errorf("archive entries = %d, want %d files and the manifest", len(entries), len(wantEntries))
+	}
+
+	manifest, err := csv.NewReader(strings.NewReader(entries["manifest.csv"])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Expert, document type, document, file name in the folder and status of each row
+	wantRows := []string{
+		"1 cv 1 cv_Amal Hasan.pdf included",
+		"1 approval 2 approval_Amal Hasan.pdf included",
+		"1 certificate 3 certificate_Amal Hasan.pdf included",
+		"1 certificate 4 certificate_Amal Hasan_4.pdf included",
+		"2 cv 5  unreadable",
+		"2 approval 2 approval_Amal Hasan.pdf included",
+		"2 certificate   missing",
+	}
+	if len(manifest) != len(wantRows)+1 {
+		t.Fatalf("manifest has %d rows, want a header and %d", len(manifest), len(wantRows))
+	}
+	for i, want := range wantRows {
+		row := manifest[i+1]
+		got := fmt.Sprintf("%s %s %s %s %s", row[4], row[6], row[7], strings.TrimPrefix(row[8], folder.Name+"/"), row[11])
+		if got != want {
+			t.Errorf("manifest row %d = %q, want %q", i+1, got, want)
+		}
+		if row[1] != "7" || row[2] != "University" {
+			t.Errorf("manifest row %d application = %v, want application 7", i+1, row[1:4])
+		}
+	}
+	if note := manifest[6][12]; note != "shared with another expert of the folder" {
+		t.Errorf("shared approval note = %q", note)
+	}
+	if note := manifest[5][12]; note == "" {
+		t.Error("unreadable document has no note")
+	}
+}
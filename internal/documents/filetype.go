@@ -0,0 +1,68 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// sniffLen is the number of leading bytes inspected to identify a file
+const sniffLen = 512
+
+// File signatures of the accepted document formats
+var (
+	pdfMagic  = []byte("%PDF-")
+	jpegMagic = []byte{0xFF, 0xD8, 0xFF}
+	pngMagic  = []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}
+	oleMagic  = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1} // Compound File Binary, used by .doc
+	zipMagic  = []byte{'P', 'K', 0x03, 0x04}                           // ZIP container, used by .docx
+)
+
+// detectContentType identifies a file from its content rather than its name or the client's claim
+// Formats other than the accepted document types are reported as http.DetectContentType sees them
+func detectContentType(r io.ReaderAt, size int64) string {
+	head := make([]byte, sniffLen)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "application/octet-stream"
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, pdfMagic):
+		return "application/pdf"
+	case bytes.HasPrefix(head, jpegMagic):
+		return "image/jpeg"
+	case bytes.HasPrefix(head, pngMagic):
+		return "image/png"
+	case bytes.HasPrefix(head, oleMagic):
+		return "application/msword"
+	case bytes.HasPrefix(head, zipMagic):
+		if isWordDocument(r, size) {
+			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		}
+		return "application/zip"
+	}
+
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// isWordDocument reports whether a ZIP archive is a Word document, which always holds word/document.xml
+func isWordDocument(r io.ReaderAt, size int64) bool {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return false
+	}
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
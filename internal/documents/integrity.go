@@ -0,0 +1,197 @@
+package documents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// hashFile returns the hex SHA-256 of the stored file at filePath
+func (s *Service) hashFile(filePath string) (string, error) {
+	f, _, err := s.blobs.Get(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// storeFile writes src to filePath while hashing it, returning the path the content is stored at and its checksum
+// If a stored document already has identical, intact content, the new copy is discarded and the existing path is
+// returned instead, so identical uploads share a single file
+func (s *Service) storeFile(filePath string, src io.Reader, contentType string) (string, string, error) {
+	log := logger.Get()
+
+	h := sha256.New()
+	if err := s.blobs.Put(filePath, io.TeeReader(src, h), contentType); err != nil {
+		return "", "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	log.Debug("File written successfully to %s (sha256 %s)", filePath, sum)
+
+	existing, err := s.store.FindDocumentBySHA256(sum)
+	if err != nil {
+		if err != domain.ErrNotFound {
+			log.Warn("Failed to look up duplicate content for %s, keeping new copy: %v", filePath, err)
+		}
+		return filePath, sum, nil
+	}
+
+	// Only share the existing file if it still holds the content its checksum claims
+	if existingSum, err := s.hashFile(existing.FilePath); err != nil || existingSum != sum {
+		log.Warn("Existing document %d with identical checksum is missing or altered, keeping new copy", existing.ID)
+		return filePath, sum, nil
+	}
+
+	if err := s.blobs.Delete(filePath); err != nil {
+		log.Warn("Failed to remove duplicate upload %s: %v", filePath, err)
+		return filePath, sum, nil
+	}
+	log.Debug("Upload is identical to document %d, sharing file %s", existing.ID, existing.FilePath)
+	return existing.FilePath, sum, nil
+}
+
+// removeFileIfUnused deletes a stored file once no document record points at it any more
+func (s *Service) removeFileIfUnused(filePath string) {
+	log := logger.Get()
+	if filePath == "" {
+		return
+	}
+
+	count, err := s.store.CountFileReferences(filePath, 0)
+	if err != nil {
+		log.Warn("Failed to check references to %s, keeping file: %v", filePath, err)
+		return
+	}
+	if count > 0 {
+		log.Debug("Keeping file %s still used by %d document(s)", filePath, count)
+		return
+	}
+
+	if err := s.blobs.Delete(filePath); err != nil {
+		log.Warn("Failed to delete document file (%s): %v", filePath, err)
+		return
+	}
+	log.Debug("Deleted document file: %s", filePath)
+}
+
+// isShared reports whether other documents or their versions point at the same file as doc
+func (s *Service) isShared(doc *domain.Document) (bool, error) {
+	count, err := s.store.CountFileReferences(doc.FilePath, doc.ID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// VerifyDocument checks a document's file against its stored checksum
+func (s *Service) VerifyDocument(id int64) (*domain.DocumentIntegrityResult, error) {
+	doc, err := s.store.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sum, err := s.hashFile(doc.FilePath)
+	result := checkIntegrity(doc, sum, err)
+	return &result, nil
+}
+
+// ScanIntegrity verifies every stored document, reporting missing and altered files
+// With backfill set, documents without a checksum get one recorded from their current content
+func (s *Service) ScanIntegrity(backfill bool) (*domain.DocumentIntegrityReport, error) {
+	log := logger.Get()
+
+	docs, err := s.store.ListAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DocumentIntegrityReport{
+		ScannedAt: time.Now(),
+		Total:     len(docs),
+		Problems:  []domain.DocumentIntegrityResult{},
+	}
+
+	// Deduplicated documents share a file, so each path is only hashed once
+	type hashed struct {
+		sum string
+		err error
+	}
+	cache := make(map[string]hashed)
+
+	for _, doc := range docs {
+		h, ok := cache[doc.FilePath]
+		if !ok {
+			h.sum, h.err = s.hashFile(doc.FilePath)
+			cache[doc.FilePath] = h
+		}
+
+		result := checkIntegrity(doc, h.sum, h.err)
+		switch result.Status {
+		case domain.DocumentIntegrityOK:
+			report.OK++
+		case domain.DocumentIntegrityMissing:
+			report.Missing++
+			report.Problems = append(report.Problems, result)
+		case domain.DocumentIntegrityAltered:
+			report.Altered++
+			report.Problems = append(report.Problems, result)
+		case domain.DocumentIntegrityUnreadable:
+			report.Unreadable++
+			report.Problems = append(report.Problems, result)
+		case domain.DocumentIntegrityUnhashed:
+			if !backfill {
+				continue
+			}
+			if err := s.store.SetDocumentSHA256(doc.ID, h.sum); err != nil {
+				log.Warn("Failed to record checksum for document %d: %v", doc.ID, err)
+				continue
+			}
+			report.Backfilled++
+		}
+	}
+
+	log.Info("Document integrity scan: %d documents, %d ok, %d missing, %d altered, %d unreadable, %d backfilled",
+		report.Total, report.OK, report.Missing, report.Altered, report.Unreadable, report.Backfilled)
+	return report, nil
+}
+
+// checkIntegrity classifies a document given the checksum of its file, or the error reading it
+func checkIntegrity(doc *domain.Document, sum string, readErr error) domain.DocumentIntegrityResult {
+	result := domain.DocumentIntegrityResult{
+		DocumentID:     doc.ID,
+		ExpertID:       doc.ExpertID,
+		DocumentType:   doc.DocumentType,
+		Filename:       doc.Filename,
+		FilePath:       doc.FilePath,
+		ExpectedSHA256: doc.SHA256,
+		ActualSHA256:   sum,
+	}
+
+	switch {
+	case errors.Is(readErr, blobstore.ErrNotFound):
+		result.Status = domain.DocumentIntegrityMissing
+	case readErr != nil:
+		result.Status = domain.DocumentIntegrityUnreadable
+		result.Error = readErr.Error()
+	case doc.SHA256 == "":
+		result.Status = domain.DocumentIntegrityUnhashed
+	case sum != doc.SHA256:
+		result.Status = domain.DocumentIntegrityAltered
+	default:
+		result.Status = domain.DocumentIntegrityOK
+		result.Intact = true
+	}
+	return result
+}
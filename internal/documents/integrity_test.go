@@ -0,0 +1,131 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestStoreFileDeduplicates(t *testing.T) {
+	tests := []struct {
+		name    string
+		second  string // Content of the second upload
+		tamper  bool   // Whether the first file is altered before the second upload
+		wantSet bool   // Whether both documents end up sharing one file
+	}{
+		{"identical", "%PDF-1.4\nsame\n%%EOF", false, true},
+		{"different", "%PDF-1.4\nother\n%%EOF", false, false},
+		{"identical to an altered file", "%PDF-1.4\nsame\n%%EOF", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, blobs := newMemoryService(t)
+			file, header := newUpload(t, "first.pdf", "application/pdf", []byte("%PDF-1.4\nsame\n%%EOF"))
+			first, err := s.CreateDocumentForRequest(1, file, header, domain.DocumentTypeCV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper {
+				if err := blobs.Put(first.FilePath, strings.NewReader("tampered"), "application/pdf"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			file, header = newUpload(t, "second.pdf", "application/pdf", []byte(tt.second))
+			second, err := s.CreateDocumentForRequest(2, file, header, domain.DocumentTypeCV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shared := second.FilePath == first.FilePath; shared != tt.wantSet {
+				t.Errorf("second file %s, first %s; want shared %v", second.FilePath, first.FilePath, tt.wantSet)
+			}
+			want := 2
+			if tt.wantSet {
+				want = 1
+			}
+			if files := storedKeys(t, blobs); len(files) != want {
+				t.Errorf("files = %v, want %d", files, want)
+			}
+		})
+	}
+}
+
+func TestDeleteDocumentKeepsSharedFile(t *testing.T) {
+	s, _, blobs := newMemoryService(t)
+	content := []byte("%PDF-1.4\nshared\n%%EOF")
+	var docs []*domain.Document
+	for requestID := int64(1); requestID <= 2; requestID++ {
+		file, header := newUpload(t, "cv.pdf", "application/pdf", content)
+		doc, err := s.CreateDocumentForRequest(requestID, file, header, domain.DocumentTypeCV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+
+	if err := s.DeleteDocument(docs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blobs.Stat(docs[1].FilePath); err != nil {
+		t.Errorf("shared file removed while another document uses it: %v", err)
+	}
+	if err := s.DeleteDocument(docs[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if files := storedKeys(t, blobs); len(files) != 0 {
+		t.Errorf("files %v left after deleting every document using them", files)
+	}
+}
+
+func TestScanIntegrity(t *testing.T) {
+	s, store, blobs := newMemoryService(t)
+	requestID := int64(0)
+	upload := func(content string) *domain.Document {
+		// Each request gets its own file name; files of one request within a second would collide
+		requestID++
+		file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\n"+content+"\n%%EOF"))
+		doc, err := s.CreateDocumentForRequest(requestID, file, header, domain.DocumentTypeCV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+	intact := upload("intact")
+	missing := upload("missing")
+	altered := upload("altered")
+	unhashed := upload("unhashed")
+	blobs.Delete(missing.FilePath)
+	blobs.Put(altered.FilePath, strings.NewReader("tampered"), "application/pdf")
+	store.documents[unhashed.ID].SHA256 = ""
+
+	tests := []struct {
+		doc  *domain.Document
+		want string
+	}{
+		{intact, domain.DocumentIntegrityOK},
+		{missing, domain.DocumentIntegrityMissing},
+		{altered, domain.DocumentIntegrityAltered},
+		{unhashed, domain.DocumentIntegrityUnhashed},
+	}
+	for _, tt := range tests {
+		result, err := s.VerifyDocument(tt.doc.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Status != tt.want || result.Intact != (tt.want == domain.DocumentIntegrityOK) {
+			t.Errorf("VerifyDocument(%s) = %s (intact %v), want %s", tt.doc.FilePath, result.Status, result.Intact, tt.want)
+		}
+	}
+
+	report, err := s.ScanIntegrity(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 4 || report.OK != 1 || report.Missing != 1 || report.Altered != 1 || report.Backfilled != 1 || len(report.Problems) != 2 {
+		t.Errorf("report = %+v, want 1 ok, 1 missing, 1 altered and 1 backfilled", report)
+	}
+	if sum, _ := s.hashFile(unhashed.FilePath); store.documents[unhashed.ID].SHA256 != sum {
+		t.Error("checksum not backfilled from the file content")
+	}
+}
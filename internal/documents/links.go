@@ -0,0 +1,147 @@
+package documents
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Errors returned when a download link cannot be used
+var (
+	ErrLinkInvalid     = errors.New("download link is invalid")
+	ErrLinkUnavailable = errors.New("download link has expired, been revoked or already been used")
+)
+
+// SetLinkSecret sets the key download links are signed with
+// Links signed with a previous key stop working
+func (s *Service) SetLinkSecret(secret []byte) {
+	s.linkSecret = secret
+}
+
+// signLink returns the HMAC-SHA256 signature of a link's ID and expiry
+func (s *Service) signLink(linkID, expires int64) string {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	fmt.Fprintf(mac, "document-link:%d:%d", linkID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DownloadLinkPath returns the signed path that downloads a link's document
+func (s *Service) DownloadLinkPath(link *domain.DocumentDownloadLink) string {
+	expires := link.ExpiresAt.Unix()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {s.signLink(link.ID, expires)},
+	}
+	return fmt.Sprintf("/api/document-links/%d/download?%s", link.ID, query.Encode())
+}
+
+// CreateDownloadLink stores a download link for an existing document
+func (s *Service) CreateDownloadLink(link *domain.DocumentDownloadLink) error {
+	if len(s.linkSecret) == 0 {
+		return fmt.Errorf("download links are not configured")
+	}
+	if _, err := s.store.GetDocument(link.DocumentID); err != nil {
+		return err
+	}
+
+	// The signature covers the expiry in whole seconds, so the stored time must match it exactly
+	link.ExpiresAt = time.Unix(link.ExpiresAt.Unix(), 0)
+	if _, err := s.store.CreateDocumentDownloadLink(link); err != nil {
+		return err
+	}
+
+	logger.Get().Info("Download link %d for document %d created by user %d for %q (expires %s, single use %t)",
+		link.ID, link.DocumentID, link.CreatedBy, link.Recipient, link.ExpiresAt.Format(time.RFC3339), link.SingleUse)
+	return nil
+}
+
+// ListDownloadLinks returns the download links of a document, newest first
+func (s *Service) ListDownloadLinks(documentID int64) ([]*domain.DocumentDownloadLink, error) {
+	if _, err := s.store.GetDocument(documentID); err != nil {
+		return nil, err
+	}
+	return s.store.ListDocumentDownloadLinks(documentID)
+}
+
+// RevokeDownloadLink stops a download link from working
+func (s *Service) RevokeDownloadLink(id int64) error {
+	return s.store.RevokeDocumentDownloadLink(id)
+}
+
+// ListLinkDownloads returns the downloads made through a link, most recent first
+func (s *Service) ListLinkDownloads(linkID int64) ([]*domain.DocumentDownload, error) {
+	if _, err := s.store.GetDocumentDownloadLink(linkID); err != nil {
+		return nil, err
+	}
+	return s.store.ListDocumentDownloads(linkID)
+}
+
+// OpenDownloadLink checks a link's signature and expiry and opens its document for download
+// The download is counted and logged with the client's IP address and user agent before the file is
+// returned. Returns ErrLinkInvalid for unknown links and bad signatures, and ErrLinkUnavailable for
+// links that have expired, been revoked or used up
+func (s *Service) OpenDownloadLink(linkID int64, expires, signature, ipAddress, userAgent string) (*domain.Document, io.ReadCloser, int64, error) {
+	if len(s.linkSecret) == 0 {
+		return nil, nil, 0, ErrLinkInvalid
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, nil, 0, ErrLinkInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signLink(linkID, expiresUnix))) {
+		return nil, nil, 0, ErrLinkInvalid
+	}
+
+	link, err := s.store.GetDocumentDownloadLink(linkID)
+	if err == domain.ErrNotFound {
+		return nil, nil, 0, ErrLinkInvalid
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if link.ExpiresAt.Unix() != expiresUnix {
+		return nil, nil, 0, ErrLinkInvalid
+	}
+	if !link.Usable(time.Now()) {
+		return nil, nil, 0, ErrLinkUnavailable
+	}
+
+	doc, err := s.store.GetDocument(link.DocumentID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	file, size, err := s.blobs.Get(doc.FilePath)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Counting the download last keeps a single-use link usable if the file could not be opened
+	download := &domain.DocumentDownload{
+		LinkID:        link.ID,
+		DocumentID:    doc.ID,
+		Recipient:     link.Recipient,
+		LinkCreatedBy: link.CreatedBy,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+	}
+	if err := s.store.UseDocumentDownloadLink(download); err != nil {
+		file.Close()
+		if errors.Is(err, domain.ErrValidation) {
+			return nil, nil, 0, ErrLinkUnavailable
+		}
+		return nil, nil, 0, err
+	}
+
+	logger.Get().Info("Document %d downloaded through link %d (recipient %q, created by user %d) from %s",
+		doc.ID, link.ID, link.Recipient, link.CreatedBy, ipAddress)
+	return doc, file, size, nil
+}
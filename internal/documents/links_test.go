@@ -0,0 +1,114 @@
+package documents
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// parseLinkPath splits a signed download path into the link ID, expiry and signature
+func parseLinkPath(t *testing.T, path string) (int64, string, string) {
+	t.Helper()
+	u, err := url.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := strconv.ParseInt(strings.Split(strings.TrimPrefix(u.Path, "/api/document-links/"), "/")[0], 10, 64)
+	if err != nil {
+		t.Fatalf("no link ID in %s: %v", path, err)
+	}
+	return id, u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestOpenDownloadLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		singleUse bool
+		expiresIn time.Duration
+		tamper    func(id int64, expires, signature string) (int64, string, string)
+		revoke    bool
+		rekey     bool    // Whether the secret changes after the link is created
+		want      []error // Outcome of each download in turn
+	}{
+		{"reusable", false, time.Hour, nil, false, false, []error{nil, nil}},
+		{"single use", true, time.Hour, nil, false, false, []error{nil, ErrLinkUnavailable}},
+		{"expired", false, -time.Minute, nil, false, false, []error{ErrLinkUnavailable}},
+		{"revoked", false, time.Hour, nil, true, false, []error{ErrLinkUnavailable}},
+		{"new secret", false, time.Hour, nil, false, true, []error{ErrLinkInvalid}},
+		{"bad signature", false, time.Hour, func(id int64, expires, signature string) (int64, string, string) {
+			return id, expires, strings.Repeat("0", len(signature))
+		}, false, false, []error{ErrLinkInvalid}},
+		{"extended expiry", false, -time.Minute, func(id int64, expires, signature string) (int64, string, string) {
+			return id, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10), signature
+		}, false, false, []error{ErrLinkInvalid}},
+		{"other link", false, time.Hour, func(id int64, expires, signature string) (int64, string, string) {
+			return id + 1, expires, signature
+		}, false, false, []error{ErrLinkInvalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, _ := newMemoryService(t)
+			s.SetLinkSecret([]byte("link secret"))
+			file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\nlinked\n%%EOF"))
+			doc, err := s.CreateDocumentForRequest(1, file, header, domain.DocumentTypeCV)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			link := &domain.DocumentDownloadLink{
+				DocumentID: doc.ID,
+				Recipient:  "Ministry of Education",
+				SingleUse:  tt.singleUse,
+				ExpiresAt:  time.Now().Add(tt.expiresIn),
+			}
+			if err := s.CreateDownloadLink(link); err != nil {
+				t.Fatalf("CreateDownloadLink: %v", err)
+			}
+			if tt.revoke {
+				s.RevokeDownloadLink(link.ID)
+			}
+			id, expires, signature := parseLinkPath(t, s.DownloadLinkPath(link))
+			if tt.rekey {
+				s.SetLinkSecret([]byte("rotated secret"))
+			}
+			if tt.tamper != nil {
+				id, expires, signature = tt.tamper(id, expires, signature)
+			}
+
+			for i, want := range tt.want {
+				got, content, _, err := s.OpenDownloadLink(id, expires, signature, "10.0.0.1", "curl")
+				if !errors.Is(err, want) {
+					t.Fatalf("download %d: error = %v, want %v", i+1, err, want)
+				}
+				if err != nil {
+					continue
+				}
+				data, _ := io.ReadAll(content)
+				content.Close()
+				if got.ID != doc.ID || !strings.Contains(string(data), "linked") {
+					t.Errorf("download %d returned document %d with %q", i+1, got.ID, data)
+				}
+			}
+			if len(tt.want) == 1 && tt.want[0] != nil && store.links[0].DownloadCount != 0 {
+				t.Error("refused download counted against the link")
+			}
+		})
+	}
+}
+
+func TestCreateDownloadLinkWithoutSecret(t *testing.T) {
+	s, _, _ := newMemoryService(t)
+	link := &domain.DocumentDownloadLink{DocumentID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.CreateDownloadLink(link); err == nil {
+		t.Error("CreateDownloadLink succeeded without a link secret")
+	}
+	if _, _, _, err := s.OpenDownloadLink(1, "0", "", "", ""); !errors.Is(err, ErrLinkInvalid) {
+		t.Errorf("OpenDownloadLink without a secret: error = %v, want ErrLinkInvalid", err)
+	}
+}
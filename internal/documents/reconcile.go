@@ -0,0 +1,308 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// quarantineDir holds flagged uploads and quarantined orphans; reconciliation leaves it alone
+const quarantineDir = "quarantine/"
+
+// reconcileScanner is recorded as the scanner of orphaned files moved to quarantine
+const reconcileScanner = "reconciliation"
+
+// ReconcileDocuments compares the files in storage with document records and the document references of
+// experts and expert requests. It reports files no record points at, records whose file is missing and
+// references to documents that no longer exist.
+// With repair set, records whose file is missing are pointed at an orphaned file with the same checksum,
+// and dangling references are cleared. With quarantine set, the remaining orphaned files are moved under
+// quarantine/ and recorded as quarantined uploads, where they can be reviewed and deleted.
+func (s *Service) ReconcileDocuments(repair, quarantine bool) (*domain.DocumentReconciliationReport, error) {
+	log := logger.Get()
+
+	report := &domain.DocumentReconciliationReport{
+		StartedAt:          time.Now(),
+		Repair:             repair,
+		Quarantine:         quarantine,
+		OrphanedFiles:      []domain.OrphanedFile{},
+		MissingFiles:       []domain.MissingDocumentFile{},
+		DanglingReferences: []domain.DanglingDocumentReference{},
+	}
+
+	// Records are read before files, so a document uploaded during the scan is never taken for an orphan
+	docs, err := s.store.ListAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.store.ListAllDocumentVersions()
+	if err != nil {
+		return nil, err
+	}
+	quarantined, err := s.store.ListQuarantinedUploads()
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := s.store.ListAllUploadChunks()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]int64)
+	var keys []string
+	err = s.blobs.List(func(key string, size int64) error {
+		if !strings.HasPrefix(key, quarantineDir) {
+			files[key] = size
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored files: %w", err)
+	}
+	report.FilesScanned = len(keys)
+	report.DocumentsChecked = len(docs)
+
+	referenced := make(map[string]bool)
+	for _, doc := range docs {
+		referenced[s.blobs.Key(doc.FilePath)] = true
+		if s.isMissing(files, doc.FilePath) {
+			report.MissingFiles = append(report.MissingFiles, domain.MissingDocumentFile{
+				DocumentID:   doc.ID,
+				ExpertID:     doc.ExpertID,
+				DocumentType: doc.DocumentType,
+				Filename:     doc.Filename,
+				FilePath:     doc.FilePath,
+				SHA256:       doc.SHA256,
+			})
+		}
+	}
+	documentsByID := make(map[int64]*domain.Document, len(docs))
+	for _, doc := range docs {
+		documentsByID[doc.ID] = doc
+	}
+	for _, v := range versions {
+		referenced[s.blobs.Key(v.FilePath)] = true
+		doc := documentsByID[v.DocumentID]
+		// The current version shares its file with the document, which was checked above
+		if v.IsCurrent || doc == nil || !s.isMissing(files, v.FilePath) {
+			continue
+		}
+		report.MissingFiles = append(report.MissingFiles, domain.MissingDocumentFile{
+			DocumentID:   v.DocumentID,
+			Version:      v.Version,
+			ExpertID:     doc.ExpertID,
+			DocumentType: doc.DocumentType,
+			Filename:     v.Filename,
+			FilePath:     v.FilePath,
+			SHA256:       v.SHA256,
+		})
+	}
+	for _, upload := range quarantined {
+		referenced[s.blobs.Key(upload.FilePath)] = true
+	}
+	for _, chunk := range chunks {
+		referenced[s.blobs.Key(chunk.FilePath)] = true
+	}
+
+	orphans := make(map[string]bool)
+	for _, key := range keys {
+		if !referenced[key] {
+			orphans[key] = true
+		}
+	}
+
+	if repair {
+		s.relinkMissingFiles(report, orphans)
+	}
+
+	var types []*domain.DocumentType
+	if quarantine {
+		if types, err = s.store.ListDocumentTypes(); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range keys {
+		if !orphans[key] {
+			continue
+		}
+		orphan := domain.OrphanedFile{FilePath: key, FileSize: files[key]}
+		if quarantine {
+			s.quarantineOrphan(&orphan, types)
+		}
+		report.OrphanedFiles = append(report.OrphanedFiles, orphan)
+	}
+
+	refs, err := s.store.ListDanglingDocumentReferences()
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if repair {
+			if err := s.store.ClearDocumentReference(ref); err != nil && err != domain.ErrNotFound {
+				ref.Action = domain.ReconcileFailed
+				ref.Error = err.Error()
+			} else {
+				ref.Action = domain.ReconcileCleared
+			}
+		}
+		report.DanglingReferences = append(report.DanglingReferences, ref)
+	}
+
+	log.Info("Document reconciliation: %d files, %d documents, %d orphaned files, %d missing files, %d dangling references",
+		report.FilesScanned, report.DocumentsChecked, len(report.OrphanedFiles), len(report.MissingFiles), len(report.DanglingReferences))
+	return report, nil
+}
+
+// isMissing reports whether the file at filePath is absent from the listed files
+// Listing misses files stored after it ran, so absent files are confirmed before being reported
+func (s *Service) isMissing(files map[string]int64, filePath string) bool {
+	if _, ok := files[s.blobs.Key(filePath)]; ok {
+		return false
+	}
+	_, err := s.blobs.Stat(filePath)
+	return errors.Is(err, blobstore.ErrNotFound)
+}
+
+// relinkMissingFiles points records whose file is missing at an orphaned file with the same checksum,
+// as left behind when a file was moved but its record was not updated
+// Relinked orphans are removed from orphans
+func (s *Service) relinkMissingFiles(report *domain.DocumentReconciliationReport, orphans map[string]bool) {
+	log := logger.Get()
+
+	wanted := false
+	for _, missing := range report.MissingFiles {
+		wanted = wanted || missing.SHA256 != ""
+	}
+	if !wanted || len(orphans) == 0 {
+		return
+	}
+
+	bySum := make(map[string]string)
+	for key := range orphans {
+		sum, err := s.hashFile(key)
+		if err != nil {
+			log.Warn("Failed to hash orphaned file %s: %v", key, err)
+			continue
+		}
+		bySum[sum] = key
+	}
+
+	// Records sharing a missing file are all updated by the first relink
+	relinked := make(map[string]string)
+	for i := range report.MissingFiles {
+		missing := &report.MissingFiles[i]
+		if key, done := relinked[missing.FilePath]; done {
+			missing.Action = domain.ReconcileRelinked
+			missing.RelinkedTo = key
+			continue
+		}
+		key, found := bySum[missing.SHA256]
+		if missing.SHA256 == "" || !found {
+			continue
+		}
+
+		if _, err := s.store.UpdateDocumentFilePath(missing.FilePath, key); err != nil {
+			missing.Action = domain.ReconcileFailed
+			missing.Error = err.Error()
+			continue
+		}
+		log.Info("Relinked document %d from missing file %s to %s", missing.DocumentID, missing.FilePath, key)
+		relinked[missing.FilePath] = key
+		delete(orphans, key)
+		missing.Action = domain.ReconcileRelinked
+		missing.RelinkedTo = key
+	}
+}
+
+// quarantineOrphan moves an orphaned file under quarantine/ and records it as a quarantined upload
+// types are the registered document types, used to tell what the file was uploaded as
+func (s *Service) quarantineOrphan(orphan *domain.OrphanedFile, types []*domain.DocumentType) {
+	fail := func(err error) {
+		orphan.Action = domain.ReconcileFailed
+		orphan.Error = err.Error()
+	}
+
+	// A document may have been stored at this path since the files were listed
+	count, err := s.store.CountFileReferences(orphan.FilePath, 0)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if count > 0 {
+		fail(fmt.Errorf("file is now used by %d document(s)", count))
+		return
+	}
+
+	sum, err := s.hashFile(orphan.FilePath)
+	if err != nil {
+		fail(err)
+		return
+	}
+	base := path.Base(orphan.FilePath)
+	ext := path.Ext(base)
+	timestamp := time.Now().Format("20060102_150405")
+	target := path.Join("quarantine", fmt.Sprintf("orphan_%s_%s%s", timestamp, sum[:12], ext))
+	// Orphans with identical content quarantined in the same second would otherwise share a name
+	for n := 2; ; n++ {
+		if _, err := s.blobs.Stat(target); errors.Is(err, blobstore.ErrNotFound) {
+			break
+		}
+		target = path.Join("quarantine", fmt.Sprintf("orphan_%s_%s_%d%s", timestamp, sum[:12], n, ext))
+	}
+
+	if err := s.blobs.Move(orphan.FilePath, target); err != nil {
+		fail(err)
+		return
+	}
+
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, err = s.store.CreateQuarantinedUpload(&domain.QuarantinedUpload{
+		Filename:     base,
+		FilePath:     target,
+		ContentType:  contentType,
+		FileSize:     orphan.FileSize,
+		SHA256:       sum,
+		Scanner:      reconcileScanner,
+		Signature:    "orphaned file",
+		DocumentType: orphanDocumentType(base, types),
+		Source:       orphan.FilePath,
+	})
+	if err != nil {
+		s.blobs.Move(target, orphan.FilePath) // Put the file back so it is reported again
+		fail(err)
+		return
+	}
+
+	orphan.Action = domain.ReconcileQuarantined
+	orphan.QuarantinedAs = target
+}
+
+// orphanDocumentType infers the document type of an orphaned file from the naming scheme of uploads
+// Expert documents are named after their type's code; the longest matching code wins
+func orphanDocumentType(name string, types []*domain.DocumentType) string {
+	switch {
+	case strings.HasPrefix(name, "expert_request_") && strings.Contains(name, "_approval_"):
+		return domain.DocumentTypeApproval
+	case strings.HasPrefix(name, "expert_request_"):
+		return domain.DocumentTypeCV
+	}
+
+	match := ""
+	for _, t := range types {
+		if strings.HasPrefix(name, t.Code+"_") && len(t.Code) > len(match) {
+			match = t.Code
+		}
+	}
+	return match
+}
@@ -0,0 +1,106 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestReconcileDocuments(t *testing.T) {
+	tests := []struct {
+		name               string
+		repair, quarantine bool
+		wantMissing        string // Action taken on the moved document's missing file
+		wantOrphan         string // Action taken on the unrelated orphaned file
+		wantDangling       string // Action taken on the dangling reference
+	}{
+		{"report only", false, false, "", "", ""},
+		{"repair", true, false, domain.ReconcileRelinked, "", domain.ReconcileCleared},
+		{"repair and quarantine", true, true, domain.ReconcileRelinked, domain.ReconcileQuarantined, domain.ReconcileCleared},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, blobs := newMemoryService(t)
+			upload := func(requestID int64, content string) *domain.Document {
+				file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\n"+content+"\n%%EOF"))
+				doc, err := s.CreateDocumentForRequest(requestID, file, header, domain.DocumentTypeCV)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return doc
+			}
+			upload(1, "intact")
+			moved := upload(2, "moved")
+			// The file was moved without updating its record
+			if err := blobs.Move(moved.FilePath, "experts/moved.pdf"); err != nil {
+				t.Fatal(err)
+			}
+			blobs.Put("experts/cv_7_20250101_090000.pdf", strings.NewReader("stray"), "application/pdf")
+			blobs.Put("quarantine/flagged.pdf", strings.NewReader("flagged"), "application/pdf")
+			store.requestCV[3] = 99
+
+			report, err := s.ReconcileDocuments(tt.repair, tt.quarantine)
+			if err != nil {
+				t.Fatalf("ReconcileDocuments: %v", err)
+			}
+			if report.FilesScanned != 3 || report.DocumentsChecked != 2 {
+				t.Errorf("scanned %d files and %d documents, want 3 and 2", report.FilesScanned, report.DocumentsChecked)
+			}
+
+			if len(report.MissingFiles) != 1 || report.MissingFiles[0].DocumentID != moved.ID || report.MissingFiles[0].Action != tt.wantMissing {
+				t.Errorf("missing files = %+v, want document %d %q", report.MissingFiles, moved.ID, tt.wantMissing)
+			}
+			relinked := store.documents[moved.ID].FilePath == "experts/moved.pdf"
+			if relinked != (tt.wantMissing == domain.ReconcileRelinked) {
+				t.Errorf("moved document points at %s", store.documents[moved.ID].FilePath)
+			}
+
+			// The moved file only counts as orphaned until it is relinked
+			wantOrphans := 2
+			if tt.repair {
+				wantOrphans = 1
+			}
+			if len(report.OrphanedFiles) != wantOrphans {
+				t.Fatalf("orphaned files = %+v, want %d", report.OrphanedFiles, wantOrphans)
+			}
+			stray := report.OrphanedFiles[0] // Orphans are listed by path
+			if stray.FilePath != "experts/cv_7_20250101_090000.pdf" || stray.Action != tt.wantOrphan {
+				t.Errorf("orphan = %+v, want the stray file %q", stray, tt.wantOrphan)
+			}
+			if tt.quarantine {
+				if len(store.quarantined) != 1 || store.quarantined[0].FilePath != stray.QuarantinedAs || store.quarantined[0].DocumentType != domain.DocumentTypeCV {
+					t.Errorf("quarantined uploads = %+v, want the stray CV at %s", store.quarantined, stray.QuarantinedAs)
+				}
+				if _, err := blobs.Stat(stray.FilePath); err == nil {
+					t.Error("quarantined orphan left in place")
+				}
+			}
+
+			if len(report.DanglingReferences) != 1 || report.DanglingReferences[0].Action != tt.wantDangling {
+				t.Errorf("dangling references = %+v, want one %q", report.DanglingReferences, tt.wantDangling)
+			}
+			if _, kept := store.requestCV[3]; kept == tt.repair {
+				t.Errorf("dangling reference kept %v with repair %v", kept, tt.repair)
+			}
+		})
+	}
+}
+
+func TestOrphanDocumentType(t *testing.T) {
+	types := []*domain.DocumentType{{Code: "cv"}, {Code: "certificate"}, {Code: "certificate_translation"}}
+	tests := []struct {
+		name, want string
+	}{
+		{"expert_request_4_20250101_090000.pdf", domain.DocumentTypeCV},
+		{"expert_request_4_approval_20250101_090000.pdf", domain.DocumentTypeApproval},
+		{"cv_12_20250101_090000.pdf", "cv"},
+		{"certificate_translation_12_20250101_090000.pdf", "certificate_translation"},
+		{"notes.txt", ""},
+	}
+	for _, tt := range tests {
+		if got := orphanDocumentType(tt.name, types); got != tt.want {
+			t.Errorf("orphanDocumentType(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package documents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/filescan"
+	"expertdb/internal/logger"
+)
+
+// SetScanner sets the malware scanner applied to uploads
+// Without one, uploads are only checked for their file type
+func (s *Service) SetScanner(scanner filescan.Scanner) {
+	if scanner == nil {
+		scanner = filescan.Nop{}
+	}
+	s.scanner = scanner
+}
+
+// inspectUpload checks an upload's content before it is stored
+// The content must match the declared content type, and files flagged by the malware scanner are
+// moved to quarantine and rejected. docType and source describe the upload in the quarantine record
+func (s *Service) inspectUpload(file multipart.File, header *multipart.FileHeader, contentType, docType, source string) error {
+	log := logger.Get()
+
+	detected := detectContentType(file, header.Size)
+	if detected != contentType {
+		log.Warn("Rejected upload %q for %s: declared as %s but content is %s", header.Filename, source, contentType, detected)
+		return fmt.Errorf("%w: file content does not match its declared type %s (detected %s)", domain.ErrValidation, contentType, detected)
+	}
+
+	result, err := s.scanner.Scan(io.NewSectionReader(file, 0, header.Size))
+	if err != nil {
+		return fmt.Errorf("failed to scan upload for malware: %w", err)
+	}
+	if !result.Infected {
+		return nil
+	}
+
+	log.Warn("Upload %q for %s flagged by %s: %s", header.Filename, source, s.scanner.Name(), result.Signature)
+	if err := s.quarantine(file, header, contentType, docType, source, result.Signature); err != nil {
+		log.Error("Failed to quarantine flagged upload %q: %v", header.Filename, err)
+	}
+	return fmt.Errorf("%w: file was flagged by the malware scanner (%s) and has been quarantined", domain.ErrValidation, result.Signature)
+}
+
+// quarantine stores a flagged upload under quarantine/ and records it for review
+func (s *Service) quarantine(file multipart.File, header *multipart.FileHeader, contentType, docType, source, signature string) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(file, 0, header.Size)); err != nil {
+		return fmt.Errorf("failed to read flagged upload: %w", err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	timestamp := time.Now().Format("20060102_150405")
+	filePath := path.Join("quarantine", fmt.Sprintf("%s_%s_%s%s", docType, timestamp, sum[:12], filepath.Ext(header.Filename)))
+	if err := s.blobs.Put(filePath, io.NewSectionReader(file, 0, header.Size), "application/octet-stream"); err != nil {
+		return err
+	}
+
+	_, err := s.store.CreateQuarantinedUpload(&domain.QuarantinedUpload{
+		Filename:     header.Filename,
+		FilePath:     filePath,
+		ContentType:  contentType,
+		FileSize:     header.Size,
+		SHA256:       sum,
+		Scanner:      s.scanner.Name(),
+		Signature:    signature,
+		DocumentType: docType,
+		Source:       source,
+	})
+	if err != nil {
+		s.blobs.Delete(filePath)
+		return err
+	}
+	return nil
+}
+
+// ListQuarantinedUploads returns every quarantined upload, most recent first
+func (s *Service) ListQuarantinedUploads() ([]*domain.QuarantinedUpload, error) {
+	return s.store.ListQuarantinedUploads()
+}
+
+// DeleteQuarantinedUpload permanently removes a quarantined upload and its file
+func (s *Service) DeleteQuarantinedUpload(id int64) error {
+	upload, err := s.store.GetQuarantinedUpload(id)
+	if err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(upload.FilePath); err != nil {
+		return fmt.Errorf("failed to delete quarantined file: %w", err)
+	}
+	return s.store.DeleteQuarantinedUpload(id)
+}
@@ -0,0 +1,227 @@
+package documents
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
+	"expertdb/internal/filescan"
+	"expertdb/internal/storage"
+)
+
+// eicar is the standard antivirus test string, which clamd reports as Eicar-Test-Signature
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// screeningStore provides the storage methods used when an upload is screened
+// Any other method panics through the nil embedded interface
+type screeningStore struct {
+	storage.Storage
+	quarantined []*domain.QuarantinedUpload
+	documents   int
+}
+
+func (s *screeningStore) GetDocumentType(code string) (*domain.DocumentType, error) {
+	return &domain.DocumentType{
+		Code:             code,
+		AllowedMimeTypes: []string{"application/pdf"},
+		MaxSize:          1 << 20,
+		Directory:        "experts",
+	}, nil
+}
+
+func (s *screeningStore) CountExpertDocumentsOfType(expertID int64, code string) (int, error) {
+	return 0, nil
+}
+
+func (s *screeningStore) CreateDocument(doc *domain.Document) (int64, error) {
+	s.documents++
+	return int64(s.documents), nil
+}
+
+func (s *screeningStore) CreateQuarantinedUpload(upload *domain.QuarantinedUpload) (int64, error) {
+	s.quarantined = append(s.quarantined, upload)
+	return int64(len(s.quarantined)), nil
+}
+
+// startClamd runs a minimal clamd stand-in that flags streams containing the EICAR string
+// and answers ERROR to streams containing "unscannable"
+func startClamd(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+					return
+				}
+				var data []byte
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					chunk := make([]byte, size)
+					if _, err := io.ReadFull(r, chunk); err != nil {
+						return
+					}
+					data = append(data, chunk...)
+				}
+				switch {
+				case bytes.Contains(data, []byte(eicar)):
+					io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+				case bytes.Contains(data, []byte("unscannable")):
+					io.WriteString(conn, "stream: Can't allocate memory ERROR\x00")
+				default:
+					io.WriteString(conn, "stream: OK\x00")
+				}
+			}(conn)
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+// newUpload returns content as a multipart file upload
+func newUpload(t *testing.T, filename, contentType string, content []byte) (multipart.File, *multipart.FileHeader) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := form.File["file"][0]
+	file, err := header.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file, header
+}
+
+func newScreeningService(t *testing.T) (*Service, *screeningStore, blobstore.Store) {
+	t.Helper()
+	store := &screeningStore{}
+	blobs := blobstore.NewFileSystem(t.TempDir())
+	s, err := New(store, blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner, err := filescan.NewClamAV(startClamd(t), 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetScanner(scanner)
+	return s, store, blobs
+}
+
+func TestFlaggedUploadIsQuarantined(t *testing.T) {
+	s, store, blobs := newScreeningService(t)
+	content := []byte("%PDF-1.4\n" + eicar + "\n%%EOF")
+	file, header := newUpload(t, "cv.pdf", "application/pdf", content)
+
+	_, err := s.CreateDocument(12, file, header, domain.DocumentTypeCV)
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "quarantined") {
+		t.Fatalf("CreateDocument error = %v, want a validation error reporting the quarantine", err)
+	}
+	if store.documents != 0 {
+		t.Errorf("%d documents created for a flagged upload", store.documents)
+	}
+
+	if len(store.quarantined) != 1 {
+		t.Fatalf("%d quarantine records, want 1", len(store.quarantined))
+	}
+	q := store.quarantined[0]
+	sum := sha256.Sum256(content)
+	if q.Signature != "Eicar-Test-Signature" || q.Source != "expert 12" || q.DocumentType != domain.DocumentTypeCV ||
+		q.Filename != "cv.pdf" || q.FileSize != int64(len(content)) || q.SHA256 != hex.EncodeToString(sum[:]) ||
+		!strings.HasPrefix(q.Scanner, "clamav:tcp:") {
+		t.Errorf("quarantine record = %+v", q)
+	}
+	if !strings.HasPrefix(q.FilePath, "quarantine/") || !strings.HasSuffix(q.FilePath, ".pdf") {
+		t.Errorf("quarantined file stored at %q, want quarantine/*.pdf", q.FilePath)
+	}
+
+	r, _, err := blobs.Get(q.FilePath)
+	if err != nil {
+		t.Fatalf("quarantined file not stored: %v", err)
+	}
+	defer r.Close()
+	if stored, _ := io.ReadAll(r); !bytes.Equal(stored, content) {
+		t.Error("quarantined file differs from the upload")
+	}
+}
+
+func TestCleanUploadPassesScreening(t *testing.T) {
+	s, store, _ := newScreeningService(t)
+	file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\nclean\n%%EOF"))
+
+	if err := s.inspectUpload(file, header, "application/pdf", domain.DocumentTypeCV, "expert 12"); err != nil {
+		t.Errorf("inspectUpload of a clean file: %v", err)
+	}
+	if len(store.quarantined) != 0 {
+		t.Errorf("clean upload quarantined: %+v", store.quarantined[0])
+	}
+}
+
+func TestUnscannableUploadIsRejected(t *testing.T) {
+	s, store, _ := newScreeningService(t)
+	file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\nunscannable\n%%EOF"))
+
+	err := s.inspectUpload(file, header, "application/pdf", domain.DocumentTypeCV, "expert 12")
+	if err == nil || errors.Is(err, domain.ErrValidation) {
+		t.Errorf("inspectUpload error = %v, want a scanner failure", err)
+	}
+	if len(store.quarantined) != 0 {
+		t.Error("upload quarantined although it could not be scanned")
+	}
+}
+
+func TestMislabelledUploadIsRejectedBeforeScanning(t *testing.T) {
+	s, store, _ := newScreeningService(t)
+	file, header := newUpload(t, "cv.pdf", "application/pdf", []byte(eicar))
+
+	err := s.inspectUpload(file, header, "application/pdf", domain.DocumentTypeCV, "expert 12")
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("inspectUpload error = %v, want a content type mismatch", err)
+	}
+	if len(store.quarantined) != 0 {
+		t.Error("mislabelled upload quarantined")
+	}
+}
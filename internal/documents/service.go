@@ -6,105 +6,86 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 	
+	"expertdb/internal/blobstore"
 	"expertdb/internal/domain"
+	"expertdb/internal/filescan"
 	"expertdb/internal/logger"
 	"expertdb/internal/storage"
 )
 
 // Service manages document uploads and storage
+// Files are kept in a blob store under keys such as "experts/cv_12_20250101_090000.pdf",
+// which are recorded as the document's file path. Uploads are checked against the document
+// type registry, which sets the accepted content types, size limit and directory of each type
 type Service struct {
 	store       storage.Storage
-	uploadDir   string
-	maxSize     int64
-	allowedTypes map[string]bool
+	blobs       blobstore.Store
+	scanner     filescan.Scanner
+	linkSecret  []byte // Signs download links; see SetLinkSecret
 }
 
-// New creates a new Service instance
-func New(store storage.Storage, uploadDir string) (*Service, error) {
-	// Create the upload directory if it doesn't exist
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+// New creates a new Service instance storing files in blobs
+func New(store storage.Storage, blobs blobstore.Store) (*Service, error) {
+	if blobs == nil {
+		return nil, fmt.Errorf("a blob store is required")
 	}
 
 	return &Service{
 		store:     store,
-		uploadDir: uploadDir,
-		maxSize:   10 * 1024 * 1024, // 10 MB default limit
-		allowedTypes: map[string]bool{
-			"application/pdf":                                       true,
-			"application/msword":                                    true,
-			"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-			"image/jpeg":                                            true,
-			"image/png":                                             true,
-		},
+		blobs:     blobs,
+		scanner:   filescan.Nop{},
 	}, nil
 }
 
 // CreateDocument handles core file upload and database registration only
+// Types that allow a single document per expert reject a second one; it must be uploaded as a new version.
+// A CV or approval is also linked to the expert as their current one
 func (s *Service) CreateDocument(expertID int64, file multipart.File, header *multipart.FileHeader, docType string) (*domain.Document, error) {
-	log := logger.Get()
-	log.Debug("Creating document: expertID=%d, docType='%s', filename='%s'", expertID, docType, header.Filename)
-	
-	// Validate document type
-	validTypes := map[string]bool{
-		"cv":       true,
-		"approval": true,
+	t, err := s.uploadType(docType)
+	if err != nil {
+		return nil, err
 	}
-	
-	if !validTypes[docType] {
-		return nil, fmt.Errorf("document type '%s' is not allowed; must be one of: cv, approval", docType)
+	if err := s.checkMultiplicity(expertID, t); err != nil {
+		return nil, err
 	}
-	
-	// Validate file size
-	if header.Size > s.maxSize {
-		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", s.maxSize)
+	doc, err := s.createDocument(expertID, file, header, docType, 0)
+	if err != nil || !t.IsLinked() {
+		return doc, err
 	}
 
-	// Validate content type
-	contentType := header.Header.Get("Content-Type")
-	if !s.allowedTypes[contentType] {
-		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	// An expert's single CV or approval is the one their record references
+	if err := s.LinkDocumentToExpert(expertID, doc.ID, docType); err != nil {
+		s.store.DeleteDocument(doc.ID)
+		s.removeFileIfUnused(doc.FilePath)
+		return nil, fmt.Errorf("failed to link new document: %w", err)
 	}
+	return doc, nil
+}
 
-	// Determine directory and filename based on document type
-	var targetDir, filename string
-	timestamp := time.Now().Format("20060102_150405")
-	extension := filepath.Ext(header.Filename)
-	
-	switch docType {
-	case "cv":
-		targetDir = filepath.Join(s.uploadDir, "experts")
-		filename = fmt.Sprintf("cv_%d_%s%s", expertID, timestamp, extension)
-	case "approval":
-		targetDir = filepath.Join(s.uploadDir, "approvals")
-		filename = fmt.Sprintf("approval_%d_%s%s", expertID, timestamp, extension)
-	}
+// createDocument uploads a new document, recording uploadedBy as the uploader of its first version when set
+func (s *Service) createDocument(expertID int64, file multipart.File, header *multipart.FileHeader, docType string, uploadedBy int64) (*domain.Document, error) {
+	log := logger.Get()
+	log.Debug("Creating document: expertID=%d, docType='%s', filename='%s'", expertID, docType, header.Filename)
 	
-	// Create the target directory
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	filePath := filepath.Join(targetDir, filename)
-
-	// Create and write file
-	dst, err := os.Create(filePath)
+	t, contentType, err := s.validateUpload(docType, header)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		return nil, err
+	}
+	if err := s.inspectUpload(file, header, contentType, docType, fmt.Sprintf("expert %d", expertID)); err != nil {
+		return nil, err
 	}
-	defer dst.Close()
 
-	// Copy the file data
-	_, err = io.Copy(dst, file)
+	// Write the file to the type's directory, sharing an existing copy if the content was uploaded before
+	key := expertDocumentPath(t, strconv.FormatInt(expertID, 10), filepath.Ext(header.Filename))
+	filePath, sum, err := s.storeFile(key, file, contentType)
 	if err != nil {
-		os.Remove(filePath) // Clean up on error
-		return nil, fmt.Errorf("failed to save file: %w", err)
+		return nil, err
 	}
 
 	// Create document record
@@ -116,16 +97,21 @@ func (s *Service) CreateDocument(expertID int64, file multipart.File, header *mu
 		ContentType:  contentType,
 		FileSize:     header.Size,
 		UploadDate:   time.Now(),
+		SHA256:       sum,
+	}
+	if uploadedBy > 0 {
+		doc.UploadedBy = &uploadedBy
 	}
 
 	// Store in database
 	docID, err := s.store.CreateDocument(doc)
 	if err != nil {
-		os.Remove(filePath) // Clean up on error
+		s.removeFileIfUnused(filePath) // Clean up on error
 		return nil, fmt.Errorf("failed to store document in database: %w", err)
 	}
 
 	doc.ID = docID
+	s.indexText(doc)
 	log.Debug("Document created successfully with ID: %d", docID)
 	return doc, nil
 }
@@ -164,7 +150,8 @@ func (s *Service) GetExpertDocument(expertID int64, docType string) (*domain.Doc
 	case "cv":
 		query = `
 			SELECT d.id, d.expert_id, d.document_type, d.filename, d.file_path, 
-			       d.content_type, d.file_size, d.upload_date
+			       d.content_type, d.file_size, d.upload_date, d.sha256,
+			       d.current_version, d.uploaded_by
 			FROM expert_documents d
 			INNER JOIN experts e ON d.id = e.cv_document_id
 			WHERE e.id = ? AND d.document_type = 'cv'
@@ -172,7 +159,8 @@ func (s *Service) GetExpertDocument(expertID int64, docType string) (*domain.Doc
 	case "approval":
 		query = `
 			SELECT d.id, d.expert_id, d.document_type, d.filename, d.file_path, 
-			       d.content_type, d.file_size, d.upload_date
+			       d.content_type, d.file_size, d.upload_date, d.sha256,
+			       d.current_version, d.uploaded_by
 			FROM expert_documents d
 			INNER JOIN experts e ON d.id = e.approval_document_id
 			WHERE e.id = ? AND d.document_type = 'approval'
@@ -182,11 +170,18 @@ func (s *Service) GetExpertDocument(expertID int64, docType string) (*domain.Doc
 	}
 	
 	var doc domain.Document
+	var sha256 sql.NullString
+	var uploadedBy sql.NullInt64
 	db := s.store.GetDB().(*sql.DB)
 	err := db.QueryRow(query, expertID).Scan(
 		&doc.ID, &doc.ExpertID, &doc.DocumentType, &doc.Filename, &doc.FilePath,
-		&doc.ContentType, &doc.FileSize, &doc.UploadDate,
+		&doc.ContentType, &doc.FileSize, &doc.UploadDate, &sha256,
+		&doc.CurrentVersion, &uploadedBy,
 	)
+	doc.SHA256 = sha256.String
+	if uploadedBy.Valid {
+		doc.UploadedBy = &uploadedBy.Int64
+	}
 	
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
@@ -200,19 +195,44 @@ func (s *Service) GetExpertDocument(expertID int64, docType string) (*domain.Doc
 	return &doc, nil
 }
 
-// ReplaceExpertDocument replaces an expert's document with cleanup of old document
-func (s *Service) ReplaceExpertDocument(expertID int64, file multipart.File, header *multipart.FileHeader, docType string) (*domain.Document, error) {
+// ReplaceExpertDocument replaces an expert's document
+// An existing document gets the upload as a new version, keeping earlier files in its history;
+// otherwise a new document is created and linked to the expert. A document shared with other
+// experts, such as a batch approval, is left as it is for them and the expert is linked to a new
+// one. uploadedBy and note are recorded on the version and may be empty
+func (s *Service) ReplaceExpertDocument(expertID int64, file multipart.File, header *multipart.FileHeader, docType string, uploadedBy int64, note string) (*domain.Document, error) {
 	log := logger.Get()
 	log.Debug("Replacing %s document for expert %d", docType, expertID)
 	
-	// Get current document for cleanup (if exists)
+	// Get current document (if exists)
 	oldDoc, err := s.GetExpertDocument(expertID, docType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for existing document: %w", err)
 	}
 	
+	if oldDoc != nil {
+		shared, err := s.store.CountDocumentExperts(oldDoc.ID, expertID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for experts sharing the document: %w", err)
+		}
+		if shared > 0 {
+			log.Info("%s document %d of expert %d is shared with %d other experts; linking a new document instead of adding a version",
+				docType, oldDoc.ID, expertID, shared)
+			oldDoc = nil
+		}
+	}
+	
+	if oldDoc != nil {
+		doc, err := s.AddDocumentVersion(oldDoc.ID, file, header, uploadedBy, note)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add new document version: %w", err)
+		}
+		log.Debug("Replaced %s document %d for expert %d with version %d", docType, doc.ID, expertID, doc.CurrentVersion)
+		return doc, nil
+	}
+	
 	// Create new document
-	newDoc, err := s.CreateDocument(expertID, file, header, docType)
+	newDoc, err := s.createDocument(expertID, file, header, docType, uploadedBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new document: %w", err)
 	}
@@ -221,90 +241,36 @@ func (s *Service) ReplaceExpertDocument(expertID int64, file multipart.File, hea
 	if err := s.LinkDocumentToExpert(expertID, newDoc.ID, docType); err != nil {
 		// Rollback: delete the new document we just created
 		s.store.DeleteDocument(newDoc.ID)
-		if newDoc.FilePath != "" {
-			os.Remove(newDoc.FilePath)
-		}
+		s.removeFileIfUnused(newDoc.FilePath)
 		return nil, fmt.Errorf("failed to link new document: %w", err)
 	}
 	
-	// Clean up old document if it exists
-	if oldDoc != nil {
-		log.Debug("Cleaning up old %s document with ID %d", docType, oldDoc.ID)
-		
-		// Delete from database
-		if err := s.store.DeleteDocument(oldDoc.ID); err != nil {
-			log.Warn("Failed to delete old document from database (ID: %d): %v", oldDoc.ID, err)
-			// Continue anyway - new document is already linked
-		}
-		
-		// Delete file from filesystem
-		if oldDoc.FilePath != "" {
-			if err := os.Remove(oldDoc.FilePath); err != nil {
-				log.Warn("Failed to delete old document file (%s): %v", oldDoc.FilePath, err)
-				// Continue anyway - file might already be missing
-			} else {
-				log.Debug("Deleted old document file: %s", oldDoc.FilePath)
-			}
-		}
-	}
-	
-	log.Debug("Successfully replaced %s document for expert %d (new ID: %d)", docType, expertID, newDoc.ID)
+	log.Debug("Successfully created %s document for expert %d (new ID: %d)", docType, expertID, newDoc.ID)
 	return newDoc, nil
 }
 
-
 // CreateDocumentForRequest handles document upload for expert requests using request ID
 func (s *Service) CreateDocumentForRequest(requestID int64, file multipart.File, header *multipart.FileHeader, docType string) (*domain.Document, error) {
 	log := logger.Get()
 	log.Debug("Creating %s document for expert request %d", docType, requestID)
 	
 	// Validate document type
-	if docType != "cv" && docType != "approval" {
+	if docType != domain.DocumentTypeCV && docType != domain.DocumentTypeApproval {
 		return nil, fmt.Errorf("invalid document type '%s' for request; must be 'cv' or 'approval'", docType)
 	}
 	
-	// Validate file size
-	if header.Size > s.maxSize {
-		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", s.maxSize)
-	}
-
-	// Validate content type
-	contentType := header.Header.Get("Content-Type")
-	if !s.allowedTypes[contentType] {
-		return nil, fmt.Errorf("file type %s is not allowed", contentType)
-	}
-
-	// Create expert_requests directory
-	targetDir := filepath.Join(s.uploadDir, "expert_requests")
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Generate filename for expert request
-	timestamp := time.Now().Format("20060102_150405")
-	extension := filepath.Ext(header.Filename)
-	var filename string
-	if docType == "approval" {
-		filename = fmt.Sprintf("expert_request_%d_approval_%s%s", requestID, timestamp, extension)
-	} else {
-		filename = fmt.Sprintf("expert_request_%d_%s%s", requestID, timestamp, extension)
-	}
-	filePath := filepath.Join(targetDir, filename)
-
-	// Create and write file
-	dst, err := os.Create(filePath)
+	_, contentType, err := s.validateUpload(docType, header)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		return nil, err
+	}
+	if err := s.inspectUpload(file, header, contentType, docType, fmt.Sprintf("expert request %d", requestID)); err != nil {
+		return nil, err
 	}
-	defer dst.Close()
 
-	// Copy the file data
-	bytesWritten, err := io.Copy(dst, file)
+	filePath, sum, err := s.writeRequestFile(requestID, docType, file, header, contentType)
 	if err != nil {
-		os.Remove(filePath) // Clean up on error
-		return nil, fmt.Errorf("failed to save file: %w", err)
+		return nil, err
 	}
-	log.Debug("File written successfully: %d bytes to %s", bytesWritten, filePath)
 
 	// Create document record
 	doc := &domain.Document{
@@ -315,12 +281,13 @@ func (s *Service) CreateDocumentForRequest(requestID int64, file multipart.File,
 		ContentType:  contentType,
 		FileSize:     header.Size,
 		UploadDate:   time.Now(),
+		SHA256:       sum,
 	}
 
 	// Store in database
 	docID, err := s.store.CreateDocument(doc)
 	if err != nil {
-		os.Remove(filePath) // Clean up on error
+		s.removeFileIfUnused(filePath) // Clean up on error
 		return nil, fmt.Errorf("failed to store document in database: %w", err)
 	}
 	doc.ID = docID
@@ -333,14 +300,71 @@ func (s *Service) CreateDocumentForRequest(requestID int64, file multipart.File,
 	}
 	if err != nil {
 		s.store.DeleteDocument(doc.ID)
-		os.Remove(filePath)
+		s.removeFileIfUnused(filePath)
 		return nil, fmt.Errorf("failed to update request reference: %w", err)
 	}
 
+	s.indexText(doc)
 	log.Debug("Document created and expert request updated successfully: ID %d", docID)
 	return doc, nil
 }
 
+// CreateExpertRequestWithCV creates an expert request and stores its CV as one unit of work
+// Either the request, its document record and the file all persist, or none of them do
+func (s *Service) CreateExpertRequestWithCV(req *domain.ExpertRequest, file multipart.File, header *multipart.FileHeader) (int64, *domain.Document, error) {
+	log := logger.Get()
+
+	_, contentType, err := s.validateUpload(domain.DocumentTypeCV, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.inspectUpload(file, header, contentType, "cv", "new expert request"); err != nil {
+		return 0, nil, err
+	}
+
+	doc := &domain.Document{
+		DocumentType: "cv",
+		Filename:     header.Filename,
+		ContentType:  contentType,
+		FileSize:     header.Size,
+		UploadDate:   time.Now(),
+	}
+
+	// The file name needs the request ID, so the file is written from within the transaction
+	var writtenPath string
+	requestID, err := s.store.CreateExpertRequestWithDocument(req, doc, func(requestID int64) (string, error) {
+		filePath, sum, err := s.writeRequestFile(requestID, "cv", file, header, contentType)
+		writtenPath = filePath
+		doc.SHA256 = sum
+		return filePath, err
+	})
+	if err != nil {
+		// The transaction was rolled back; don't leave the file behind as an orphan
+		// A deduplicated upload points at another document's file, which must stay
+		s.removeFileIfUnused(writtenPath)
+		return 0, nil, err
+	}
+
+	s.indexText(doc)
+	log.Debug("Expert request %d created with CV document %d", requestID, doc.ID)
+	return requestID, doc, nil
+}
+
+// writeRequestFile saves an uploaded request document under the expert_requests directory and returns its path
+// and checksum. Identical content already on file is shared rather than stored again (see storeFile)
+func (s *Service) writeRequestFile(requestID int64, docType string, file multipart.File, header *multipart.FileHeader, contentType string) (string, string, error) {
+	// Generate filename for expert request
+	timestamp := time.Now().Format("20060102_150405")
+	extension := filepath.Ext(header.Filename)
+	var filename string
+	if docType == "approval" {
+		filename = fmt.Sprintf("expert_request_%d_approval_%s%s", requestID, timestamp, extension)
+	} else {
+		filename = fmt.Sprintf("expert_request_%d_%s%s", requestID, timestamp, extension)
+	}
+	return s.storeFile(path.Join("expert_requests", filename), file, contentType)
+}
+
 // CreateDocumentForExpertRequest maintains compatibility - delegates to CreateDocumentForRequest
 func (s *Service) CreateDocumentForExpertRequest(requestID int64, file multipart.File, header *multipart.FileHeader) (*domain.Document, error) {
 	return s.CreateDocumentForRequest(requestID, file, header, "cv")
@@ -351,6 +375,25 @@ func (s *Service) CreateApprovalDocumentForExpertRequest(requestID int64, file m
 	return s.CreateDocumentForRequest(requestID, file, header, "approval")
 }
 
+// DiscardRequestDocument removes a document uploaded for an expert request whose update then failed
+// The request is pointed back at previousID (nil for none) before the document and its file go
+func (s *Service) DiscardRequestDocument(requestID int64, doc *domain.Document, previousID *int64) error {
+	var previous int64
+	if previousID != nil {
+		previous = *previousID
+	}
+	var err error
+	if doc.DocumentType == domain.DocumentTypeApproval {
+		err = s.store.UpdateExpertRequestApprovalDocument(requestID, previous)
+	} else {
+		err = s.store.UpdateExpertRequestCVDocument(requestID, previous)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore request document reference: %w", err)
+	}
+	return s.DeleteDocument(doc.ID)
+}
+
 // MoveRequestDocumentToExpert moves a document from expert_requests to expert directories during approval
 func (s *Service) MoveRequestDocumentToExpert(documentID, expertID int64) error {
 	log := logger.Get()
@@ -362,33 +405,36 @@ func (s *Service) MoveRequestDocumentToExpert(documentID, expertID int64) error
 		return fmt.Errorf("failed to get document: %w", err)
 	}
 	
-	// Generate new path and filename
-	timestamp := time.Now().Format("20060102_150405")
-	extension := filepath.Ext(doc.FilePath)
-	var newDir, newFilename string
+	// A file shared with other documents stays where it is; only the record moves to the expert
+	shared, err := s.isShared(doc)
+	if err != nil {
+		return fmt.Errorf("failed to check for shared file: %w", err)
+	}
+	if shared {
+		doc.ExpertID = expertID
+		if err := s.store.UpdateDocument(doc); err != nil {
+			return fmt.Errorf("failed to update document record: %w", err)
+		}
+		log.Debug("Document %d shares its file; reassigned to expert %d without moving %s", documentID, expertID, doc.FilePath)
+		return nil
+	}
 	
-	switch doc.DocumentType {
-	case "cv":
-		newDir = filepath.Join(s.uploadDir, "experts")
-		newFilename = fmt.Sprintf("cv_%d_%s%s", expertID, timestamp, extension)
-	case "approval":
-		newDir = filepath.Join(s.uploadDir, "approvals")
-		newFilename = fmt.Sprintf("approval_%d_%s%s", expertID, timestamp, extension)
-	default:
+	// Generate new path and filename in the directory of the document's type
+	if doc.DocumentType != domain.DocumentTypeCV && doc.DocumentType != domain.DocumentTypeApproval {
 		return fmt.Errorf("unsupported document type for migration: %s", doc.DocumentType)
 	}
-	
-	// Create target directory
-	if err := os.MkdirAll(newDir, 0755); err != nil {
-		return fmt.Errorf("failed to create target directory: %w", err)
+	t, err := s.store.GetDocumentType(doc.DocumentType)
+	if err != nil {
+		return fmt.Errorf("failed to get document type %s: %w", doc.DocumentType, err)
 	}
 	
-	newPath := filepath.Join(newDir, newFilename)
+	oldPath := doc.FilePath
+	newPath := expertDocumentPath(t, strconv.FormatInt(expertID, 10), filepath.Ext(doc.FilePath))
 	
 	// Move the file
-	err = os.Rename(doc.FilePath, newPath)
+	err = s.blobs.Move(oldPath, newPath)
 	if err != nil {
-		log.Error("Failed to move file from %s to %s: %v", doc.FilePath, newPath, err)
+		log.Error("Failed to move file from %s to %s: %v", oldPath, newPath, err)
 		return fmt.Errorf("failed to move file: %w", err)
 	}
 	
@@ -398,7 +444,7 @@ func (s *Service) MoveRequestDocumentToExpert(documentID, expertID int64) error
 	err = s.store.UpdateDocument(doc)
 	if err != nil {
 		// Try to move file back on database update failure
-		os.Rename(newPath, doc.FilePath)
+		s.blobs.Move(newPath, oldPath)
 		return fmt.Errorf("failed to update document record: %w", err)
 	}
 	
@@ -419,50 +465,26 @@ func (s *Service) CreateApprovalDocument(expertIDs []int64, file multipart.File,
 	
 	log.Debug("Creating approval document for expert IDs: %s, filename: %s", expertIDsStr, header.Filename)
 	
-	// Validate file size
-	if header.Size > s.maxSize {
-		log.Debug("Document service: File size %d exceeds maximum %d", header.Size, s.maxSize)
-		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", s.maxSize)
-	}
-
-	// Validate content type
-	contentType := header.Header.Get("Content-Type")
-	log.Debug("Document service: Validating content type: '%s'", contentType)
-	if !s.allowedTypes[contentType] {
-		log.Debug("Document service: Content type '%s' not allowed", contentType)
-		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	// Validate size and content type against the approval document type
+	t, contentType, err := s.validateUpload(domain.DocumentTypeApproval, header)
+	if err != nil {
+		log.Debug("Document service: Approval upload rejected: %v", err)
+		return nil, err
 	}
-
-	// Create approvals directory
-	targetDir := filepath.Join(s.uploadDir, "approvals")
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		log.Debug("Document service: Failed to create directory: %v", err)
-		return nil, fmt.Errorf("failed to create directory: %w", err)
+	if err := s.inspectUpload(file, header, contentType, "approval", "experts "+expertIDsStr); err != nil {
+		return nil, err
 	}
 
-	// Generate filename for approval
-	timestamp := time.Now().Format("20060102_150405")
-	extension := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("approval_%s_%s%s", expertIDsStr, timestamp, extension)
-	filePath := filepath.Join(targetDir, filename)
-	log.Debug("Document service: Full file path: %s", filePath)
+	// Generate storage key for approval
+	key := expertDocumentPath(t, expertIDsStr, filepath.Ext(header.Filename))
+	log.Debug("Document service: Storage key: %s", key)
 
-	// Create the file
-	dst, err := os.Create(filePath)
+	// Write the file, sharing an existing copy if the content was uploaded before
+	filePath, sum, err := s.storeFile(key, file, contentType)
 	if err != nil {
-		log.Debug("Document service: Failed to create file: %v", err)
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		log.Debug("Document service: Failed to save file: %v", err)
+		return nil, err
 	}
-	defer dst.Close()
-
-	// Copy the file data
-	bytesWritten, err := io.Copy(dst, file)
-	if err != nil {
-		log.Debug("Document service: Failed to copy file data: %v", err)
-		os.Remove(filePath) // Clean up on error
-		return nil, fmt.Errorf("failed to save file: %w", err)
-	}
-	log.Debug("Document service: File data copied successfully (%d bytes written)", bytesWritten)
 
 	// Create document record - use first expert ID for database record
 	doc := &domain.Document{
@@ -473,17 +495,19 @@ func (s *Service) CreateApprovalDocument(expertIDs []int64, file multipart.File,
 		ContentType:  contentType,
 		FileSize:     header.Size,
 		UploadDate:   time.Now(),
+		SHA256:       sum,
 	}
 
 	// Store in database
 	docID, err := s.store.CreateDocument(doc)
 	if err != nil {
 		log.Debug("Document service: Failed to store in database: %v", err)
-		os.Remove(filePath) // Clean up on error
+		s.removeFileIfUnused(filePath) // Clean up on error
 		return nil, fmt.Errorf("failed to store document in database: %w", err)
 	}
 
 	doc.ID = docID
+	s.indexText(doc)
 	log.Debug("Approval document created successfully - doc ID: %d, path: %s", docID, filePath)
 	return doc, nil
 }
@@ -498,24 +522,30 @@ func (s *Service) ListDocuments(expertID int64) ([]*domain.Document, error) {
 	return s.store.ListDocuments(expertID)
 }
 
-// DeleteDocument removes a document and its file
+// DeleteDocument removes a document, its versions and their files
 func (s *Service) DeleteDocument(id int64) error {
-	// Get the document first to find the file path
+	// Get the document and its versions first to find the file paths
 	doc, err := s.store.GetDocument(id)
 	if err != nil {
 		return err
 	}
+	versions, err := s.store.ListDocumentVersions(id)
+	if err != nil {
+		return err
+	}
 
 	// Delete from database first
 	if err := s.store.DeleteDocument(id); err != nil {
 		return err
 	}
 
-	// Delete the file
-	if err := os.Remove(doc.FilePath); err != nil {
-		// Log but don't fail if file is missing
-		// The database record is already deleted
-		fmt.Printf("Warning: Could not delete file %s: %v\n", doc.FilePath, err)
+	// Delete the files unless another document shares their content
+	// Failures are only logged; the database records are already deleted
+	s.removeFileIfUnused(doc.FilePath)
+	for _, v := range versions {
+		if v.FilePath != doc.FilePath {
+			s.removeFileIfUnused(v.FilePath)
+		}
 	}
 
 	return nil
@@ -530,6 +560,11 @@ func (s *Service) GetDocumentPath(documentID int64) (string, error) {
 	return doc.FilePath, nil
 }
 
+// OpenDocument opens a document's file for reading, returning its size in bytes
+func (s *Service) OpenDocument(doc *domain.Document) (io.ReadCloser, int64, error) {
+	return s.blobs.Get(doc.FilePath)
+}
+
 // GetExpertCVPath retrieves the CV file path for an expert
 func (s *Service) GetExpertCVPath(expertID int64) (string, error) {
 	expert, err := s.store.GetExpert(expertID)
@@ -571,22 +606,27 @@ func (s *Service) MoveApprovalDocumentToExpert(documentID, expertID int64) error
 	// Check if it's already properly named (in case it was already moved)
 	currentPath := doc.FilePath
 	
-	// Generate the new filename with proper expert ID
-	timestamp := time.Now().Format("20060102_150405")
-	extension := filepath.Ext(currentPath)
-	newFilename := fmt.Sprintf("approval_%d_%s%s", expertID, timestamp, extension)
-	
-	// Create target directory (approvals)
-	targetDir := filepath.Join(s.uploadDir, "approvals")
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return fmt.Errorf("failed to create approvals directory: %w", err)
+	// A file shared with other documents keeps its name, since they point at it too
+	shared, err := s.isShared(doc)
+	if err != nil {
+		return fmt.Errorf("failed to check for shared file: %w", err)
+	}
+	if shared {
+		log.Debug("Approval document %d shares its file; leaving %s in place", documentID, currentPath)
+		return nil
 	}
 	
-	newPath := filepath.Join(targetDir, newFilename)
+	// Generate the new filename with proper expert ID
+	t, err := s.store.GetDocumentType(domain.DocumentTypeApproval)
+	if err != nil {
+		return fmt.Errorf("failed to get approval document type: %w", err)
+	}
+	newPath := expertDocumentPath(t, strconv.FormatInt(expertID, 10), filepath.Ext(currentPath))
+	newFilename := path.Base(newPath)
 	
 	// Move the file if paths are different
 	if currentPath != newPath {
-		err = os.Rename(currentPath, newPath)
+		err = s.blobs.Move(currentPath, newPath)
 		if err != nil {
 			log.Error("Failed to move approval document from %s to %s: %v", currentPath, newPath, err)
 			return fmt.Errorf("failed to move file: %w", err)
@@ -597,7 +637,7 @@ func (s *Service) MoveApprovalDocumentToExpert(documentID, expertID int64) error
 		err = s.store.UpdateDocument(doc)
 		if err != nil {
 			// Try to move the file back on database update failure
-			os.Rename(newPath, currentPath)
+			s.blobs.Move(newPath, currentPath)
 			return fmt.Errorf("failed to update document path in database: %w", err)
 		}
 		
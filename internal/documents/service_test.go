@@ -0,0 +1,98 @@
+package documents
+
+import (
+	"errors"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestDiscardRequestDocument(t *testing.T) {
+	previous := int64(40)
+	tests := []struct {
+		name       string
+		docType    string
+		previousID *int64
+		want       int64 // Document the request points at afterwards; 0 for none
+	}{
+		{"replaced CV", domain.DocumentTypeCV, &previous, previous},
+		{"first CV", domain.DocumentTypeCV, nil, 0},
+		{"replaced approval", domain.DocumentTypeApproval, &previous, previous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, blobs := newMemoryService(t)
+			file, header := newUpload(t, "upload.pdf", "application/pdf", []byte("%PDF-1.4\n"+tt.name+"\n%%EOF"))
+			doc, err := s.CreateDocumentForRequest(7, file, header, tt.docType)
+			if err != nil {
+				t.Fatalf("CreateDocumentForRequest: %v", err)
+			}
+
+			if err := s.DiscardRequestDocument(7, doc, tt.previousID); err != nil {
+				t.Fatalf("DiscardRequestDocument: %v", err)
+			}
+			references := store.requestCV
+			if tt.docType == domain.DocumentTypeApproval {
+				references = store.approvals
+			}
+			if references[7] != tt.want {
+				t.Errorf("request points at document %d, want %d", references[7], tt.want)
+			}
+			if _, ok := store.documents[doc.ID]; ok {
+				t.Error("discarded document record kept")
+			}
+			if _, err := blobs.Stat(doc.FilePath); err == nil {
+				t.Errorf("discarded file %s kept", doc.FilePath)
+			}
+		})
+	}
+}
+
+func TestCreateExpertRequestWithCV(t *testing.T) {
+	content := []byte("%PDF-1.4\nCV\n%%EOF")
+	tests := []struct {
+		name      string
+		createErr error
+		duplicate bool // Whether another document already holds identical content
+	}{
+		{"created", nil, false},
+		{"request fails", errors.New("database is locked"), false},
+		{"request fails on a shared file", errors.New("database is locked"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, blobs := newMemoryService(t)
+			var existing *domain.Document
+			if tt.duplicate {
+				file, header := newUpload(t, "existing.pdf", "application/pdf", content)
+				var err error
+				if existing, err = s.CreateDocumentForRequest(1, file, header, domain.DocumentTypeCV); err != nil {
+					t.Fatal(err)
+				}
+			}
+			store.createRequestErr = tt.createErr
+
+			file, header := newUpload(t, "cv.pdf", "application/pdf", content)
+			requestID, doc, err := s.CreateExpertRequestWithCV(&domain.ExpertRequest{Name: "Amal Hasan"}, file, header)
+			if !errors.Is(err, tt.createErr) {
+				t.Fatalf("CreateExpertRequestWithCV error = %v, want %v", err, tt.createErr)
+			}
+
+			files := storedKeys(t, blobs)
+			if tt.createErr == nil {
+				if store.requestCV[requestID] != doc.ID || len(files) != 1 || doc.SHA256 == "" {
+					t.Errorf("request %d points at %d with files %v, want document %d with its file", requestID, store.requestCV[requestID], files, doc.ID)
+				}
+				return
+			}
+			// Only a file another document still uses may survive the failure
+			if tt.duplicate {
+				if len(files) != 1 || files[0] != blobs.Key(existing.FilePath) {
+					t.Errorf("files = %v, want only the shared %s", files, existing.FilePath)
+				}
+			} else if len(files) != 0 {
+				t.Errorf("files %v left behind by a failed request", files)
+			}
+		})
+	}
+}
@@ -0,0 +1,501 @@
+package documents
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
+	"expertdb/internal/storage"
+)
+
+// memoryStore keeps documents, their versions and expert request document references in memory
+// Any other storage method panics through the nil embedded interface
+type memoryStore struct {
+	storage.Storage
+	types     map[string]*domain.DocumentType
+	documents map[int64]*domain.Document
+	versions  map[int64][]*domain.DocumentVersion
+	requestCV map[int64]int64 // Expert request ID to CV document ID
+	approvals map[int64]int64 // Expert request ID to approval document ID
+	expertCV  map[int64]int64 // Expert ID to CV document ID
+	experts   map[int64]*domain.Expert
+	nextID    int64
+
+	quarantined []*domain.QuarantinedUpload
+	uploads     map[int64]*domain.UploadSession
+	chunks      map[int64][]*domain.UploadChunk // Upload ID to chunks in offset order
+	links       []*domain.DocumentDownloadLink  // Indexed by link ID - 1
+
+	// createRequestErr fails CreateExpertRequestWithDocument after the file was written
+	createRequestErr error
+}
+
+func newMemoryStore() *memoryStore {
+	newType := func(code, directory string) *domain.DocumentType {
+		return &domain.DocumentType{
+			Code:               code,
+			Name:               code,
+			AllowedMimeTypes:   []string{"application/pdf"},
+			MaxSize:            1 << 20,
+			Directory:          directory,
+			ExpertRequirement:  domain.DocumentRequired,
+			RequestRequirement: domain.DocumentOptional,
+		}
+	}
+	return &memoryStore{
+		types: map[string]*domain.DocumentType{
+			domain.DocumentTypeCV:       newType(domain.DocumentTypeCV, "experts"),
+			domain.DocumentTypeApproval: newType(domain.DocumentTypeApproval, "approvals"),
+		},
+		documents: map[int64]*domain.Document{},
+		versions:  map[int64][]*domain.DocumentVersion{},
+		requestCV: map[int64]int64{},
+		approvals: map[int64]int64{},
+		expertCV:  map[int64]int64{},
+		experts:   map[int64]*domain.Expert{},
+		uploads:   map[int64]*domain.UploadSession{},
+		chunks:    map[int64][]*domain.UploadChunk{},
+	}
+}
+
+// newMemoryService returns a service on a memory store with files kept in a temporary directory
+func newMemoryService(t *testing.T) (*Service, *memoryStore, blobstore.Store) {
+	t.Helper()
+	store := newMemoryStore()
+	blobs := blobstore.NewFileSystem(t.TempDir())
+	s, err := New(store, blobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, store, blobs
+}
+
+func (s *memoryStore) GetDocumentType(code string) (*domain.DocumentType, error) {
+	t, ok := s.types[code]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+// ListDocumentTypes returns the registered types ordered by code
+func (s *memoryStore) ListDocumentTypes() ([]*domain.DocumentType, error) {
+	types := []*domain.DocumentType{}
+	for _, t := range s.types {
+		copied := *t
+		types = append(types, &copied)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Code < types[j].Code })
+	return types, nil
+}
+
+func (s *memoryStore) CreateDocumentType(t *domain.DocumentType) error {
+	if _, ok := s.types[t.Code]; ok {
+		return fmt.Errorf("%w: document type '%s' already exists", domain.ErrValidation, t.Code)
+	}
+	stored := *t
+	s.types[t.Code] = &stored
+	return nil
+}
+
+func (s *memoryStore) SetDocumentTypeRetired(code string, retired bool) error {
+	t, ok := s.types[code]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	t.RetiredAt = nil
+	if retired {
+		now := time.Now()
+		t.RetiredAt = &now
+	}
+	return nil
+}
+
+func (s *memoryStore) CountExpertDocumentsOfType(expertID int64, code string) (int, error) {
+	count := 0
+	for _, doc := range s.documents {
+		if doc.ExpertID == expertID && doc.DocumentType == code {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *memoryStore) CreateDocument(doc *domain.Document) (int64, error) {
+	s.nextID++
+	stored := *doc
+	stored.ID = s.nextID
+	stored.CurrentVersion = 1
+	s.documents[stored.ID] = &stored
+	s.versions[stored.ID] = []*domain.DocumentVersion{documentVersion(&stored, 1)}
+	return stored.ID, nil
+}
+
+// documentVersion describes the current file of doc as its given version
+func documentVersion(doc *domain.Document, version int) *domain.DocumentVersion {
+	return &domain.DocumentVersion{
+		DocumentID:  doc.ID,
+		Version:     version,
+		Filename:    doc.Filename,
+		FilePath:    doc.FilePath,
+		ContentType: doc.ContentType,
+		FileSize:    doc.FileSize,
+		SHA256:      doc.SHA256,
+		IsCurrent:   true,
+	}
+}
+
+func (s *memoryStore) GetDocument(id int64) (*domain.Document, error) {
+	doc, ok := s.documents[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *doc
+	return &copied, nil
+}
+
+func (s *memoryStore) DeleteDocument(id int64) error {
+	if _, ok := s.documents[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(s.documents, id)
+	delete(s.versions, id)
+	return nil
+}
+
+func (s *memoryStore) ListAllDocuments() ([]*domain.Document, error) {
+	var docs []*domain.Document
+	for id := int64(1); id <= s.nextID; id++ {
+		if doc, ok := s.documents[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func (s *memoryStore) SetDocumentSHA256(id int64, sum string) error {
+	doc, ok := s.documents[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	doc.SHA256 = sum
+	return nil
+}
+
+func (s *memoryStore) ListDocumentVersions(documentID int64) ([]*domain.DocumentVersion, error) {
+	return s.versions[documentID], nil
+}
+
+func (s *memoryStore) AddDocumentVersion(version *domain.DocumentVersion) (*domain.Document, error) {
+	doc, ok := s.documents[version.DocumentID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	versions := s.versions[doc.ID]
+	for _, v := range versions {
+		v.IsCurrent = false
+	}
+	version.Version = len(versions) + 1
+	version.IsCurrent = true
+	s.versions[doc.ID] = append([]*domain.DocumentVersion{version}, versions...)
+	s.makeCurrent(doc, version)
+	return s.GetDocument(doc.ID)
+}
+
+func (s *memoryStore) GetDocumentVersion(documentID int64, version int) (*domain.DocumentVersion, error) {
+	for _, v := range s.versions[documentID] {
+		if v.Version == version {
+			copied := *v
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *memoryStore) PromoteDocumentVersion(documentID int64, version int) (*domain.Document, error) {
+	doc, ok := s.documents[documentID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	for _, v := range s.versions[documentID] {
+		v.IsCurrent = v.Version == version
+		if v.IsCurrent {
+			s.makeCurrent(doc, v)
+		}
+	}
+	return s.GetDocument(documentID)
+}
+
+// makeCurrent points doc at the file of version v
+func (s *memoryStore) makeCurrent(doc *domain.Document, v *domain.DocumentVersion) {
+	doc.Filename = v.Filename
+	doc.FilePath = v.FilePath
+	doc.ContentType = v.ContentType
+	doc.FileSize = v.FileSize
+	doc.SHA256 = v.SHA256
+	doc.CurrentVersion = v.Version
+}
+
+func (s *memoryStore) ListAllDocumentVersions() ([]*domain.DocumentVersion, error) {
+	var versions []*domain.DocumentVersion
+	for id := int64(1); id <= s.nextID; id++ {
+		versions = append(versions, s.versions[id]...)
+	}
+	return versions, nil
+}
+
+func (s *memoryStore) UpdateDocumentFilePath(oldPath, newPath string) (int64, error) {
+	var updated int64
+	for id, doc := range s.documents {
+		if doc.FilePath == oldPath {
+			doc.FilePath = newPath
+			updated++
+		}
+		for _, v := range s.versions[id] {
+			if v.FilePath == oldPath {
+				v.FilePath = newPath
+			}
+		}
+	}
+	return updated, nil
+}
+
+func (s *memoryStore) FindDocumentBySHA256(sum string) (*domain.Document, error) {
+	for _, doc := range s.documents {
+		if doc.SHA256 == sum {
+			return doc, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *memoryStore) CountFileReferences(filePath string, excludeDocumentID int64) (int, error) {
+	// Each document counts once, whether its current file or one of its versions uses the path
+	count := 0
+	for id, doc := range s.documents {
+		if id == excludeDocumentID {
+			continue
+		}
+		uses := doc.FilePath == filePath
+		for _, v := range s.versions[id] {
+			uses = uses || v.FilePath == filePath
+		}
+		if uses {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *memoryStore) UpdateExpertRequestCVDocument(requestID, documentID int64) error {
+	s.requestCV[requestID] = documentID
+	return nil
+}
+
+func (s *memoryStore) UpdateExpertRequestApprovalDocument(requestID, documentID int64) error {
+	s.approvals[requestID] = documentID
+	return nil
+}
+
+func (s *memoryStore) CreateExpertRequestWithDocument(req *domain.ExpertRequest, doc *domain.Document, writeFile func(requestID int64) (string, error)) (int64, error) {
+	requestID := int64(len(s.requestCV) + 1)
+	filePath, err := writeFile(requestID)
+	if err != nil {
+		return 0, err
+	}
+	if s.createRequestErr != nil {
+		return 0, s.createRequestErr
+	}
+	doc.ExpertID = requestID
+	doc.FilePath = filePath
+	doc.ID, _ = s.CreateDocument(doc)
+	s.requestCV[requestID] = doc.ID
+	return requestID, nil
+}
+
+func (s *memoryStore) CreateQuarantinedUpload(upload *domain.QuarantinedUpload) (int64, error) {
+	s.quarantined = append(s.quarantined, upload)
+	upload.ID = int64(len(s.quarantined))
+	return upload.ID, nil
+}
+
+func (s *memoryStore) ListQuarantinedUploads() ([]*domain.QuarantinedUpload, error) {
+	return s.quarantined, nil
+}
+
+func (s *memoryStore) GetExpert(id int64) (*domain.Expert, error) {
+	expert, ok := s.experts[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return expert, nil
+}
+
+// ListDocuments returns the documents of an expert by ID
+func (s *memoryStore) ListDocuments(expertID int64) ([]*domain.Document, error) {
+	docs := []*domain.Document{}
+	for _, doc := range s.documents {
+		if doc.ExpertID == expertID {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+func (s *memoryStore) UpdateExpertCVDocument(expertID, documentID int64) error {
+	s.expertCV[expertID] = documentID
+	return nil
+}
+
+func (s *memoryStore) CreateUploadSession(upload *domain.UploadSession) (int64, error) {
+	s.nextID++
+	upload.ID = s.nextID
+	upload.Received = 0
+	upload.Status = domain.UploadStatusUploading
+	stored := *upload
+	s.uploads[upload.ID] = &stored
+	return upload.ID, nil
+}
+
+func (s *memoryStore) GetUploadSession(id int64) (*domain.UploadSession, error) {
+	upload, ok := s.uploads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *upload
+	return &copied, nil
+}
+
+// AddUploadChunk records a chunk only at the upload's received offset, like the SQLite store
+func (s *memoryStore) AddUploadChunk(chunk *domain.UploadChunk) (bool, error) {
+	upload, ok := s.uploads[chunk.UploadID]
+	if !ok || upload.Received != chunk.Offset || upload.Status != domain.UploadStatusUploading {
+		return false, nil
+	}
+	upload.Received += chunk.Size
+	s.chunks[chunk.UploadID] = append(s.chunks[chunk.UploadID], chunk)
+	return true, nil
+}
+
+func (s *memoryStore) ListUploadChunks(uploadID int64) ([]*domain.UploadChunk, error) {
+	return s.chunks[uploadID], nil
+}
+
+func (s *memoryStore) ListAllUploadChunks() ([]*domain.UploadChunk, error) {
+	ids := make([]int64, 0, len(s.chunks))
+	for id := range s.chunks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var chunks []*domain.UploadChunk
+	for _, id := range ids {
+		chunks = append(chunks, s.chunks[id]...)
+	}
+	return chunks, nil
+}
+
+func (s *memoryStore) CompleteUploadSession(id int64) error {
+	upload, ok := s.uploads[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	upload.Status = domain.UploadStatusComplete
+	return nil
+}
+
+func (s *memoryStore) DeleteUploadSession(id int64) error {
+	if _, ok := s.uploads[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(s.uploads, id)
+	delete(s.chunks, id)
+	return nil
+}
+
+func (s *memoryStore) ListExpiredUploadSessions(now time.Time) ([]*domain.UploadSession, error) {
+	var expired []*domain.UploadSession
+	for _, upload := range s.uploads {
+		if upload.ExpiresAt.Before(now) {
+			expired = append(expired, upload)
+		}
+	}
+	return expired, nil
+}
+
+// ListDanglingDocumentReferences reports the CV references of expert requests to missing documents
+func (s *memoryStore) ListDanglingDocumentReferences() ([]domain.DanglingDocumentReference, error) {
+	refs := []domain.DanglingDocumentReference{}
+	for requestID, documentID := range s.requestCV {
+		if _, ok := s.documents[documentID]; documentID != 0 && !ok {
+			refs = append(refs, domain.DanglingDocumentReference{
+				Owner: "expert_request", OwnerID: requestID, Field: "cv_document_id", DocumentID: documentID,
+			})
+		}
+	}
+	return refs, nil
+}
+
+func (s *memoryStore) ClearDocumentReference(ref domain.DanglingDocumentReference) error {
+	if s.requestCV[ref.OwnerID] != ref.DocumentID {
+		return domain.ErrNotFound
+	}
+	delete(s.requestCV, ref.OwnerID)
+	return nil
+}
+
+func (s *memoryStore) CreateDocumentDownloadLink(link *domain.DocumentDownloadLink) (int64, error) {
+	stored := *link
+	s.links = append(s.links, &stored)
+	stored.ID = int64(len(s.links))
+	link.ID = stored.ID
+	return link.ID, nil
+}
+
+func (s *memoryStore) GetDocumentDownloadLink(id int64) (*domain.DocumentDownloadLink, error) {
+	if id < 1 || id > int64(len(s.links)) {
+		return nil, domain.ErrNotFound
+	}
+	copied := *s.links[id-1]
+	return &copied, nil
+}
+
+func (s *memoryStore) RevokeDocumentDownloadLink(id int64) error {
+	if id < 1 || id > int64(len(s.links)) {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	s.links[id-1].RevokedAt = &now
+	return nil
+}
+
+func (s *memoryStore) UseDocumentDownloadLink(download *domain.DocumentDownload) error {
+	link := s.links[download.LinkID-1]
+	if !link.Usable(time.Now()) {
+		return fmt.Errorf("%w: download link is no longer valid", domain.ErrValidation)
+	}
+	link.DownloadCount++
+	return nil
+}
+
+func (s *memoryStore) SetDocumentText(id int64, text string) error {
+	return nil
+}
+
+// storedKeys lists the keys of every file in blobs
+func storedKeys(t *testing.T, blobs blobstore.Store) []string {
+	t.Helper()
+	var keys []string
+	err := blobs.List(func(key string, size int64) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
@@ -0,0 +1,145 @@
+package documents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+	"expertdb/internal/textextract"
+)
+
+// maxExtractedText caps the stored text of one document, in bytes
+const maxExtractedText = 1 << 20
+
+// errTextSource marks failures to read a document's file, as opposed to failures to parse it
+var errTextSource = errors.New("failed to read document file")
+
+// extractText returns the plain text of a document's file
+// Read failures wrap errTextSource; unsupported types return textextract.ErrUnsupported
+func (s *Service) extractText(doc *domain.Document) (string, error) {
+	if !textextract.Supports(doc.ContentType) {
+		return "", textextract.ErrUnsupported
+	}
+
+	f, _, err := s.blobs.Get(doc.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errTextSource, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errTextSource, err)
+	}
+
+	text, err := textextract.Extract(bytes.NewReader(data), int64(len(data)), doc.ContentType)
+	if err != nil {
+		return "", err
+	}
+	if len(text) > maxExtractedText {
+		text = text[:maxExtractedText]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+	return text, nil
+}
+
+// indexText extracts and stores the text of a newly stored document
+// Failures are logged rather than returned: the upload itself succeeded, and documents whose file
+// could not be read are picked up again by the backfill
+func (s *Service) indexText(doc *domain.Document) {
+	log := logger.Get()
+
+	text, err := s.extractText(doc)
+	if err != nil && errors.Is(err, errTextSource) {
+		log.Warn("Failed to extract text from document %d, leaving it for the backfill: %v", doc.ID, err)
+		return
+	}
+	if err != nil && err != textextract.ErrUnsupported {
+		log.Warn("No text extracted from document %d (%s): %v", doc.ID, doc.Filename, err)
+	}
+
+	if err := s.store.SetDocumentText(doc.ID, text); err != nil {
+		log.Warn("Failed to store text of document %d: %v", doc.ID, err)
+		return
+	}
+	log.Debug("Extracted %d bytes of text from document %d", len(text), doc.ID)
+}
+
+// GetDocumentText returns the text extracted from a document, empty when there is none
+func (s *Service) GetDocumentText(id int64) (string, error) {
+	return s.store.GetDocumentText(id)
+}
+
+// ExtractDocumentText extracts text from documents that have not been processed yet
+// With force set, every document is processed again
+func (s *Service) ExtractDocumentText(force bool) (*domain.DocumentTextExtractionReport, error) {
+	log := logger.Get()
+
+	var docs []*domain.Document
+	var err error
+	if force {
+		docs, err = s.store.ListAllDocuments()
+	} else {
+		docs, err = s.store.ListDocumentsWithoutText()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DocumentTextExtractionReport{
+		StartedAt: time.Now(),
+		Failures:  []domain.DocumentTextExtractionFailure{},
+	}
+
+	// Deduplicated documents share a file, which only needs extracting once
+	type extraction struct {
+		text string
+		err  error
+	}
+	byPath := map[string]extraction{}
+
+	for _, doc := range docs {
+		report.Total++
+
+		result, seen := byPath[doc.FilePath]
+		if !seen {
+			result.text, result.err = s.extractText(doc)
+			byPath[doc.FilePath] = result
+		}
+
+		switch {
+		case result.err == textextract.ErrUnsupported:
+			report.Unsupported++
+		case result.err != nil:
+			report.Failed++
+			report.Failures = append(report.Failures, domain.DocumentTextExtractionFailure{
+				DocumentID: doc.ID,
+				Filename:   doc.Filename,
+				Error:      result.err.Error(),
+			})
+			if errors.Is(result.err, errTextSource) {
+				continue // Retried on the next run
+			}
+		case result.text == "":
+			report.Empty++
+		default:
+			report.Extracted++
+		}
+
+		if err := s.store.SetDocumentText(doc.ID, result.text); err != nil {
+			return nil, fmt.Errorf("failed to store text of document %d: %w", doc.ID, err)
+		}
+	}
+
+	if report.Total > 0 {
+		log.Info("Document text extraction: %d processed, %d with text, %d empty, %d unsupported, %d failed",
+			report.Total, report.Extracted, report.Empty, report.Unsupported, report.Failed)
+	}
+	return report, nil
+}
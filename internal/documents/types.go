@@ -0,0 +1,268 @@
+package documents
+
+import (
+	"fmt"
+	"mime/multipart"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Storage directories the registry cannot assign, as they hold files the service manages itself
+var reservedDirectories = []string{"quarantine", "expert_requests", uploadsDir}
+
+var (
+	documentTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+	directoryPattern        = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*$`)
+)
+
+// ValidateDocumentType checks a document type's settings, returning one message per problem
+func ValidateDocumentType(t *domain.DocumentType) []string {
+	var problems []string
+
+	if !documentTypeCodePattern.MatchString(t.Code) {
+		problems = append(problems, "code must start with a lowercase letter and contain only lowercase letters, digits and underscores (at most 32)")
+	}
+	if strings.HasPrefix(t.Code, "expert_request") {
+		problems = append(problems, "code must not start with expert_request, which names request uploads")
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if len(t.AllowedMimeTypes) == 0 {
+		problems = append(problems, "allowedMimeTypes must list at least one content type")
+	}
+	for _, mimeType := range t.AllowedMimeTypes {
+		major, minor, ok := strings.Cut(mimeType, "/")
+		if !ok || major == "" || minor == "" || strings.ContainsAny(mimeType, " ,;") {
+			problems = append(problems, fmt.Sprintf("allowedMimeTypes: '%s' is not a content type such as application/pdf", mimeType))
+		}
+	}
+	if t.MaxSize <= 0 {
+		problems = append(problems, "maxSize must be a positive number of bytes")
+	}
+	if len(t.Directory) > 64 || !directoryPattern.MatchString(t.Directory) {
+		problems = append(problems, "directory must be a relative path of lowercase letters, digits, underscores and hyphens")
+	}
+	for _, reserved := range reservedDirectories {
+		if t.Directory == reserved || strings.HasPrefix(t.Directory, reserved+"/") {
+			problems = append(problems, fmt.Sprintf("directory %s is reserved", reserved))
+		}
+	}
+	if !validRequirement(t.ExpertRequirement) {
+		problems = append(problems, "expertRequirement must be one of: required, optional, none")
+	}
+	if !validRequirement(t.RequestRequirement) {
+		problems = append(problems, "requestRequirement must be one of: required, optional, none")
+	} else if t.RequestRequirement != domain.DocumentNotUsed && !t.IsLinked() {
+		problems = append(problems, "requestRequirement must be none, as expert requests only hold cv and approval documents")
+	}
+	if t.Multiple && t.IsLinked() {
+		problems = append(problems, "multiple cannot be set for cv and approval, which experts reference one document of")
+	}
+
+	return problems
+}
+
+// validRequirement reports whether requirement is a document requirement level
+func validRequirement(requirement string) bool {
+	switch requirement {
+	case domain.DocumentRequired, domain.DocumentOptional, domain.DocumentNotUsed:
+		return true
+	}
+	return false
+}
+
+// normalizeDocumentType trims a document type's fields and fills in defaults for omitted ones
+func normalizeDocumentType(t *domain.DocumentType) {
+	t.Code = strings.TrimSpace(t.Code)
+	t.Name = strings.TrimSpace(t.Name)
+	t.Description = strings.TrimSpace(t.Description)
+	t.Directory = strings.Trim(strings.TrimSpace(t.Directory), "/")
+	for i, mimeType := range t.AllowedMimeTypes {
+		t.AllowedMimeTypes[i] = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	if t.ExpertRequirement == "" {
+		t.ExpertRequirement = domain.DocumentOptional
+	}
+	if t.RequestRequirement == "" {
+		t.RequestRequirement = domain.DocumentNotUsed
+	}
+}
+
+// ListDocumentTypes returns the registered document types, optionally including retired ones
+func (s *Service) ListDocumentTypes(includeRetired bool) ([]*domain.DocumentType, error) {
+	types, err := s.store.ListDocumentTypes()
+	if err != nil {
+		return nil, err
+	}
+	if includeRetired {
+		return types, nil
+	}
+
+	active := []*domain.DocumentType{}
+	for _, t := range types {
+		if t.RetiredAt == nil {
+			active = append(active, t)
+		}
+	}
+	return active, nil
+}
+
+// GetDocumentType retrieves a registered document type by code
+func (s *Service) GetDocumentType(code string) (*domain.DocumentType, error) {
+	return s.store.GetDocumentType(code)
+}
+
+// CreateDocumentType registers a new document type
+func (s *Service) CreateDocumentType(t *domain.DocumentType) error {
+	normalizeDocumentType(t)
+	if errs := ValidateDocumentType(t); len(errs) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrValidation, strings.Join(errs, "; "))
+	}
+	if err := s.store.CreateDocumentType(t); err != nil {
+		return err
+	}
+
+	logger.Get().Info("Document type %s registered (directory %s, max %d bytes)", t.Code, t.Directory, t.MaxSize)
+	return nil
+}
+
+// UpdateDocumentType saves new settings for a registered document type
+// Changes apply to later uploads; stored files keep their directory
+func (s *Service) UpdateDocumentType(t *domain.DocumentType) error {
+	normalizeDocumentType(t)
+	if errs := ValidateDocumentType(t); len(errs) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrValidation, strings.Join(errs, "; "))
+	}
+	if err := s.store.UpdateDocumentType(t); err != nil {
+		return err
+	}
+
+	logger.Get().Info("Document type %s updated", t.Code)
+	return nil
+}
+
+// SetDocumentTypeRetired retires a document type, so it accepts no new uploads, or restores it
+func (s *Service) SetDocumentTypeRetired(code string, retired bool) error {
+	t, err := s.store.GetDocumentType(code)
+	if err != nil {
+		return err
+	}
+	if retired && t.IsLinked() {
+		return fmt.Errorf("%w: document type %s is referenced by experts and expert requests and cannot be retired", domain.ErrValidation, code)
+	}
+	if err := s.store.SetDocumentTypeRetired(code, retired); err != nil {
+		return err
+	}
+
+	logger.Get().Info("Document type %s retired: %t", code, retired)
+	return nil
+}
+
+// uploadType returns the registered document type an upload is made as, rejecting unknown and retired types
+func (s *Service) uploadType(code string) (*domain.DocumentType, error) {
+	t, err := s.store.GetDocumentType(code)
+	if err != nil && err != domain.ErrNotFound {
+		return nil, fmt.Errorf("failed to look up document type: %w", err)
+	}
+	if err == nil && t.RetiredAt == nil {
+		return t, nil
+	}
+
+	var codes []string
+	if types, err := s.ListDocumentTypes(false); err == nil {
+		for _, active := range types {
+			codes = append(codes, active.Code)
+		}
+	}
+	return nil, fmt.Errorf("%w: document type '%s' is not allowed; must be one of: %s", domain.ErrValidation, code, strings.Join(codes, ", "))
+}
+
+// validateUpload checks an upload against the size and content types of its document type
+// It returns the type and the upload's content type
+func (s *Service) validateUpload(docType string, header *multipart.FileHeader) (*domain.DocumentType, string, error) {
+	t, err := s.uploadType(docType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Validate file size
+	if header.Size > t.MaxSize {
+		return nil, "", fmt.Errorf("%w: file size exceeds maximum allowed size of %d bytes for %s documents", domain.ErrValidation, t.MaxSize, t.Code)
+	}
+
+	// Validate content type
+	contentType := header.Header.Get("Content-Type")
+	if !t.Allows(contentType) {
+		return nil, "", fmt.Errorf("%w: file type %s is not allowed for %s documents", domain.ErrValidation, contentType, t.Code)
+	}
+
+	return t, contentType, nil
+}
+
+// checkMultiplicity rejects a new document of a single-document type for an expert who already has one
+func (s *Service) checkMultiplicity(expertID int64, t *domain.DocumentType) error {
+	if t.Multiple {
+		return nil
+	}
+	count, err := s.store.CountExpertDocumentsOfType(expertID, t.Code)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: expert %d already has a document of type %s; upload a new version of it instead", domain.ErrValidation, expertID, t.Code)
+	}
+	return nil
+}
+
+// expertDocumentPath returns the storage key of a new expert document of type t, named after owner
+// (an expert ID, or the IDs of several experts sharing an approval)
+func expertDocumentPath(t *domain.DocumentType, owner, extension string) string {
+	timestamp := time.Now().Format("20060102_150405")
+	return path.Join(t.Directory, fmt.Sprintf("%s_%s_%s%s", t.Code, owner, timestamp, extension))
+}
+
+// MissingExpertDocuments returns the codes of required document types an expert has no document of
+func (s *Service) MissingExpertDocuments(expertID int64, docs []*domain.Document) ([]string, error) {
+	types, err := s.ListDocumentTypes(false)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make(map[string]bool)
+	for _, doc := range docs {
+		held[doc.DocumentType] = true
+	}
+	missing := []string{}
+	for _, t := range types {
+		if t.ExpertRequirement == domain.DocumentRequired && !held[t.Code] {
+			missing = append(missing, t.Code)
+		}
+	}
+	return missing, nil
+}
+
+// CheckRequestDocuments returns a validation error naming the first document type required for expert
+// requests that the request has no document of
+func (s *Service) CheckRequestDocuments(request *domain.ExpertRequest) error {
+	types, err := s.ListDocumentTypes(false)
+	if err != nil {
+		return fmt.Errorf("failed to load document types: %w", err)
+	}
+
+	for _, t := range types {
+		if t.RequestRequirement != domain.DocumentRequired {
+			continue
+		}
+		if (t.Code == domain.DocumentTypeCV && request.CVDocumentID == nil) ||
+			(t.Code == domain.DocumentTypeApproval && request.ApprovalDocumentID == nil) {
+			return fmt.Errorf("%w: %s document is required before approving a request", domain.ErrValidation, t.Code)
+		}
+	}
+	return nil
+}
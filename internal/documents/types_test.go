@@ -0,0 +1,181 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestValidateDocumentType(t *testing.T) {
+	valid := func() *domain.DocumentType {
+		return &domain.DocumentType{
+			Code:               "certificate",
+			Name:               "Certificate",
+			AllowedMimeTypes:   []string{"application/pdf"},
+			MaxSize:            1 << 20,
+			Directory:          "certificates",
+			ExpertRequirement:  domain.DocumentOptional,
+			RequestRequirement: domain.DocumentNotUsed,
+			Multiple:           true,
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(*domain.DocumentType)
+		want   string // Part of the single expected problem; empty for a valid type
+	}{
+		{"valid", func(*domain.DocumentType) {}, ""},
+		{"nested directory", func(d *domain.DocumentType) { d.Directory = "experts/certificates" }, ""},
+		{"uppercase code", func(d *domain.DocumentType) { d.Code = "Certificate" }, "code must start"},
+		{"request code", func(d *domain.DocumentType) { d.Code = "expert_request_cv" }, "must not start with expert_request"},
+		{"no name", func(d *domain.DocumentType) { d.Name = " " }, "name is required"},
+		{"no content types", func(d *domain.DocumentType) { d.AllowedMimeTypes = nil }, "at least one content type"},
+		{"bad content type", func(d *domain.DocumentType) { d.AllowedMimeTypes = []string{"pdf"} }, "'pdf' is not a content type"},
+		{"zero size", func(d *domain.DocumentType) { d.MaxSize = 0 }, "maxSize"},
+		{"absolute directory", func(d *domain.DocumentType) { d.Directory = "/etc" }, "directory must be a relative path"},
+		{"parent directory", func(d *domain.DocumentType) { d.Directory = "../experts" }, "directory must be a relative path"},
+		{"reserved directory", func(d *domain.DocumentType) { d.Directory = "quarantine/certificates" }, "directory quarantine is reserved"},
+		{"bad requirement", func(d *domain.DocumentType) { d.ExpertRequirement = "always" }, "expertRequirement"},
+		{"request document", func(d *domain.DocumentType) { d.RequestRequirement = domain.DocumentOptional }, "requestRequirement must be none"},
+		{"multiple CVs", func(d *domain.DocumentType) { d.Code = domain.DocumentTypeCV }, "multiple cannot be set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.change(d)
+			problems := ValidateDocumentType(d)
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Errorf("problems = %q, want none", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Errorf("problems = %q, want one containing %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadType(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	if err := store.SetDocumentTypeRetired(domain.DocumentTypeApproval, true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		code    string
+		wantErr bool
+	}{
+		{domain.DocumentTypeCV, false},
+		{domain.DocumentTypeApproval, true}, // Retired
+		{"passport", true},
+	}
+	for _, tt := range tests {
+		docType, err := svc.uploadType(tt.code)
+		if tt.wantErr {
+			if !errors.Is(err, domain.ErrValidation) || !strings.HasSuffix(err.Error(), "must be one of: cv") {
+				t.Errorf("uploadType(%s) error = %v, want a validation error listing cv", tt.code, err)
+			}
+			continue
+		}
+		if err != nil || docType.Code != tt.code {
+			t.Errorf("uploadType(%s) = %v, %v", tt.code, docType, err)
+		}
+	}
+}
+
+func TestCheckMultiplicity(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	store.documents[1] = &domain.Document{ID: 1, ExpertID: 7, DocumentType: domain.DocumentTypeCV}
+
+	tests := []struct {
+		expertID int64
+		multiple bool
+		wantErr  bool
+	}{
+		{7, false, true},
+		{7, true, false},
+		{8, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("expert %d multiple %v", tt.expertID, tt.multiple), func(t *testing.T) {
+			err := svc.checkMultiplicity(tt.expertID, &domain.DocumentType{Code: domain.DocumentTypeCV, Multiple: tt.multiple})
+			if tt.wantErr != errors.Is(err, domain.ErrValidation) || (!tt.wantErr && err != nil) {
+				t.Errorf("checkMultiplicity = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetDocumentTypeRetired(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	if err := svc.CreateDocumentType(&domain.DocumentType{
+		Code: "certificate", Name: "Certificate", AllowedMimeTypes: []string{" Application/PDF "},
+		MaxSize: 1 << 20, Directory: "/certificates/", Multiple: true,
+	}); err != nil {
+		t.Fatalf("CreateDocumentType: %v", err)
+	}
+	if created := store.types["certificate"]; created.AllowedMimeTypes[0] != "application/pdf" || created.Directory != "certificates" ||
+		created.ExpertRequirement != domain.DocumentOptional || created.RequestRequirement != domain.DocumentNotUsed {
+		t.Errorf("created type = %+v, want it normalized with default requirements", created)
+	}
+
+	tests := []struct {
+		code    string
+		retired bool
+		wantErr error
+	}{
+		{"certificate", true, nil},
+		{"certificate", false, nil},
+		{domain.DocumentTypeCV, true, domain.ErrValidation},
+		{domain.DocumentTypeApproval, true, domain.ErrValidation},
+		{domain.DocumentTypeApproval, false, nil},
+		{"passport", true, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		err := svc.SetDocumentTypeRetired(tt.code, tt.retired)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("SetDocumentTypeRetired(%s, %v) = %v, want %v", tt.code, tt.retired, err, tt.wantErr)
+		}
+	}
+	for _, code := range []string{"certificate", domain.DocumentTypeCV} {
+		if store.types[code].RetiredAt != nil {
+			t.Errorf("%s is retired, want it active", code)
+		}
+	}
+}
+
+func TestMissingExpertDocuments(t *testing.T) {
+	svc, store, _ := newMemoryService(t)
+	store.types["certificate"] = &domain.DocumentType{Code: "certificate", ExpertRequirement: domain.DocumentRequired}
+	store.types["publication"] = &domain.DocumentType{Code: "publication", ExpertRequirement: domain.DocumentOptional}
+	if err := store.SetDocumentTypeRetired("certificate", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		held []string
+		want string
+	}{
+		{nil, "[approval cv]"},
+		{[]string{domain.DocumentTypeCV, "publication"}, "[approval]"},
+		{[]string{domain.DocumentTypeCV, domain.DocumentTypeApproval}, "[]"}, // Retired types are not required
+	}
+	for _, tt := range tests {
+		var docs []*domain.Document
+		for _, code := range tt.held {
+			docs = append(docs, &domain.Document{DocumentType: code})
+		}
+		missing, err := svc.MissingExpertDocuments(1, docs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(missing) != tt.want {
+			t.Errorf("MissingExpertDocuments holding %v = %v, want %s", tt.held, missing, tt.want)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package documents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// Limits of resumable uploads
+const (
+	// MaxUploadChunkSize is the largest chunk accepted in one request
+	MaxUploadChunkSize = 8 << 20
+	// uploadLifetime is how long an upload may take, and stay unused once complete, before it is discarded
+	uploadLifetime = 24 * time.Hour
+	// uploadsDir holds the chunks of uploads in progress
+	uploadsDir = "uploads"
+)
+
+// ErrUploadOffset is returned for a chunk that does not start where the received bytes end
+// The client resumes from the upload's received count
+var ErrUploadOffset = errors.New("chunk offset does not match the bytes received")
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// StartUpload begins a resumable upload of a file of a registered document type
+// The declared size and content type are checked against the type up front, so a file that would be
+// rejected is not sent first
+func (s *Service) StartUpload(upload *domain.UploadSession) error {
+	upload.Filename = strings.TrimSpace(upload.Filename)
+	upload.ContentType = strings.ToLower(strings.TrimSpace(upload.ContentType))
+	upload.SHA256 = strings.ToLower(strings.TrimSpace(upload.SHA256))
+
+	t, err := s.uploadType(upload.DocumentType)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	if upload.Filename == "" || strings.ContainsAny(upload.Filename, `/\`) {
+		problems = append(problems, "filename is required and must not contain a path")
+	}
+	if upload.FileSize <= 0 {
+		problems = append(problems, "fileSize must be a positive number of bytes")
+	} else if upload.FileSize > t.MaxSize {
+		problems = append(problems, fmt.Sprintf("fileSize exceeds maximum allowed size of %d bytes for %s documents", t.MaxSize, t.Code))
+	}
+	if !t.Allows(upload.ContentType) {
+		problems = append(problems, fmt.Sprintf("file type %s is not allowed for %s documents", upload.ContentType, t.Code))
+	}
+	if !sha256Pattern.MatchString(upload.SHA256) {
+		problems = append(problems, "sha256 must be the hex SHA-256 checksum of the file")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrValidation, strings.Join(problems, "; "))
+	}
+
+	upload.ExpiresAt = time.Now().Add(uploadLifetime)
+	if _, err := s.store.CreateUploadSession(upload); err != nil {
+		return err
+	}
+	upload.ChunkSize = MaxUploadChunkSize
+
+	logger.Get().Info("Upload %d started by user %d: %q (%s, %d bytes)", upload.ID, upload.CreatedBy, upload.Filename, upload.DocumentType, upload.FileSize)
+	return nil
+}
+
+// GetUpload returns an upload session of userID
+// Uploads of other users are reported as not found
+func (s *Service) GetUpload(id, userID int64) (*domain.UploadSession, error) {
+	upload, err := s.store.GetUploadSession(id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.CreatedBy != userID {
+		return nil, domain.ErrNotFound
+	}
+	upload.ChunkSize = MaxUploadChunkSize
+	return upload, nil
+}
+
+// AddUploadChunk stores the chunk read from body at offset of an upload and returns the updated session
+// A chunk at the wrong offset returns ErrUploadOffset along with the session, whose Received count is
+// where the client resumes
+func (s *Service) AddUploadChunk(id, userID, offset int64, body io.Reader) (*domain.UploadSession, error) {
+	upload, err := s.GetUpload(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != domain.UploadStatusUploading {
+		return nil, fmt.Errorf("%w: upload %d is already complete", domain.ErrValidation, id)
+	}
+	if offset != upload.Received {
+		return upload, ErrUploadOffset
+	}
+
+	// Read one byte beyond the limit to tell an oversized chunk from one that fits exactly
+	limit := min(upload.FileSize-upload.Received, MaxUploadChunkSize)
+	counted := &countingReader{r: io.LimitReader(body, limit+1)}
+	filePath := path.Join(uploadsDir, fmt.Sprintf("%d", id), fmt.Sprintf("%012d_%d", offset, time.Now().UnixNano()))
+	if err := s.blobs.Put(filePath, counted, "application/octet-stream"); err != nil {
+		return nil, fmt.Errorf("failed to store upload chunk: %w", err)
+	}
+	switch {
+	case counted.n == 0:
+		s.blobs.Delete(filePath)
+		return nil, fmt.Errorf("%w: chunk is empty", domain.ErrValidation)
+	case counted.n > limit:
+		s.blobs.Delete(filePath)
+		return nil, fmt.Errorf("%w: chunk is larger than %d bytes, the most accepted at offset %d", domain.ErrValidation, limit, offset)
+	}
+
+	added, err := s.store.AddUploadChunk(&domain.UploadChunk{UploadID: id, Offset: offset, Size: counted.n, FilePath: filePath})
+	if err != nil || !added {
+		s.blobs.Delete(filePath)
+		if err != nil {
+			return nil, err
+		}
+		// Another request for the same offset got there first
+		if upload, err = s.GetUpload(id, userID); err != nil {
+			return nil, err
+		}
+		return upload, ErrUploadOffset
+	}
+
+	return s.GetUpload(id, userID)
+}
+
+// CompleteUpload verifies that every byte of an upload has arrived and matches the declared checksum
+// An upload whose content does not match is discarded, as it cannot be repaired by resending chunks
+func (s *Service) CompleteUpload(id, userID int64) (*domain.UploadSession, error) {
+	log := logger.Get()
+
+	upload, err := s.GetUpload(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == domain.UploadStatusComplete {
+		return upload, nil
+	}
+	if upload.Received != upload.FileSize {
+		return nil, fmt.Errorf("%w: upload %d has received %d of %d bytes", domain.ErrValidation, id, upload.Received, upload.FileSize)
+	}
+
+	chunks, err := s.store.ListUploadChunks(id)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, &chunkReader{service: s, chunks: chunks}); err != nil {
+		return nil, fmt.Errorf("failed to read upload chunks: %w", err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != upload.SHA256 {
+		log.Warn("Upload %d discarded: received content has checksum %s, declared %s", id, sum, upload.SHA256)
+		if err := s.DiscardUpload(id); err != nil {
+			log.Warn("Failed to discard upload %d: %v", id, err)
+		}
+		return nil, fmt.Errorf("%w: checksum of the received file (%s) does not match the declared checksum. The upload was discarded and must be started again", domain.ErrValidation, sum)
+	}
+
+	if err := s.store.CompleteUploadSession(id); err != nil {
+		return nil, err
+	}
+	log.Info("Upload %d complete: %q (%d bytes in %d chunks)", id, upload.Filename, upload.FileSize, len(chunks))
+	return s.GetUpload(id, userID)
+}
+
+// AssembledUpload is a complete upload reassembled into a temporary file, usable wherever a multipart file is
+type AssembledUpload struct {
+	*domain.UploadSession
+	File   *os.File
+	Header *multipart.FileHeader
+}
+
+// Close removes the assembled file
+func (u *AssembledUpload) Close() error {
+	u.File.Close()
+	return os.Remove(u.File.Name())
+}
+
+// OpenUpload assembles a complete upload of userID so it can be stored like a multipart file
+// docType, when set, is the document type the upload must have been made as. The upload remains until it
+// is discarded, so a failed attempt to use it can be retried
+func (s *Service) OpenUpload(id, userID int64, docType string) (*AssembledUpload, error) {
+	upload, err := s.GetUpload(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != domain.UploadStatusComplete {
+		return nil, fmt.Errorf("%w: upload %d is not complete", domain.ErrValidation, id)
+	}
+	if docType != "" && upload.DocumentType != docType {
+		return nil, fmt.Errorf("%w: upload %d was made for %s documents, not %s", domain.ErrValidation, id, upload.DocumentType, docType)
+	}
+
+	chunks, err := s.store.ListUploadChunks(id)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp("", "expertdb-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file for upload %d: %w", id, err)
+	}
+	assembled := &AssembledUpload{
+		UploadSession: upload,
+		File:          file,
+		Header: &multipart.FileHeader{
+			Filename: upload.Filename,
+			Header:   textproto.MIMEHeader{"Content-Type": {upload.ContentType}},
+			Size:     upload.FileSize,
+		},
+	}
+
+	// The chunks were verified on completion; check again in case a stored chunk changed since
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, h), &chunkReader{service: s, chunks: chunks}); err != nil {
+		assembled.Close()
+		return nil, fmt.Errorf("failed to assemble upload %d: %w", id, err)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != upload.SHA256 {
+		assembled.Close()
+		return nil, fmt.Errorf("upload %d no longer matches its checksum", id)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		assembled.Close()
+		return nil, err
+	}
+	return assembled, nil
+}
+
+// CreateDocumentFromUpload stores a complete upload as a new document of an expert, then discards the upload
+// The document gets the upload's type and goes through the same checks as a multipart upload
+func (s *Service) CreateDocumentFromUpload(expertID, uploadID, userID int64) (*domain.Document, error) {
+	upload, err := s.OpenUpload(uploadID, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	defer upload.Close()
+
+	doc, err := s.CreateDocument(expertID, upload.File, upload.Header, upload.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DiscardUpload(uploadID); err != nil {
+		logger.Get().Warn("Failed to discard upload %d after storing it as document %d: %v", uploadID, doc.ID, err)
+	}
+	return doc, nil
+}
+
+// CancelUpload discards an upload of userID
+func (s *Service) CancelUpload(id, userID int64) error {
+	if _, err := s.GetUpload(id, userID); err != nil {
+		return err
+	}
+	return s.DiscardUpload(id)
+}
+
+// DiscardUpload removes an upload session and its chunk files
+// Chunk files that cannot be deleted are left for reconciliation, which reports them as orphans
+func (s *Service) DiscardUpload(id int64) error {
+	chunks, err := s.store.ListUploadChunks(id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteUploadSession(id); err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err := s.blobs.Delete(chunk.FilePath); err != nil {
+			logger.Get().Warn("Failed to delete chunk %s of upload %d: %v", chunk.FilePath, id, err)
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredUploads discards uploads that were not completed and used in time, returning how many
+func (s *Service) PurgeExpiredUploads() (int, error) {
+	expired, err := s.store.ListExpiredUploadSessions(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, upload := range expired {
+		if err := s.DiscardUpload(upload.ID); err != nil {
+			logger.Get().Warn("Failed to discard expired upload %d: %v", upload.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// chunkReader reads the stored chunks of an upload one after another, opening each only when it is reached
+type chunkReader struct {
+	service *Service
+	chunks  []*domain.UploadChunk
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			f, _, err := r.service.blobs.Get(r.chunks[0].FilePath)
+			if err != nil {
+				return 0, err
+			}
+			r.current = f
+			r.chunks = r.chunks[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		} else if err != nil {
+			r.current.Close()
+			r.current = nil
+		}
+		return n, err
+	}
+}
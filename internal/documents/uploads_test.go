@@ -0,0 +1,217 @@
+package documents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// startTestUpload starts an upload of content as a CV by user 1
+func startTestUpload(t *testing.T, svc *Service, content string) *domain.UploadSession {
+	t.Helper()
+	sum := sha256.Sum256([]byte(content))
+	upload := &domain.UploadSession{
+		DocumentType: domain.DocumentTypeCV,
+		Filename:     "cv.pdf",
+		ContentType:  "application/pdf",
+		FileSize:     int64(len(content)),
+		SHA256:       hex.EncodeToString(sum[:]),
+		CreatedBy:    1,
+	}
+	if err := svc.StartUpload(upload); err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+	return upload
+}
+
+// sendChunks sends content to an upload in chunks of at most size bytes
+func sendChunks(t *testing.T, svc *Service, id int64, content string, size int) {
+	t.Helper()
+	for offset := 0; offset < len(content); offset += size {
+		end := min(offset+size, len(content))
+		if _, err := svc.AddUploadChunk(id, 1, int64(offset), strings.NewReader(content[offset:end])); err != nil {
+			t.Fatalf("chunk at %d: %v", offset, err)
+		}
+	}
+}
+
+func TestStartUpload(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*domain.UploadSession)
+		wantErr string // Part of the validation error; empty when the upload starts
+	}{
+		{"valid", func(*domain.UploadSession) {}, ""},
+		{"normalized", func(u *domain.UploadSession) {
+			u.ContentType = " Application/PDF"
+			u.SHA256 = strings.ToUpper(u.SHA256)
+		}, ""},
+		{"unknown type", func(u *domain.UploadSession) { u.DocumentType = "passport" }, "document type 'passport' is not allowed"},
+		{"path in filename", func(u *domain.UploadSession) { u.Filename = "../cv.pdf" }, "must not contain a path"},
+		{"empty file", func(u *domain.UploadSession) { u.FileSize = 0 }, "fileSize must be a positive"},
+		{"too large", func(u *domain.UploadSession) { u.FileSize = 1<<20 + 1 }, "exceeds maximum allowed size"},
+		{"content type", func(u *domain.UploadSession) { u.ContentType = "image/gif" }, "file type image/gif is not allowed"},
+		{"checksum", func(u *domain.UploadSession) { u.SHA256 = "abc" }, "sha256 must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, _ := newMemoryService(t)
+			upload := &domain.UploadSession{
+				DocumentType: domain.DocumentTypeCV, Filename: " cv.pdf ", ContentType: "application/pdf",
+				FileSize: 100, SHA256: strings.Repeat("ab", 32), CreatedBy: 1,
+			}
+			tt.change(upload)
+			err := svc.StartUpload(upload)
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("StartUpload error = %v, want a validation error containing %q", err, tt.wantErr)
+				}
+				if len(store.uploads) != 0 {
+					t.Error("rejected upload was recorded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StartUpload: %v", err)
+			}
+			if upload.Filename != "cv.pdf" || upload.ContentType != "application/pdf" || upload.SHA256 != strings.Repeat("ab", 32) ||
+				upload.ChunkSize != MaxUploadChunkSize || time.Until(upload.ExpiresAt) < uploadLifetime-time.Minute {
+				t.Errorf("upload = %+v", upload)
+			}
+		})
+	}
+}
+
+func TestAddUploadChunk(t *testing.T) {
+	svc, _, blobs := newMemoryService(t)
+	upload := startTestUpload(t, svc, "0123456789")
+
+	steps := []struct {
+		name     string
+		offset   int64
+		chunk    string
+		wantErr  error
+		received int64
+	}{
+		{"first chunk", 0, "0123", nil, 4},
+		{"retried chunk", 0, "0123", ErrUploadOffset, 4},
+		{"gap", 6, "6", ErrUploadOffset, 4},
+		{"empty chunk", 4, "", domain.ErrValidation, 4},
+		{"past the declared size", 4, "4567890", domain.ErrValidation, 4},
+		{"last chunk", 4, "456789", nil, 10},
+		{"after the last chunk", 10, "0", domain.ErrValidation, 10},
+	}
+	for _, step := range steps {
+		got, err := svc.AddUploadChunk(upload.ID, 1, step.offset, strings.NewReader(step.chunk))
+		if !errors.Is(err, step.wantErr) || (step.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", step.name, err, step.wantErr)
+			continue
+		}
+		// Offset mismatches return the session, so the client knows where to resume
+		if got != nil && got.Received != step.received {
+			t.Errorf("%s: received %d, want %d", step.name, got.Received, step.received)
+		}
+		if current, _ := svc.GetUpload(upload.ID, 1); current.Received != step.received {
+			t.Errorf("%s: stored received count %d, want %d", step.name, current.Received, step.received)
+		}
+	}
+
+	if keys := storedKeys(t, blobs); len(keys) != 2 {
+		t.Errorf("stored chunk files = %v, want the 2 accepted chunks", keys)
+	}
+	if _, err := svc.AddUploadChunk(upload.ID, 2, 10, strings.NewReader("0")); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("chunk from another user: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCompleteUpload(t *testing.T) {
+	content := "%PDF-1.4\nchunked\n%%EOF"
+	tests := []struct {
+		name    string
+		send    string // Content actually sent, which may differ from the declared one
+		wantErr string
+		kept    bool // Whether the upload survives a failed completion
+	}{
+		{"matching checksum", content, "", true},
+		{"incomplete", content[:10], "has received 10 of", true},
+		{"different content", strings.Replace(content, "chunked", "CHUNKED", 1), "does not match the declared checksum", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, blobs := newMemoryService(t)
+			upload := startTestUpload(t, svc, content)
+			sendChunks(t, svc, upload.ID, tt.send, 7)
+
+			completed, err := svc.CompleteUpload(upload.ID, 1)
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CompleteUpload error = %v, want a validation error containing %q", err, tt.wantErr)
+				}
+				_, err := svc.GetUpload(upload.ID, 1)
+				if kept := err == nil; kept != tt.kept {
+					t.Errorf("upload kept = %v, want %v", kept, tt.kept)
+				}
+				if !tt.kept && len(storedKeys(t, blobs)) != 0 {
+					t.Errorf("chunk files left behind: %v", storedKeys(t, blobs))
+				}
+				return
+			}
+			if err != nil || completed.Status != domain.UploadStatusComplete {
+				t.Fatalf("CompleteUpload = %+v, %v", completed, err)
+			}
+			if _, err := svc.AddUploadChunk(upload.ID, 1, completed.Received, strings.NewReader("x")); !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("chunk after completion: error = %v, want a validation error", err)
+			}
+			if _, err := svc.OpenUpload(upload.ID, 1, domain.DocumentTypeApproval); !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("opening as another document type: error = %v, want a validation error", err)
+			}
+
+			doc, err := svc.CreateDocumentFromUpload(7, upload.ID, 1)
+			if err != nil {
+				t.Fatalf("CreateDocumentFromUpload: %v", err)
+			}
+			r, _, err := blobs.Get(doc.FilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			if stored, _ := io.ReadAll(r); string(stored) != content || doc.FileSize != int64(len(content)) {
+				t.Errorf("document %+v holds %q, want the assembled upload", doc, stored)
+			}
+			if store.expertCV[7] != doc.ID {
+				t.Errorf("expert CV = %d, want document %d", store.expertCV[7], doc.ID)
+			}
+			if len(store.uploads) != 0 || len(storedKeys(t, blobs)) != 1 {
+				t.Errorf("upload not discarded: sessions %d, files %v", len(store.uploads), storedKeys(t, blobs))
+			}
+		})
+	}
+}
+
+func TestPurgeExpiredUploads(t *testing.T) {
+	svc, store, blobs := newMemoryService(t)
+	expired := startTestUpload(t, svc, "expired")
+	sendChunks(t, svc, expired.ID, "expired", 3)
+	active := startTestUpload(t, svc, "active")
+	store.uploads[expired.ID].ExpiresAt = time.Now().Add(-time.Minute)
+
+	purged, err := svc.PurgeExpiredUploads()
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeExpiredUploads = %d, %v, want 1", purged, err)
+	}
+	if _, err := svc.GetUpload(expired.ID, 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expired upload: error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.GetUpload(active.ID, 1); err != nil {
+		t.Errorf("active upload: %v", err)
+	}
+	if keys := storedKeys(t, blobs); len(keys) != 0 {
+		t.Errorf("chunk files left behind: %v", keys)
+	}
+}
@@ -0,0 +1,119 @@
+package documents
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// AddDocumentVersion uploads a new file for an existing document and makes it the current version
+// Earlier versions and their files are kept. uploadedBy and note are optional
+func (s *Service) AddDocumentVersion(documentID int64, file multipart.File, header *multipart.FileHeader, uploadedBy int64, note string) (*domain.Document, error) {
+	log := logger.Get()
+
+	doc, err := s.store.GetDocument(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, contentType, err := s.validateUpload(doc.DocumentType, header)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.inspectUpload(file, header, contentType, doc.DocumentType, fmt.Sprintf("document %d", documentID)); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.store.ListDocumentVersions(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document versions: %w", err)
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[0].Version + 1
+	}
+
+	// New versions sit next to the current file, named with their version number so that
+	// replacements within the same second don't overwrite each other
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("%s_%d_v%d_%s%s", doc.DocumentType, doc.ExpertID, next, timestamp, filepath.Ext(header.Filename))
+	filePath, sum, err := s.storeFile(path.Join(path.Dir(filepath.ToSlash(doc.FilePath)), filename), file, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	version := &domain.DocumentVersion{
+		DocumentID:  documentID,
+		Filename:    header.Filename,
+		FilePath:    filePath,
+		ContentType: contentType,
+		FileSize:    header.Size,
+		SHA256:      sum,
+		UploadedAt:  time.Now(),
+		Note:        note,
+	}
+	if uploadedBy > 0 {
+		version.UploadedBy = &uploadedBy
+	}
+
+	updated, err := s.store.AddDocumentVersion(version)
+	if err != nil {
+		s.removeFileIfUnused(filePath) // Clean up on error
+		return nil, fmt.Errorf("failed to store document version: %w", err)
+	}
+
+	s.indexText(updated)
+	log.Debug("Document %d now at version %d: %s", documentID, version.Version, filePath)
+	return updated, nil
+}
+
+// ListDocumentVersions returns every version of a document, newest first
+func (s *Service) ListDocumentVersions(documentID int64) ([]*domain.DocumentVersion, error) {
+	if _, err := s.store.GetDocument(documentID); err != nil {
+		return nil, err
+	}
+	return s.store.ListDocumentVersions(documentID)
+}
+
+// GetDocumentVersion returns one version of a document
+func (s *Service) GetDocumentVersion(documentID int64, version int) (*domain.DocumentVersion, error) {
+	return s.store.GetDocumentVersion(documentID, version)
+}
+
+// PromoteDocumentVersion makes an earlier version the document's current file again
+func (s *Service) PromoteDocumentVersion(documentID int64, version int) (*domain.Document, error) {
+	log := logger.Get()
+
+	v, err := s.store.GetDocumentVersion(documentID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v.IsCurrent {
+		return nil, fmt.Errorf("%w: version %d is already the current version", domain.ErrValidation, version)
+	}
+
+	// Refuse to promote a version whose file is gone rather than leave the document pointing at nothing
+	if _, err := s.blobs.Stat(v.FilePath); err != nil {
+		return nil, fmt.Errorf("%w: the file of version %d is not available: %v", domain.ErrValidation, version, err)
+	}
+
+	doc, err := s.store.PromoteDocumentVersion(documentID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	s.indexText(doc)
+	log.Info("Document %d promoted to version %d", documentID, version)
+	return doc, nil
+}
+
+// OpenDocumentVersion opens the file of a document version for reading, returning its size in bytes
+func (s *Service) OpenDocumentVersion(v *domain.DocumentVersion) (io.ReadCloser, int64, error) {
+	return s.blobs.Get(v.FilePath)
+}
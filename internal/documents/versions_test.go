@@ -0,0 +1,72 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestDocumentVersions(t *testing.T) {
+	s, store, blobs := newMemoryService(t)
+	contents := []string{"first", "second", "third"}
+	file, header := newUpload(t, "cv.pdf", "application/pdf", []byte("%PDF-1.4\n"+contents[0]+"\n%%EOF"))
+	doc, err := s.CreateDocumentForRequest(1, file, header, domain.DocumentTypeCV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{doc.FilePath}
+	for i, content := range contents[1:] {
+		file, header := newUpload(t, fmt.Sprintf("cv_v%d.pdf", i+2), "application/pdf", []byte("%PDF-1.4\n"+content+"\n%%EOF"))
+		updated, err := s.AddDocumentVersion(doc.ID, file, header, 9, "Updated CV")
+		if err != nil {
+			t.Fatalf("AddDocumentVersion: %v", err)
+		}
+		if updated.CurrentVersion != i+2 {
+			t.Errorf("current version = %d, want %d", updated.CurrentVersion, i+2)
+		}
+		paths = append(paths, updated.FilePath)
+	}
+	if files := storedKeys(t, blobs); len(files) != 3 {
+		t.Fatalf("files = %v, want every version kept", files)
+	}
+
+	// Version 1's file disappears, so only version 2 can be promoted
+	blobs.Delete(paths[0])
+	tests := []struct {
+		version int
+		wantErr error
+	}{
+		{3, domain.ErrValidation}, // Already current
+		{1, domain.ErrValidation}, // File gone
+		{4, domain.ErrNotFound},
+		{2, nil},
+	}
+	for _, tt := range tests {
+		promoted, err := s.PromoteDocumentVersion(doc.ID, tt.version)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("PromoteDocumentVersion(%d) error = %v, want %v", tt.version, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (promoted.CurrentVersion != tt.version || promoted.FilePath != paths[tt.version-1]) {
+			t.Errorf("promoted document = version %d at %s, want version %d", promoted.CurrentVersion, promoted.FilePath, tt.version)
+		}
+	}
+
+	versions, err := s.ListDocumentVersions(doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 || versions[0].Version != 3 || !versions[1].IsCurrent {
+		t.Errorf("versions = %+v, want 3 newest first with version 2 current", versions)
+	}
+
+	// Deleting the document removes the files of every version
+	if err := s.DeleteDocument(doc.ID); err != nil {
+		t.Fatal(err)
+	}
+	if files := storedKeys(t, blobs); len(files) != 0 || len(store.versions) != 0 {
+		t.Errorf("files %v left after deleting the document", files)
+	}
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Engagement statuses
+const (
+	EngagementStatusPending   = "pending"   // Starts on its start date
+	EngagementStatusActive    = "active"    // Under way
+	EngagementStatusCompleted = "completed" // Finished (final)
+	EngagementStatusCancelled = "cancelled" // Called off (final)
+)
+
+// EngagementStatuses lists every valid engagement status
+var EngagementStatuses = []string{
+	EngagementStatusPending, EngagementStatusActive, EngagementStatusCompleted, EngagementStatusCancelled,
+}
+
+// engagementTransitions maps each status to the statuses it may move to
+// Completed and cancelled engagements are final and have no outgoing transitions
+var engagementTransitions = map[string][]string{
+	EngagementStatusPending: {EngagementStatusActive, EngagementStatusCancelled},
+	EngagementStatusActive:  {EngagementStatusCompleted, EngagementStatusCancelled},
+}
+
+// IsValidEngagementStatus reports whether status is a known engagement status
+func IsValidEngagementStatus(status string) bool {
+	for _, s := range EngagementStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEngagementTransition checks that an engagement may move from one status to another
+// Keeping the current status is always allowed, even one outside the list. Engagements holding such
+// a status, recorded before statuses were checked, may move to any valid status
+func ValidateEngagementTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	if !IsValidEngagementStatus(to) {
+		return fmt.Errorf("%w: invalid status '%s'", ErrValidation, to)
+	}
+	if !IsValidEngagementStatus(from) {
+		return nil
+	}
+
+	for _, allowed := range engagementTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: an engagement cannot move from '%s' to '%s'", ErrValidation, from, to)
+}
+
+// EngagementEnded reports whether an engagement's end date has passed at now
+// An end date without a time of day (midnight) covers that whole day
+func EngagementEnded(end, now time.Time) bool {
+	if end.IsZero() {
+		return false
+	}
+	if end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0 && end.Nanosecond() == 0 {
+		end = end.AddDate(0, 0, 1)
+	}
+	return !now.Before(end)
+}
+
+// DueEngagementStatus returns the status an engagement moves to on its own at now: pending engagements
+// become active on their start date and active ones complete once their end date has passed
+func DueEngagementStatus(e *Engagement, now time.Time) (string, bool) {
+	switch e.Status {
+	case EngagementStatusPending:
+		if !now.Before(e.StartDate) {
+			return EngagementStatusActive, true
+		}
+	case EngagementStatusActive:
+		if EngagementEnded(e.EndDate, now) {
+			return EngagementStatusCompleted, true
+		}
+	}
+	return "", false
+}
+
+// EngagementOverdue reports whether an active engagement without an end date has run for longer than
+// overdueAfter at now
+func EngagementOverdue(e *Engagement, now time.Time, overdueAfter time.Duration) bool {
+	return e.Status == EngagementStatusActive && e.EndDate.IsZero() && now.Sub(e.StartDate) > overdueAfter
+}
+
+// EngagementLifecycleReport counts the changes made by one run of the engagement scheduler
+type EngagementLifecycleReport struct {
+	Activated int `json:"activated"` // Pending engagements whose start date arrived
+	Completed int `json:"completed"` // Active engagements whose end date passed
+	Overdue   int `json:"overdue"`   // Active engagements newly flagged for running without an end date
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateEngagementTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{EngagementStatusPending, EngagementStatusActive, true},
+		{EngagementStatusPending, EngagementStatusCancelled, true},
+		{EngagementStatusPending, EngagementStatusCompleted, false},
+		{EngagementStatusActive, EngagementStatusCompleted, true},
+		{EngagementStatusActive, EngagementStatusPending, false},
+		{EngagementStatusCompleted, EngagementStatusActive, false},
+		{EngagementStatusCancelled, EngagementStatusPending, false},
+		{EngagementStatusCompleted, EngagementStatusCompleted, true},
+		{EngagementStatusActive, "confirmed", false},
+		// Legacy statuses may be kept or left for any valid status
+		{"confirmed", "confirmed", true},
+		{"confirmed", EngagementStatusCompleted, true},
+		{"confirmed", "in_progress", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := ValidateEngagementTransition(tt.from, tt.to)
+			if tt.valid && err != nil {
+				t.Errorf("ValidateEngagementTransition = %v, want it allowed", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrValidation) {
+				t.Errorf("ValidateEngagementTransition = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestDueEngagementStatus(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status string
+		start  time.Time
+		end    time.Time
+		want   string // Empty when nothing is due
+	}{
+		{"pending before its start", EngagementStatusPending, now.Add(time.Hour), time.Time{}, ""},
+		{"pending on its start", EngagementStatusPending, now, time.Time{}, EngagementStatusActive},
+		{"active ending today", EngagementStatusActive, today.AddDate(0, 0, -5), today, ""},
+		{"active ended yesterday", EngagementStatusActive, today.AddDate(0, 0, -5), today.AddDate(0, 0, -1), EngagementStatusCompleted},
+		{"active ended an hour ago", EngagementStatusActive, today.AddDate(0, 0, -5), now.Add(-time.Hour), EngagementStatusCompleted},
+		{"active without an end", EngagementStatusActive, today.AddDate(-1, 0, 0), time.Time{}, ""},
+		{"completed", EngagementStatusCompleted, today.AddDate(0, 0, -5), today.AddDate(0, 0, -1), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, due := DueEngagementStatus(&Engagement{Status: tt.status, StartDate: tt.start, EndDate: tt.end}, now)
+			if status != tt.want || due != (tt.want != "") {
+				t.Errorf("DueEngagementStatus = %q, %v, want %q", status, due, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngagementOverdue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		e       Engagement
+		overdue bool
+	}{
+		{"long running", Engagement{Status: EngagementStatusActive, StartDate: now.AddDate(0, 0, -100)}, true},
+		{"recent", Engagement{Status: EngagementStatusActive, StartDate: now.AddDate(0, 0, -10)}, false},
+		{"with an end date", Engagement{Status: EngagementStatusActive, StartDate: now.AddDate(0, 0, -100), EndDate: now.AddDate(0, 0, 1)}, false},
+		{"pending", Engagement{Status: EngagementStatusPending, StartDate: now.AddDate(0, 0, -100)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EngagementOverdue(&tt.e, now, 90*24*time.Hour); got != tt.overdue {
+				t.Errorf("EngagementOverdue = %v, want %v", got, tt.overdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Expert request workflow statuses
+const (
+	RequestStatusDraft        = "draft"         // Saved by the requester but not yet submitted
+	RequestStatusSubmitted    = "submitted"     // Waiting for a reviewer
+	RequestStatusUnderReview  = "under_review"  // Picked up by a reviewer
+	RequestStatusNeedsChanges = "needs_changes" // Returned to the requester for corrections
+	RequestStatusApproved     = "approved"      // Expert created from the request (final)
+	RequestStatusRejected     = "rejected"      // Declined; the requester may correct and resubmit
+	RequestStatusWithdrawn    = "withdrawn"     // Withdrawn by the requester (final)
+)
+
+// ExpertRequestApprovalPolicy controls how many reviewers must approve a request before the expert is created
+// Submitters can never approve their own requests
+type ExpertRequestApprovalPolicy struct {
+	RequiredApprovals    int  // Number of distinct reviewers that must approve
+	RequireDistinctRoles bool // Each approving reviewer must hold a different role (e.g. one admin and one super user)
+}
+
+// OpenExpertRequestStatuses are the statuses in which a request is waiting on someone
+var OpenExpertRequestStatuses = []string{RequestStatusSubmitted, RequestStatusUnderReview, RequestStatusNeedsChanges}
+
+// ExpertRequestSLA holds the review target for each status; a request breaches it after spending longer
+// than the target in that status. Statuses without a target never breach
+type ExpertRequestSLA map[string]time.Duration
+
+// DefaultExpertRequestSLA gives reviewers 3 days to pick up a request and a week to decide,
+// and requesters 2 weeks to make requested changes
+var DefaultExpertRequestSLA = ExpertRequestSLA{
+	RequestStatusSubmitted:    72 * time.Hour,
+	RequestStatusUnderReview:  168 * time.Hour,
+	RequestStatusNeedsChanges: 336 * time.Hour,
+}
+
+// DueAt returns when a request that entered status at since reaches its review target
+func (sla ExpertRequestSLA) DueAt(status string, since time.Time) (time.Time, bool) {
+	target, ok := sla[status]
+	if !ok || target <= 0 {
+		return time.Time{}, false
+	}
+	return since.Add(target), true
+}
+
+// Expert request batch actions
+const (
+	ExpertRequestBatchReject   = "reject"   // Reject requests awaiting review (reviewers)
+	ExpertRequestBatchWithdraw = "withdraw" // Withdraw open requests (their owners)
+	ExpertRequestBatchReassign = "reassign" // Hand requests awaiting review to another reviewer (reviewers)
+)
+
+// ExpertRequestBatch is one action applied to several expert requests
+// With AllOrNothing, a failure on any request leaves every request unchanged; otherwise
+// each request is applied on its own and failures don't affect the others
+type ExpertRequestBatch struct {
+	Action       string                   // See ExpertRequestBatch* constants
+	Items        []ExpertRequestBatchItem // Requests to act on
+	ReviewerID   int64                    // New reviewer for reassign
+	AllOrNothing bool                     // Apply every item or none
+}
+
+// ExpertRequestBatchItem is one request in a batch, with the reason recorded for it
+type ExpertRequestBatchItem struct {
+	ID     int64  `json:"id"`               // Expert request ID
+	Reason string `json:"reason,omitempty"` // Rejection, withdrawal or reassignment reason
+}
+
+// Parties allowed to perform an expert request transition
+const (
+	requestActorOwner    = "owner"    // The user who created the request
+	requestActorReviewer = "reviewer" // An admin or super user
+)
+
+// expertRequestTransitions maps each status to the statuses it may move to and who may move it there
+// Approved and withdrawn requests are final and have no outgoing transitions
+var expertRequestTransitions = map[string]map[string]string{
+	RequestStatusDraft: {
+		RequestStatusSubmitted: requestActorOwner,
+		RequestStatusWithdrawn: requestActorOwner,
+	},
+	RequestStatusSubmitted: {
+		RequestStatusUnderReview:  requestActorReviewer,
+		RequestStatusNeedsChanges: requestActorReviewer,
+		RequestStatusApproved:     requestActorReviewer,
+		RequestStatusRejected:     requestActorReviewer,
+		RequestStatusWithdrawn:    requestActorOwner,
+	},
+	RequestStatusUnderReview: {
+		RequestStatusNeedsChanges: requestActorReviewer,
+		RequestStatusApproved:     requestActorReviewer,
+		RequestStatusRejected:     requestActorReviewer,
+		RequestStatusWithdrawn:    requestActorOwner,
+	},
+	RequestStatusNeedsChanges: {
+		RequestStatusSubmitted: requestActorOwner,
+		RequestStatusWithdrawn: requestActorOwner,
+	},
+	RequestStatusRejected: {
+		RequestStatusSubmitted: requestActorOwner,
+	},
+}
+
+// ExpertRequestStatuses lists every valid expert request status
+var ExpertRequestStatuses = []string{
+	RequestStatusDraft, RequestStatusSubmitted, RequestStatusUnderReview, RequestStatusNeedsChanges,
+	RequestStatusApproved, RequestStatusRejected, RequestStatusWithdrawn,
+}
+
+// IsValidExpertRequestStatus reports whether status is a known expert request status
+func IsValidExpertRequestStatus(status string) bool {
+	for _, s := range ExpertRequestStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateExpertRequestTransition checks that a request may move from one status to another
+// isOwner is true for the request's creator and isReviewer for admins and super users
+// Returns ErrForbidden if the transition exists but the user may not perform it
+func ValidateExpertRequestTransition(from, to string, isOwner, isReviewer bool) error {
+	if !IsValidExpertRequestStatus(to) {
+		return fmt.Errorf("%w: invalid status '%s'", ErrValidation, to)
+	}
+
+	actor, ok := expertRequestTransitions[from][to]
+	if !ok {
+		return fmt.Errorf("%w: a request cannot move from '%s' to '%s'", ErrValidation, from, to)
+	}
+
+	if (actor == requestActorOwner && !isOwner) || (actor == requestActorReviewer && !isReviewer) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// AllowedExpertRequestTransitions returns the statuses the user may move a request to, sorted by name
+func AllowedExpertRequestTransitions(from string, isOwner, isReviewer bool) []string {
+	allowed := []string{}
+	for to, actor := range expertRequestTransitions[from] {
+		if (actor == requestActorOwner && isOwner) || (actor == requestActorReviewer && isReviewer) {
+			allowed = append(allowed, to)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// ExpertRequestEditable reports whether the request's fields may be edited in its current status
+// Requesters edit drafts and returned requests; reviewers edit requests awaiting a decision
+func ExpertRequestEditable(status string, isOwner, isReviewer bool) bool {
+	switch status {
+	case RequestStatusDraft, RequestStatusNeedsChanges, RequestStatusRejected:
+		return isOwner
+	case RequestStatusSubmitted, RequestStatusUnderReview:
+		return isReviewer
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValidateExpertRequestTransition(t *testing.T) {
+	tests := []struct {
+		from, to        string
+		owner, reviewer bool
+		want            error // nil when allowed
+	}{
+		{RequestStatusDraft, RequestStatusSubmitted, true, false, nil},
+		{RequestStatusDraft, RequestStatusSubmitted, false, true, ErrForbidden},
+		{RequestStatusSubmitted, RequestStatusUnderReview, false, true, nil},
+		{RequestStatusSubmitted, RequestStatusUnderReview, true, false, ErrForbidden},
+		{RequestStatusSubmitted, RequestStatusWithdrawn, true, false, nil},
+		{RequestStatusUnderReview, RequestStatusApproved, false, true, nil},
+		{RequestStatusUnderReview, RequestStatusSubmitted, true, true, ErrValidation},
+		{RequestStatusNeedsChanges, RequestStatusSubmitted, true, false, nil},
+		{RequestStatusNeedsChanges, RequestStatusApproved, false, true, ErrValidation},
+		{RequestStatusRejected, RequestStatusSubmitted, true, false, nil},
+		{RequestStatusRejected, RequestStatusWithdrawn, true, false, ErrValidation},
+		{RequestStatusApproved, RequestStatusRejected, true, true, ErrValidation},
+		{RequestStatusWithdrawn, RequestStatusSubmitted, true, true, ErrValidation},
+		{RequestStatusSubmitted, "pending", true, true, ErrValidation},
+		// Owners who are also reviewers may act as either
+		{RequestStatusSubmitted, RequestStatusRejected, true, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s to %s owner %v reviewer %v", tt.from, tt.to, tt.owner, tt.reviewer), func(t *testing.T) {
+			err := ValidateExpertRequestTransition(tt.from, tt.to, tt.owner, tt.reviewer)
+			if tt.want == nil && err != nil {
+				t.Errorf("ValidateExpertRequestTransition = %v, want it allowed", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("ValidateExpertRequestTransition = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowedExpertRequestTransitions(t *testing.T) {
+	tests := []struct {
+		from            string
+		owner, reviewer bool
+		want            string
+	}{
+		{RequestStatusDraft, true, false, "[submitted withdrawn]"},
+		{RequestStatusSubmitted, true, false, "[withdrawn]"},
+		{RequestStatusSubmitted, false, true, "[approved needs_changes rejected under_review]"},
+		{RequestStatusUnderReview, false, true, "[approved needs_changes rejected]"},
+		{RequestStatusNeedsChanges, false, true, "[]"},
+		{RequestStatusRejected, true, false, "[submitted]"},
+		{RequestStatusApproved, true, true, "[]"},
+	}
+	for _, tt := range tests {
+		got := fmt.Sprint(AllowedExpertRequestTransitions(tt.from, tt.owner, tt.reviewer))
+		if got != tt.want {
+			t.Errorf("AllowedExpertRequestTransitions(%s, owner %v, reviewer %v) = %s, want %s",
+				tt.from, tt.owner, tt.reviewer, got, tt.want)
+		}
+	}
+}
+
+func TestExpertRequestEditable(t *testing.T) {
+	tests := []struct {
+		status              string
+		ownerOK, reviewerOK bool
+	}{
+		{RequestStatusDraft, true, false},
+		{RequestStatusSubmitted, false, true},
+		{RequestStatusUnderReview, false, true},
+		{RequestStatusNeedsChanges, true, false},
+		{RequestStatusRejected, true, false},
+		{RequestStatusApproved, false, false},
+		{RequestStatusWithdrawn, false, false},
+	}
+	for _, tt := range tests {
+		if got := ExpertRequestEditable(tt.status, true, false); got != tt.ownerOK {
+			t.Errorf("ExpertRequestEditable(%s) for the owner = %v, want %v", tt.status, got, tt.ownerOK)
+		}
+		if got := ExpertRequestEditable(tt.status, false, true); got != tt.reviewerOK {
+			t.Errorf("ExpertRequestEditable(%s) for a reviewer = %v, want %v", tt.status, got, tt.reviewerOK)
+		}
+	}
+}
+
+func TestExpertRequestApplySLA(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	sla := ExpertRequestSLA{RequestStatusSubmitted: 72 * time.Hour, RequestStatusUnderReview: 0}
+	tests := []struct {
+		name         string
+		status       string
+		created      time.Time
+		changed      time.Time // Zero for requests from before status times were kept
+		ageHours     int
+		due          bool
+		wantBreached bool
+	}{
+		{"within target", RequestStatusSubmitted, now.Add(-100 * time.Hour), now.Add(-71 * time.Hour), 71, true, false},
+		{"past target", RequestStatusSubmitted, now.Add(-100 * time.Hour), now.Add(-73 * time.Hour), 73, true, true},
+		{"aged from creation", RequestStatusSubmitted, now.Add(-100 * time.Hour), time.Time{}, 100, true, true},
+		{"zero target", RequestStatusUnderReview, now.Add(-500 * time.Hour), now.Add(-400 * time.Hour), 400, false, false},
+		{"no target", RequestStatusNeedsChanges, now.Add(-500 * time.Hour), now.Add(-400 * time.Hour), 400, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ExpertRequest{Status: tt.status, CreatedAt: tt.created, StatusChangedAt: tt.changed}
+			r.ApplySLA(sla, now)
+			if r.AgeHours != tt.ageHours || (r.SLADueAt != nil) != tt.due || r.SLABreached != tt.wantBreached {
+				t.Errorf("age %d, due %v, breached %v; want %d, %v, %v", r.AgeHours, r.SLADueAt, r.SLABreached, tt.ageHours, tt.due, tt.wantBreached)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -111,7 +112,8 @@ type Expert struct {
 	SpecializedArea          string                  `json:"-" db:"specialized_area"`                     // Comma-separated specialized area IDs (e.g., "1,4,6") - internal use only
 	SpecializedAreaNames     string                  `json:"specializedAreaNames"`                        // Comma-separated specialized area names (e.g., "Software Engineering, Database Design")
 	SpecializedAreasResolved []*SpecializedArea      `json:"specialized_areas_resolved,omitempty" db:"-"` // Resolved specialized area names
-	IsTrained                bool                    `json:"isTrained"`                                   // Indicates if expert has completed required training
+	IsTrained                bool                    `json:"isTrained"`                                   // Derived: expert holds at least one unexpired training attendance
+	TrainingRecords          []TrainingAttendance    `json:"trainingRecords,omitempty"`                   // Training attendance history
 	CVDocumentID             *int64                  `json:"cvDocumentId,omitempty"`                      // Reference to CV document
 	ApprovalDocumentID       *int64                  `json:"approvalDocumentId,omitempty"`                // Reference to approval document
 	CVDocument               *Document               `json:"cvDocument,omitempty"`                        // Resolved CV document
@@ -143,17 +145,79 @@ type ExpertEditHistoryEntry struct {
 	EditorName    *string   `json:"editorName,omitempty" db:"-"`               // Name of the user who made the edit (resolved)
 }
 
-// Area represents an expert specialization area
+// Area represents an expert specialization area within the area hierarchy
 type Area struct {
-	ID   int64  `json:"id"`   // Unique identifier for the area
-	Name string `json:"name"` // Name of the specialization area
+	ID           int64      `json:"id"`                     // Unique identifier for the area
+	Name         string     `json:"name"`                   // Name of the specialization area
+	ParentID     *int64     `json:"parentId,omitempty"`     // Parent area ID (nil for top-level areas)
+	Path         string     `json:"path,omitempty"`         // Full display path, e.g. "Business - Banking & Finance"
+	Children     []*Area    `json:"children,omitempty"`     // Child areas (populated when returned as a tree)
+	RetiredAt    *time.Time `json:"retiredAt,omitempty"`    // When the area was retired (hidden from new selections)
+	MergedIntoID *int64     `json:"mergedIntoId,omitempty"` // Area this one was merged into
 }
 
 // SpecializedArea represents a specialized area for experts
 type SpecializedArea struct {
-	ID        int64     `json:"id" db:"id"`                 // Unique identifier for the specialized area
-	Name      string    `json:"name" db:"name"`             // Name of the specialized area
-	CreatedAt time.Time `json:"created_at" db:"created_at"` // Timestamp when specialized area was created
+	ID            int64      `json:"id" db:"id"`                                     // Unique identifier for the specialized area
+	Name          string     `json:"name" db:"name"`                                 // Name of the specialized area
+	GeneralAreaID *int64     `json:"general_area_id,omitempty" db:"general_area_id"` // General area this specialized area belongs to
+	RetiredAt     *time.Time `json:"retired_at,omitempty" db:"retired_at"`           // When the area was retired (hidden from new selections)
+	MergedIntoID  *int64     `json:"merged_into_id,omitempty" db:"merged_into_id"`   // Specialized area this one was merged into
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`                     // Timestamp when specialized area was created
+}
+
+// AreaMergeResult summarises the records repointed by an area merge
+type AreaMergeResult struct {
+	SourceID                int64 `json:"sourceId"`                          // Area that was merged away (now retired)
+	TargetID                int64 `json:"targetId"`                          // Area that absorbed the source
+	ExpertsUpdated          int   `json:"expertsUpdated"`                    // Experts repointed to the target
+	RequestsUpdated         int   `json:"requestsUpdated"`                   // Expert requests repointed to the target
+	SpecializedAreasUpdated int   `json:"specializedAreasUpdated,omitempty"` // Specialized areas remapped (general areas only)
+	ChildAreasMoved         int   `json:"childAreasMoved,omitempty"`         // Child areas moved under the target (general areas only)
+}
+
+// AreaAuditEntry records a maintenance operation on a general or specialized area
+type AreaAuditEntry struct {
+	ID            int64     `json:"id"`                      // Primary key identifier
+	AreaType      string    `json:"areaType"`                // "general" or "specialized"
+	AreaID        int64     `json:"areaId"`                  // ID of the affected area
+	Action        string    `json:"action"`                  // "merge", "retire" or "restore"
+	TargetAreaID  *int64    `json:"targetAreaId,omitempty"`  // Area merged into (merge only)
+	Details       string    `json:"details,omitempty"`       // JSON object with counts and reason
+	PerformedBy   int64     `json:"performedBy"`             // ID of the user who performed the operation
+	PerformerName string    `json:"performerName,omitempty"` // Name of the user (not stored in DB)
+	PerformedAt   time.Time `json:"performedAt"`             // When the operation was performed
+}
+
+// SpecializedAreaSuggestion is a user-suggested specialized area awaiting moderation
+// Suggestions are grouped case- and whitespace-insensitively across expert requests
+type SpecializedAreaSuggestion struct {
+	Name             string                 `json:"name"`             // Suggested name as first submitted
+	RequestCount     int                    `json:"requestCount"`     // Number of expert requests carrying the suggestion
+	RequestIDs       []int64                `json:"requestIds"`       // IDs of those expert requests
+	FirstSuggestedAt time.Time              `json:"firstSuggestedAt"` // Creation time of the oldest request
+	Matches          []SpecializedAreaMatch `json:"matches"`          // Near-duplicate existing specialized areas, best first
+}
+
+// SpecializedAreaMatch is an existing specialized area resembling a suggestion
+type SpecializedAreaMatch struct {
+	ID    int64   `json:"id"`    // Specialized area ID
+	Name  string  `json:"name"`  // Specialized area name
+	Score float64 `json:"score"` // Similarity between 0 and 1 (1 = same normalized name)
+}
+
+// SuggestionResolution records how a specialized area suggestion was moderated
+type SuggestionResolution struct {
+	ID                  int64     `json:"id"`                     // Primary key identifier
+	SuggestedName       string    `json:"suggestedName"`          // Suggested name as submitted
+	Resolution          string    `json:"resolution"`             // "accepted" (new area created) or "mapped" (existing area)
+	SpecializedAreaID   int64     `json:"specializedAreaId"`      // Area created or mapped to
+	SpecializedAreaName string    `json:"specializedAreaName"`    // Name of that area (not stored in DB)
+	RequestsUpdated     int       `json:"requestsUpdated"`        // Expert requests updated
+	ExpertsUpdated      int       `json:"expertsUpdated"`         // Experts updated
+	ResolvedBy          int64     `json:"resolvedBy"`             // ID of the moderating user
+	ResolverName        string    `json:"resolverName,omitempty"` // Name of the moderating user (not stored in DB)
+	ResolvedAt          time.Time `json:"resolvedAt"`             // When the suggestion was resolved
 }
 
 // ExpertRequest represents a request to add a new expert
@@ -179,12 +243,234 @@ type ExpertRequest struct {
 	ApprovalDocument          *Document                      `json:"approvalDocument,omitempty"`   // Resolved approval document
 	ExperienceEntries         []ExpertRequestExperienceEntry `json:"experienceEntries"`            // Professional experience entries
 	EducationEntries          []ExpertRequestEducationEntry  `json:"educationEntries"`             // Educational background entries
-	Status                    string                         `json:"status"`                       // Request status: "pending", "approved", "rejected"
-	RejectionReason           string                         `json:"rejectionReason,omitempty"`    // Reason for rejection if status is "rejected"
+	Status                    string                         `json:"status"`                       // Workflow status, see RequestStatus* constants
+	RejectionReason           string                         `json:"rejectionReason,omitempty"`    // Reason given when rejected or returned for changes
 	CreatedAt                 time.Time                      `json:"createdAt"`                    // Timestamp when request was submitted
 	ReviewedAt                time.Time                      `json:"reviewedAt,omitempty"`         // Timestamp when request was reviewed
 	ReviewedBy                int64                          `json:"reviewedBy,omitempty"`         // ID of admin who reviewed the request
 	CreatedBy                 int64                          `json:"createdBy,omitempty"`          // ID of user who created the request
+	Comments                  []*ExpertRequestComment        `json:"comments,omitempty"`           // Comment threads visible to the viewer (not stored in DB)
+	UnreadComments            int                            `json:"unreadComments"`               // Comments the viewer has not read yet (not stored in DB)
+	Approvals                 []*ExpertRequestApproval       `json:"approvals,omitempty"`          // Standing approval votes (not stored in DB)
+	RequiredApprovals         int                            `json:"requiredApprovals,omitempty"`  // Votes needed before the expert is created (not stored in DB)
+	DuplicateMatches          []*DuplicateMatch              `json:"duplicateMatches,omitempty"`   // Possible duplicates found at submission
+	DuplicateConfirmed        bool                           `json:"duplicateConfirmed"`           // Submitter confirmed the request is not a duplicate of the matches
+	IsSelfNominated           bool                           `json:"isSelfNominated"`              // Submitted by the expert through the public nomination portal
+	NominationInviteID        *int64                         `json:"nominationInviteId,omitempty"` // Invite used for a self-nomination
+	StatusChangedAt           time.Time                      `json:"statusChangedAt"`              // When the request entered its current status
+	AgeHours                  int                            `json:"ageHours"`                     // Hours spent in the current status (not stored in DB)
+	SLAHours                  int                            `json:"slaHours,omitempty"`           // Review target for the current status (not stored in DB)
+	SLADueAt                  *time.Time                     `json:"slaDueAt,omitempty"`           // When the review target is reached (not stored in DB)
+	SLABreached               bool                           `json:"slaBreached"`                  // Request is past its review target (not stored in DB)
+}
+
+// ApplySLA fills in the request's age in its current status and whether it has breached its review target
+func (r *ExpertRequest) ApplySLA(sla ExpertRequestSLA, now time.Time) {
+	since := r.StatusChangedAt
+	if since.IsZero() {
+		since = r.CreatedAt
+	}
+	r.AgeHours = int(now.Sub(since).Hours())
+
+	if due, ok := sla.DueAt(r.Status, since); ok {
+		r.SLAHours = int(sla[r.Status].Hours())
+		r.SLADueAt = &due
+		r.SLABreached = now.After(due)
+	}
+}
+
+// Admin notification kinds
+const (
+	AdminNotificationSLABreach         = "sla_breach"         // An expert request is past its review target
+	AdminNotificationEngagementOverdue = "engagement_overdue" // An engagement has run too long without an end date
+)
+
+// AdminNotification is an entry in the administrators' notification feed
+type AdminNotification struct {
+	ID             int64      `json:"id"`                       // Primary key identifier
+	Kind           string     `json:"kind"`                     // See AdminNotification* constants
+	RequestID      *int64     `json:"requestId,omitempty"`      // Expert request the notification is about
+	RequestName    string     `json:"requestName,omitempty"`    // Name on the expert request (not stored in DB)
+	EngagementID   *int64     `json:"engagementId,omitempty"`   // Engagement the notification is about
+	Status         string     `json:"status,omitempty"`         // Request status the notification is about
+	Message        string     `json:"message"`                  // Human-readable description
+	CreatedAt      time.Time  `json:"createdAt"`                // When the notification was raised
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"` // When an admin acknowledged it
+	AcknowledgedBy *int64     `json:"acknowledgedBy,omitempty"` // Admin who acknowledged it
+}
+
+// ExpertRequestAgingBucket counts open requests whose time in their current status falls in a range of days
+type ExpertRequestAgingBucket struct {
+	Label    string `json:"label"`             // e.g. "3-7 days"
+	MinDays  int    `json:"minDays"`           // Inclusive lower bound
+	MaxDays  *int   `json:"maxDays,omitempty"` // Inclusive upper bound; nil for the last bucket
+	Count    int    `json:"count"`             // Requests in the bucket
+	Breached int    `json:"breached"`          // Requests in the bucket past their review target
+}
+
+// NewExpertRequestAgingBuckets returns the empty age buckets used by the aging report
+func NewExpertRequestAgingBuckets() []ExpertRequestAgingBucket {
+	bounds := []struct{ min, max int }{{0, 2}, {3, 7}, {8, 14}, {15, 30}}
+	buckets := make([]ExpertRequestAgingBucket, 0, len(bounds)+1)
+	for _, b := range bounds {
+		max := b.max
+		buckets = append(buckets, ExpertRequestAgingBucket{Label: fmt.Sprintf("%d-%d days", b.min, b.max), MinDays: b.min, MaxDays: &max})
+	}
+	return append(buckets, ExpertRequestAgingBucket{Label: "over 30 days", MinDays: 31})
+}
+
+// ExpertRequestReviewerAging summarizes the open requests assigned to one reviewer
+type ExpertRequestReviewerAging struct {
+	ReviewerID     int64                      `json:"reviewerId,omitempty"` // 0 for requests nobody has picked up
+	ReviewerName   string                     `json:"reviewerName"`         // "Unassigned" when ReviewerID is 0
+	Open           int                        `json:"open"`                 // Open requests
+	Breached       int                        `json:"breached"`             // Open requests past their review target
+	OldestAgeHours int                        `json:"oldestAgeHours"`       // Age of the longest-waiting request
+	Buckets        []ExpertRequestAgingBucket `json:"buckets"`              // Open requests by age
+}
+
+// ExpertRequestAgingReport shows how long open expert requests have been waiting
+type ExpertRequestAgingReport struct {
+	GeneratedAt   time.Time                     `json:"generatedAt"`   // When the report was computed
+	SLAHours      map[string]int                `json:"slaHours"`      // Review target per status
+	TotalOpen     int                           `json:"totalOpen"`     // Open requests
+	TotalBreached int                           `json:"totalBreached"` // Open requests past their review target
+	Buckets       []ExpertRequestAgingBucket    `json:"buckets"`       // Open requests by age
+	ByReviewer    []*ExpertRequestReviewerAging `json:"byReviewer"`    // Open requests by reviewer, most breaches first
+}
+
+// NominationInvite lets a prospective expert submit a self-nomination without an account
+type NominationInvite struct {
+	ID        int64      `json:"id"`                  // Primary key identifier
+	Token     string     `json:"token,omitempty"`     // Secret token, only returned when the invite is created (not stored in DB)
+	Email     string     `json:"email,omitempty"`     // Person the invite was sent to
+	Note      string     `json:"note,omitempty"`      // Admin note, e.g. who recommended the expert
+	MaxUses   int        `json:"maxUses"`             // Number of nominations the invite accepts
+	UseCount  int        `json:"useCount"`            // Nominations submitted so far
+	ExpiresAt time.Time  `json:"expiresAt"`           // When the invite stops working
+	RevokedAt *time.Time `json:"revokedAt,omitempty"` // When an admin revoked the invite
+	CreatedBy int64      `json:"createdBy"`           // ID of the admin who created the invite
+	CreatedAt time.Time  `json:"createdAt"`           // When the invite was created
+}
+
+// Usable reports whether the invite can still be used to submit a nomination
+func (i *NominationInvite) Usable(now time.Time) bool {
+	return i.RevokedAt == nil && i.UseCount < i.MaxUses && now.Before(i.ExpiresAt)
+}
+
+// DocumentDownloadLink lets someone without an account download one document through a signed URL
+type DocumentDownloadLink struct {
+	ID            int64      `json:"id"`
+	DocumentID    int64      `json:"documentId"`
+	Recipient     string     `json:"recipient"` // Who the link was made for
+	SingleUse     bool       `json:"singleUse"` // Link stops working after the first download
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	DownloadCount int        `json:"downloadCount"`
+	LastUsedAt    *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt     *time.Time `json:"revokedAt,omitempty"`
+	CreatedBy     int64      `json:"createdBy"` // ID of the user who created the link
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// Usable reports whether the link can still be used to download its document
+func (l *DocumentDownloadLink) Usable(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt) && !(l.SingleUse && l.DownloadCount > 0)
+}
+
+// DocumentDownload records one download made through a download link
+type DocumentDownload struct {
+	ID            int64     `json:"id"`
+	LinkID        int64     `json:"linkId"`
+	DocumentID    int64     `json:"documentId"`
+	Recipient     string    `json:"recipient"`     // Recipient label of the link
+	LinkCreatedBy int64     `json:"linkCreatedBy"` // User who created the link
+	IPAddress     string    `json:"ipAddress"`
+	UserAgent     string    `json:"userAgent"`
+	DownloadedAt  time.Time `json:"downloadedAt"`
+}
+
+// Outbox email statuses
+const (
+	EmailStatusPending = "pending" // Waiting to be sent (or retried)
+	EmailStatusSent    = "sent"    // Delivered to the mail server
+	EmailStatusFailed  = "failed"  // Gave up after repeated failures
+)
+
+// OutboxEmail is an email queued for delivery by the background dispatcher
+type OutboxEmail struct {
+	ID        int64      `json:"id"`                  // Primary key identifier
+	Recipient string     `json:"recipient"`           // Email address to deliver to
+	Subject   string     `json:"subject"`             // Subject line
+	Body      string     `json:"body"`                // Plain-text body
+	Status    string     `json:"status"`              // See EmailStatus* constants
+	Attempts  int        `json:"attempts"`            // Delivery attempts so far
+	LastError string     `json:"lastError,omitempty"` // Error from the last failed attempt
+	CreatedAt time.Time  `json:"createdAt"`           // When the email was queued
+	SentAt    *time.Time `json:"sentAt,omitempty"`    // When the email was delivered
+}
+
+// Sources of a duplicate match
+const (
+	DuplicateSourceExpert        = "expert"         // An existing expert profile
+	DuplicateSourceExpertRequest = "expert_request" // Another request that has not been decided yet
+)
+
+// DuplicateMatch is an existing expert or pending request that may describe the same person as a new request
+type DuplicateMatch struct {
+	Source     string   `json:"source"`           // DuplicateSourceExpert or DuplicateSourceExpertRequest
+	ID         int64    `json:"id"`               // ID of the expert or expert request
+	Name       string   `json:"name"`             // Name on the matching record
+	Email      string   `json:"email"`            // Email on the matching record
+	Phone      string   `json:"phone"`            // Phone on the matching record
+	Status     string   `json:"status,omitempty"` // Workflow status (requests only)
+	Confidence float64  `json:"confidence"`       // Likelihood of being the same person, between 0 and 1
+	MatchedOn  []string `json:"matchedOn"`        // Fields that matched: "name", "email", "phone"
+}
+
+// ExpertRequestComment is a comment in a discussion thread on an expert request
+type ExpertRequestComment struct {
+	ID         int64                   `json:"id"`                 // Primary key identifier
+	RequestID  int64                   `json:"requestId"`          // Expert request the comment belongs to
+	ParentID   *int64                  `json:"parentId,omitempty"` // Comment being replied to (nil starts a thread)
+	AuthorID   int64                   `json:"authorId"`           // ID of the user who wrote the comment
+	AuthorName string                  `json:"authorName"`         // Name of the author (not stored in DB)
+	AuthorRole string                  `json:"authorRole"`         // Role of the author (not stored in DB)
+	Body       string                  `json:"body"`               // Comment text
+	IsInternal bool                    `json:"isInternal"`         // Admin-only note hidden from the requester
+	CreatedAt  time.Time               `json:"createdAt"`          // When the comment was posted
+	Replies    []*ExpertRequestComment `json:"replies,omitempty"`  // Replies to this comment, oldest first
+}
+
+// ExpertRequestStatusChange records a single workflow transition of an expert request
+type ExpertRequestStatusChange struct {
+	ID            int64     `json:"id"`                      // Primary key identifier
+	RequestID     int64     `json:"requestId"`               // Expert request that changed status
+	FromStatus    string    `json:"fromStatus,omitempty"`    // Previous status (empty for the initial status)
+	ToStatus      string    `json:"toStatus"`                // New status
+	Note          string    `json:"note,omitempty"`          // Reason given with the change
+	ChangedBy     int64     `json:"changedBy"`               // ID of the user who made the change
+	ChangedByName string    `json:"changedByName,omitempty"` // Name of that user (not stored in DB)
+	ChangedAt     time.Time `json:"changedAt"`               // When the change was made
+}
+
+// ExpertRequestApproval is a reviewer's approval vote on an expert request
+type ExpertRequestApproval struct {
+	ID            int64      `json:"id"`                      // Primary key identifier
+	RequestID     int64      `json:"requestId"`               // Expert request being approved
+	ApproverID    int64      `json:"approverId"`              // ID of the reviewer who approved
+	ApproverName  string     `json:"approverName,omitempty"`  // Name of the reviewer (not stored in DB)
+	ApproverRole  string     `json:"approverRole"`            // Role of the reviewer when the vote was cast
+	CreatedAt     time.Time  `json:"createdAt"`               // When the vote was cast
+	InvalidatedAt *time.Time `json:"invalidatedAt,omitempty"` // When the request was sent back and the vote stopped counting
+}
+
+// ExpertRequestApprovalResult reports the outcome of an approval vote
+type ExpertRequestApprovalResult struct {
+	RequestID         int64 `json:"requestId"`          // Expert request that was voted on
+	Approvals         int   `json:"approvals"`          // Standing votes including this one
+	RequiredApprovals int   `json:"requiredApprovals"`  // Votes needed to approve the request
+	Approved          bool  `json:"approved"`           // True once the quorum was met and the expert created
+	ExpertID          int64 `json:"expertId,omitempty"` // Expert created from the request (when approved)
 }
 
 // Document resolution methods for Expert
@@ -243,28 +529,291 @@ type User struct {
 
 // Document represents an uploaded document for an expert
 type Document struct {
-	ID           int64     `json:"id"`           // Primary key identifier
-	ExpertID     int64     `json:"expertId"`     // Foreign key reference to expert
-	DocumentType string    `json:"documentType"` // Type of document: "cv" or "approval"
-	Filename     string    `json:"filename"`     // Original filename as uploaded
-	FilePath     string    `json:"filePath"`     // Path where file is stored on server
-	ContentType  string    `json:"contentType"`  // MIME type of the document
-	FileSize     int64     `json:"fileSize"`     // Size of document in bytes
-	UploadDate   time.Time `json:"uploadDate"`   // Timestamp when document was uploaded
+	ID             int64     `json:"id"`                      // Primary key identifier
+	ExpertID       int64     `json:"expertId"`                // Foreign key reference to expert
+	DocumentType   string    `json:"documentType"`            // Code of a registered document type, e.g. "cv" or "approval"
+	Filename       string    `json:"filename"`                // Original filename as uploaded
+	FilePath       string    `json:"filePath"`                // Path where file is stored on server
+	ContentType    string    `json:"contentType"`             // MIME type of the document
+	FileSize       int64     `json:"fileSize"`                // Size of document in bytes
+	UploadDate     time.Time `json:"uploadDate"`              // Timestamp when document was uploaded
+	SHA256         string    `json:"sha256,omitempty"`        // Hex SHA-256 of the file content, empty for documents not yet scanned
+	CurrentVersion int       `json:"currentVersion"`          // Version whose file the document currently points at
+	UploadedBy     *int64    `json:"uploadedBy,omitempty"`    // User who uploaded the current version, when known
+	ExtractedText  string    `json:"extractedText,omitempty"` // Plain text of PDF and DOCX files; only loaded for single-document reads
+}
+
+// Document requirement levels of a document type for experts and expert requests
+const (
+	DocumentRequired = "required" // Every expert (or request, before approval) must have one
+	DocumentOptional = "optional" // May be attached
+	DocumentNotUsed  = "none"     // Not attached to this kind of record
+)
+
+// Document types that are linked from experts and expert requests through cv_document_id and
+// approval_document_id; they can be reconfigured but not retired
+const (
+	DocumentTypeCV       = "cv"
+	DocumentTypeApproval = "approval"
+)
+
+// DocumentType is an entry of the document type registry, which admins manage
+// Uploads are checked against the allowed content types and size of their type
+type DocumentType struct {
+	Code               string     `json:"code"` // Stored as the document type of documents; immutable
+	Name               string     `json:"name"`
+	Description        string     `json:"description,omitempty"`
+	AllowedMimeTypes   []string   `json:"allowedMimeTypes"`
+	MaxSize            int64      `json:"maxSize"`             // Largest accepted upload in bytes
+	Directory          string     `json:"directory"`           // Storage directory of expert documents of this type
+	ExpertRequirement  string     `json:"expertRequirement"`   // "required", "optional" or "none"
+	RequestRequirement string     `json:"requestRequirement"`  // "required", "optional" or "none"; only cv and approval can be attached to requests
+	Multiple           bool       `json:"multiple"`            // Whether an expert may hold several documents of this type
+	RetiredAt          *time.Time `json:"retiredAt,omitempty"` // Retired types accept no new uploads
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+// Allows reports whether uploads with the given content type are accepted
+func (t *DocumentType) Allows(contentType string) bool {
+	for _, allowed := range t.AllowedMimeTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLinked reports whether the type is referenced from experts and expert requests
+func (t *DocumentType) IsLinked() bool {
+	return t.Code == DocumentTypeCV || t.Code == DocumentTypeApproval
+}
+
+// DocumentVersion is one uploaded file of a document; replacing a CV or approval adds a version
+// instead of discarding the previous file
+type DocumentVersion struct {
+	ID          int64     `json:"id"`
+	DocumentID  int64     `json:"documentId"`
+	Version     int       `json:"version"`
+	Filename    string    `json:"filename"`
+	FilePath    string    `json:"filePath"`
+	ContentType string    `json:"contentType"`
+	FileSize    int64     `json:"fileSize"`
+	SHA256      string    `json:"sha256,omitempty"`
+	UploadedBy  *int64    `json:"uploadedBy,omitempty"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+	Note        string    `json:"note,omitempty"`
+	IsCurrent   bool      `json:"isCurrent"`
+}
+
+// Document integrity statuses reported by verification and integrity scans
+const (
+	DocumentIntegrityOK         = "ok"         // File present and matches its stored checksum
+	DocumentIntegrityMissing    = "missing"    // File no longer exists at its stored path
+	DocumentIntegrityAltered    = "altered"    // File content differs from its stored checksum
+	DocumentIntegrityUnreadable = "unreadable" // File exists but could not be read
+	DocumentIntegrityUnhashed   = "unhashed"   // No checksum stored yet (uploaded before checksums were recorded)
+)
+
+// DocumentIntegrityResult is the outcome of checking one document's file against its stored checksum
+type DocumentIntegrityResult struct {
+	DocumentID     int64  `json:"documentId"`
+	ExpertID       int64  `json:"expertId"`
+	DocumentType   string `json:"documentType"`
+	Filename       string `json:"filename"`
+	FilePath       string `json:"filePath"`
+	Status         string `json:"status"`
+	Intact         bool   `json:"intact"`
+	ExpectedSHA256 string `json:"expectedSha256,omitempty"`
+	ActualSHA256   string `json:"actualSha256,omitempty"`
+	Error          string `json:"error,omitempty"` // Read error for unreadable files
+}
+
+// DocumentIntegrityReport summarizes a scan over every stored document
+// Only documents that are missing, altered or unreadable are listed individually
+type DocumentIntegrityReport struct {
+	ScannedAt  time.Time                 `json:"scannedAt"`
+	Total      int                       `json:"total"`
+	OK         int                       `json:"ok"`
+	Missing    int                       `json:"missing"`
+	Altered    int                       `json:"altered"`
+	Unreadable int                       `json:"unreadable"`
+	Backfilled int                       `json:"backfilled"` // Unhashed documents whose checksum was recorded by this scan
+	Problems   []DocumentIntegrityResult `json:"problems"`
+}
+
+// DocumentTextExtractionReport summarizes a text extraction run over stored documents
+type DocumentTextExtractionReport struct {
+	StartedAt   time.Time                       `json:"startedAt"`
+	Total       int                             `json:"total"`       // Documents processed
+	Extracted   int                             `json:"extracted"`   // Documents with text
+	Empty       int                             `json:"empty"`       // Supported documents without any text, such as scanned PDFs
+	Unsupported int                             `json:"unsupported"` // Documents whose file type has no text extraction
+	Failed      int                             `json:"failed"`
+	Failures    []DocumentTextExtractionFailure `json:"failures"`
+}
+
+// DocumentTextExtractionFailure records a document text could not be extracted from
+type DocumentTextExtractionFailure struct {
+	DocumentID int64  `json:"documentId"`
+	Filename   string `json:"filename"`
+	Error      string `json:"error"`
+}
+
+// Actions taken by a document reconciliation; findings that were only reported have no action
+const (
+	ReconcileRelinked    = "relinked"    // Record pointed at a missing file and was pointed at an orphaned file with its checksum
+	ReconcileCleared     = "cleared"     // Dangling document reference was set to NULL
+	ReconcileQuarantined = "quarantined" // Orphaned file was moved under quarantine/ and recorded as a quarantined upload
+	ReconcileFailed      = "failed"      // The repair was attempted but failed; see the error
+)
+
+// OrphanedFile is a stored file that no document, document version or quarantined upload points at
+type OrphanedFile struct {
+	FilePath      string `json:"filePath"`
+	FileSize      int64  `json:"fileSize"`
+	Action        string `json:"action,omitempty"`
+	QuarantinedAs string `json:"quarantinedAs,omitempty"` // Quarantine key the file was moved to
+	Error         string `json:"error,omitempty"`
+}
+
+// MissingDocumentFile is a document, or an earlier version of one, whose file is not in storage
+type MissingDocumentFile struct {
+	DocumentID   int64  `json:"documentId"`
+	Version      int    `json:"version,omitempty"` // Set for earlier versions; absent for a document's current file
+	ExpertID     int64  `json:"expertId"`
+	DocumentType string `json:"documentType"`
+	Filename     string `json:"filename"`
+	FilePath     string `json:"filePath"`
+	SHA256       string `json:"sha256,omitempty"`
+	Action       string `json:"action,omitempty"`
+	RelinkedTo   string `json:"relinkedTo,omitempty"` // Orphaned file the record now points at
+	Error        string `json:"error,omitempty"`
+}
+
+// DanglingDocumentReference is a cv_document_id or approval_document_id naming a document that does not exist
+type DanglingDocumentReference struct {
+	Owner      string `json:"owner"` // "expert" or "expert_request"
+	OwnerID    int64  `json:"ownerId"`
+	Field      string `json:"field"` // "cv_document_id" or "approval_document_id"
+	DocumentID int64  `json:"documentId"`
+	Action     string `json:"action,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DocumentReconciliationReport compares stored files with document records and the references to them
+type DocumentReconciliationReport struct {
+	StartedAt          time.Time                   `json:"startedAt"`
+	Repair             bool                        `json:"repair"`
+	Quarantine         bool                        `json:"quarantine"`
+	FilesScanned       int                         `json:"filesScanned"`
+	DocumentsChecked   int                         `json:"documentsChecked"`
+	OrphanedFiles      []OrphanedFile              `json:"orphanedFiles"`
+	MissingFiles       []MissingDocumentFile       `json:"missingFiles"`
+	DanglingReferences []DanglingDocumentReference `json:"danglingReferences"`
+}
+
+// QuarantinedUpload is an uploaded file that the malware scanner flagged
+// The file is kept under quarantine/ for review and is never linked to an expert
+type QuarantinedUpload struct {
+	ID            int64     `json:"id"`
+	Filename      string    `json:"filename"` // Name given by the uploader
+	FilePath      string    `json:"filePath"` // Storage key under quarantine/
+	ContentType   string    `json:"contentType"`
+	FileSize      int64     `json:"fileSize"`
+	SHA256        string    `json:"sha256"`
+	Scanner       string    `json:"scanner"`      // Scanner that flagged the file
+	Signature     string    `json:"signature"`    // Signature reported by the scanner
+	DocumentType  string    `json:"documentType"` // Document type the upload was intended as
+	Source        string    `json:"source"`       // What the upload was for, e.g. "expert 12"
+	QuarantinedAt time.Time `json:"quarantinedAt"`
+}
+
+// Upload session statuses
+const (
+	UploadStatusUploading = "uploading" // Chunks are still being received
+	UploadStatusComplete  = "complete"  // All bytes were received and match the declared checksum
+)
+
+// UploadSession is a resumable upload of one file, sent in chunks
+// A complete upload is used in place of a multipart file, after which it is discarded
+type UploadSession struct {
+	ID           int64     `json:"id"`
+	DocumentType string    `json:"documentType"` // Code of the document type the file is uploaded as
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"contentType"`
+	FileSize     int64     `json:"fileSize"` // Declared size of the whole file
+	SHA256       string    `json:"sha256"`   // Declared checksum, verified on completion
+	Received     int64     `json:"received"` // Bytes received so far; the offset of the next chunk
+	Status       string    `json:"status"`   // UploadStatusUploading or UploadStatusComplete
+	CreatedBy    int64     `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	ChunkSize    int64     `json:"chunkSize,omitempty"` // Largest chunk the server accepts
+}
+
+// UploadChunk is a part of an upload session's file, stored as a separate file
+type UploadChunk struct {
+	UploadID int64  `json:"uploadId"`
+	Offset   int64  `json:"offset"`
+	Size     int64  `json:"size"`
+	FilePath string `json:"filePath"`
 }
 
 // Engagement represents expert assignment to projects/activities
 type Engagement struct {
-	ID             int64     `json:"id"`                      // Primary key identifier
-	ExpertID       int64     `json:"expertId"`                // Foreign key reference to expert
-	EngagementType string    `json:"engagementType"`          // Type of work: "evaluation", "consultation", "project", etc.
-	StartDate      time.Time `json:"startDate"`               // Date when engagement begins
-	EndDate        time.Time `json:"endDate,omitempty"`       // Date when engagement ends
-	ProjectName    string    `json:"projectName,omitempty"`   // Name of the project or activity
-	Status         string    `json:"status"`                  // Current status: "pending", "active", "completed", "cancelled"
-	FeedbackScore  int       `json:"feedbackScore,omitempty"` // Performance rating (1-5 scale)
-	Notes          string    `json:"notes,omitempty"`         // Additional comments or observations
-	CreatedAt      time.Time `json:"createdAt"`               // Timestamp when record was created
+	ID             int64      `json:"id"`                      // Primary key identifier
+	ExpertID       int64      `json:"expertId"`                // Foreign key reference to expert
+	EngagementType string     `json:"engagementType"`          // Type of work: "evaluation", "consultation", "project", etc.
+	StartDate      time.Time  `json:"startDate"`               // Date when engagement begins
+	EndDate        time.Time  `json:"endDate,omitempty"`       // Date when engagement ends
+	ProjectName    string     `json:"projectName,omitempty"`   // Name of the project or activity
+	Status         string     `json:"status"`                  // Current status; see EngagementStatus* constants
+	FeedbackScore  int        `json:"feedbackScore,omitempty"` // Performance rating (1-5 scale)
+	Notes          string     `json:"notes,omitempty"`         // Additional comments or observations
+	CreatedAt      time.Time  `json:"createdAt"`               // Timestamp when record was created
+	OverdueSince   *time.Time `json:"overdueSince,omitempty"`  // When the engagement was flagged for running too long without an end date
+}
+
+// TrainingProgram represents a training course that experts can complete
+type TrainingProgram struct {
+	ID             int64     `json:"id"`                    // Primary key identifier
+	Name           string    `json:"name"`                  // Name of the program
+	Description    string    `json:"description,omitempty"` // Optional description of the program
+	ValidityMonths int       `json:"validityMonths"`        // Months a completion remains valid (0 = never expires)
+	IsActive       bool      `json:"isActive"`              // Inactive programs cannot receive new sessions
+	CreatedAt      time.Time `json:"createdAt"`             // Timestamp when program was created
+}
+
+// TrainingSession represents a single delivery of a training program
+type TrainingSession struct {
+	ID          int64                `json:"id"`                   // Primary key identifier
+	ProgramID   int64                `json:"programId"`            // Foreign key reference to training program
+	ProgramName string               `json:"programName"`          // Name of the program (not stored in DB)
+	SessionDate time.Time            `json:"sessionDate"`          // Date the session took place
+	Location    string               `json:"location,omitempty"`   // Venue of the session
+	Trainer     string               `json:"trainer,omitempty"`    // Name of the trainer
+	Notes       string               `json:"notes,omitempty"`      // Additional comments
+	Attendance  []TrainingAttendance `json:"attendance,omitempty"` // Attendance records for this session
+	CreatedAt   time.Time            `json:"createdAt"`            // Timestamp when session was created
+}
+
+// TrainingAttendance represents an expert's attendance at a training session
+type TrainingAttendance struct {
+	ID                    int64      `json:"id"`                              // Primary key identifier
+	SessionID             int64      `json:"sessionId"`                       // Foreign key reference to training session
+	ExpertID              int64      `json:"expertId"`                        // Foreign key reference to expert
+	ExpertName            string     `json:"expertName,omitempty"`            // Name of the expert (not stored in DB)
+	ProgramID             int64      `json:"programId"`                       // Program of the session (not stored in DB)
+	ProgramName           string     `json:"programName,omitempty"`           // Name of the program (not stored in DB)
+	SessionDate           time.Time  `json:"sessionDate"`                     // Date of the session (not stored in DB)
+	Status                string     `json:"status"`                          // Attendance status: "registered", "attended", "absent"
+	CompletedAt           *time.Time `json:"completedAt,omitempty"`           // Date the training was completed
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty"`             // Date the completion lapses (nil = never)
+	CertificateDocumentID *int64     `json:"certificateDocumentId,omitempty"` // Reference to certificate document
+	IsValid               bool       `json:"isValid"`                         // Attended and not yet expired (computed)
+	CreatedAt             time.Time  `json:"createdAt"`                       // Timestamp when record was created
 }
 
 // Statistics represents system-wide statistics
@@ -0,0 +1,152 @@
+// Package email delivers queued outgoing emails for the ExpertDB application
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+	"expertdb/internal/validation"
+)
+
+// Sender delivers a single email
+type Sender interface {
+	Send(recipient, subject, body string) error
+}
+
+// SMTPSender sends emails through an SMTP server
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send delivers the email as plain text
+func (s *SMTPSender) Send(recipient, subject, body string) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + s.From,
+		"To: " + recipient,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{recipient}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// LogSender writes emails to the application log instead of sending them
+// Used when no SMTP server is configured
+type LogSender struct{}
+
+// Send logs the email
+func (LogSender) Send(recipient, subject, body string) error {
+	logger.Get().Info("Email to %s: %s\n%s", recipient, subject, body)
+	return nil
+}
+
+// Dispatcher periodically sends the emails waiting in the outbox
+type Dispatcher struct {
+	store       storage.Storage
+	sender      Sender
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewDispatcher creates an outbox dispatcher
+func NewDispatcher(store storage.Storage, sender Sender, interval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		sender:      sender,
+		interval:    interval,
+		batchSize:   50,
+		maxAttempts: 5,
+	}
+}
+
+// Run sends pending emails every interval until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.DispatchPending()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending sends one batch of pending emails and returns how many were delivered
+// Failed emails stay in the outbox and are retried on later runs
+func (d *Dispatcher) DispatchPending() int {
+	log := logger.Get()
+
+	emails, err := d.store.ListPendingEmails(d.batchSize)
+	if err != nil {
+		log.Error("Failed to load pending emails: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, e := range emails {
+		if err := d.sender.Send(e.Recipient, e.Subject, e.Body); err != nil {
+			log.Warn("Failed to send email %d to %s (attempt %d): %v", e.ID, e.Recipient, e.Attempts+1, err)
+			if err := d.store.MarkEmailFailed(e.ID, err.Error(), d.maxAttempts); err != nil {
+				log.Error("Failed to record email failure: %v", err)
+			}
+			continue
+		}
+
+		if err := d.store.MarkEmailSent(e.ID); err != nil {
+			log.Error("Failed to mark email %d sent: %v", e.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent
+}
+
+// ValidateAddress checks that address is a plain email address that can be used as a recipient
+// Returns an error wrapping domain.ErrValidation otherwise
+func ValidateAddress(address string) error {
+	if result := validation.ValidateEmail("email", address, "email", true); result.HasErrors() {
+		return fmt.Errorf("%w: %s", domain.ErrValidation, result.First())
+	}
+	return nil
+}
+
+// Enqueue queues an email for delivery
+// Malformed recipients are rejected rather than queued, as they could never be delivered
+func Enqueue(store storage.Storage, recipient, subject, body string) error {
+	if err := ValidateAddress(recipient); err != nil {
+		return err
+	}
+	_, err := store.EnqueueEmail(&domain.OutboxEmail{
+		Recipient: recipient,
+		Subject:   subject,
+		Body:      body,
+	})
+	return err
+}
@@ -0,0 +1,56 @@
+package email
+
+import (
+	"errors"
+	"testing"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/storage"
+)
+
+// outboxStore records queued emails; any other storage method panics through the nil embedded interface
+type outboxStore struct {
+	storage.Storage
+	queued []*domain.OutboxEmail
+}
+
+func (s *outboxStore) EnqueueEmail(email *domain.OutboxEmail) (int64, error) {
+	s.queued = append(s.queued, email)
+	return int64(len(s.queued)), nil
+}
+
+func TestEnqueueValidatesRecipient(t *testing.T) {
+	tests := []struct {
+		recipient string
+		valid     bool
+	}{
+		{"expert@example.com", true},
+		{"first.last+cv@uob.edu.bh", true},
+		{"", false},
+		{"expert", false},
+		{"expert@", false},
+		{"@example.com", false},
+		{"expert@example", false},
+		{"two words@example.com", false},
+		{"Expert <expert@example.com>", false},
+		{"expert@example.com\r\nBcc: other@example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.recipient, func(t *testing.T) {
+			store := &outboxStore{}
+			err := Enqueue(store, tt.recipient, "Subject", "Body")
+			if tt.valid {
+				if err != nil || len(store.queued) != 1 || store.queued[0].Recipient != tt.recipient {
+					t.Errorf("Enqueue = %v with %d emails queued, want the email queued", err, len(store.queued))
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Enqueue error = %v, want a validation error", err)
+			}
+			if len(store.queued) != 0 {
+				t.Error("email to a malformed address was queued")
+			}
+		})
+	}
+}
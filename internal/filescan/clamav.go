@@ -0,0 +1,149 @@
+package filescan
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks streamed to clamd
+// It must stay below clamd's StreamMaxLength, which defaults to 25 MB
+const clamdChunkSize = 64 * 1024
+
+// ClamAV scans files with a clamd daemon using its INSTREAM command
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV creates a scanner talking to clamd at address
+// The address is either unix:///path/to/clamd.sock, tcp://host:port, a bare socket path or a bare host:port
+func NewClamAV(address string, timeout time.Duration) (*ClamAV, error) {
+	if address == "" {
+		return nil, fmt.Errorf("ClamAV scanning requires a clamd address")
+	}
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	c := &ClamAV{timeout: timeout}
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		c.network, c.address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		c.network, c.address = "tcp", strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "/"):
+		c.network, c.address = "unix", address
+	default:
+		c.network, c.address = "tcp", address
+	}
+	return c, nil
+}
+
+// Name identifies the scanner in logs and quarantine records
+func (c *ClamAV) Name() string {
+	return "clamav:" + c.network + ":" + c.address
+}
+
+// Ping checks that clamd is reachable
+func (c *ClamAV) Ping() error {
+	reply, err := c.command("zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected reply from clamd: %q", reply)
+	}
+	return nil
+}
+
+// Scan streams r to clamd and reports its verdict
+func (c *ClamAV) Scan(r io.Reader) (Result, error) {
+	reply, err := c.command("zINSTREAM\x00", r)
+	if err != nil {
+		return Result{}, err
+	}
+	return parseScanReply(reply)
+}
+
+// command sends cmd to clamd, followed by the content of body in INSTREAM chunks when body is set,
+// and returns clamd's NUL-terminated reply
+func (c *ClamAV) command(cmd string, body io.Reader) (string, error) {
+	conn, err := net.DialTimeout(c.network, c.address, c.timeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	w := bufio.NewWriterSize(conn, clamdChunkSize+4)
+	if _, err := w.WriteString(cmd); err != nil {
+		return "", fmt.Errorf("failed to send command to clamd: %w", err)
+	}
+	if body != nil {
+		if err := writeChunks(w, body); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("failed to send data to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && (err != io.EOF || reply == "") {
+		return "", fmt.Errorf("failed to read reply from clamd: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// writeChunks writes body as length-prefixed chunks followed by the zero-length terminator
+func writeChunks(w io.Writer, body io.Reader) error {
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, werr := w.Write(size); werr != nil {
+				return fmt.Errorf("failed to stream data to clamd: %w", werr)
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("failed to stream data to clamd: %w", werr)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file for scanning: %w", err)
+		}
+	}
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	if err != nil {
+		return fmt.Errorf("failed to stream data to clamd: %w", err)
+	}
+	return nil
+}
+
+// parseScanReply interprets clamd's reply to INSTREAM, such as "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseScanReply(reply string) (Result, error) {
+	verdict := reply
+	if i := strings.Index(reply, ": "); i >= 0 {
+		verdict = reply[i+2:]
+	}
+	switch {
+	case verdict == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	case strings.HasSuffix(verdict, "ERROR"):
+		return Result{}, fmt.Errorf("clamd could not scan the file: %s", reply)
+	default:
+		return Result{}, fmt.Errorf("unexpected reply from clamd: %q", reply)
+	}
+}
@@ -0,0 +1,235 @@
+package filescan
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClamd is a local stand-in for clamd that answers zPING and zINSTREAM like the real daemon,
+// recording the chunks it receives
+type fakeClamd struct {
+	listener net.Listener
+	reply    func(data []byte) string // Verdict for streamed content, e.g. "stream: OK"
+
+	mu       sync.Mutex
+	commands []string
+	chunks   []int  // Sizes of the chunks of the last stream
+	data     []byte // Content of the last stream
+}
+
+func startFakeClamd(t *testing.T, network, address string, reply func(data []byte) string) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeClamd{listener: listener, reply: reply}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeClamd) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+	cmd = strings.TrimSuffix(cmd, "\x00")
+
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd)
+	f.mu.Unlock()
+
+	switch cmd {
+	case "zPING":
+		io.WriteString(conn, "PONG\x00")
+	case "zINSTREAM":
+		var chunks []int
+		var data []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			chunks = append(chunks, int(size))
+			data = append(data, chunk...)
+		}
+
+		f.mu.Lock()
+		f.chunks, f.data = chunks, data
+		f.mu.Unlock()
+		io.WriteString(conn, f.reply(data)+"\x00")
+	default:
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+	}
+}
+
+func newTestClamAV(t *testing.T, f *fakeClamd) *ClamAV {
+	t.Helper()
+	c, err := NewClamAV("tcp://"+f.listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClamAV: %v", err)
+	}
+	return c
+}
+
+func TestClamAVStreamsChunks(t *testing.T) {
+	f := startFakeClamd(t, "tcp", "127.0.0.1:0", func([]byte) string { return "stream: OK" })
+	c := newTestClamAV(t, f)
+
+	tests := []struct {
+		name   string
+		size   int
+		chunks []int
+	}{
+		{"empty", 0, nil},
+		{"small", 10, []int{10}},
+		{"exact chunk", clamdChunkSize, []int{clamdChunkSize}},
+		{"several chunks", 2*clamdChunkSize + 7, []int{clamdChunkSize, clamdChunkSize, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := bytes.Repeat([]byte("0123456789abcdef"), tt.size/16+1)[:tt.size]
+			result, err := c.Scan(bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if result.Infected {
+				t.Errorf("Scan reported %+v for clean content", result)
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if len(f.chunks) != len(tt.chunks) {
+				t.Fatalf("clamd received chunks %v, want %v", f.chunks, tt.chunks)
+			}
+			for i := range tt.chunks {
+				if f.chunks[i] != tt.chunks[i] {
+					t.Errorf("chunk %d is %d bytes, want %d", i, f.chunks[i], tt.chunks[i])
+				}
+			}
+			if !bytes.Equal(f.data, content) {
+				t.Error("clamd received different content than was scanned")
+			}
+			if cmd := f.commands[len(f.commands)-1]; cmd != "zINSTREAM" {
+				t.Errorf("command = %q, want zINSTREAM", cmd)
+			}
+		})
+	}
+}
+
+func TestClamAVReplies(t *testing.T) {
+	tests := []struct {
+		reply     string
+		infected  bool
+		signature string
+		wantErr   string
+	}{
+		{reply: "stream: OK"},
+		{reply: "OK"},
+		{reply: "stream: Eicar-Test-Signature FOUND", infected: true, signature: "Eicar-Test-Signature"},
+		{reply: "stream: Win.Test.EICAR_HDB-1 FOUND", infected: true, signature: "Win.Test.EICAR_HDB-1"},
+		{reply: "INSTREAM size limit exceeded. ERROR", wantErr: "could not scan"},
+		{reply: "stream: Can't allocate memory ERROR", wantErr: "could not scan"},
+		{reply: "stream: something else", wantErr: "unexpected reply"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			f := startFakeClamd(t, "tcp", "127.0.0.1:0", func([]byte) string { return tt.reply })
+			result, err := newTestClamAV(t, f).Scan(strings.NewReader("content"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Scan error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if result.Infected != tt.infected || result.Signature != tt.signature {
+				t.Errorf("Scan = %+v, want infected %v with signature %q", result, tt.infected, tt.signature)
+			}
+		})
+	}
+}
+
+func TestClamAVPing(t *testing.T) {
+	f := startFakeClamd(t, "unix", filepath.Join(t.TempDir(), "clamd.sock"), nil)
+	c, err := NewClamAV("unix://"+f.listener.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("NewClamAV: %v", err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+}
+
+func TestClamAVUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	c, err := NewClamAV(address, time.Second)
+	if err != nil {
+		t.Fatalf("NewClamAV: %v", err)
+	}
+	if _, err := c.Scan(strings.NewReader("content")); err == nil {
+		t.Error("Scan succeeded without a clamd to talk to")
+	}
+}
+
+func TestNewClamAVAddress(t *testing.T) {
+	tests := []struct {
+		address, network, target string
+	}{
+		{"unix:///var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+		{"/var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+		{"tcp://clamav:3310", "tcp", "clamav:3310"},
+		{"localhost:3310", "tcp", "localhost:3310"},
+	}
+	for _, tt := range tests {
+		c, err := NewClamAV(tt.address, 0)
+		if err != nil {
+			t.Fatalf("NewClamAV(%q): %v", tt.address, err)
+		}
+		if c.network != tt.network || c.address != tt.target {
+			t.Errorf("NewClamAV(%q) dials %s %s, want %s %s", tt.address, c.network, c.address, tt.network, tt.target)
+		}
+		if c.timeout <= 0 {
+			t.Errorf("NewClamAV(%q) has no timeout", tt.address)
+		}
+	}
+	if _, err := NewClamAV("", time.Second); err == nil {
+		t.Error("NewClamAV accepted an empty address")
+	}
+}
@@ -0,0 +1,61 @@
+// Package filescan provides malware scanners for uploaded document files
+package filescan
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Backend names accepted by Open
+const (
+	BackendNone   = "none"
+	BackendClamAV = "clamav"
+)
+
+// Result is the outcome of scanning one file
+type Result struct {
+	Infected  bool   // The scanner flagged the content
+	Signature string // Name of the matched signature when infected
+}
+
+// Scanner inspects file content for malware
+type Scanner interface {
+	// Scan reads r to the end and reports whether its content was flagged
+	// An error means the content could not be scanned, not that it is unsafe
+	Scan(r io.Reader) (Result, error)
+	// Name identifies the scanner in logs and quarantine records
+	Name() string
+}
+
+// Config selects and configures a scanner
+type Config struct {
+	Backend       string        // BackendNone or BackendClamAV
+	ClamAVAddress string        // clamd socket, e.g. unix:///var/run/clamav/clamd.ctl or tcp://localhost:3310
+	Timeout       time.Duration // Per-scan timeout for network scanners
+}
+
+// Open creates the scanner described by cfg
+func Open(cfg Config) (Scanner, error) {
+	switch cfg.Backend {
+	case "", BackendNone:
+		return Nop{}, nil
+	case BackendClamAV:
+		return NewClamAV(cfg.ClamAVAddress, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown malware scanner %q; must be %q or %q", cfg.Backend, BackendNone, BackendClamAV)
+	}
+}
+
+// Nop accepts every file without inspecting it
+type Nop struct{}
+
+// Scan reports r as clean
+func (Nop) Scan(r io.Reader) (Result, error) {
+	return Result{}, nil
+}
+
+// Name identifies the scanner in logs
+func (Nop) Name() string {
+	return BackendNone
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+)
+
+// EngagementScheduler periodically moves engagements through their status by date: pending engagements
+// start on their start date and active ones complete after their end date. Active engagements running
+// longer than the overdue threshold without an end date are flagged in the admin notification feed
+type EngagementScheduler struct {
+	store        storage.Storage
+	interval     time.Duration
+	overdueAfter time.Duration
+}
+
+// NewEngagementScheduler creates an engagement lifecycle job
+func NewEngagementScheduler(store storage.Storage, interval, overdueAfter time.Duration) *EngagementScheduler {
+	return &EngagementScheduler{
+		store:        store,
+		interval:     interval,
+		overdueAfter: overdueAfter,
+	}
+}
+
+// Run advances engagements every interval until the context is cancelled
+func (s *EngagementScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the transitions due now and reports what changed
+func (s *EngagementScheduler) RunOnce() *domain.EngagementLifecycleReport {
+	log := logger.Get()
+
+	report, err := s.store.AdvanceEngagements(time.Now(), s.overdueAfter)
+	if err != nil {
+		log.Error("Failed to advance engagements by date: %v", err)
+	}
+	if report == nil {
+		return &domain.EngagementLifecycleReport{}
+	}
+	if report.Activated > 0 || report.Completed > 0 || report.Overdue > 0 {
+		log.Info("Engagement scheduler: %d activated, %d completed, %d flagged as overdue",
+			report.Activated, report.Completed, report.Overdue)
+	}
+
+	return report
+}
@@ -0,0 +1,56 @@
+// Package jobs contains background jobs run by the ExpertDB server
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"expertdb/internal/logger"
+	"expertdb/internal/storage"
+)
+
+// SLAEscalator periodically escalates expert requests that are past their review target
+// into the admin notification feed
+type SLAEscalator struct {
+	store    storage.Storage
+	interval time.Duration
+}
+
+// NewSLAEscalator creates an SLA escalation job
+func NewSLAEscalator(store storage.Storage, interval time.Duration) *SLAEscalator {
+	return &SLAEscalator{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Run checks for breaches every interval until the context is cancelled
+func (e *SLAEscalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce escalates newly breaching requests and returns how many were escalated
+func (e *SLAEscalator) RunOnce() int {
+	log := logger.Get()
+
+	escalated, err := e.store.EscalateBreachedExpertRequests(time.Now())
+	if err != nil {
+		log.Error("Failed to escalate expert requests past their review target: %v", err)
+	}
+	if escalated > 0 {
+		log.Info("Escalated %d expert requests past their review target", escalated)
+	}
+
+	return escalated
+}
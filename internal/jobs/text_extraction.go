@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"expertdb/internal/documents"
+	"expertdb/internal/logger"
+)
+
+// TextExtractionBackfill periodically extracts text from documents that have none yet,
+// covering documents uploaded before extraction existed and uploads whose file could not be read
+type TextExtractionBackfill struct {
+	documents *documents.Service
+	interval  time.Duration
+}
+
+// NewTextExtractionBackfill creates a text extraction backfill job
+func NewTextExtractionBackfill(documentService *documents.Service, interval time.Duration) *TextExtractionBackfill {
+	return &TextExtractionBackfill{
+		documents: documentService,
+		interval:  interval,
+	}
+}
+
+// Run extracts pending documents every interval until the context is cancelled
+func (b *TextExtractionBackfill) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		b.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce extracts text from every pending document and returns how many were processed
+func (b *TextExtractionBackfill) RunOnce() int {
+	log := logger.Get()
+
+	report, err := b.documents.ExtractDocumentText(false)
+	if err != nil {
+		log.Error("Failed to extract text from stored documents: %v", err)
+		return 0
+	}
+	return report.Total
+}
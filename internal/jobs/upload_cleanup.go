@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"expertdb/internal/documents"
+	"expertdb/internal/logger"
+)
+
+// UploadCleanup periodically discards resumable uploads that were abandoned, or completed but never used,
+// along with their stored chunks
+type UploadCleanup struct {
+	documents *documents.Service
+	interval  time.Duration
+}
+
+// NewUploadCleanup creates an upload cleanup job
+func NewUploadCleanup(documentService *documents.Service, interval time.Duration) *UploadCleanup {
+	return &UploadCleanup{
+		documents: documentService,
+		interval:  interval,
+	}
+}
+
+// Run discards expired uploads every interval until the context is cancelled
+func (c *UploadCleanup) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce discards every expired upload and returns how many were discarded
+func (c *UploadCleanup) RunOnce() int {
+	log := logger.Get()
+
+	purged, err := c.documents.PurgeExpiredUploads()
+	if err != nil {
+		log.Error("Failed to discard expired uploads: %v", err)
+	}
+	if purged > 0 {
+		log.Info("Discarded %d expired uploads", purged)
+	}
+	return purged
+}
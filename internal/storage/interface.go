@@ -2,6 +2,8 @@
 package storage
 
 import (
+	"time"
+	
 	"expertdb/internal/domain"
 )
 
@@ -19,15 +21,48 @@ type Storage interface {
 	CreateExpertEditHistory(entry *domain.ExpertEditHistoryEntry) error
 	
 	// Expert request methods
-	ListExpertRequests(status string, limit, offset int) ([]*domain.ExpertRequest, error)
-	ListExpertRequestsByUser(userID int64, status string, limit, offset int) ([]*domain.ExpertRequest, error)
+	ListExpertRequests(filters map[string]interface{}, limit, offset int) ([]*domain.ExpertRequest, error)
+	ListExpertRequestsByUser(userID int64, filters map[string]interface{}, limit, offset int) ([]*domain.ExpertRequest, error)
+	CountExpertRequests(filters map[string]interface{}) (int, error)
 	GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 	CreateExpertRequest(req *domain.ExpertRequest) (int64, error)
-	UpdateExpertRequestStatus(id int64, status, rejectionReason string, reviewedBy int64) error
+	FindDuplicateExperts(name, email, phone string, excludeRequestID int64) ([]*domain.DuplicateMatch, error)
+	SetExpertRequestDuplicates(id int64, matches []*domain.DuplicateMatch, confirmed bool) error
+	CreateExpertRequestWithDocument(req *domain.ExpertRequest, doc *domain.Document, writeFile func(requestID int64) (string, error)) (int64, error)
+	TransitionExpertRequest(id int64, fromStatus, toStatus, note string, changedBy int64) error
+	ListExpertRequestHistory(requestID int64) ([]*domain.ExpertRequestStatusChange, error)
+	CreateExpertRequestComment(comment *domain.ExpertRequestComment) (int64, error)
+	ListExpertRequestComments(requestID int64, includeInternal bool) ([]*domain.ExpertRequestComment, error)
+	MarkExpertRequestCommentsRead(requestID, userID int64) error
+	CountUnreadExpertRequestComments(requestID, userID int64, includeInternal bool) (int, error)
 	UpdateExpertRequest(req *domain.ExpertRequest) error
-	ApproveExpertRequestWithDocument(requestID, reviewedBy int64, documentService interface{}) (int64, error)
-	BatchApproveExpertRequestsWithFileMove(requestIDs []int64, reviewedBy int64, documentService interface{}) ([]int64, []int64, map[int64]error)
+	UpdateAndResubmitExpertRequest(req *domain.ExpertRequest, fromStatus, note string, changedBy int64) error
+	ApproveExpertRequestWithDocument(requestID, reviewedBy int64, documentService interface{}) (*domain.ExpertRequestApprovalResult, error)
+	BatchApproveExpertRequestsWithFileMove(requestIDs []int64, reviewedBy int64, documentService interface{}) ([]*domain.ExpertRequestApprovalResult, map[int64]error)
+	BatchUpdateExpertRequests(batch *domain.ExpertRequestBatch, userID int64, isReviewer bool) ([]int64, map[int64]error)
 	UpdateExpertsApprovalPath(expertIDs []int64, approvalPath string) error
+	ListExpertRequestApprovals(requestID int64) ([]*domain.ExpertRequestApproval, error)
+	ExpertRequestApprovalPolicy() domain.ExpertRequestApprovalPolicy
+	ExpertRequestSLA() domain.ExpertRequestSLA
+	GetExpertRequestAgingReport(now time.Time) (*domain.ExpertRequestAgingReport, error)
+	EscalateBreachedExpertRequests(now time.Time) (int, error)
+	
+	// Admin notification methods
+	ListAdminNotifications(unacknowledgedOnly bool, limit, offset int) ([]*domain.AdminNotification, error)
+	CountAdminNotifications(unacknowledgedOnly bool) (int, error)
+	AcknowledgeAdminNotification(id, userID int64) error
+	
+	// Nomination invite methods
+	CreateNominationInvite(invite *domain.NominationInvite, token string) (int64, error)
+	GetNominationInviteByToken(token string) (*domain.NominationInvite, error)
+	ListNominationInvites() ([]*domain.NominationInvite, error)
+	RevokeNominationInvite(id int64) error
+	
+	// Email outbox methods
+	EnqueueEmail(email *domain.OutboxEmail) (int64, error)
+	ListPendingEmails(limit int) ([]*domain.OutboxEmail, error)
+	MarkEmailSent(id int64) error
+	MarkEmailFailed(id int64, sendErr string, maxAttempts int) error
 	
 	// Document reference methods
 	UpdateExpertCVDocument(expertID, documentID int64) error
@@ -50,13 +85,26 @@ type Storage interface {
 	// Area methods
 	ListAreas() ([]*domain.Area, error)
 	GetArea(id int64) (*domain.Area, error)
-	CreateArea(name string) (int64, error)
+	CreateArea(name string, parentID *int64) (int64, error)
 	UpdateArea(id int64, name string) error
+	MoveArea(id int64, parentID *int64) error
+	GetAreaDescendantIDs(id int64) ([]int64, error)
+	MergeAreas(sourceID, targetID, performedBy int64) (*domain.AreaMergeResult, error)
+	SetAreaRetired(id int64, retired bool, reason string, performedBy int64) error
+	ListAreaAuditLog(areaType string, areaID int64) ([]*domain.AreaAuditEntry, error)
 	
 	// Specialized area methods
 	ListSpecializedAreas() ([]*domain.SpecializedArea, error)
+	GetSpecializedArea(id int64) (*domain.SpecializedArea, error)
 	GetSpecializedAreasByIds(ids []int64) ([]*domain.SpecializedArea, error)
 	CreateSpecializedArea(area *domain.SpecializedArea) (int64, error)
+	UpdateSpecializedAreaGeneralArea(id int64, generalAreaID *int64) error
+	MergeSpecializedAreas(sourceID, targetID, performedBy int64) (*domain.AreaMergeResult, error)
+	SetSpecializedAreaRetired(id int64, retired bool, reason string, performedBy int64) error
+	ListSpecializedAreaSuggestions() ([]*domain.SpecializedAreaSuggestion, error)
+	AcceptSpecializedAreaSuggestion(name string, generalAreaID *int64, resolvedBy int64) (*domain.SuggestionResolution, error)
+	MapSpecializedAreaSuggestion(name string, specializedAreaID, resolvedBy int64) (*domain.SuggestionResolution, error)
+	ListSuggestionResolutions() ([]*domain.SuggestionResolution, error)
 	
 	// Document methods
 	ListDocuments(expertID int64) ([]*domain.Document, error)
@@ -64,6 +112,52 @@ type Storage interface {
 	CreateDocument(doc *domain.Document) (int64, error)
 	UpdateDocument(doc *domain.Document) error
 	DeleteDocument(id int64) error
+	FindDocumentBySHA256(sum string) (*domain.Document, error)
+	CountFileReferences(filePath string, excludeDocumentID int64) (int, error)
+	CountDocumentExperts(documentID, excludeExpertID int64) (int, error)
+	ListAllDocuments() ([]*domain.Document, error)
+	SetDocumentSHA256(id int64, sum string) error
+	SetDocumentText(id int64, text string) error
+	GetDocumentText(id int64) (string, error)
+	ListDocumentsWithoutText() ([]*domain.Document, error)
+	UpdateDocumentFilePath(oldPath, newPath string) (int64, error)
+	AddDocumentVersion(version *domain.DocumentVersion) (*domain.Document, error)
+	ListDocumentVersions(documentID int64) ([]*domain.DocumentVersion, error)
+	ListAllDocumentVersions() ([]*domain.DocumentVersion, error)
+	ListDanglingDocumentReferences() ([]domain.DanglingDocumentReference, error)
+	ClearDocumentReference(ref domain.DanglingDocumentReference) error
+	GetDocumentVersion(documentID int64, version int) (*domain.DocumentVersion, error)
+	PromoteDocumentVersion(documentID int64, version int) (*domain.Document, error)
+	CreateQuarantinedUpload(upload *domain.QuarantinedUpload) (int64, error)
+	ListQuarantinedUploads() ([]*domain.QuarantinedUpload, error)
+	GetQuarantinedUpload(id int64) (*domain.QuarantinedUpload, error)
+	DeleteQuarantinedUpload(id int64) error
+	
+	// Document download link methods
+	CreateDocumentDownloadLink(link *domain.DocumentDownloadLink) (int64, error)
+	GetDocumentDownloadLink(id int64) (*domain.DocumentDownloadLink, error)
+	ListDocumentDownloadLinks(documentID int64) ([]*domain.DocumentDownloadLink, error)
+	RevokeDocumentDownloadLink(id int64) error
+	UseDocumentDownloadLink(download *domain.DocumentDownload) error
+	ListDocumentDownloads(linkID int64) ([]*domain.DocumentDownload, error)
+	
+	// Document type registry methods
+	ListDocumentTypes() ([]*domain.DocumentType, error)
+	GetDocumentType(code string) (*domain.DocumentType, error)
+	CreateDocumentType(t *domain.DocumentType) error
+	UpdateDocumentType(t *domain.DocumentType) error
+	SetDocumentTypeRetired(code string, retired bool) error
+	CountExpertDocumentsOfType(expertID int64, code string) (int, error)
+	
+	// Resumable upload methods
+	CreateUploadSession(upload *domain.UploadSession) (int64, error)
+	GetUploadSession(id int64) (*domain.UploadSession, error)
+	AddUploadChunk(chunk *domain.UploadChunk) (bool, error)
+	ListUploadChunks(uploadID int64) ([]*domain.UploadChunk, error)
+	ListAllUploadChunks() ([]*domain.UploadChunk, error)
+	CompleteUploadSession(id int64) error
+	DeleteUploadSession(id int64) error
+	ListExpiredUploadSessions(now time.Time) ([]*domain.UploadSession, error)
 	
 	// Engagement methods
 	ListEngagements(expertID int64, engagementType string, limit, offset int) ([]*domain.Engagement, error)
@@ -72,6 +166,20 @@ type Storage interface {
 	UpdateEngagement(engagement *domain.Engagement) error
 	DeleteEngagement(id int64) error
 	ImportEngagements(engagements []*domain.Engagement) (int, map[int]error)
+	AdvanceEngagements(now time.Time, overdueAfter time.Duration) (*domain.EngagementLifecycleReport, error)
+	
+	// Training methods
+	ListTrainingPrograms() ([]*domain.TrainingProgram, error)
+	GetTrainingProgram(id int64) (*domain.TrainingProgram, error)
+	CreateTrainingProgram(program *domain.TrainingProgram) (int64, error)
+	UpdateTrainingProgram(program *domain.TrainingProgram) error
+	ListTrainingSessions(programID int64) ([]*domain.TrainingSession, error)
+	GetTrainingSession(id int64) (*domain.TrainingSession, error)
+	CreateTrainingSession(session *domain.TrainingSession) (int64, error)
+	GetTrainingAttendance(id int64) (*domain.TrainingAttendance, error)
+	RecordTrainingAttendance(attendance *domain.TrainingAttendance) (int64, error)
+	DeleteTrainingAttendance(id int64) error
+	ListExpertTrainingRecords(expertID int64) ([]*domain.TrainingAttendance, error)
 	
 	// Statistics methods
 	GetStatistics(years int) (*domain.Statistics, error)
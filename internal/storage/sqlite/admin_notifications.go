@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// ListAdminNotifications retrieves admin notifications, newest first
+func (s *SQLiteStore) ListAdminNotifications(unacknowledgedOnly bool, limit, offset int) ([]*domain.AdminNotification, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := `
+		SELECT n.id, n.kind, n.request_id, COALESCE(r.name, ''), n.engagement_id, COALESCE(n.status, ''), n.message,
+			n.created_at, n.acknowledged_at, n.acknowledged_by
+		FROM admin_notifications n
+		LEFT JOIN expert_requests r ON r.id = n.request_id
+	`
+	if unacknowledgedOnly {
+		query += " WHERE n.acknowledged_at IS NULL"
+	}
+	query += " ORDER BY n.created_at DESC, n.id DESC LIMIT ? OFFSET ?"
+
+	rows, err := s.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []*domain.AdminNotification{}
+	for rows.Next() {
+		var n domain.AdminNotification
+		var requestID, engagementID, acknowledgedBy sql.NullInt64
+		var acknowledgedAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.Kind, &requestID, &n.RequestName, &engagementID, &n.Status, &n.Message,
+			&n.CreatedAt, &acknowledgedAt, &acknowledgedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan admin notification: %w", err)
+		}
+		if requestID.Valid {
+			n.RequestID = &requestID.Int64
+		}
+		if engagementID.Valid {
+			n.EngagementID = &engagementID.Int64
+		}
+		if acknowledgedAt.Valid {
+			n.AcknowledgedAt = &acknowledgedAt.Time
+		}
+		if acknowledgedBy.Valid {
+			n.AcknowledgedBy = &acknowledgedBy.Int64
+		}
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admin notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountAdminNotifications counts admin notifications, optionally only those not yet acknowledged
+func (s *SQLiteStore) CountAdminNotifications(unacknowledgedOnly bool) (int, error) {
+	query := "SELECT COUNT(*) FROM admin_notifications"
+	if unacknowledgedOnly {
+		query += " WHERE acknowledged_at IS NULL"
+	}
+
+	var count int
+	if err := s.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count admin notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// AcknowledgeAdminNotification marks a notification as handled
+// Acknowledging an already acknowledged notification keeps the original acknowledgement
+func (s *SQLiteStore) AcknowledgeAdminNotification(id, userID int64) error {
+	result, err := s.db.Exec(`
+		UPDATE admin_notifications SET acknowledged_at = ?, acknowledged_by = ?
+		WHERE id = ? AND acknowledged_at IS NULL
+	`, time.Now(), userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge admin notification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM admin_notifications WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check admin notification: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+	}
+
+	return nil
+}
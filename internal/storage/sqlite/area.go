@@ -4,14 +4,20 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 	
 	"expertdb/internal/domain"
 	"expertdb/internal/logger"
 )
 
-// ListAreas retrieves all expert areas
+// ListAreas retrieves all expert areas with their parent and full path, ordered by path
 func (s *SQLiteStore) ListAreas() ([]*domain.Area, error) {
-	query := "SELECT id, name FROM expert_areas ORDER BY name"
+	query := `
+		SELECT a.id, a.name, a.parent_id, COALESCE(p.path, a.name), a.retired_at, a.merged_into_id
+		FROM expert_areas a
+		LEFT JOIN expert_area_paths p ON p.id = a.id
+		ORDER BY COALESCE(p.path, a.name)
+	`
 	
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -22,9 +28,20 @@ func (s *SQLiteStore) ListAreas() ([]*domain.Area, error) {
 	var areas []*domain.Area
 	for rows.Next() {
 		var area domain.Area
-		if err := rows.Scan(&area.ID, &area.Name); err != nil {
+		var parentID, mergedIntoID sql.NullInt64
+		var retiredAt sql.NullTime
+		if err := rows.Scan(&area.ID, &area.Name, &parentID, &area.Path, &retiredAt, &mergedIntoID); err != nil {
 			return nil, fmt.Errorf("failed to scan area row: %w", err)
 		}
+		if parentID.Valid {
+			area.ParentID = &parentID.Int64
+		}
+		if retiredAt.Valid {
+			area.RetiredAt = &retiredAt.Time
+		}
+		if mergedIntoID.Valid {
+			area.MergedIntoID = &mergedIntoID.Int64
+		}
 		areas = append(areas, &area)
 	}
 	
@@ -38,18 +55,34 @@ func (s *SQLiteStore) ListAreas() ([]*domain.Area, error) {
 // GetArea retrieves a specific area by its ID
 func (s *SQLiteStore) GetArea(id int64) (*domain.Area, error) {
 	var area domain.Area
-	err := s.db.QueryRow("SELECT id, name FROM expert_areas WHERE id = ?", id).Scan(&area.ID, &area.Name)
+	var parentID, mergedIntoID sql.NullInt64
+	var retiredAt sql.NullTime
+	err := s.db.QueryRow(`
+		SELECT a.id, a.name, a.parent_id, COALESCE(p.path, a.name), a.retired_at, a.merged_into_id
+		FROM expert_areas a
+		LEFT JOIN expert_area_paths p ON p.id = a.id
+		WHERE a.id = ?
+	`, id).Scan(&area.ID, &area.Name, &parentID, &area.Path, &retiredAt, &mergedIntoID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get expert area: %w", err)
 	}
+	if parentID.Valid {
+		area.ParentID = &parentID.Int64
+	}
+	if retiredAt.Valid {
+		area.RetiredAt = &retiredAt.Time
+	}
+	if mergedIntoID.Valid {
+		area.MergedIntoID = &mergedIntoID.Int64
+	}
 	return &area, nil
 }
 
-// CreateArea creates a new expert area with the given name
-func (s *SQLiteStore) CreateArea(name string) (int64, error) {
+// CreateArea creates a new expert area with the given name, optionally under a parent area
+func (s *SQLiteStore) CreateArea(name string, parentID *int64) (int64, error) {
 	log := logger.Get()
 	
 	// Check for empty name
@@ -68,8 +101,18 @@ func (s *SQLiteStore) CreateArea(name string) (int64, error) {
 		return 0, fmt.Errorf("area with name '%s' already exists", name)
 	}
 	
+	// Check that the parent area exists
+	if parentID != nil {
+		if _, err := s.GetArea(*parentID); err != nil {
+			if err == domain.ErrNotFound {
+				return 0, fmt.Errorf("parent area %d does not exist", *parentID)
+			}
+			return 0, err
+		}
+	}
+	
 	// Insert new area
-	result, err := s.db.Exec("INSERT INTO expert_areas (name) VALUES (?)", name)
+	result, err := s.db.Exec("INSERT INTO expert_areas (name, parent_id) VALUES (?, ?)", name, parentID)
 	if err != nil {
 		log.Error("Failed to insert new area: %v", err)
 		return 0, fmt.Errorf("failed to create expert area: %w", err)
@@ -142,4 +185,256 @@ func (s *SQLiteStore) UpdateArea(id int64, name string) error {
 	
 	log.Info("Updated expert area ID %d from '%s' to '%s'", id, existing.Name, name)
 	return nil
-}
\ No newline at end of file
+}
+
+// MoveArea changes the parent of an area; a nil parentID makes it a top-level area
+func (s *SQLiteStore) MoveArea(id int64, parentID *int64) error {
+	log := logger.Get()
+	
+	if _, err := s.GetArea(id); err != nil {
+		return err
+	}
+	
+	if parentID != nil {
+		if *parentID == id {
+			return fmt.Errorf("an area cannot be its own parent")
+		}
+		
+		if _, err := s.GetArea(*parentID); err != nil {
+			if err == domain.ErrNotFound {
+				return fmt.Errorf("parent area %d does not exist", *parentID)
+			}
+			return err
+		}
+		
+		// Reject moves that would create a cycle (parent is a descendant of the area)
+		descendants, err := s.GetAreaDescendantIDs(id)
+		if err != nil {
+			return err
+		}
+		for _, descendantID := range descendants {
+			if descendantID == *parentID {
+				return fmt.Errorf("cannot move an area under one of its own descendants")
+			}
+		}
+	}
+	
+	_, err := s.db.Exec("UPDATE expert_areas SET parent_id = ? WHERE id = ?", parentID, id)
+	if err != nil {
+		return fmt.Errorf("failed to move area: %w", err)
+	}
+	
+	if parentID == nil {
+		log.Info("Moved expert area ID %d to top level", id)
+	} else {
+		log.Info("Moved expert area ID %d under parent %d", id, *parentID)
+	}
+	return nil
+}
+
+// GetAreaDescendantIDs returns the IDs of all areas below the given area (excluding the area itself)
+func (s *SQLiteStore) GetAreaDescendantIDs(id int64) ([]int64, error) {
+	rows, err := s.db.Query(`
+		WITH RECURSIVE area_tree(id) AS (
+			SELECT id FROM expert_areas WHERE parent_id = ?
+			UNION
+			SELECT a.id FROM expert_areas a JOIN area_tree t ON a.parent_id = t.id
+		)
+		SELECT id FROM area_tree
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get descendant areas: %w", err)
+	}
+	defer rows.Close()
+	
+	var ids []int64
+	for rows.Next() {
+		var descendantID int64
+		if err := rows.Scan(&descendantID); err != nil {
+			return nil, fmt.Errorf("failed to scan descendant area: %w", err)
+		}
+		ids = append(ids, descendantID)
+	}
+	
+	return ids, rows.Err()
+}
+
+// MergeAreas repoints every expert, expert request, specialized area and child area from the
+// source area to the target area in a single transaction, then retires the source
+func (s *SQLiteStore) MergeAreas(sourceID, targetID, performedBy int64) (*domain.AreaMergeResult, error) {
+	log := logger.Get()
+	
+	if sourceID == targetID {
+		return nil, fmt.Errorf("an area cannot be merged into itself")
+	}
+	
+	source, err := s.GetArea(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.MergedIntoID != nil {
+		return nil, fmt.Errorf("area '%s' has already been merged", source.Name)
+	}
+	
+	target, err := s.GetArea(targetID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, fmt.Errorf("target area %d does not exist", targetID)
+		}
+		return nil, err
+	}
+	if target.RetiredAt != nil {
+		return nil, fmt.Errorf("cannot merge into retired area '%s'", target.Name)
+	}
+	
+	// The source's children move under the target, so the target must not be one of them
+	descendants, err := s.GetAreaDescendantIDs(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, descendantID := range descendants {
+		if descendantID == targetID {
+			return nil, fmt.Errorf("cannot merge an area into one of its own descendants")
+		}
+	}
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result := &domain.AreaMergeResult{SourceID: sourceID, TargetID: targetID}
+	
+	// Collect affected experts first so each one gets an edit history entry
+	rows, err := tx.Query("SELECT id FROM experts WHERE general_area = ?", sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find experts in area: %w", err)
+	}
+	var expertIDs []int64
+	for rows.Next() {
+		var expertID int64
+		if err := rows.Scan(&expertID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expert ID: %w", err)
+		}
+		expertIDs = append(expertIDs, expertID)
+	}
+	rows.Close()
+	
+	now := time.Now()
+	reason := fmt.Sprintf("Area '%s' merged into '%s'", source.Path, target.Path)
+	for _, expertID := range expertIDs {
+		_, err = tx.Exec("UPDATE experts SET general_area = ?, updated_at = ?, last_edited_by = ?, last_edited_at = ? WHERE id = ?",
+			targetID, now, performedBy, now, expertID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repoint expert %d: %w", expertID, err)
+		}
+		
+		err = s.createExpertEditHistoryWithReasonTx(tx, expertID, performedBy, []string{"generalArea"},
+			map[string]interface{}{"generalArea": sourceID}, map[string]interface{}{"generalArea": targetID}, reason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit history: %w", err)
+		}
+	}
+	result.ExpertsUpdated = len(expertIDs)
+	
+	res, err := tx.Exec("UPDATE expert_requests SET general_area = ? WHERE general_area = ?", targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to repoint expert requests: %w", err)
+	}
+	requestsUpdated, _ := res.RowsAffected()
+	result.RequestsUpdated = int(requestsUpdated)
+	
+	res, err = tx.Exec("UPDATE specialized_areas SET general_area_id = ? WHERE general_area_id = ?", targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remap specialized areas: %w", err)
+	}
+	specializedUpdated, _ := res.RowsAffected()
+	result.SpecializedAreasUpdated = int(specializedUpdated)
+	
+	res, err = tx.Exec("UPDATE expert_areas SET parent_id = ? WHERE parent_id = ?", targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move child areas: %w", err)
+	}
+	childrenMoved, _ := res.RowsAffected()
+	result.ChildAreasMoved = int(childrenMoved)
+	
+	_, err = tx.Exec("UPDATE expert_areas SET retired_at = COALESCE(retired_at, ?), merged_into_id = ? WHERE id = ?", now, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire merged area: %w", err)
+	}
+	
+	err = s.writeAreaAuditTx(tx, "general", sourceID, "merge", &targetID, map[string]interface{}{
+		"sourceName":              source.Path,
+		"targetName":              target.Path,
+		"expertsUpdated":          result.ExpertsUpdated,
+		"requestsUpdated":         result.RequestsUpdated,
+		"specializedAreasUpdated": result.SpecializedAreasUpdated,
+		"childAreasMoved":         result.ChildAreasMoved,
+	}, performedBy)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit area merge: %w", err)
+	}
+	
+	log.Info("Merged expert area %d into %d: %d experts, %d requests repointed", sourceID, targetID, result.ExpertsUpdated, result.RequestsUpdated)
+	return result, nil
+}
+
+// SetAreaRetired retires an area (hiding it from new selections) or restores a retired area
+// Existing experts and requests keep their reference either way
+func (s *SQLiteStore) SetAreaRetired(id int64, retired bool, reason string, performedBy int64) error {
+	area, err := s.GetArea(id)
+	if err != nil {
+		return err
+	}
+	
+	if retired == (area.RetiredAt != nil) {
+		return nil // Already in the requested state
+	}
+	if !retired && area.MergedIntoID != nil {
+		return fmt.Errorf("area '%s' was merged and cannot be restored", area.Name)
+	}
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	action := "restore"
+	var retiredAt interface{}
+	if retired {
+		action = "retire"
+		retiredAt = time.Now()
+	}
+	
+	if _, err := tx.Exec("UPDATE expert_areas SET retired_at = ? WHERE id = ?", retiredAt, id); err != nil {
+		return fmt.Errorf("failed to update area: %w", err)
+	}
+	
+	var expertCount int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM experts WHERE general_area = ?", id).Scan(&expertCount); err != nil {
+		return fmt.Errorf("failed to count experts in area: %w", err)
+	}
+	
+	err = s.writeAreaAuditTx(tx, "general", id, action, nil, map[string]interface{}{
+		"name":          area.Path,
+		"reason":        reason,
+		"expertsInArea": expertCount,
+	}, performedBy)
+	if err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit area %s: %w", action, err)
+	}
+	
+	logger.Get().Info("Expert area %d %sd by user %d", id, action, performedBy)
+	return nil
+}
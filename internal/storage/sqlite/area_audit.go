@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// writeAreaAuditTx records an area maintenance operation within a transaction
+func (s *SQLiteStore) writeAreaAuditTx(tx *sql.Tx, areaType string, areaID int64, action string, targetAreaID *int64, details map[string]interface{}, performedBy int64) error {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO area_audit_log (area_type, area_id, action, target_area_id, details, performed_by, performed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, areaType, areaID, action, targetAreaID, string(detailsJSON), performedBy, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to write area audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListAreaAuditLog retrieves area maintenance entries, newest first
+// If areaType is empty all types are returned; if areaID is 0 all areas are returned
+func (s *SQLiteStore) ListAreaAuditLog(areaType string, areaID int64) ([]*domain.AreaAuditEntry, error) {
+	query := `
+		SELECT al.id, al.area_type, al.area_id, al.action, al.target_area_id, al.details,
+		       al.performed_by, u.name, al.performed_at
+		FROM area_audit_log al
+		LEFT JOIN users u ON u.id = al.performed_by
+		WHERE 1=1
+	`
+	var params []interface{}
+	if areaType != "" {
+		query += " AND al.area_type = ?"
+		params = append(params, areaType)
+	}
+	if areaID > 0 {
+		query += " AND (al.area_id = ? OR al.target_area_id = ?)"
+		params = append(params, areaID, areaID)
+	}
+	query += " ORDER BY al.performed_at DESC, al.id DESC"
+
+	rows, err := s.db.Query(query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query area audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AreaAuditEntry
+	for rows.Next() {
+		var entry domain.AreaAuditEntry
+		var targetAreaID sql.NullInt64
+		var details, performerName sql.NullString
+
+		err := rows.Scan(&entry.ID, &entry.AreaType, &entry.AreaID, &entry.Action, &targetAreaID,
+			&details, &entry.PerformedBy, &performerName, &entry.PerformedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan area audit entry: %w", err)
+		}
+
+		if targetAreaID.Valid {
+			entry.TargetAreaID = &targetAreaID.Int64
+		}
+		if details.Valid {
+			entry.Details = details.String
+		}
+		if performerName.Valid {
+			entry.PerformerName = performerName.String
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating area audit rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// replaceSpecializedAreaID swaps one ID for another in a comma-separated specialized area list,
+// dropping duplicates while keeping the original order
+func replaceSpecializedAreaID(list string, sourceID, targetID int64) string {
+	source := strconv.FormatInt(sourceID, 10)
+	target := strconv.FormatInt(targetID, 10)
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, value := range parseMultiValue(list) {
+		if value == source {
+			value = target
+		}
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+
+	return strings.Join(result, ",")
+}
+
+// containsSpecializedAreaCondition matches rows whose comma-separated column contains the given ID parameter
+func containsSpecializedAreaCondition(column string) string {
+	return fmt.Sprintf("',' || REPLACE(%s, ' ', '') || ',' LIKE '%%,' || ? || ',%%'", column)
+}
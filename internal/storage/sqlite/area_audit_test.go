@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestReplaceSpecializedAreaID(t *testing.T) {
+	tests := []struct {
+		list string
+		want string
+	}{
+		{"3", "9"},
+		{"1,3,5", "1,9,5"},
+		{"1, 3 ,5", "1,9,5"},
+		{"3,9", "9"},
+		{"9,3", "9"},
+		{"13,31", "13,31"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := replaceSpecializedAreaID(tt.list, 3, 9); got != tt.want {
+			t.Errorf("replaceSpecializedAreaID(%q, 3, 9) = %q, want %q", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAreas(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	source := createTestArea(t, s, "Finance")
+	target := createTestArea(t, s, "Business")
+	child := createTestChildArea(t, s, "Banking", source)
+	expertID := createTestExpert(t, s, "Moved expert", "moved@example.com", source)
+	requestID := createTestExpertRequest(t, s, newTestExpertRequest("Moved request", source, admin))
+	specialized, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: "Auditing", GeneralAreaID: &source})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.MergeAreas(source, target, admin)
+	if err != nil {
+		t.Fatalf("MergeAreas: %v", err)
+	}
+	want := domain.AreaMergeResult{SourceID: source, TargetID: target, ExpertsUpdated: 1, RequestsUpdated: 1,
+		SpecializedAreasUpdated: 1, ChildAreasMoved: 1}
+	if *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+
+	if expert, _ := s.GetExpert(expertID); expert.GeneralArea != target {
+		t.Errorf("expert in area %d, want %d", expert.GeneralArea, target)
+	}
+	if req, _ := s.GetExpertRequest(requestID); req.GeneralArea != target {
+		t.Errorf("request in area %d, want %d", req.GeneralArea, target)
+	}
+	if area, _ := s.GetSpecializedArea(specialized); area.GeneralAreaID == nil || *area.GeneralAreaID != target {
+		t.Errorf("specialized area mapped to %v, want %d", area.GeneralAreaID, target)
+	}
+	if area, _ := s.GetArea(child); area.ParentID == nil || *area.ParentID != target {
+		t.Errorf("child area under %v, want %d", area.ParentID, target)
+	}
+	merged, _ := s.GetArea(source)
+	if merged.RetiredAt == nil || merged.MergedIntoID == nil || *merged.MergedIntoID != target {
+		t.Errorf("source area retired %v, merged into %v; want retired and merged into %d", merged.RetiredAt, merged.MergedIntoID, target)
+	}
+
+	entries, err := s.ListAreaAuditLog("general", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Action != "merge" || entries[0].PerformedBy != admin ||
+		entries[0].TargetAreaID == nil || *entries[0].TargetAreaID != target {
+		t.Errorf("audit log = %+v, want the merge by user %d", entries, admin)
+	}
+
+	// A merged area is gone for good
+	if err := s.SetAreaRetired(source, false, "", admin); err == nil {
+		t.Error("merged area restored")
+	}
+	if _, err := s.MergeAreas(source, target, admin); err == nil {
+		t.Error("area merged twice")
+	}
+}
+
+func TestMergeAreasRejects(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	parent := createTestArea(t, s, "Business")
+	child := createTestChildArea(t, s, "Finance", parent)
+	retired := createTestArea(t, s, "Old")
+	if err := s.SetAreaRetired(retired, true, "Unused", admin); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		source, target int64
+	}{
+		{"into itself", parent, parent},
+		{"into a descendant", parent, child},
+		{"into a retired area", child, retired},
+		{"into a missing area", child, 999999},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.MergeAreas(tt.source, tt.target, admin); err == nil {
+				t.Error("MergeAreas succeeded")
+			}
+			if area, _ := s.GetArea(tt.source); area.MergedIntoID != nil {
+				t.Error("source area marked as merged")
+			}
+		})
+	}
+}
+
+func TestMergeSpecializedAreas(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+	create := func(name string) int64 {
+		id, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: fmt.Sprintf("%s %d", name, fixtureSeq.Add(1))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	source, target, other := create("Structures"), create("Civil"), create("Roads")
+
+	tests := []struct {
+		name, before, after string
+	}{
+		{"source only", fmt.Sprint(source), fmt.Sprint(target)},
+		{"source and target", fmt.Sprintf("%d,%d", source, target), fmt.Sprint(target)},
+		{"among others", fmt.Sprintf("%d,%d", other, source), fmt.Sprintf("%d,%d", other, target)},
+		{"unrelated", fmt.Sprint(other), fmt.Sprint(other)},
+	}
+	experts := make([]int64, len(tests))
+	for i, tt := range tests {
+		experts[i] = createTestExpert(t, s, tt.name, fmt.Sprintf("specialized%d@example.com", i), area)
+		if _, err := s.db.Exec("UPDATE experts SET specialized_area = ? WHERE id = ?", tt.before, experts[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := s.MergeSpecializedAreas(source, target, admin)
+	if err != nil {
+		t.Fatalf("MergeSpecializedAreas: %v", err)
+	}
+	if result.ExpertsUpdated != 3 {
+		t.Errorf("%d experts updated, want 3", result.ExpertsUpdated)
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if expert, _ := s.GetExpert(experts[i]); expert.SpecializedArea != tt.after {
+				t.Errorf("specialized areas = %q, want %q", expert.SpecializedArea, tt.after)
+			}
+		})
+	}
+	if merged, _ := s.GetSpecializedArea(source); merged.RetiredAt == nil || merged.MergedIntoID == nil {
+		t.Error("source specialized area not retired as merged")
+	}
+}
+
+func TestSetAreaRetired(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+	expertID := createTestExpert(t, s, "Kept expert", "kept@example.com", area)
+
+	steps := []struct {
+		retired bool
+		action  string // Audit entry expected, if any
+	}{
+		{true, "retire"},
+		{true, ""}, // Already retired
+		{false, "restore"},
+	}
+	wantEntries := 0
+	for _, step := range steps {
+		if err := s.SetAreaRetired(area, step.retired, "Reorganisation", admin); err != nil {
+			t.Fatalf("SetAreaRetired(%v): %v", step.retired, err)
+		}
+		if a, _ := s.GetArea(area); (a.RetiredAt != nil) != step.retired {
+			t.Errorf("area retired = %v, want %v", a.RetiredAt != nil, step.retired)
+		}
+		if step.action != "" {
+			wantEntries++
+		}
+		entries, _ := s.ListAreaAuditLog("general", area)
+		if len(entries) != wantEntries {
+			t.Fatalf("%d audit entries, want %d", len(entries), wantEntries)
+		}
+	}
+	if expert, _ := s.GetExpert(expertID); expert.GeneralArea != area {
+		t.Error("retiring the area moved its expert")
+	}
+}
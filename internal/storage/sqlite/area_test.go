@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+// createTestChildArea adds an area under parent; names are numbered as in createTestArea
+func createTestChildArea(t *testing.T, s *SQLiteStore, name string, parent int64) int64 {
+	t.Helper()
+	name = fmt.Sprintf("%s %d", name, fixtureSeq.Add(1))
+	id, err := s.CreateArea(name, &parent)
+	if err != nil {
+		t.Fatalf("create area %s: %v", name, err)
+	}
+	return id
+}
+
+func TestAreaHierarchy(t *testing.T) {
+	s := newTestStore(t)
+	business := createTestArea(t, s, "Business")
+	finance := createTestChildArea(t, s, "Finance", business)
+	banking := createTestChildArea(t, s, "Banking", finance)
+	law := createTestArea(t, s, "Law")
+
+	get := func(id int64) *domain.Area {
+		a, err := s.GetArea(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	// Names stay short; the path spells out the whole branch
+	if want := get(business).Name + " - " + get(finance).Name + " - " + get(banking).Name; get(banking).Path != want {
+		t.Errorf("path = %q, want %q", get(banking).Path, want)
+	}
+
+	descendants, err := s.GetAreaDescendantIDs(business)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(descendants, func(i, j int) bool { return descendants[i] < descendants[j] })
+	if fmt.Sprint(descendants) != fmt.Sprint([]int64{finance, banking}) {
+		t.Errorf("descendants of business = %v, want %v", descendants, []int64{finance, banking})
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		parent  int64 // 0 for the top level
+		wantErr bool
+	}{
+		{"under itself", finance, finance, true},
+		{"under a descendant", business, banking, true},
+		{"under a missing area", finance, 999999, true},
+		{"under another tree", finance, law, false},
+		{"to the top level", banking, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parent *int64
+			if tt.parent != 0 {
+				parent = &tt.parent
+			}
+			err := s.MoveArea(tt.id, parent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveArea error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got int64
+			if moved := get(tt.id); moved.ParentID != nil {
+				got = *moved.ParentID
+			}
+			if got != tt.parent {
+				t.Errorf("parent = %d, want %d", got, tt.parent)
+			}
+		})
+	}
+}
+
+func TestListExpertsAreaDescendants(t *testing.T) {
+	s := newTestStore(t)
+	business := createTestArea(t, s, "Business")
+	finance := createTestChildArea(t, s, "Finance", business)
+	other := createTestArea(t, s, "Law")
+	inBusiness := createTestExpert(t, s, "Business expert", "business@example.com", business)
+	inFinance := createTestExpert(t, s, "Finance expert", "finance@example.com", finance)
+	createTestExpert(t, s, "Law expert", "law@example.com", other)
+
+	tests := []struct {
+		name        string
+		descendants bool
+		want        []int64
+	}{
+		{"selected area only", false, []int64{inBusiness}},
+		{"with descendants", true, []int64{inBusiness, inFinance}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			experts, err := s.ListExperts(map[string]interface{}{
+				"general_area": fmt.Sprint(business), "general_area_descendants": tt.descendants,
+			}, 10, 0)
+			if err != nil {
+				t.Fatalf("ListExperts: %v", err)
+			}
+			var got []int64
+			for _, e := range experts {
+				got = append(got, e.ID)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("experts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
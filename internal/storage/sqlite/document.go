@@ -12,7 +12,7 @@ import (
 func (s *SQLiteStore) ListDocuments(expertID int64) ([]*domain.Document, error) {
 	query := `
 		SELECT id, expert_id, document_type, filename, file_path,
-				content_type, file_size, upload_date
+				content_type, file_size, upload_date, sha256, current_version, uploaded_by
 		FROM expert_documents
 		WHERE expert_id = ?
 	`
@@ -25,17 +25,12 @@ func (s *SQLiteStore) ListDocuments(expertID int64) ([]*domain.Document, error)
 	
 	var docs []*domain.Document
 	for rows.Next() {
-		var doc domain.Document
-		err := rows.Scan(
-			&doc.ID, &doc.ExpertID, &doc.DocumentType, &doc.Filename,
-			&doc.FilePath, &doc.ContentType, &doc.FileSize, &doc.UploadDate,
-		)
+		doc, err := scanDocument(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan document row: %w", err)
 		}
 		
-		
-		docs = append(docs, &doc)
+		docs = append(docs, doc)
 	}
 	
 	if err := rows.Err(); err != nil {
@@ -49,17 +44,12 @@ func (s *SQLiteStore) ListDocuments(expertID int64) ([]*domain.Document, error)
 func (s *SQLiteStore) GetDocument(id int64) (*domain.Document, error) {
 	query := `
 		SELECT id, expert_id, document_type, filename, file_path,
-				content_type, file_size, upload_date
+				content_type, file_size, upload_date, sha256, current_version, uploaded_by
 		FROM expert_documents
 		WHERE id = ?
 	`
 	
-	var doc domain.Document
-	err := s.db.QueryRow(query, id).Scan(
-		&doc.ID, &doc.ExpertID, &doc.DocumentType, &doc.Filename,
-		&doc.FilePath, &doc.ContentType, &doc.FileSize, &doc.UploadDate,
-	)
-	
+	doc, err := scanDocument(s.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
@@ -67,17 +57,56 @@ func (s *SQLiteStore) GetDocument(id int64) (*domain.Document, error) {
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
 	
-	
+	return doc, nil
+}
+
+// scanDocument scans a row selected with the standard expert_documents column list
+func scanDocument(scanner interface{ Scan(...interface{}) error }) (*domain.Document, error) {
+	var doc domain.Document
+	var sha256 sql.NullString
+	var uploadedBy sql.NullInt64
+	err := scanner.Scan(
+		&doc.ID, &doc.ExpertID, &doc.DocumentType, &doc.Filename,
+		&doc.FilePath, &doc.ContentType, &doc.FileSize, &doc.UploadDate, &sha256,
+		&doc.CurrentVersion, &uploadedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	doc.SHA256 = sha256.String
+	if uploadedBy.Valid {
+		doc.UploadedBy = &uploadedBy.Int64
+	}
 	return &doc, nil
 }
 
 // CreateDocument creates a new document in the database
 func (s *SQLiteStore) CreateDocument(doc *domain.Document) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	id, err := insertDocument(tx, doc)
+	if err != nil {
+		return 0, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit document: %w", err)
+	}
+	return id, nil
+}
+
+// insertDocument inserts a document record and its first version using an open transaction
+func insertDocument(ex execer, doc *domain.Document) (int64, error) {
 	query := `
 		INSERT INTO expert_documents (
 			expert_id, document_type, filename, file_path,
-			content_type, file_size, upload_date
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+			content_type, file_size, upload_date, sha256,
+			current_version, uploaded_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, ?)
 	`
 	
 	// Handle potentially nullable fields
@@ -91,10 +120,10 @@ func (s *SQLiteStore) CreateDocument(doc *domain.Document) (int64, error) {
 		doc.UploadDate = time.Now()
 	}
 	
-	result, err := s.db.Exec(
+	result, err := ex.Exec(
 		query,
 		doc.ExpertID, doc.DocumentType, doc.Filename, doc.FilePath,
-		contentType, doc.FileSize, doc.UploadDate,
+		contentType, doc.FileSize, doc.UploadDate, nullableSHA256(doc.SHA256), doc.UploadedBy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create document: %w", err)
@@ -105,16 +134,44 @@ func (s *SQLiteStore) CreateDocument(doc *domain.Document) (int64, error) {
 		return 0, fmt.Errorf("failed to get document ID: %w", err)
 	}
 	
+	_, err = ex.Exec(`
+		INSERT INTO document_versions (
+			document_id, version, filename, file_path, content_type,
+			file_size, sha256, uploaded_by, uploaded_at
+		) VALUES (?, 1, ?, ?, ?, ?, ?, ?, ?)`,
+		id, doc.Filename, doc.FilePath, contentType,
+		doc.FileSize, nullableSHA256(doc.SHA256), doc.UploadedBy, doc.UploadDate,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create document version: %w", err)
+	}
+	
 	doc.ID = id
+	doc.CurrentVersion = 1
 	return id, nil
 }
 
 // UpdateDocument updates a document record in the database
+// When the file moves, versions that pointed at the old file follow it
 func (s *SQLiteStore) UpdateDocument(doc *domain.Document) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var oldPath string
+	if err := tx.QueryRow("SELECT file_path FROM expert_documents WHERE id = ?", doc.ID).Scan(&oldPath); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	
 	query := `
 		UPDATE expert_documents
 		SET expert_id = ?, document_type = ?, filename = ?, file_path = ?, 
-		    content_type = ?, file_size = ?, upload_date = ?
+		    content_type = ?, file_size = ?, upload_date = ?, sha256 = ?
 		WHERE id = ?
 	`
 	
@@ -124,30 +181,38 @@ func (s *SQLiteStore) UpdateDocument(doc *domain.Document) error {
 		contentType = doc.ContentType
 	}
 	
-	result, err := s.db.Exec(
+	_, err = tx.Exec(
 		query,
 		doc.ExpertID, doc.DocumentType, doc.Filename, doc.FilePath,
-		contentType, doc.FileSize, doc.UploadDate, doc.ID,
+		contentType, doc.FileSize, doc.UploadDate, nullableSHA256(doc.SHA256), doc.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update document: %w", err)
 	}
 	
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if oldPath != doc.FilePath {
+		_, err = tx.Exec("UPDATE document_versions SET file_path = ? WHERE document_id = ? AND file_path = ?",
+			doc.FilePath, doc.ID, oldPath)
+		if err != nil {
+			return fmt.Errorf("failed to update document versions: %w", err)
+		}
 	}
 	
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document update: %w", err)
 	}
-	
 	return nil
 }
 
-// DeleteDocument deletes a document by ID
+// DeleteDocument deletes a document and its versions by ID
 func (s *SQLiteStore) DeleteDocument(id int64) error {
-	result, err := s.db.Exec("DELETE FROM expert_documents WHERE id = ?", id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.Exec("DELETE FROM expert_documents WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete document: %w", err)
 	}
@@ -161,5 +226,49 @@ func (s *SQLiteStore) DeleteDocument(id int64) error {
 		return domain.ErrNotFound
 	}
 	
+	if _, err := tx.Exec("DELETE FROM document_versions WHERE document_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete document versions: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document deletion: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// nullableSHA256 stores an empty checksum as NULL so unscanned documents stay distinguishable
+func nullableSHA256(sum string) interface{} {
+	if sum == "" {
+		return nil
+	}
+	return sum
+}
+
+// UpdateDocumentFilePath points every document and version stored at oldPath to newPath, returning how many
+// documents were updated. Deduplicated documents share a file, so they are moved together
+func (s *SQLiteStore) UpdateDocumentFilePath(oldPath, newPath string) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.Exec("UPDATE expert_documents SET file_path = ? WHERE file_path = ?", newPath, oldPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update document file path: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if _, err := tx.Exec("UPDATE document_versions SET file_path = ? WHERE file_path = ?", newPath, oldPath); err != nil {
+		return 0, fmt.Errorf("failed to update document version file path: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit file path update: %w", err)
+	}
+	return rowsAffected, nil
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"expertdb/internal/domain"
+)
+
+// FindDocumentBySHA256 returns the oldest document whose content has the given checksum
+// Returns domain.ErrNotFound when no stored document has that content
+func (s *SQLiteStore) FindDocumentBySHA256(sum string) (*domain.Document, error) {
+	query := `
+		SELECT id, expert_id, document_type, filename, file_path,
+				content_type, file_size, upload_date, sha256, current_version, uploaded_by
+		FROM expert_documents
+		WHERE sha256 = ?
+		ORDER BY id
+		LIMIT 1
+	`
+
+	doc, err := scanDocument(s.db.QueryRow(query, sum))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find document by checksum: %w", err)
+	}
+	return doc, nil
+}
+
+// CountFileReferences returns how many documents, other than excludeDocumentID, point at a stored file
+// through their current file or any of their versions. Deduplicated uploads share one file, so it may
+// only be removed once this reaches zero; pass 0 to count every document
+func (s *SQLiteStore) CountFileReferences(filePath string, excludeDocumentID int64) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT document_id) FROM (
+			SELECT id AS document_id FROM expert_documents WHERE file_path = ?
+			UNION ALL
+			SELECT document_id FROM document_versions WHERE file_path = ?
+		) WHERE document_id != ?
+	`
+	var count int
+	if err := s.db.QueryRow(query, filePath, filePath, excludeDocumentID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count references to file: %w", err)
+	}
+	return count, nil
+}
+
+// CountDocumentExperts returns how many experts, other than excludeExpertID, link a document as their
+// CV or approval. Batch approvals link one approval document to every expert of the batch
+func (s *SQLiteStore) CountDocumentExperts(documentID, excludeExpertID int64) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM experts
+		WHERE (cv_document_id = ? OR approval_document_id = ?) AND id != ?
+	`
+	var count int
+	if err := s.db.QueryRow(query, documentID, documentID, excludeExpertID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count experts linked to document: %w", err)
+	}
+	return count, nil
+}
+
+// ListAllDocuments retrieves every document record, ordered by ID
+func (s *SQLiteStore) ListAllDocuments() ([]*domain.Document, error) {
+	return s.listDocumentsWhere("1 = 1")
+}
+
+// listDocumentsWhere returns the documents matching condition, ordered by ID
+func (s *SQLiteStore) listDocumentsWhere(condition string) ([]*domain.Document, error) {
+	query := `
+		SELECT id, expert_id, document_type, filename, file_path,
+				content_type, file_size, upload_date, sha256, current_version, uploaded_by
+		FROM expert_documents
+		WHERE ` + condition + `
+		ORDER BY id
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []*domain.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document rows: %w", err)
+	}
+
+	return docs, nil
+}
+
+// SetDocumentSHA256 records the checksum of a document's file on the document and its current version
+func (s *SQLiteStore) SetDocumentSHA256(id int64, sum string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE expert_documents SET sha256 = ? WHERE id = ?", nullableSHA256(sum), id)
+	if err != nil {
+		return fmt.Errorf("failed to update document checksum: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	_, err = tx.Exec(`
+		UPDATE document_versions SET sha256 = ?
+		WHERE document_id = ? AND version = (SELECT current_version FROM expert_documents WHERE id = ?)`,
+		nullableSHA256(sum), id, id)
+	if err != nil {
+		return fmt.Errorf("failed to update document version checksum: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document checksum: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+// createTestDocument adds a CV document of an expert stored at filePath with checksum sum (empty for none)
+func createTestDocument(t *testing.T, s *SQLiteStore, expertID int64, filePath, sum string) int64 {
+	t.Helper()
+	id, err := s.CreateDocument(&domain.Document{
+		ExpertID:     expertID,
+		DocumentType: domain.DocumentTypeCV,
+		Filename:     "cv.pdf",
+		FilePath:     filePath,
+		ContentType:  "application/pdf",
+		FileSize:     9,
+		SHA256:       sum,
+	})
+	if err != nil {
+		t.Fatalf("create document %s: %v", filePath, err)
+	}
+	return id
+}
+
+func TestFindDocumentBySHA256(t *testing.T) {
+	s := newTestStore(t)
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	oldest := createTestDocument(t, s, expert, "experts/cv_1.pdf", "aaaa")
+	createTestDocument(t, s, expert, "experts/cv_1.pdf", "aaaa")
+	createTestDocument(t, s, expert, "experts/cv_2.pdf", "")
+
+	tests := []struct {
+		sum     string
+		want    int64
+		wantErr error
+	}{
+		{"aaaa", oldest, nil},
+		{"bbbb", 0, domain.ErrNotFound},
+		{"", 0, domain.ErrNotFound}, // Documents without a checksum never match
+	}
+	for _, tt := range tests {
+		doc, err := s.FindDocumentBySHA256(tt.sum)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("FindDocumentBySHA256(%q) error = %v, want %v", tt.sum, err, tt.wantErr)
+			continue
+		}
+		if err == nil && doc.ID != tt.want {
+			t.Errorf("FindDocumentBySHA256(%q) = document %d, want %d", tt.sum, doc.ID, tt.want)
+		}
+	}
+}
+
+func TestCountFileReferences(t *testing.T) {
+	s := newTestStore(t)
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	first := createTestDocument(t, s, expert, "experts/shared.pdf", "aaaa")
+	createTestDocument(t, s, expert, "experts/shared.pdf", "aaaa")
+	createTestDocument(t, s, expert, "experts/other.pdf", "bbbb")
+
+	tests := []struct {
+		path    string
+		exclude int64
+		want    int
+	}{
+		{"experts/shared.pdf", 0, 2},
+		{"experts/shared.pdf", first, 1},
+		{"experts/other.pdf", first, 1},
+		{"experts/missing.pdf", 0, 0},
+	}
+	for _, tt := range tests {
+		count, err := s.CountFileReferences(tt.path, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != tt.want {
+			t.Errorf("CountFileReferences(%s, %d) = %d, want %d", tt.path, tt.exclude, count, tt.want)
+		}
+	}
+}
+
+func TestSetDocumentSHA256(t *testing.T) {
+	s := newTestStore(t)
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	id := createTestDocument(t, s, expert, "experts/cv.pdf", "")
+
+	if err := s.SetDocumentSHA256(id, "cccc"); err != nil {
+		t.Fatalf("SetDocumentSHA256: %v", err)
+	}
+	if doc, _ := s.GetDocument(id); doc.SHA256 != "cccc" {
+		t.Errorf("document checksum = %q, want cccc", doc.SHA256)
+	}
+	if versions, _ := s.ListDocumentVersions(id); len(versions) != 1 || versions[0].SHA256 != "cccc" {
+		t.Errorf("versions = %+v, want the current version backfilled", versions)
+	}
+	if err := s.SetDocumentSHA256(id+1000, "cccc"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing document: error = %v, want ErrNotFound", err)
+	}
+}
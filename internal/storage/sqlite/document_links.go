@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+const documentDownloadLinkColumns = `id, document_id, recipient, single_use, expires_at, download_count,
+	last_used_at, revoked_at, created_by, created_at`
+
+// CreateDocumentDownloadLink stores a download link for a document
+func (s *SQLiteStore) CreateDocumentDownloadLink(link *domain.DocumentDownloadLink) (int64, error) {
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	var createdBy interface{}
+	if link.CreatedBy != 0 {
+		createdBy = link.CreatedBy
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO document_download_links (document_id, recipient, single_use, expires_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, link.DocumentID, link.Recipient, link.SingleUse, link.ExpiresAt, createdBy, link.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create document download link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get document download link ID: %w", err)
+	}
+	link.ID = id
+
+	return id, nil
+}
+
+// scanDocumentDownloadLink reads a download link row selected with documentDownloadLinkColumns
+func scanDocumentDownloadLink(row interface{ Scan(...interface{}) error }) (*domain.DocumentDownloadLink, error) {
+	var link domain.DocumentDownloadLink
+	var lastUsedAt, revokedAt sql.NullTime
+	var createdBy sql.NullInt64
+
+	if err := row.Scan(&link.ID, &link.DocumentID, &link.Recipient, &link.SingleUse, &link.ExpiresAt,
+		&link.DownloadCount, &lastUsedAt, &revokedAt, &createdBy, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	link.CreatedBy = createdBy.Int64
+	if lastUsedAt.Valid {
+		link.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+
+	return &link, nil
+}
+
+// GetDocumentDownloadLink retrieves a download link by ID
+func (s *SQLiteStore) GetDocumentDownloadLink(id int64) (*domain.DocumentDownloadLink, error) {
+	row := s.db.QueryRow("SELECT "+documentDownloadLinkColumns+" FROM document_download_links WHERE id = ?", id)
+
+	link, err := scanDocumentDownloadLink(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document download link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ListDocumentDownloadLinks returns the download links of a document, newest first
+func (s *SQLiteStore) ListDocumentDownloadLinks(documentID int64) ([]*domain.DocumentDownloadLink, error) {
+	rows, err := s.db.Query("SELECT "+documentDownloadLinkColumns+` FROM document_download_links
+		WHERE document_id = ? ORDER BY created_at DESC, id DESC`, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document download links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*domain.DocumentDownloadLink{}
+	for rows.Next() {
+		link, err := scanDocumentDownloadLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document download link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document download links: %w", err)
+	}
+
+	return links, nil
+}
+
+// RevokeDocumentDownloadLink stops a download link from working
+func (s *SQLiteStore) RevokeDocumentDownloadLink(id int64) error {
+	result, err := s.db.Exec("UPDATE document_download_links SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke document download link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM document_download_links WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check document download link: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// UseDocumentDownloadLink counts a download against a link and records it in the download log
+// The conditional update keeps a single-use link from being used twice by concurrent requests
+func (s *SQLiteStore) UseDocumentDownloadLink(download *domain.DocumentDownload) error {
+	if download.DownloadedAt.IsZero() {
+		download.DownloadedAt = time.Now()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE document_download_links SET download_count = download_count + 1, last_used_at = ?
+		WHERE id = ? AND revoked_at IS NULL AND expires_at > ? AND (single_use = 0 OR download_count = 0)
+	`, download.DownloadedAt, download.LinkID, download.DownloadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to use document download link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: download link is no longer valid", domain.ErrValidation)
+	}
+
+	var createdBy interface{}
+	if download.LinkCreatedBy != 0 {
+		createdBy = download.LinkCreatedBy
+	}
+	res, err := tx.Exec(`
+		INSERT INTO document_download_log (link_id, document_id, recipient, link_created_by, ip_address, user_agent, downloaded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, download.LinkID, download.DocumentID, download.Recipient, createdBy, download.IPAddress, download.UserAgent, download.DownloadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record document download: %w", err)
+	}
+	if download.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get document download ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document download: %w", err)
+	}
+	return nil
+}
+
+// ListDocumentDownloads returns the downloads made through a link, most recent first
+func (s *SQLiteStore) ListDocumentDownloads(linkID int64) ([]*domain.DocumentDownload, error) {
+	rows, err := s.db.Query(`
+		SELECT id, link_id, document_id, recipient, link_created_by, ip_address, user_agent, downloaded_at
+		FROM document_download_log
+		WHERE link_id = ?
+		ORDER BY downloaded_at DESC, id DESC
+	`, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document downloads: %w", err)
+	}
+	defer rows.Close()
+
+	downloads := []*domain.DocumentDownload{}
+	for rows.Next() {
+		var d domain.DocumentDownload
+		var createdBy sql.NullInt64
+		var ip, userAgent sql.NullString
+		if err := rows.Scan(&d.ID, &d.LinkID, &d.DocumentID, &d.Recipient, &createdBy, &ip, &userAgent, &d.DownloadedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan document download: %w", err)
+		}
+		d.LinkCreatedBy = createdBy.Int64
+		d.IPAddress = ip.String
+		d.UserAgent = userAgent.String
+		downloads = append(downloads, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document downloads: %w", err)
+	}
+
+	return downloads, nil
+}
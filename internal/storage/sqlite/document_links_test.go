@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+func TestUseDocumentDownloadLink(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	doc := createTestDocument(t, s, expert, "experts/cv.pdf", "")
+
+	tests := []struct {
+		name      string
+		singleUse bool
+		expiresIn time.Duration
+		revoke    bool
+		uses      []bool // Whether each download in turn is allowed
+	}{
+		{"reusable", false, time.Hour, false, []bool{true, true}},
+		{"single use", true, time.Hour, false, []bool{true, false}},
+		{"expired", false, -time.Minute, false, []bool{false}},
+		{"revoked", false, time.Hour, true, []bool{false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := &domain.DocumentDownloadLink{
+				DocumentID: doc,
+				Recipient:  "Ministry of Education",
+				SingleUse:  tt.singleUse,
+				ExpiresAt:  time.Now().Add(tt.expiresIn),
+				CreatedBy:  admin,
+			}
+			if _, err := s.CreateDocumentDownloadLink(link); err != nil {
+				t.Fatal(err)
+			}
+			if tt.revoke {
+				if err := s.RevokeDocumentDownloadLink(link.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			allowed := 0
+			for i, want := range tt.uses {
+				err := s.UseDocumentDownloadLink(&domain.DocumentDownload{
+					LinkID: link.ID, DocumentID: doc, Recipient: link.Recipient, LinkCreatedBy: admin, IPAddress: "10.0.0.1",
+				})
+				if want && err != nil {
+					t.Fatalf("download %d: %v", i+1, err)
+				}
+				if !want && !errors.Is(err, domain.ErrValidation) {
+					t.Fatalf("download %d: error = %v, want a validation error", i+1, err)
+				}
+				if want {
+					allowed++
+				}
+			}
+
+			stored, err := s.GetDocumentDownloadLink(link.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			downloads, err := s.ListDocumentDownloads(link.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.DownloadCount != allowed || len(downloads) != allowed {
+				t.Errorf("count %d with %d logged downloads, want %d", stored.DownloadCount, len(downloads), allowed)
+			}
+			if stored.Usable(time.Now()) != (tt.expiresIn > 0 && !tt.revoke && !(tt.singleUse && allowed > 0)) {
+				t.Errorf("link usable = %v after the downloads", stored.Usable(time.Now()))
+			}
+		})
+	}
+}
+
+func TestRevokeDocumentDownloadLink(t *testing.T) {
+	s := newTestStore(t)
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	link := &domain.DocumentDownloadLink{DocumentID: createTestDocument(t, s, expert, "experts/cv.pdf", ""), ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := s.CreateDocumentDownloadLink(link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id      int64
+		wantErr error
+	}{
+		{link.ID, nil},
+		{link.ID, nil}, // Revoking again is harmless
+		{link.ID + 1000, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		if err := s.RevokeDocumentDownloadLink(tt.id); !errors.Is(err, tt.wantErr) {
+			t.Errorf("RevokeDocumentDownloadLink(%d) error = %v, want %v", tt.id, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"expertdb/internal/domain"
+)
+
+// documentReferenceTables maps the owners of document references to their tables
+var documentReferenceTables = map[string]string{
+	"expert":         "experts",
+	"expert_request": "expert_requests",
+}
+
+// ListDanglingDocumentReferences returns the cv_document_id and approval_document_id values of experts and
+// expert requests that name a document which no longer exists
+func (s *SQLiteStore) ListDanglingDocumentReferences() ([]domain.DanglingDocumentReference, error) {
+	query := `
+		SELECT owner, owner_id, field, document_id FROM (
+			SELECT 'expert' AS owner, id AS owner_id, 'cv_document_id' AS field, cv_document_id AS document_id FROM experts
+			UNION ALL
+			SELECT 'expert', id, 'approval_document_id', approval_document_id FROM experts
+			UNION ALL
+			SELECT 'expert_request', id, 'cv_document_id', cv_document_id FROM expert_requests
+			UNION ALL
+			SELECT 'expert_request', id, 'approval_document_id', approval_document_id FROM expert_requests
+		)
+		WHERE document_id IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM expert_documents d WHERE d.id = document_id)
+		ORDER BY owner, owner_id, field
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dangling document references: %w", err)
+	}
+	defer rows.Close()
+
+	refs := []domain.DanglingDocumentReference{}
+	for rows.Next() {
+		var ref domain.DanglingDocumentReference
+		if err := rows.Scan(&ref.Owner, &ref.OwnerID, &ref.Field, &ref.DocumentID); err != nil {
+			return nil, fmt.Errorf("failed to scan document reference: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document references: %w", err)
+	}
+
+	return refs, nil
+}
+
+// ClearDocumentReference sets an expert's or expert request's document reference to NULL
+// The reference is only cleared while it still names documentID, so a document linked in the meantime is kept
+func (s *SQLiteStore) ClearDocumentReference(ref domain.DanglingDocumentReference) error {
+	table, ok := documentReferenceTables[ref.Owner]
+	if !ok {
+		return fmt.Errorf("unknown document reference owner %q", ref.Owner)
+	}
+	if ref.Field != "cv_document_id" && ref.Field != "approval_document_id" {
+		return fmt.Errorf("unknown document reference field %q", ref.Field)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s = NULL WHERE id = ? AND %s = ?", table, ref.Field, ref.Field)
+	result, err := s.db.Exec(query, ref.OwnerID, ref.DocumentID)
+	if err != nil {
+		return fmt.Errorf("failed to clear document reference: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
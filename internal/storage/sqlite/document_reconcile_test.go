@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestDanglingDocumentReferences(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	area := createTestArea(t, s, "Engineering")
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", area)
+	request := createTestExpertRequest(t, s, newTestExpertRequest("Badr Saleh", area, owner))
+	kept := createTestDocument(t, s, expert, "experts/cv.pdf", "")
+	missing := kept + 1000
+
+	if _, err := s.db.Exec("UPDATE experts SET cv_document_id = ?, approval_document_id = ? WHERE id = ?", kept, missing, expert); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec("UPDATE expert_requests SET cv_document_id = ? WHERE id = ?", missing, request); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := s.ListDanglingDocumentReferences()
+	if err != nil {
+		t.Fatalf("ListDanglingDocumentReferences: %v", err)
+	}
+	want := []domain.DanglingDocumentReference{
+		{Owner: "expert", OwnerID: expert, Field: "approval_document_id", DocumentID: missing},
+		{Owner: "expert_request", OwnerID: request, Field: "cv_document_id", DocumentID: missing},
+	}
+	if fmt.Sprintf("%+v", refs) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("dangling references = %+v, want %+v", refs, want)
+	}
+
+	tests := []struct {
+		name    string
+		ref     domain.DanglingDocumentReference
+		wantErr error
+	}{
+		{"expert", refs[0], nil},
+		{"expert request", refs[1], nil},
+		{"already cleared", refs[1], domain.ErrNotFound},
+		{"relinked meanwhile", domain.DanglingDocumentReference{Owner: "expert", OwnerID: expert, Field: "cv_document_id", DocumentID: missing}, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		if err := s.ClearDocumentReference(tt.ref); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	for _, ref := range []domain.DanglingDocumentReference{
+		{Owner: "users", OwnerID: expert, Field: "cv_document_id"},
+		{Owner: "expert", OwnerID: expert, Field: "name"},
+	} {
+		if err := s.ClearDocumentReference(ref); err == nil || errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("ClearDocumentReference(%+v) error = %v, want it rejected", ref, err)
+		}
+	}
+
+	if refs, _ := s.ListDanglingDocumentReferences(); len(refs) != 0 {
+		t.Errorf("dangling references left: %v", refs)
+	}
+	if e, _ := s.GetExpert(expert); e.CVDocumentID == nil || *e.CVDocumentID != kept {
+		t.Error("valid reference cleared")
+	}
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// SetDocumentText records the text extracted from a document's file
+// An empty text marks extraction as attempted without finding any text
+func (s *SQLiteStore) SetDocumentText(id int64, text string) error {
+	var value interface{}
+	if text != "" {
+		value = text
+	}
+
+	result, err := s.db.Exec(
+		"UPDATE expert_documents SET extracted_text = ?, text_extracted_at = ? WHERE id = ?",
+		value, time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store document text: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// GetDocumentText returns the text extracted from a document's file, empty when there is none
+func (s *SQLiteStore) GetDocumentText(id int64) (string, error) {
+	var text sql.NullString
+	err := s.db.QueryRow("SELECT extracted_text FROM expert_documents WHERE id = ?", id).Scan(&text)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get document text: %w", err)
+	}
+	return text.String, nil
+}
+
+// ListDocumentsWithoutText returns the documents text extraction has not been attempted for
+func (s *SQLiteStore) ListDocumentsWithoutText() ([]*domain.Document, error) {
+	return s.listDocumentsWhere("text_extracted_at IS NULL")
+}
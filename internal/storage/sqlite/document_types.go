@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+const documentTypeColumns = `code, name, description, allowed_mime_types, max_size, directory,
+	expert_requirement, request_requirement, multiple, retired_at, created_at, updated_at`
+
+// scanDocumentType reads a document type row selected with documentTypeColumns
+func scanDocumentType(row interface{ Scan(...interface{}) error }) (*domain.DocumentType, error) {
+	var t domain.DocumentType
+	var description sql.NullString
+	var mimeTypes string
+	var retiredAt sql.NullTime
+
+	if err := row.Scan(&t.Code, &t.Name, &description, &mimeTypes, &t.MaxSize, &t.Directory,
+		&t.ExpertRequirement, &t.RequestRequirement, &t.Multiple, &retiredAt, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	t.Description = description.String
+	t.AllowedMimeTypes = []string{}
+	for _, mimeType := range strings.Split(mimeTypes, ",") {
+		if mimeType = strings.TrimSpace(mimeType); mimeType != "" {
+			t.AllowedMimeTypes = append(t.AllowedMimeTypes, mimeType)
+		}
+	}
+	if retiredAt.Valid {
+		t.RetiredAt = &retiredAt.Time
+	}
+
+	return &t, nil
+}
+
+// ListDocumentTypes returns every registered document type, including retired ones, ordered by name
+func (s *SQLiteStore) ListDocumentTypes() ([]*domain.DocumentType, error) {
+	rows, err := s.db.Query("SELECT " + documentTypeColumns + " FROM document_types ORDER BY name COLLATE NOCASE, code")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document types: %w", err)
+	}
+	defer rows.Close()
+
+	types := []*domain.DocumentType{}
+	for rows.Next() {
+		t, err := scanDocumentType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document type: %w", err)
+		}
+		types = append(types, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document types: %w", err)
+	}
+
+	return types, nil
+}
+
+// GetDocumentType retrieves a document type by code
+func (s *SQLiteStore) GetDocumentType(code string) (*domain.DocumentType, error) {
+	t, err := scanDocumentType(s.db.QueryRow("SELECT "+documentTypeColumns+" FROM document_types WHERE code = ?", code))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document type: %w", err)
+	}
+
+	return t, nil
+}
+
+// CreateDocumentType registers a new document type
+func (s *SQLiteStore) CreateDocumentType(t *domain.DocumentType) error {
+	now := time.Now()
+	_, err := s.db.Exec(`
+		INSERT INTO document_types (code, name, description, allowed_mime_types, max_size, directory,
+			expert_requirement, request_requirement, multiple, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, t.Code, t.Name, t.Description, strings.Join(t.AllowedMimeTypes, ","), t.MaxSize, t.Directory,
+		t.ExpertRequirement, t.RequestRequirement, t.Multiple, now, now)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("%w: document type '%s' already exists", domain.ErrValidation, t.Code)
+		}
+		return fmt.Errorf("failed to create document type: %w", err)
+	}
+
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return nil
+}
+
+// UpdateDocumentType saves the settings of a document type; its code and retirement are left unchanged
+func (s *SQLiteStore) UpdateDocumentType(t *domain.DocumentType) error {
+	now := time.Now()
+	result, err := s.db.Exec(`
+		UPDATE document_types
+		SET name = ?, description = ?, allowed_mime_types = ?, max_size = ?, directory = ?,
+			expert_requirement = ?, request_requirement = ?, multiple = ?, updated_at = ?
+		WHERE code = ?
+	`, t.Name, t.Description, strings.Join(t.AllowedMimeTypes, ","), t.MaxSize, t.Directory,
+		t.ExpertRequirement, t.RequestRequirement, t.Multiple, now, t.Code)
+	if err != nil {
+		return fmt.Errorf("failed to update document type: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	t.UpdatedAt = now
+	return nil
+}
+
+// SetDocumentTypeRetired retires a document type, so it accepts no new uploads, or restores it
+// Documents of a retired type are kept
+func (s *SQLiteStore) SetDocumentTypeRetired(code string, retired bool) error {
+	var retiredAt interface{}
+	if retired {
+		retiredAt = time.Now()
+	}
+
+	// Retiring a retired type keeps its original retirement time
+	result, err := s.db.Exec(`
+		UPDATE document_types SET retired_at = CASE WHEN ? IS NULL THEN NULL ELSE COALESCE(retired_at, ?) END, updated_at = ?
+		WHERE code = ?
+	`, retiredAt, retiredAt, time.Now(), code)
+	if err != nil {
+		return fmt.Errorf("failed to update document type: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// CountExpertDocumentsOfType returns how many documents of a type an expert holds
+// CVs and approvals are counted through the expert's own reference, as request documents keep the
+// request ID in expert_id and batch approvals the first expert's; other types exclude request documents
+func (s *SQLiteStore) CountExpertDocumentsOfType(expertID int64, code string) (int, error) {
+	var query string
+	args := []interface{}{expertID}
+	switch code {
+	case domain.DocumentTypeCV, domain.DocumentTypeApproval:
+		query = fmt.Sprintf(`
+			SELECT COUNT(*) FROM experts e
+			JOIN expert_documents d ON d.id = e.%s_document_id
+			WHERE e.id = ?`, code)
+	default:
+		query = `
+			SELECT COUNT(*) FROM expert_documents
+			WHERE expert_id = ? AND document_type = ?
+			  AND id NOT IN (
+			      SELECT cv_document_id FROM expert_requests WHERE cv_document_id IS NOT NULL
+			      UNION
+			      SELECT approval_document_id FROM expert_requests WHERE approval_document_id IS NOT NULL
+			  )`
+		args = append(args, code)
+	}
+
+	var count int
+	err := s.db.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count expert documents: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestListDocumentTypesSeeded(t *testing.T) {
+	s := newTestStore(t)
+	types, err := s.ListDocumentTypes()
+	if err != nil {
+		t.Fatalf("ListDocumentTypes: %v", err)
+	}
+
+	seeded := map[string]*domain.DocumentType{}
+	for _, dt := range types {
+		seeded[dt.Code] = dt
+	}
+	tests := []struct {
+		code            string
+		expert, request string
+		directory       string
+		multiple        bool
+	}{
+		{domain.DocumentTypeCV, domain.DocumentRequired, domain.DocumentRequired, "experts", false},
+		{domain.DocumentTypeApproval, domain.DocumentRequired, domain.DocumentRequired, "approvals", false},
+		{"certificate", domain.DocumentOptional, domain.DocumentNotUsed, "certificates", true},
+	}
+	for _, tt := range tests {
+		dt, ok := seeded[tt.code]
+		if !ok {
+			t.Errorf("document type %s not seeded", tt.code)
+			continue
+		}
+		if dt.ExpertRequirement != tt.expert || dt.RequestRequirement != tt.request || dt.Directory != tt.directory ||
+			dt.Multiple != tt.multiple || !dt.Allows("application/pdf") || dt.MaxSize != 10<<20 {
+			t.Errorf("document type %s = %+v", tt.code, dt)
+		}
+	}
+}
+
+func TestCreateDocumentType(t *testing.T) {
+	s := newTestStore(t)
+	dt := &domain.DocumentType{
+		Code: "passport", Name: "Passport", AllowedMimeTypes: []string{"image/jpeg", "image/png"}, MaxSize: 1 << 20,
+		Directory: "passports", ExpertRequirement: domain.DocumentOptional, RequestRequirement: domain.DocumentNotUsed,
+	}
+	if err := s.CreateDocumentType(dt); err != nil {
+		t.Fatalf("CreateDocumentType: %v", err)
+	}
+	got, err := s.GetDocumentType("passport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got.AllowedMimeTypes) != "[image/jpeg image/png]" || got.Directory != "passports" || got.RetiredAt != nil {
+		t.Errorf("stored type = %+v", got)
+	}
+
+	if err := s.CreateDocumentType(dt); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("duplicate code: error = %v, want a validation error", err)
+	}
+	if _, err := s.GetDocumentType("visa"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing type: error = %v, want ErrNotFound", err)
+	}
+	if err := s.UpdateDocumentType(&domain.DocumentType{Code: "visa", Name: "Visa"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("updating a missing type: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetDocumentTypeRetiredStore(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.SetDocumentTypeRetired("certificate", true); err != nil {
+		t.Fatalf("SetDocumentTypeRetired: %v", err)
+	}
+	first, _ := s.GetDocumentType("certificate")
+	if first.RetiredAt == nil {
+		t.Fatal("certificate not retired")
+	}
+
+	steps := []struct {
+		name      string
+		code      string
+		retired   bool
+		wantErr   error
+		wantSince bool // Whether the type keeps its first retirement time
+	}{
+		{"retired again", "certificate", true, nil, true},
+		{"restored", "certificate", false, nil, false},
+		{"missing type", "visa", true, domain.ErrNotFound, false},
+	}
+	for _, step := range steps {
+		err := s.SetDocumentTypeRetired(step.code, step.retired)
+		if !errors.Is(err, step.wantErr) || (step.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", step.name, err, step.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got, _ := s.GetDocumentType(step.code)
+		if step.wantSince && (got.RetiredAt == nil || !got.RetiredAt.Equal(*first.RetiredAt)) {
+			t.Errorf("%s: retired at %v, want %v", step.name, got.RetiredAt, first.RetiredAt)
+		}
+		if !step.retired && got.RetiredAt != nil {
+			t.Errorf("%s: retired at %v, want it active", step.name, got.RetiredAt)
+		}
+	}
+}
+
+func TestCountExpertDocumentsOfType(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	area := createTestArea(t, s, "Engineering")
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", area)
+	other := createTestExpert(t, s, "Badr Saleh", "badr@example.com", area)
+
+	create := func(expertID int64, code string) int64 {
+		id, err := s.CreateDocument(&domain.Document{
+			ExpertID: expertID, DocumentType: code, Filename: code + ".pdf",
+			FilePath: fmt.Sprintf("%ss/%s_%d.pdf", code, code, expertID), ContentType: "application/pdf", FileSize: 9,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// The expert's own CV is referenced; a second CV document with its ID is not
+	cv := create(expert, domain.DocumentTypeCV)
+	create(expert, domain.DocumentTypeCV)
+	if _, err := s.db.Exec("UPDATE experts SET cv_document_id = ? WHERE id = ?", cv, expert); err != nil {
+		t.Fatal(err)
+	}
+	create(expert, "certificate")
+	create(expert, "certificate")
+
+	// Request documents keep the request ID in expert_id, which may equal an expert's
+	request := createTestExpertRequest(t, s, newTestExpertRequest("Carla Diaz", area, owner))
+	requestDoc := create(other, "certificate")
+	if _, err := s.db.Exec("UPDATE expert_requests SET cv_document_id = ? WHERE id = ?", requestDoc, request); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expertID int64
+		code     string
+		want     int
+	}{
+		{expert, domain.DocumentTypeCV, 1},
+		{expert, domain.DocumentTypeApproval, 0},
+		{expert, "certificate", 2},
+		{other, domain.DocumentTypeCV, 0},
+		{other, "certificate", 0},
+	}
+	for _, tt := range tests {
+		count, err := s.CountExpertDocumentsOfType(tt.expertID, tt.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != tt.want {
+			t.Errorf("CountExpertDocumentsOfType(%d, %s) = %d, want %d", tt.expertID, tt.code, count, tt.want)
+		}
+	}
+}
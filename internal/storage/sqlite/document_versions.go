@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// AddDocumentVersion records a newly uploaded file as the next version of a document and makes it current
+// version.Version is assigned here; the updated document is returned
+func (s *SQLiteStore) AddDocumentVersion(version *domain.DocumentVersion) (*domain.Document, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var latest sql.NullInt64
+	err = tx.QueryRow("SELECT MAX(version) FROM document_versions WHERE document_id = ?", version.DocumentID).Scan(&latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest document version: %w", err)
+	}
+
+	var exists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM expert_documents WHERE id = ?", version.DocumentID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if exists == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	version.Version = int(latest.Int64) + 1
+	if version.UploadedAt.IsZero() {
+		version.UploadedAt = time.Now()
+	}
+
+	var note interface{}
+	if version.Note != "" {
+		note = version.Note
+	}
+	result, err := tx.Exec(`
+		INSERT INTO document_versions (
+			document_id, version, filename, file_path, content_type,
+			file_size, sha256, uploaded_by, uploaded_at, note
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		version.DocumentID, version.Version, version.Filename, version.FilePath, version.ContentType,
+		version.FileSize, nullableSHA256(version.SHA256), version.UploadedBy, version.UploadedAt, note,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create document version: %w", err)
+	}
+	if version.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get document version ID: %w", err)
+	}
+
+	if err := makeVersionCurrentTx(tx, version); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit document version: %w", err)
+	}
+
+	version.IsCurrent = true
+	return s.GetDocument(version.DocumentID)
+}
+
+// ListDocumentVersions returns every version of a document, newest first
+func (s *SQLiteStore) ListDocumentVersions(documentID int64) ([]*domain.DocumentVersion, error) {
+	return s.listDocumentVersionsWhere("v.document_id = ?", documentID)
+}
+
+// ListAllDocumentVersions returns the versions of every document, ordered by document and newest first
+func (s *SQLiteStore) ListAllDocumentVersions() ([]*domain.DocumentVersion, error) {
+	return s.listDocumentVersionsWhere("1 = 1")
+}
+
+// listDocumentVersionsWhere returns the versions matching condition, ordered by document and newest first
+func (s *SQLiteStore) listDocumentVersionsWhere(condition string, args ...interface{}) ([]*domain.DocumentVersion, error) {
+	query := `
+		SELECT v.id, v.document_id, v.version, v.filename, v.file_path, v.content_type,
+		       v.file_size, v.sha256, v.uploaded_by, v.uploaded_at, v.note,
+		       v.version = d.current_version
+		FROM document_versions v
+		INNER JOIN expert_documents d ON d.id = v.document_id
+		WHERE ` + condition + `
+		ORDER BY v.document_id, v.version DESC
+	`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []*domain.DocumentVersion{}
+	for rows.Next() {
+		version, err := scanDocumentVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetDocumentVersion returns one version of a document
+func (s *SQLiteStore) GetDocumentVersion(documentID int64, version int) (*domain.DocumentVersion, error) {
+	query := `
+		SELECT v.id, v.document_id, v.version, v.filename, v.file_path, v.content_type,
+		       v.file_size, v.sha256, v.uploaded_by, v.uploaded_at, v.note,
+		       v.version = d.current_version
+		FROM document_versions v
+		INNER JOIN expert_documents d ON d.id = v.document_id
+		WHERE v.document_id = ? AND v.version = ?
+	`
+
+	v, err := scanDocumentVersion(s.db.QueryRow(query, documentID, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get document version: %w", err)
+	}
+	return v, nil
+}
+
+// PromoteDocumentVersion makes an earlier version the document's current file again
+// Later versions are kept, so the promotion can itself be undone
+func (s *SQLiteStore) PromoteDocumentVersion(documentID int64, version int) (*domain.Document, error) {
+	v, err := s.GetDocumentVersion(documentID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := makeVersionCurrentTx(tx, v); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit document version promotion: %w", err)
+	}
+	return s.GetDocument(documentID)
+}
+
+// makeVersionCurrentTx points a document at one of its versions
+// The extracted text belonged to the previous file, so it is cleared for extraction to run again
+func makeVersionCurrentTx(tx *sql.Tx, v *domain.DocumentVersion) error {
+	_, err := tx.Exec(`
+		UPDATE expert_documents
+		SET filename = ?, file_path = ?, content_type = ?, file_size = ?, sha256 = ?,
+		    upload_date = ?, uploaded_by = ?, current_version = ?,
+		    extracted_text = NULL, text_extracted_at = NULL
+		WHERE id = ?`,
+		v.Filename, v.FilePath, v.ContentType, v.FileSize, nullableSHA256(v.SHA256),
+		v.UploadedAt, v.UploadedBy, v.Version, v.DocumentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update current document version: %w", err)
+	}
+	return nil
+}
+
+// scanDocumentVersion scans a document_versions row followed by its is-current flag
+func scanDocumentVersion(scanner interface{ Scan(...interface{}) error }) (*domain.DocumentVersion, error) {
+	var v domain.DocumentVersion
+	var sha256, note sql.NullString
+	var uploadedBy sql.NullInt64
+	err := scanner.Scan(
+		&v.ID, &v.DocumentID, &v.Version, &v.Filename, &v.FilePath, &v.ContentType,
+		&v.FileSize, &sha256, &uploadedBy, &v.UploadedAt, &note, &v.IsCurrent,
+	)
+	if err != nil {
+		return nil, err
+	}
+	v.SHA256 = sha256.String
+	v.Note = note.String
+	if uploadedBy.Valid {
+		v.UploadedBy = &uploadedBy.Int64
+	}
+	return &v, nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestDocumentVersions(t *testing.T) {
+	s := newTestStore(t)
+	uploader := createTestUser(t, s, "admin")
+	expert := createTestExpert(t, s, "Amal Hasan", "amal@example.com", createTestArea(t, s, "Engineering"))
+	id := createTestDocument(t, s, expert, "experts/cv_v1.pdf", "aaaa")
+	if _, err := s.db.Exec("UPDATE expert_documents SET extracted_text = 'old text' WHERE id = ?", id); err != nil {
+		t.Fatal(err)
+	}
+
+	for version := 2; version <= 3; version++ {
+		v := &domain.DocumentVersion{
+			DocumentID:  id,
+			Filename:    fmt.Sprintf("cv_v%d.pdf", version),
+			FilePath:    fmt.Sprintf("experts/cv_v%d.pdf", version),
+			ContentType: "application/pdf",
+			FileSize:    int64(version),
+			SHA256:      fmt.Sprintf("%d%d%d%d", version, version, version, version),
+			UploadedBy:  &uploader,
+			Note:        "Updated CV",
+		}
+		doc, err := s.AddDocumentVersion(v)
+		if err != nil {
+			t.Fatalf("AddDocumentVersion: %v", err)
+		}
+		if v.Version != version || doc.CurrentVersion != version || doc.FilePath != v.FilePath || doc.SHA256 != v.SHA256 {
+			t.Errorf("document = version %d at %s, want version %d at %s", doc.CurrentVersion, doc.FilePath, version, v.FilePath)
+		}
+		if doc.ExtractedText != "" {
+			t.Error("text of the replaced file kept")
+		}
+	}
+	if _, err := s.AddDocumentVersion(&domain.DocumentVersion{DocumentID: id + 1000, FilePath: "x"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("version of a missing document: error = %v, want ErrNotFound", err)
+	}
+
+	doc, err := s.PromoteDocumentVersion(id, 1)
+	if err != nil {
+		t.Fatalf("PromoteDocumentVersion: %v", err)
+	}
+	if doc.CurrentVersion != 1 || doc.FilePath != "experts/cv_v1.pdf" || doc.SHA256 != "aaaa" {
+		t.Errorf("promoted document = version %d at %s, want version 1", doc.CurrentVersion, doc.FilePath)
+	}
+	if _, err := s.PromoteDocumentVersion(id, 9); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing version: error = %v, want ErrNotFound", err)
+	}
+
+	versions, err := s.ListDocumentVersions(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, fmt.Sprintf("%d:%v", v.Version, v.IsCurrent))
+	}
+	if fmt.Sprint(got) != "[3:false 2:false 1:true]" {
+		t.Errorf("versions = %v, want newest first with version 1 current", got)
+	}
+
+	// Files of earlier versions stay referenced by the document
+	for _, path := range []string{"experts/cv_v1.pdf", "experts/cv_v3.pdf"} {
+		if count, _ := s.CountFileReferences(path, 0); count != 1 {
+			t.Errorf("CountFileReferences(%s) = %d, want 1", path, count)
+		}
+		if count, _ := s.CountFileReferences(path, id); count != 0 {
+			t.Errorf("CountFileReferences(%s) excluding the document = %d, want 0", path, count)
+		}
+	}
+}
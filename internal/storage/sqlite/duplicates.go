@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"expertdb/internal/domain"
+)
+
+// Thresholds for reporting possible duplicate experts
+const (
+	duplicateNameThreshold       = 0.8 // Name similarity needed for the name to count as a match
+	duplicateConfidenceThreshold = 0.6 // Confidence needed for a record to be reported
+	duplicatePhoneDigits         = 8   // Trailing digits compared, so country codes don't matter
+	maxDuplicateMatches          = 10
+)
+
+// Confidence contributed by each matching field
+const (
+	duplicateEmailWeight = 0.95
+	duplicatePhoneWeight = 0.85
+	duplicateNameWeight  = 0.8 // Scaled by the name similarity; people do share names
+)
+
+// pendingRequestCondition matches expert requests that are still open
+const pendingRequestCondition = "status IN ('draft', 'submitted', 'under_review', 'needs_changes')"
+
+// nameTitles are designations dropped before comparing person names
+var nameTitles = map[string]bool{"prof": true, "dr": true, "mr": true, "ms": true, "mrs": true, "miss": true, "eng": true}
+
+// personNameSimilarity scores two person names like nameSimilarity, ignoring titles such as "Dr."
+func personNameSimilarity(a, b string) float64 {
+	stripTitles := func(name string) string {
+		words := strings.Fields(normalizeName(name))
+		for len(words) > 1 && nameTitles[words[0]] {
+			words = words[1:]
+		}
+		return strings.Join(words, " ")
+	}
+	return nameSimilarity(stripTitles(a), stripTitles(b))
+}
+
+// normalizePhone keeps the trailing digits of a phone number
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if len(digits) > duplicatePhoneDigits {
+		digits = digits[len(digits)-duplicatePhoneDigits:]
+	}
+	return digits
+}
+
+// scoreDuplicate compares a candidate record with the submitted details
+// Each matching field is independent evidence, so the confidence is 1 - Π(1 - weight)
+func scoreDuplicate(name, email, phone string, candidate *domain.DuplicateMatch) {
+	miss := 1.0
+	candidate.MatchedOn = []string{}
+
+	if email != "" && strings.EqualFold(strings.TrimSpace(candidate.Email), email) {
+		miss *= 1 - duplicateEmailWeight
+		candidate.MatchedOn = append(candidate.MatchedOn, "email")
+	}
+	if phone != "" && normalizePhone(candidate.Phone) == phone {
+		miss *= 1 - duplicatePhoneWeight
+		candidate.MatchedOn = append(candidate.MatchedOn, "phone")
+	}
+	if score := personNameSimilarity(name, candidate.Name); score >= duplicateNameThreshold {
+		miss *= 1 - duplicateNameWeight*score
+		candidate.MatchedOn = append(candidate.MatchedOn, "name")
+	}
+
+	candidate.Confidence = float64(int((1-miss)*100+0.5)) / 100
+}
+
+// FindDuplicateExperts looks for existing experts and open expert requests that may describe the same person
+// Matches are fuzzy on name and exact on email and phone (ignoring case, formatting and country code),
+// sorted by confidence. excludeRequestID leaves a request out of its own report (0 to exclude none)
+func (s *SQLiteStore) FindDuplicateExperts(name, email, phone string, excludeRequestID int64) ([]*domain.DuplicateMatch, error) {
+	email = strings.TrimSpace(email)
+	phone = normalizePhone(phone)
+	if len(phone) < duplicatePhoneDigits {
+		// Too short to identify anyone
+		phone = ""
+	}
+
+	queries := []struct {
+		source string
+		query  string
+		args   []interface{}
+	}{
+		{domain.DuplicateSourceExpert, "SELECT id, name, COALESCE(email, ''), COALESCE(phone, ''), '' FROM experts", nil},
+		{domain.DuplicateSourceExpertRequest, "SELECT id, name, COALESCE(email, ''), COALESCE(phone, ''), status FROM expert_requests WHERE " +
+			pendingRequestCondition + " AND id != ?", []interface{}{excludeRequestID}},
+	}
+
+	matches := []*domain.DuplicateMatch{}
+	for _, q := range queries {
+		rows, err := s.db.Query(q.query, q.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s records: %w", q.source, err)
+		}
+
+		for rows.Next() {
+			candidate := &domain.DuplicateMatch{Source: q.source}
+			if err := rows.Scan(&candidate.ID, &candidate.Name, &candidate.Email, &candidate.Phone, &candidate.Status); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s record: %w", q.source, err)
+			}
+			scoreDuplicate(name, email, phone, candidate)
+			if candidate.Confidence >= duplicateConfidenceThreshold {
+				matches = append(matches, candidate)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating %s records: %w", q.source, err)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+	if len(matches) > maxDuplicateMatches {
+		matches = matches[:maxDuplicateMatches]
+	}
+
+	return matches, nil
+}
+
+// SetExpertRequestDuplicates stores the duplicate report shown when a draft request is submitted,
+// replacing the one recorded when it was created
+func (s *SQLiteStore) SetExpertRequestDuplicates(id int64, matches []*domain.DuplicateMatch, confirmed bool) error {
+	var matchesJSON interface{}
+	if len(matches) > 0 {
+		data, err := json.Marshal(matches)
+		if err != nil {
+			return fmt.Errorf("failed to serialize duplicate matches: %w", err)
+		}
+		matchesJSON = string(data)
+	}
+
+	result, err := s.db.Exec("UPDATE expert_requests SET duplicate_matches = ?, duplicate_confirmed = ? WHERE id = ?",
+		matchesJSON, confirmed, id)
+	if err != nil {
+		return fmt.Errorf("failed to store duplicate matches of expert request %d: %w", id, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestScoreDuplicate(t *testing.T) {
+	candidate := domain.DuplicateMatch{Name: "Amal Hasan", Email: "Amal.Hasan@uob.edu.bh ", Phone: "+973 3900-1234"}
+	tests := []struct {
+		name, email, phone string // phone as passed by FindDuplicateExperts, already normalized
+		confidence         float64
+		matchedOn          string
+	}{
+		{"Someone Else", "amal.hasan@uob.edu.bh", "", 0.95, "[email]"},
+		{"Someone Else", "", "39001234", 0.85, "[phone]"},
+		{"Dr. Amal Hasan", "", "", 0.8, "[name]"},
+		{"Amal Hassan", "", "", 0.73, "[name]"},
+		{"Amal Hasan", "amal.hasan@uob.edu.bh", "39001234", 1, "[email phone name]"},
+		{"Badr Saleh", "badr@example.com", "39005678", 0, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s %s", tt.name, tt.email, tt.phone), func(t *testing.T) {
+			c := candidate
+			scoreDuplicate(tt.name, tt.email, tt.phone, &c)
+			if c.Confidence != tt.confidence || fmt.Sprint(c.MatchedOn) != tt.matchedOn {
+				t.Errorf("confidence %v matched on %v, want %v on %s", c.Confidence, c.MatchedOn, tt.confidence, tt.matchedOn)
+			}
+		})
+	}
+}
+
+func TestFindDuplicateExperts(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	expert := createTestExpert(t, s, "Amal Hasan", "amal.hasan@uob.edu.bh", area)
+	if _, err := s.db.Exec("UPDATE experts SET phone = ? WHERE id = ?", "+973 3900 1234", expert); err != nil {
+		t.Fatal(err)
+	}
+	open := createTestExpertRequest(t, s, newTestExpertRequest("Badr Saleh", area, owner))
+	closed := createTestExpertRequest(t, s, newTestExpertRequest("Carla Diaz", area, owner))
+	if err := s.TransitionExpertRequest(closed, domain.RequestStatusSubmitted, domain.RequestStatusRejected, "Out of scope", reviewer); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, email, phone string
+		exclude            int64
+		want               string // Source and ID of each match, best first
+	}{
+		{"Someone Else", "AMAL.HASAN@uob.edu.bh", "", 0, fmt.Sprintf("[expert:%d]", expert)},
+		{"Someone Else", "", "00973-3900-1234", 0, fmt.Sprintf("[expert:%d]", expert)},
+		{"Someone Else", "", "1234", 0, "[]"},
+		{"Dr Badr Saleh", "", "", 0, fmt.Sprintf("[expert_request:%d]", open)},
+		{"Badr Saleh", "", "", open, "[]"},
+		{"Carla Diaz", "", "", 0, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s %s", tt.name, tt.email, tt.phone), func(t *testing.T) {
+			matches, err := s.FindDuplicateExperts(tt.name, tt.email, tt.phone, tt.exclude)
+			if err != nil {
+				t.Fatalf("FindDuplicateExperts: %v", err)
+			}
+			got := []string{}
+			for _, m := range matches {
+				got = append(got, fmt.Sprintf("%s:%d", m.Source, m.ID))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("matches = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// EnqueueEmail queues an email for the background dispatcher
+func (s *SQLiteStore) EnqueueEmail(email *domain.OutboxEmail) (int64, error) {
+	if email.CreatedAt.IsZero() {
+		email.CreatedAt = time.Now()
+	}
+	email.Status = domain.EmailStatusPending
+
+	result, err := s.db.Exec(`
+		INSERT INTO email_outbox (recipient, subject, body, status, attempts, created_at)
+		VALUES (?, ?, ?, ?, 0, ?)
+	`, email.Recipient, email.Subject, email.Body, email.Status, email.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get email ID: %w", err)
+	}
+	email.ID = id
+
+	return id, nil
+}
+
+// ListPendingEmails returns the oldest emails still waiting to be sent
+func (s *SQLiteStore) ListPendingEmails(limit int) ([]*domain.OutboxEmail, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, recipient, subject, body, status, attempts, last_error, created_at, sent_at
+		FROM email_outbox
+		WHERE status = ?
+		ORDER BY created_at, id
+		LIMIT ?
+	`, domain.EmailStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []*domain.OutboxEmail{}
+	for rows.Next() {
+		var email domain.OutboxEmail
+		var lastError sql.NullString
+		var sentAt sql.NullTime
+		if err := rows.Scan(&email.ID, &email.Recipient, &email.Subject, &email.Body, &email.Status,
+			&email.Attempts, &lastError, &email.CreatedAt, &sentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		email.LastError = lastError.String
+		if sentAt.Valid {
+			email.SentAt = &sentAt.Time
+		}
+		emails = append(emails, &email)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// MarkEmailSent records a successful delivery
+func (s *SQLiteStore) MarkEmailSent(id int64) error {
+	_, err := s.db.Exec(`
+		UPDATE email_outbox SET status = ?, attempts = attempts + 1, last_error = NULL, sent_at = ?
+		WHERE id = ?
+	`, domain.EmailStatusSent, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark email sent: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailFailed records a failed delivery attempt
+// The email stays pending for another try until maxAttempts is reached, then it is marked failed
+func (s *SQLiteStore) MarkEmailFailed(id int64, sendErr string, maxAttempts int) error {
+	_, err := s.db.Exec(`
+		UPDATE email_outbox
+		SET attempts = attempts + 1, last_error = ?,
+			status = CASE WHEN attempts + 1 >= ? THEN ? ELSE status END
+		WHERE id = ?
+	`, sendErr, maxAttempts, domain.EmailStatusFailed, id)
+	if err != nil {
+		return fmt.Errorf("failed to record email failure: %w", err)
+	}
+	return nil
+}
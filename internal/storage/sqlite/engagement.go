@@ -17,7 +17,7 @@ func (s *SQLiteStore) ListEngagements(expertID int64, engagementType string, lim
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(`
 		SELECT id, expert_id, engagement_type, start_date, end_date,
-				project_name, status, feedback_score, notes, created_at
+				project_name, status, feedback_score, notes, created_at, overdue_since
 		FROM expert_engagements
 		WHERE 1=1
 	`)
@@ -62,7 +62,7 @@ func (s *SQLiteStore) ListEngagements(expertID int64, engagementType string, lim
 	var engagements []*domain.Engagement
 	for rows.Next() {
 		var engagement domain.Engagement
-		var endDate sql.NullTime
+		var endDate, overdueSince sql.NullTime
 		var projectName, notes sql.NullString
 		var feedbackScore sql.NullInt32
 		
@@ -70,7 +70,7 @@ func (s *SQLiteStore) ListEngagements(expertID int64, engagementType string, lim
 			&engagement.ID, &engagement.ExpertID, &engagement.EngagementType,
 			&engagement.StartDate, &endDate, &projectName,
 			&engagement.Status, &feedbackScore, &notes,
-			&engagement.CreatedAt,
+			&engagement.CreatedAt, &overdueSince,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan engagement row: %w", err)
@@ -93,6 +93,10 @@ func (s *SQLiteStore) ListEngagements(expertID int64, engagementType string, lim
 			engagement.Notes = notes.String
 		}
 		
+		if overdueSince.Valid {
+			engagement.OverdueSince = &overdueSince.Time
+		}
+		
 		engagements = append(engagements, &engagement)
 	}
 	
@@ -107,13 +111,13 @@ func (s *SQLiteStore) ListEngagements(expertID int64, engagementType string, lim
 func (s *SQLiteStore) GetEngagement(id int64) (*domain.Engagement, error) {
 	query := `
 		SELECT id, expert_id, engagement_type, start_date, end_date,
-				project_name, status, feedback_score, notes, created_at
+				project_name, status, feedback_score, notes, created_at, overdue_since
 		FROM expert_engagements
 		WHERE id = ?
 	`
 	
 	var engagement domain.Engagement
-	var endDate sql.NullTime
+	var endDate, overdueSince sql.NullTime
 	var projectName, notes sql.NullString
 	var feedbackScore sql.NullInt32
 	
@@ -121,7 +125,7 @@ func (s *SQLiteStore) GetEngagement(id int64) (*domain.Engagement, error) {
 		&engagement.ID, &engagement.ExpertID, &engagement.EngagementType,
 		&engagement.StartDate, &endDate, &projectName,
 		&engagement.Status, &feedbackScore, &notes,
-		&engagement.CreatedAt,
+		&engagement.CreatedAt, &overdueSince,
 	)
 	
 	if err != nil {
@@ -148,6 +152,10 @@ func (s *SQLiteStore) GetEngagement(id int64) (*domain.Engagement, error) {
 		engagement.Notes = notes.String
 	}
 	
+	if overdueSince.Valid {
+		engagement.OverdueSince = &overdueSince.Time
+	}
+	
 	return &engagement, nil
 }
 
@@ -171,7 +179,10 @@ func (s *SQLiteStore) CreateEngagement(engagement *domain.Engagement) (int64, er
 	}
 	
 	if engagement.Status == "" {
-		engagement.Status = "pending"
+		engagement.Status = domain.EngagementStatusPending
+	}
+	if err := validateEngagement(engagement); err != nil {
+		return 0, err
 	}
 	
 	// Handle null values for optional fields
@@ -216,6 +227,8 @@ func (s *SQLiteStore) CreateEngagement(engagement *domain.Engagement) (int64, er
 }
 
 // UpdateEngagement updates an existing engagement record
+// A new status must follow the engagement transition rules; an unchanged status is kept as it is,
+// including statuses recorded before they were checked
 func (s *SQLiteStore) UpdateEngagement(engagement *domain.Engagement) error {
 	// Get current engagement to avoid overwriting with empty values
 	current, err := s.GetEngagement(engagement.ID)
@@ -237,15 +250,28 @@ func (s *SQLiteStore) UpdateEngagement(engagement *domain.Engagement) error {
 		engagement.StartDate = current.StartDate
 	}
 	
-	// Status defaults to current if not provided
+	// Status defaults to current if not provided; a new status must follow the engagement transition rules
 	if engagement.Status == "" {
 		engagement.Status = current.Status
 	}
+	if err := domain.ValidateEngagementTransition(current.Status, engagement.Status); err != nil {
+		return err
+	}
+	
+	if engagement.EndDate.IsZero() {
+		engagement.EndDate = current.EndDate
+	}
+	// The transition check has already validated a changed status
+	if err := validateEngagementDates(engagement); err != nil {
+		return err
+	}
 	
+	// The overdue flag only applies while the engagement is active without an end date
 	query := `
 		UPDATE expert_engagements SET
 			engagement_type = ?, start_date = ?, end_date = ?,
-			project_name = ?, status = ?, feedback_score = ?, notes = ?
+			project_name = ?, status = ?, feedback_score = ?, notes = ?,
+			overdue_since = CASE WHEN ? = 'active' AND ? IS NULL THEN overdue_since END
 		WHERE id = ?
 	`
 	
@@ -253,8 +279,6 @@ func (s *SQLiteStore) UpdateEngagement(engagement *domain.Engagement) error {
 	var endDate interface{} = nil
 	if !engagement.EndDate.IsZero() {
 		endDate = engagement.EndDate
-	} else if !current.EndDate.IsZero() {
-		endDate = current.EndDate
 	}
 	
 	var projectName interface{} = nil
@@ -282,6 +306,7 @@ func (s *SQLiteStore) UpdateEngagement(engagement *domain.Engagement) error {
 		query,
 		engagement.EngagementType, engagement.StartDate, endDate,
 		projectName, engagement.Status, feedbackScore, notes,
+		engagement.Status, endDate,
 		engagement.ID,
 	)
 	
@@ -292,6 +317,22 @@ func (s *SQLiteStore) UpdateEngagement(engagement *domain.Engagement) error {
 	return nil
 }
 
+// validateEngagement checks an engagement's status and that it does not end before it starts
+func validateEngagement(engagement *domain.Engagement) error {
+	if !domain.IsValidEngagementStatus(engagement.Status) {
+		return fmt.Errorf("%w: status must be one of: %s", domain.ErrValidation, strings.Join(domain.EngagementStatuses, ", "))
+	}
+	return validateEngagementDates(engagement)
+}
+
+// validateEngagementDates checks that an engagement does not end before it starts
+func validateEngagementDates(engagement *domain.Engagement) error {
+	if !engagement.EndDate.IsZero() && engagement.EndDate.Before(engagement.StartDate) {
+		return fmt.Errorf("%w: end date must not be before the start date", domain.ErrValidation)
+	}
+	return nil
+}
+
 // DeleteEngagement deletes an engagement by ID
 func (s *SQLiteStore) DeleteEngagement(id int64) error {
 	result, err := s.db.Exec("DELETE FROM expert_engagements WHERE id = ?", id)
@@ -365,7 +406,11 @@ func (s *SQLiteStore) ImportEngagements(engagements []*domain.Engagement) (int,
 		}
 		
 		if engagement.Status == "" {
-			engagement.Status = "pending"
+			engagement.Status = domain.EngagementStatusPending
+		}
+		if err := validateEngagement(engagement); err != nil {
+			errors[i] = err
+			continue
 		}
 		
 		// Handle null values for optional fields
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// AdvanceEngagements applies the date-based engagement transitions due at now: pending engagements
+// whose start date has arrived become active, and active engagements whose end date has passed are
+// completed. Active engagements that have run for longer than overdueAfter without an end date are
+// flagged once, with an admin notification
+// Dates are compared in Go, as engagements created in different places store them in different formats
+func (s *SQLiteStore) AdvanceEngagements(now time.Time, overdueAfter time.Duration) (*domain.EngagementLifecycleReport, error) {
+	log := logger.Get()
+
+	rows, err := s.db.Query(`
+		SELECT g.id, g.expert_id, COALESCE(e.name, ''), g.engagement_type, g.start_date, g.end_date,
+			COALESCE(g.project_name, ''), g.status, g.overdue_since
+		FROM expert_engagements g
+		LEFT JOIN experts e ON e.id = g.expert_id
+		WHERE g.status IN (?, ?)
+	`, domain.EngagementStatusPending, domain.EngagementStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open engagements: %w", err)
+	}
+
+	type openEngagement struct {
+		domain.Engagement
+		expertName string
+	}
+	var open []*openEngagement
+	for rows.Next() {
+		var g openEngagement
+		var endDate, overdueSince sql.NullTime
+		if err := rows.Scan(&g.ID, &g.ExpertID, &g.expertName, &g.EngagementType, &g.StartDate, &endDate,
+			&g.ProjectName, &g.Status, &overdueSince); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan open engagement: %w", err)
+		}
+		if endDate.Valid {
+			g.EndDate = endDate.Time
+		}
+		if overdueSince.Valid {
+			g.OverdueSince = &overdueSince.Time
+		}
+		open = append(open, &g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating open engagements: %w", err)
+	}
+
+	report := &domain.EngagementLifecycleReport{}
+	for _, g := range open {
+		// A pending engagement whose end date has also passed is activated and completed in one run
+		for {
+			status, due := domain.DueEngagementStatus(&g.Engagement, now)
+			if !due {
+				break
+			}
+			result, err := s.db.Exec(`
+				UPDATE expert_engagements SET status = ?, overdue_since = NULL
+				WHERE id = ? AND status = ?
+			`, status, g.ID, g.Status)
+			if err != nil {
+				return report, fmt.Errorf("failed to move engagement %d to %s: %w", g.ID, status, err)
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				break // Changed since it was read
+			}
+
+			log.Info("Engagement %d moved from %s to %s", g.ID, g.Status, status)
+			if status == domain.EngagementStatusActive {
+				report.Activated++
+			} else {
+				report.Completed++
+			}
+			g.Status = status
+			g.OverdueSince = nil
+		}
+
+		if g.OverdueSince != nil || !domain.EngagementOverdue(&g.Engagement, now, overdueAfter) {
+			continue
+		}
+		flagged, err := s.flagOverdueEngagement(&g.Engagement, g.expertName, now)
+		if err != nil {
+			return report, err
+		}
+		if flagged {
+			report.Overdue++
+		}
+	}
+
+	return report, nil
+}
+
+// flagOverdueEngagement marks an active engagement without an end date as overdue and raises an
+// admin notification about it, reporting false if it was changed or flagged since it was read
+func (s *SQLiteStore) flagOverdueEngagement(g *domain.Engagement, expertName string, now time.Time) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE expert_engagements SET overdue_since = ?
+		WHERE id = ? AND status = ? AND end_date IS NULL AND overdue_since IS NULL
+	`, now, g.ID, domain.EngagementStatusActive)
+	if err != nil {
+		return false, fmt.Errorf("failed to flag engagement %d as overdue: %w", g.ID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	subject := fmt.Sprintf("Engagement #%d of expert #%d (%s) as %s", g.ID, g.ExpertID, expertName, g.EngagementType)
+	if g.ProjectName != "" {
+		subject += fmt.Sprintf(" for '%s'", g.ProjectName)
+	}
+	message := fmt.Sprintf("%s has been active for %s without an end date", subject, formatAge(now.Sub(g.StartDate)))
+	if _, err := tx.Exec(`
+		INSERT INTO admin_notifications (kind, engagement_id, status, message, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, domain.AdminNotificationEngagementOverdue, g.ID, g.Status, message, now); err != nil {
+		return false, fmt.Errorf("failed to notify admins of overdue engagement %d: %w", g.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit overdue flag of engagement %d: %w", g.ID, err)
+	}
+	logger.Get().Info("Flagged engagement %d as overdue: %s", g.ID, message)
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// createTestEngagement adds an evaluator engagement for a new expert
+func createTestEngagement(t *testing.T, s *SQLiteStore, status string, start, end time.Time) int64 {
+	t.Helper()
+	n := fixtureSeq.Add(1)
+	expertID := createTestExpert(t, s, fmt.Sprintf("Expert %d", n), fmt.Sprintf("engaged%d@example.com", n), createTestArea(t, s, "Engineering"))
+	id, err := s.CreateEngagement(&domain.Engagement{
+		ExpertID:       expertID,
+		EngagementType: "evaluator",
+		StartDate:      start,
+		EndDate:        end,
+		ProjectName:    "Programme review",
+		Status:         status,
+	})
+	if err != nil {
+		t.Fatalf("create %s engagement: %v", status, err)
+	}
+	return id
+}
+
+func TestAdvanceEngagements(t *testing.T) {
+	now := time.Now()
+	overdueAfter := 90 * 24 * time.Hour
+	tests := []struct {
+		name        string
+		status      string
+		start, end  time.Time
+		wantStatus  string
+		wantReport  domain.EngagementLifecycleReport
+		wantOverdue bool
+	}{
+		{"pending not yet started", domain.EngagementStatusPending, now.AddDate(0, 0, 1), time.Time{},
+			domain.EngagementStatusPending, domain.EngagementLifecycleReport{}, false},
+		{"pending started", domain.EngagementStatusPending, now.AddDate(0, 0, -1), now.AddDate(0, 0, 5),
+			domain.EngagementStatusActive, domain.EngagementLifecycleReport{Activated: 1}, false},
+		{"pending already over", domain.EngagementStatusPending, now.AddDate(0, 0, -10), now.AddDate(0, 0, -2),
+			domain.EngagementStatusCompleted, domain.EngagementLifecycleReport{Activated: 1, Completed: 1}, false},
+		{"active ended", domain.EngagementStatusActive, now.AddDate(0, 0, -10), now.AddDate(0, 0, -2),
+			domain.EngagementStatusCompleted, domain.EngagementLifecycleReport{Completed: 1}, false},
+		{"active without an end", domain.EngagementStatusActive, now.AddDate(0, 0, -100), time.Time{},
+			domain.EngagementStatusActive, domain.EngagementLifecycleReport{Overdue: 1}, true},
+		{"cancelled", domain.EngagementStatusCancelled, now.AddDate(0, 0, -100), time.Time{},
+			domain.EngagementStatusCancelled, domain.EngagementLifecycleReport{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			id := createTestEngagement(t, s, tt.status, tt.start, tt.end)
+
+			report, err := s.AdvanceEngagements(now, overdueAfter)
+			if err != nil {
+				t.Fatalf("AdvanceEngagements: %v", err)
+			}
+			if *report != tt.wantReport {
+				t.Errorf("report = %+v, want %+v", *report, tt.wantReport)
+			}
+			g, err := s.GetEngagement(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Status != tt.wantStatus || (g.OverdueSince != nil) != tt.wantOverdue {
+				t.Errorf("engagement %s, overdue since %v; want %s, overdue %v", g.Status, g.OverdueSince, tt.wantStatus, tt.wantOverdue)
+			}
+
+			// A second run finds nothing left to do
+			again, err := s.AdvanceEngagements(now.Add(time.Minute), overdueAfter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *again != (domain.EngagementLifecycleReport{}) {
+				t.Errorf("second run report = %+v, want no changes", *again)
+			}
+		})
+	}
+}
+
+func TestOverdueEngagementNotifiesOnce(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+	id := createTestEngagement(t, s, domain.EngagementStatusActive, now.AddDate(0, 0, -100), time.Time{})
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.AdvanceEngagements(now.Add(time.Duration(i)*time.Hour), 90*24*time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var notifications int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM admin_notifications WHERE kind = ? AND engagement_id = ?",
+		domain.AdminNotificationEngagementOverdue, id).Scan(&notifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 1 {
+		t.Errorf("%d overdue notifications, want 1", notifications)
+	}
+
+	// Setting an end date clears the flag
+	if err := s.UpdateEngagement(&domain.Engagement{ID: id, EndDate: now.AddDate(0, 1, 0)}); err != nil {
+		t.Fatalf("UpdateEngagement: %v", err)
+	}
+	if g, _ := s.GetEngagement(id); g.OverdueSince != nil {
+		t.Errorf("engagement with an end date still overdue since %v", g.OverdueSince)
+	}
+}
+
+func TestUpdateEngagementStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		status  string // Empty keeps the current status
+		wantErr bool
+	}{
+		{"keep", domain.EngagementStatusActive, "", false},
+		{"allowed transition", domain.EngagementStatusActive, domain.EngagementStatusCompleted, false},
+		{"final status", domain.EngagementStatusCompleted, domain.EngagementStatusActive, true},
+		{"unknown status", domain.EngagementStatusActive, "confirmed", true},
+		{"keep legacy status", "confirmed", "", false},
+		{"leave legacy status", "confirmed", domain.EngagementStatusCompleted, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			id := createTestEngagement(t, s, domain.EngagementStatusActive, time.Now().AddDate(0, 0, -1), time.Time{})
+			// Statuses outside the list were recorded before they were checked
+			if _, err := s.db.Exec("UPDATE expert_engagements SET status = ? WHERE id = ?", tt.current, id); err != nil {
+				t.Fatal(err)
+			}
+
+			err := s.UpdateEngagement(&domain.Engagement{ID: id, Status: tt.status, Notes: "Updated"})
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("UpdateEngagement error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateEngagement: %v", err)
+			}
+			want := tt.status
+			if want == "" {
+				want = tt.current
+			}
+			if g, _ := s.GetEngagement(id); g.Status != want || g.Notes != "Updated" {
+				t.Errorf("engagement = %s with notes %q, want %s with the update", g.Status, g.Notes, want)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"expertdb/internal/domain"
 	"expertdb/internal/logger"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -77,6 +76,13 @@ func (s *SQLiteStore) CreateExpert(expert *domain.Expert) (int64, error) {
 		return 0, fmt.Errorf("failed to get expert ID: %w", err)
 	}
 
+	// Training status is derived from attendance; record a declared flag as legacy training
+	if expert.IsTrained {
+		if err = s.recordLegacyTrainingTx(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
 	// Insert experience entries
 	for _, exp := range expert.ExperienceEntries {
 		expQuery := `
@@ -116,8 +122,8 @@ func (s *SQLiteStore) GetExpert(id int64) (*domain.Expert, error) {
 	query := `
 		SELECT e.id, e.name, e.designation, e.affiliation, 
 		       e.is_bahraini, e.is_available, e.rating, e.role, 
-		       e.employment_type, e.general_area, ea.name as general_area_name, 
-		       e.specialized_area, e.is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
+		       e.employment_type, e.general_area, COALESCE(eap.path, ea.name) as general_area_name, 
+		       e.specialized_area, ` + expertIsTrainedExpr + ` AS is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
 		       e.is_published, e.created_at, e.updated_at,
 		       COALESCE(
 		           (SELECT GROUP_CONCAT(sa.name, ', ')
@@ -129,6 +135,7 @@ func (s *SQLiteStore) GetExpert(id int64) (*domain.Expert, error) {
 		       ) as specialized_area_names
 		FROM experts e
 		LEFT JOIN expert_areas ea ON e.general_area = ea.id
+		LEFT JOIN expert_area_paths eap ON eap.id = e.general_area
 		WHERE e.id = ?
 	`
 
@@ -204,6 +211,14 @@ func (s *SQLiteStore) GetExpert(id int64) (*domain.Expert, error) {
 		expert.Documents = docSlice
 	}
 
+	trainingRecords, err := s.ListExpertTrainingRecords(expert.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expert training records: %w", err)
+	}
+	for _, record := range trainingRecords {
+		expert.TrainingRecords = append(expert.TrainingRecords, *record)
+	}
+
 	engagements, err := s.ListEngagements(expert.ID, "", 100, 0) // empty string for all types, default limit
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expert engagements: %w", err)
@@ -225,8 +240,8 @@ func (s *SQLiteStore) GetExpertByEmail(email string) (*domain.Expert, error) {
 	query := `
 		SELECT e.id, e.name, e.designation, e.affiliation, 
 		       e.is_bahraini, e.is_available, e.rating, e.role, 
-		       e.employment_type, e.general_area, ea.name as general_area_name, 
-		       e.specialized_area, e.is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
+		       e.employment_type, e.general_area, COALESCE(eap.path, ea.name) as general_area_name, 
+		       e.specialized_area, ` + expertIsTrainedExpr + ` AS is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
 		       e.is_published, e.created_at, e.updated_at,
 		       COALESCE(
 		           (SELECT GROUP_CONCAT(sa.name, ', ')
@@ -238,6 +253,7 @@ func (s *SQLiteStore) GetExpertByEmail(email string) (*domain.Expert, error) {
 		       ) as specialized_area_names
 		FROM experts e
 		LEFT JOIN expert_areas ea ON e.general_area = ea.id
+		LEFT JOIN expert_area_paths eap ON eap.id = e.general_area
 		WHERE e.email = ?
 	`
 
@@ -381,6 +397,8 @@ func (s *SQLiteStore) UpdateExpert(expert *domain.Expert, editedBy int64) error
 	if expert.Email == "" {
 		expert.Email = currentExpert.Email
 	}
+	// Training status is derived from attendance records and cannot be edited directly
+	expert.IsTrained = currentExpert.IsTrained
 
 	// Calculate changes for audit trail
 	changedFields, oldValues, newValues := s.calculateExpertChanges(currentExpert, expert)
@@ -522,24 +540,64 @@ func (s *SQLiteStore) DeleteExpert(id int64) error {
 	
 	// If there are associated documents, delete them first
 	if len(expert.Documents) > 0 {
-		// Delete the document files
+		// Delete the document files, including earlier versions
+		filePaths := []string{}
+		seen := map[string]bool{}
 		for _, doc := range expert.Documents {
-			// First check if file exists
-			filePath := doc.FilePath
-			if filePath != "" {
-				if _, err := os.Stat(filePath); err == nil {
-					// File exists, delete it
-					if err := os.Remove(filePath); err != nil {
-						logger.Get().Warn("Failed to delete document file: %s - %v", filePath, err)
-						// Log but continue - we still want to delete the database records
-					} else {
-						logger.Get().Debug("Deleted document file: %s", filePath)
-					}
+			paths := []string{doc.FilePath}
+			rows, err := tx.Query("SELECT file_path FROM document_versions WHERE document_id = ?", doc.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get document versions: %w", err)
+			}
+			for rows.Next() {
+				var versionPath string
+				if err := rows.Scan(&versionPath); err != nil {
+					rows.Close()
+					return fmt.Errorf("failed to scan document version: %w", err)
+				}
+				paths = append(paths, versionPath)
+			}
+			rows.Close()
+			
+			for _, filePath := range paths {
+				if filePath != "" && !seen[filePath] {
+					seen[filePath] = true
+					filePaths = append(filePaths, filePath)
 				}
 			}
 		}
 		
-		// Delete document records from database
+		for _, filePath := range filePaths {
+			// Identical uploads share one file; keep it while documents of other experts still use it
+			var sharedCount int
+			err := tx.QueryRow(`
+				SELECT COUNT(*) FROM (
+					SELECT id FROM expert_documents WHERE file_path = ? AND expert_id != ?
+					UNION ALL
+					SELECT v.id FROM document_versions v
+					INNER JOIN expert_documents d ON d.id = v.document_id
+					WHERE v.file_path = ? AND d.expert_id != ?
+				)`, filePath, id, filePath, id).Scan(&sharedCount)
+			if err != nil {
+				return fmt.Errorf("failed to check for shared document files: %w", err)
+			}
+			if sharedCount > 0 {
+				logger.Get().Debug("Keeping document file shared with other documents: %s", filePath)
+				continue
+			}
+			if err := s.blobs.Delete(filePath); err != nil {
+				logger.Get().Warn("Failed to delete document file: %s - %v", filePath, err)
+				// Log but continue - we still want to delete the database records
+			} else {
+				logger.Get().Debug("Deleted document file: %s", filePath)
+			}
+		}
+		
+		// Delete document versions and records from database
+		_, err = tx.Exec("DELETE FROM document_versions WHERE document_id IN (SELECT id FROM expert_documents WHERE expert_id = ?)", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete expert document versions: %w", err)
+		}
 		_, err = tx.Exec("DELETE FROM expert_documents WHERE expert_id = ?", id)
 		if err != nil {
 			return fmt.Errorf("failed to delete expert documents: %w", err)
@@ -582,8 +640,8 @@ func (s *SQLiteStore) ListExperts(filters map[string]interface{}, limit, offset
 	queryBase := `
 		SELECT e.id, e.name, e.designation, e.affiliation, 
 		       e.is_bahraini, e.is_available, e.rating, e.role, 
-		       e.employment_type, e.general_area, ea.name as general_area_name, 
-		       e.specialized_area, e.is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
+		       e.employment_type, e.general_area, COALESCE(eap.path, ea.name) as general_area_name, 
+		       e.specialized_area, ` + expertIsTrainedExpr + ` AS is_trained, e.cv_document_id, e.approval_document_id, e.phone, e.email, 
 		       e.is_published, e.created_at, e.updated_at,
 		       COALESCE(
 		           (SELECT GROUP_CONCAT(sa.name, ', ')
@@ -595,6 +653,7 @@ func (s *SQLiteStore) ListExperts(filters map[string]interface{}, limit, offset
 		       ) as specialized_area_names
 		FROM experts e
 		LEFT JOIN expert_areas ea ON e.general_area = ea.id
+		LEFT JOIN expert_area_paths eap ON eap.id = e.general_area
 	`
 
 	// Add WHERE clause and parameters if filters are provided
@@ -628,6 +687,7 @@ func (s *SQLiteStore) ListExperts(filters map[string]interface{}, limit, offset
 			"is_bahraini":     "e.is_bahraini",
 			"is_available":    "e.is_available",
 			"is_published":    "e.is_published",
+			"is_trained":      "is_trained",
 		}
 		
 		if columnExpr, exists := allowedSortFields[sortByStr]; exists {
@@ -922,6 +982,22 @@ func buildLikeClause(field string, values []string) (string, []interface{}) {
 	return fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), params
 }
 
+// parseSearchTerms splits a search into words, keeping "quoted phrases" together
+func parseSearchTerms(search string) []string {
+	var terms []string
+	for i, part := range strings.Split(search, `"`) {
+		if i%2 == 1 {
+			// Inside quotes: one phrase with its spacing normalized
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		terms = append(terms, strings.Fields(part)...)
+	}
+	return terms
+}
+
 // Helper function to build WHERE clause for expert filters - Clean implementation
 func buildWhereClauseForExpertFilters(filters map[string]interface{}) (string, []interface{}) {
 	var conditions []string
@@ -958,10 +1034,23 @@ func buildWhereClauseForExpertFilters(filters map[string]interface{}) (string, [
 				}
 			}
 			if len(intValues) > 0 {
-				condition, filterParams := buildInClause("e.general_area", intValues)
-				if condition != "" {
-					conditions = append(conditions, condition)
+				if includeDescendants, _ := filters["general_area_descendants"].(bool); includeDescendants {
+					// Match the selected areas and every area below them in the hierarchy
+					condition, filterParams := buildInClause("id", intValues)
+					conditions = append(conditions, `e.general_area IN (
+						WITH RECURSIVE area_tree(id) AS (
+							SELECT id FROM expert_areas WHERE `+condition+`
+							UNION
+							SELECT a.id FROM expert_areas a JOIN area_tree t ON a.parent_id = t.id
+						)
+						SELECT id FROM area_tree)`)
 					params = append(params, filterParams...)
+				} else {
+					condition, filterParams := buildInClause("e.general_area", intValues)
+					if condition != "" {
+						conditions = append(conditions, condition)
+						params = append(params, filterParams...)
+					}
 				}
 			}
 		}
@@ -1000,6 +1089,49 @@ func buildWhereClauseForExpertFilters(filters map[string]interface{}) (string, [
 		}
 	}
 
+	// Training filters - derived from valid attendance records
+	if val, ok := filters["is_trained"]; ok {
+		conditions = append(conditions, expertIsTrainedExpr+" = ?")
+		params = append(params, val)
+	}
+
+	// Trained in any of the given program IDs
+	if val, ok := filters["trained_in"]; ok && val != "" {
+		values := parseMultiValue(val.(string))
+		programIDs := make([]string, 0, len(values))
+		for _, strVal := range values {
+			if _, err := strconv.ParseInt(strVal, 10, 64); err == nil {
+				programIDs = append(programIDs, strVal)
+			}
+		}
+		if len(programIDs) > 0 {
+			condition, filterParams := buildInClause("ts.program_id", programIDs)
+			conditions = append(conditions, `EXISTS (SELECT 1 FROM training_attendance ta
+				JOIN training_sessions ts ON ts.id = ta.session_id
+				WHERE ta.expert_id = e.id AND `+validAttendanceCondition+` AND `+condition+`)`)
+			params = append(params, filterParams...)
+		}
+	}
+
+	// Valid training that lapses within the given number of days
+	if val, ok := filters["training_expiring_within"]; ok {
+		if days, isInt := val.(int); isInt && days >= 0 {
+			conditions = append(conditions, `EXISTS (SELECT 1 FROM training_attendance ta
+				WHERE ta.expert_id = e.id AND ta.status = 'attended'
+				AND ta.expires_at >= date('now') AND ta.expires_at <= date('now', ?))`)
+			params = append(params, fmt.Sprintf("+%d days", days))
+		}
+	}
+
+	// Every search term must appear in the text extracted from the expert's CV
+	if val, ok := filters["cv_text"]; ok && val != "" {
+		for _, term := range parseSearchTerms(val.(string)) {
+			conditions = append(conditions, `EXISTS (SELECT 1 FROM expert_documents d
+				WHERE d.id = e.cv_document_id AND REPLACE(d.extracted_text, char(10), ' ') LIKE ?)`)
+			params = append(params, "%"+term+"%")
+		}
+	}
+
 	// Combine conditions with AND
 	whereClause := ""
 	if len(conditions) > 0 {
@@ -1020,10 +1152,15 @@ func (s *SQLiteStore) UpdateExpertApprovalDocument(expertID, documentID int64) e
 }
 
 // updateDocumentReference is a generic helper for updating document references
+// A documentID of 0 clears the reference
 func (s *SQLiteStore) updateDocumentReference(table, column string, entityID, documentID int64) error {
 	query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", table, column)
 	
-	result, err := s.db.Exec(query, documentID, entityID)
+	var reference interface{}
+	if documentID != 0 {
+		reference = documentID
+	}
+	result, err := s.db.Exec(query, reference, entityID)
 	if err != nil {
 		return fmt.Errorf("failed to update %s document reference: %w", table, err)
 	}
@@ -1154,6 +1291,11 @@ func (s *SQLiteStore) calculateExpertChanges(oldExpert, newExpert *domain.Expert
 
 // createExpertEditHistoryTx creates an audit history entry within a transaction
 func (s *SQLiteStore) createExpertEditHistoryTx(tx *sql.Tx, expertID, editedBy int64, changedFields []string, oldValues, newValues map[string]interface{}) error {
+	return s.createExpertEditHistoryWithReasonTx(tx, expertID, editedBy, changedFields, oldValues, newValues, "")
+}
+
+// createExpertEditHistoryWithReasonTx creates an audit history entry with an optional change reason
+func (s *SQLiteStore) createExpertEditHistoryWithReasonTx(tx *sql.Tx, expertID, editedBy int64, changedFields []string, oldValues, newValues map[string]interface{}, changeReason string) error {
 	
 	changedFieldsJSON, err := json.Marshal(changedFields)
 	if err != nil {
@@ -1170,13 +1312,18 @@ func (s *SQLiteStore) createExpertEditHistoryTx(tx *sql.Tx, expertID, editedBy i
 		return fmt.Errorf("failed to marshal new values: %w", err)
 	}
 
+	var reason interface{}
+	if changeReason != "" {
+		reason = changeReason
+	}
+
 	query := `
 		INSERT INTO expert_edit_history (
-			expert_id, edited_by, edited_at, fields_changed, old_values, new_values
-		) VALUES (?, ?, ?, ?, ?, ?)
+			expert_id, edited_by, edited_at, fields_changed, old_values, new_values, change_reason
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = tx.Exec(query, expertID, editedBy, time.Now(), string(changedFieldsJSON), string(oldValuesJSON), string(newValuesJSON))
+	_, err = tx.Exec(query, expertID, editedBy, time.Now(), string(changedFieldsJSON), string(oldValuesJSON), string(newValuesJSON), reason)
 	if err != nil {
 		return fmt.Errorf("failed to insert edit history: %w", err)
 	}
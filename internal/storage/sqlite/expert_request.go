@@ -4,8 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 	
@@ -16,6 +16,81 @@ import (
 
 // CreateExpertRequest creates a new expert request in the database
 func (s *SQLiteStore) CreateExpertRequest(req *domain.ExpertRequest) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	id, err := s.createExpertRequestTx(tx, req)
+	if err != nil {
+		return 0, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit expert request: %w", err)
+	}
+	
+	log := logger.Get()
+	log.Debug("Expert request created successfully with ID: %d", id)
+	return id, nil
+}
+
+// CreateExpertRequestWithDocument creates an expert request together with its CV document as one unit of work
+// writeFile is called with the new request ID and must store the file, returning its path. If anything fails
+// (including writeFile) no rows are kept; removing a file that was already written is left to the caller
+func (s *SQLiteStore) CreateExpertRequestWithDocument(req *domain.ExpertRequest, doc *domain.Document, writeFile func(requestID int64) (string, error)) (int64, error) {
+	log := logger.Get()
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	id, err := s.createExpertRequestTx(tx, req)
+	if err != nil {
+		return 0, err
+	}
+	
+	filePath, err := writeFile(id)
+	if err != nil {
+		return 0, err
+	}
+	
+	// Documents of a pending request are keyed by the request ID until approval moves them to the expert
+	doc.ExpertID = id
+	doc.FilePath = filePath
+	docID, err := insertDocument(tx, doc)
+	if err != nil {
+		return 0, err
+	}
+	doc.ID = docID
+	
+	column := "cv_document_id"
+	if doc.DocumentType == "approval" {
+		column = "approval_document_id"
+	}
+	if _, err := tx.Exec("UPDATE expert_requests SET "+column+" = ? WHERE id = ?", docID, id); err != nil {
+		return 0, fmt.Errorf("failed to link document to expert request: %w", err)
+	}
+	if doc.DocumentType == "approval" {
+		req.ApprovalDocumentID = &docID
+	} else {
+		req.CVDocumentID = &docID
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit expert request: %w", err)
+	}
+	
+	log.Debug("Expert request created successfully with ID: %d and %s document %d", id, doc.DocumentType, docID)
+	return id, nil
+}
+
+// createExpertRequestTx inserts an expert request with its experience and education entries
+// and starts its status history, all within the given transaction
+func (s *SQLiteStore) createExpertRequestTx(tx *sql.Tx, req *domain.ExpertRequest) (int64, error) {
 	log := logger.Get()
 	log.Debug("Creating expert request for: %s", req.Name)
 	
@@ -24,21 +99,52 @@ func (s *SQLiteStore) CreateExpertRequest(req *domain.ExpertRequest) (int64, err
 			name, designation, affiliation, is_bahraini, is_available,
 			role, employment_type, general_area, specialized_area, is_trained,
 			cv_document_id, approval_document_id, phone, email, is_published, 
-			suggested_specialized_areas, status, created_at, created_by
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			suggested_specialized_areas, status, created_at, created_by,
+			duplicate_matches, duplicate_confirmed, is_self_nominated, nomination_invite_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	
 	// Convert specialized areas to JSON string for storage
 	suggestedAreasJSON := s.serializeSuggestedAreas(req.SuggestedSpecializedAreas)
 	
+	// Keep the duplicate report the submitter saw, for reviewers
+	var duplicateMatchesJSON interface{}
+	if len(req.DuplicateMatches) > 0 {
+		data, err := json.Marshal(req.DuplicateMatches)
+		if err != nil {
+			return 0, fmt.Errorf("failed to serialize duplicate matches: %w", err)
+		}
+		duplicateMatchesJSON = string(data)
+	}
+	
+	// New requests are submitted for review unless saved as a draft
+	if req.Status == "" {
+		req.Status = domain.RequestStatusSubmitted
+	}
+	
+	// Self-nominations have no submitting user
+	var createdBy interface{}
+	if req.CreatedBy != 0 {
+		createdBy = req.CreatedBy
+	}
+	
+	// A nomination consumes one use of its invite, in the same transaction so an invite
+	// can't be used more often than allowed
+	if req.NominationInviteID != nil {
+		if err := consumeNominationInviteTx(tx, *req.NominationInviteID); err != nil {
+			return 0, err
+		}
+	}
+	
 	// Set document IDs (initially nil)
 	var cvDocumentID, approvalDocumentID *int64
 	
-	result, err := s.db.Exec(query,
+	result, err := tx.Exec(query,
 		req.Name, req.Designation, req.Affiliation, req.IsBahraini, req.IsAvailable,
 		req.Role, req.EmploymentType, req.GeneralArea, req.SpecializedArea, req.IsTrained,
 		cvDocumentID, approvalDocumentID, req.Phone, req.Email, req.IsPublished, 
-		suggestedAreasJSON, req.Status, req.CreatedAt, req.CreatedBy,
+		suggestedAreasJSON, req.Status, req.CreatedAt, createdBy,
+		duplicateMatchesJSON, req.DuplicateConfirmed, req.IsSelfNominated, req.NominationInviteID,
 	)
 	if err != nil {
 		log.Error("Failed to create expert request: %v", err)
@@ -56,7 +162,7 @@ func (s *SQLiteStore) CreateExpertRequest(req *domain.ExpertRequest) (int64, err
 	
 	// Store experience entries
 	for _, entry := range req.ExperienceEntries {
-		_, err := s.db.Exec(`
+		_, err := tx.Exec(`
 			INSERT INTO expert_request_experience_entries (
 				expert_request_id, organization, position, start_date, end_date, is_current, country, description
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
@@ -69,7 +175,7 @@ func (s *SQLiteStore) CreateExpertRequest(req *domain.ExpertRequest) (int64, err
 	
 	// Store education entries
 	for _, entry := range req.EducationEntries {
-		_, err := s.db.Exec(`
+		_, err := tx.Exec(`
 			INSERT INTO expert_request_education_entries (
 				expert_request_id, institution, degree, field_of_study, graduation_year, country, description
 			) VALUES (?, ?, ?, ?, ?, ?, ?)`,
@@ -80,7 +186,11 @@ func (s *SQLiteStore) CreateExpertRequest(req *domain.ExpertRequest) (int64, err
 		}
 	}
 	
-	log.Debug("Expert request created successfully with ID: %d", id)
+	// Start the status history with the initial status
+	if err := recordExpertRequestTransition(tx, id, "", req.Status, "", req.CreatedBy); err != nil {
+		return 0, err
+	}
+	
 	return id, nil
 }
 
@@ -93,16 +203,21 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 			is_available, role, employment_type, general_area, 
 			specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
 			is_published, suggested_specialized_areas, status, rejection_reason, 
-			created_at, reviewed_at, reviewed_by, created_by
+			created_at, reviewed_at, reviewed_by, created_by,
+			duplicate_matches, duplicate_confirmed, is_self_nominated, nomination_invite_id, status_changed_at
 		FROM expert_requests
 		WHERE id = ?
 	`
 	
 	var req domain.ExpertRequest
+	var duplicateMatchesJSON sql.NullString
+	var nominationInviteID sql.NullInt64
+	var statusChangedAt sql.NullTime
 	var reviewedAt sql.NullTime
 	var reviewedBy sql.NullInt64
 	var createdBy sql.NullInt64
 	var rejectionReason sql.NullString
+	var specializedArea sql.NullString // Edits store no specialized area as NULL
 	var suggestedAreasJSON string
 	var cvDocumentID sql.NullInt64
 	var approvalDocumentID sql.NullInt64
@@ -110,10 +225,11 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 	err := s.db.QueryRow(query, id).Scan(
 		&req.ID, &req.Name, &req.Designation, &req.Affiliation, 
 		&req.IsBahraini, &req.IsAvailable, &req.Role, 
-		&req.EmploymentType, &req.GeneralArea, &req.SpecializedArea, 
+		&req.EmploymentType, &req.GeneralArea, &specializedArea, 
 		&req.IsTrained, &cvDocumentID, &approvalDocumentID, &req.Phone, &req.Email, 
 		&req.IsPublished, &suggestedAreasJSON, &req.Status, &rejectionReason, 
 		&req.CreatedAt, &reviewedAt, &reviewedBy, &createdBy,
+		&duplicateMatchesJSON, &req.DuplicateConfirmed, &req.IsSelfNominated, &nominationInviteID, &statusChangedAt,
 	)
 	
 	if err != nil {
@@ -123,6 +239,12 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 		return nil, fmt.Errorf("failed to get expert request: %w", err)
 	}
 	
+	if duplicateMatchesJSON.Valid && duplicateMatchesJSON.String != "" {
+		if err := json.Unmarshal([]byte(duplicateMatchesJSON.String), &req.DuplicateMatches); err != nil {
+			return nil, fmt.Errorf("failed to parse duplicate matches: %w", err)
+		}
+	}
+	
 	// Set nullable fields
 	
 	if reviewedAt.Valid {
@@ -141,6 +263,8 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 		req.RejectionReason = rejectionReason.String
 	}
 	
+	req.SpecializedArea = specializedArea.String
+	
 	if cvDocumentID.Valid {
 		req.CVDocumentID = &cvDocumentID.Int64
 	}
@@ -149,6 +273,15 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 		req.ApprovalDocumentID = &approvalDocumentID.Int64
 	}
 	
+	if nominationInviteID.Valid {
+		req.NominationInviteID = &nominationInviteID.Int64
+	}
+	
+	if statusChangedAt.Valid {
+		req.StatusChangedAt = statusChangedAt.Time
+	}
+	req.ApplySLA(s.requestSLA, time.Now())
+	
 	// Resolve document references
 	req.ResolveCVDocument(s.GetDocument)
 	req.ResolveApprovalDocument(s.GetDocument)
@@ -173,45 +306,83 @@ func (s *SQLiteStore) GetExpertRequest(id int64) (*domain.ExpertRequest, error)
 	return &req, nil
 }
 
-// ListExpertRequests retrieves a list of expert requests with the given status
-func (s *SQLiteStore) ListExpertRequests(status string, limit, offset int) ([]*domain.ExpertRequest, error) {
+// ListExpertRequests retrieves a paginated list of expert requests matching the given filters
+// Supported filters: status, created_by, general_area, role (comma-separated for several values),
+// is_bahraini, is_self_nominated, sla_breached, created_from / created_to (YYYY-MM-DD), search (name or affiliation),
+// sort_by and sort_order (defaults to newest first)
+func (s *SQLiteStore) ListExpertRequests(filters map[string]interface{}, limit, offset int) ([]*domain.ExpertRequest, error) {
+	return s.listExpertRequests(filters, limit, offset, 0)
+}
+
+// listExpertRequests implements ListExpertRequests. A non-zero unreadFor also counts, in the same query,
+// the public comments on each request that user has not read yet
+func (s *SQLiteStore) listExpertRequests(filters map[string]interface{}, limit, offset int, unreadFor int64) ([]*domain.ExpertRequest, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 	
-	var query string
+	unreadColumn, unreadJoin := "0", ""
 	var args []interface{}
+	if unreadFor != 0 {
+		// Counted per request in a derived table, which keeps its columns out of the filters' way
+		unreadColumn = "COALESCE(unread.unread_count, 0)"
+		unreadJoin = `
+		LEFT JOIN (
+			SELECT c.request_id AS unread_request_id, COUNT(*) AS unread_count
+			FROM expert_request_comments c
+			LEFT JOIN expert_request_comment_reads r ON r.request_id = c.request_id AND r.user_id = ?
+			WHERE COALESCE(c.author_id, 0) != ?
+			  AND c.is_internal = 0
+			  AND (r.last_read_at IS NULL OR c.created_at > r.last_read_at)
+			GROUP BY c.request_id
+		) unread ON unread.unread_request_id = expert_requests.id`
+		args = append(args, unreadFor, unreadFor)
+	}
 	
-	if status != "" && status != "all" {
-		query = `
-			SELECT 
-				id, name, designation, affiliation, is_bahraini, 
-				is_available, role, employment_type, general_area, 
-				specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
-				is_published, suggested_specialized_areas, status, rejection_reason, 
-				created_at, reviewed_at, reviewed_by, created_by
-			FROM expert_requests
-			WHERE status = ?
-			ORDER BY created_at DESC
-			LIMIT ? OFFSET ?
-		`
-		args = []interface{}{status, limit, offset}
-	} else {
-		// status is empty or "all" - return all requests
-		query = `
-			SELECT 
-				id, name, designation, affiliation, is_bahraini, 
-				is_available, role, employment_type, general_area, 
-				specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
-				is_published, suggested_specialized_areas, status, rejection_reason, 
-				created_at, reviewed_at, reviewed_by, created_by
-			FROM expert_requests
-			ORDER BY created_at DESC
-			LIMIT ? OFFSET ?
-		`
-		args = []interface{}{limit, offset}
+	query := `
+		SELECT 
+			id, name, designation, affiliation, is_bahraini, 
+			is_available, role, employment_type, general_area, 
+			specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
+			is_published, suggested_specialized_areas, status, rejection_reason, 
+			created_at, reviewed_at, reviewed_by, created_by, is_self_nominated, status_changed_at,
+			` + unreadColumn + `
+		FROM expert_requests` + unreadJoin
+	
+	now := time.Now()
+	whereClause, whereArgs := s.buildWhereClauseForExpertRequestFilters(filters, now)
+	args = append(args, whereArgs...)
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	
+	// Validate sorting against a whitelist of columns to prevent SQL injection
+	sortBy := "created_at"
+	sortOrder := "DESC"
+	allowedSortFields := map[string]string{
+		"id":                "id",
+		"name":              "name",
+		"affiliation":       "affiliation",
+		"role":              "role",
+		"status":            "status",
+		"general_area":      "general_area",
+		"is_bahraini":       "is_bahraini",
+		"created_at":        "created_at",
+		"reviewed_at":       "reviewed_at",
+		"status_changed_at": "status_changed_at",
+	}
+	if val, ok := filters["sort_by"].(string); ok {
+		if column, exists := allowedSortFields[val]; exists {
+			sortBy = column
+		}
+	}
+	if val, ok := filters["sort_order"].(string); ok && strings.ToUpper(val) == "ASC" {
+		sortOrder = "ASC"
 	}
 	
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ? OFFSET ?", sortBy, sortOrder, sortOrder)
+	args = append(args, limit, offset)
+	
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query expert requests: %w", err)
@@ -229,6 +400,7 @@ func (s *SQLiteStore) ListExpertRequests(status string, limit, offset int) ([]*d
 		var approvalDocPath sql.NullInt64
 		var rejectionReason sql.NullString
 		var suggestedAreasJSON sql.NullString
+		var statusChangedAt sql.NullTime
 		
 		err := rows.Scan(
 			&req.ID, &req.Name, &req.Designation, &req.Affiliation, 
@@ -236,7 +408,8 @@ func (s *SQLiteStore) ListExpertRequests(status string, limit, offset int) ([]*d
 			&req.EmploymentType, &req.GeneralArea, &specializedArea, 
 			&req.IsTrained, &cvPath, &approvalDocPath, &req.Phone, &req.Email, 
 			&req.IsPublished, &suggestedAreasJSON, &req.Status, &rejectionReason, 
-			&req.CreatedAt, &reviewedAt, &reviewedBy, &createdBy,
+			&req.CreatedAt, &reviewedAt, &reviewedBy, &createdBy, &req.IsSelfNominated, &statusChangedAt,
+			&req.UnreadComments,
 		)
 		
 		if err != nil {
@@ -267,6 +440,10 @@ func (s *SQLiteStore) ListExpertRequests(status string, limit, offset int) ([]*d
 		if rejectionReason.Valid {
 			req.RejectionReason = rejectionReason.String
 		}
+		if statusChangedAt.Valid {
+			req.StatusChangedAt = statusChangedAt.Time
+		}
+		req.ApplySLA(s.requestSLA, now)
 		
 		// Deserialize suggested areas from JSON
 		if suggestedAreasJSON.Valid {
@@ -297,161 +474,118 @@ func (s *SQLiteStore) ListExpertRequests(status string, limit, offset int) ([]*d
 	return requests, nil
 }
 
-// ListExpertRequestsByUser retrieves a list of expert requests created by a specific user
-func (s *SQLiteStore) ListExpertRequestsByUser(userID int64, status string, limit, offset int) ([]*domain.ExpertRequest, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	
-	var query string
-	var args []interface{}
-	
-	if status != "" && status != "all" {
-		query = `
-			SELECT 
-				id, name, designation, affiliation, is_bahraini, 
-				is_available, role, employment_type, general_area, 
-				specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
-				is_published, suggested_specialized_areas, status, rejection_reason, 
-				created_at, reviewed_at, reviewed_by, created_by
-			FROM expert_requests
-			WHERE created_by = ? AND status = ?
-			ORDER BY created_at DESC
-			LIMIT ? OFFSET ?
-		`
-		args = []interface{}{userID, status, limit, offset}
-	} else {
-		// status is empty or "all" - return all requests for this user
-		query = `
-			SELECT 
-				id, name, designation, affiliation, is_bahraini, 
-				is_available, role, employment_type, general_area, 
-				specialized_area, is_trained, cv_document_id, approval_document_id, phone, email, 
-				is_published, suggested_specialized_areas, status, rejection_reason, 
-				created_at, reviewed_at, reviewed_by, created_by
-			FROM expert_requests
-			WHERE created_by = ?
-			ORDER BY created_at DESC
-			LIMIT ? OFFSET ?
-		`
-		args = []interface{}{userID, limit, offset}
+// ListExpertRequestsByUser retrieves expert requests created by the given user, with the same filters as
+// ListExpertRequests. Each request carries its public comment threads and the user's unread count
+func (s *SQLiteStore) ListExpertRequestsByUser(userID int64, filters map[string]interface{}, limit, offset int) ([]*domain.ExpertRequest, error) {
+	userFilters := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		userFilters[k] = v
 	}
+	userFilters["created_by"] = userID
 	
-	rows, err := s.db.Query(query, args...)
+	requests, err := s.listExpertRequests(userFilters, limit, offset, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user expert requests: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 	
-	var requests []*domain.ExpertRequest
-	for rows.Next() {
-		var req domain.ExpertRequest
-		var reviewedAt sql.NullTime
-		var reviewedBy sql.NullInt64
-		var createdBy sql.NullInt64
-		var specializedArea sql.NullString
-		var cvPath sql.NullInt64
-		var approvalDocPath sql.NullInt64
-		var rejectionReason sql.NullString
-		var suggestedAreasJSON sql.NullString
-		
-		err := rows.Scan(
-			&req.ID, &req.Name, &req.Designation, &req.Affiliation, &req.IsBahraini,
-			&req.IsAvailable, &req.Role, &req.EmploymentType, &req.GeneralArea,
-			&specializedArea, &req.IsTrained, &cvPath, &approvalDocPath, &req.Phone, &req.Email,
-			&req.IsPublished, &suggestedAreasJSON, &req.Status, &rejectionReason,
-			&req.CreatedAt, &reviewedAt, &reviewedBy, &createdBy,
-		)
+	for _, req := range requests {
+		// Requesters see the public comment threads on their own requests
+		comments, err := s.ListExpertRequestComments(req.ID, false)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan expert request row: %w", err)
+			return nil, fmt.Errorf("failed to get comments for request %d: %w", req.ID, err)
 		}
-		
-		// Handle nullable fields
-		if reviewedAt.Valid {
-			req.ReviewedAt = reviewedAt.Time
-		}
-		if reviewedBy.Valid {
-			req.ReviewedBy = reviewedBy.Int64
-		}
-		if createdBy.Valid {
-			req.CreatedBy = createdBy.Int64
-		}
-		if specializedArea.Valid {
-			req.SpecializedArea = specializedArea.String
-		}
-		if cvPath.Valid {
-			cvDocID := cvPath.Int64
-			req.CVDocumentID = &cvDocID
-		}
-		if approvalDocPath.Valid {
-			approvalDocID := approvalDocPath.Int64
-			req.ApprovalDocumentID = &approvalDocID
-		}
-		if rejectionReason.Valid {
-			req.RejectionReason = rejectionReason.String
-		}
-		
-		// Deserialize suggested areas from JSON
-		if suggestedAreasJSON.Valid {
-			req.SuggestedSpecializedAreas = s.deserializeSuggestedAreas(suggestedAreasJSON.String)
-		}
-		
-		// Populate experience entries
-		experienceEntries, err := s.getExpertRequestExperienceEntries(req.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get experience entries for request %d: %w", req.ID, err)
-		}
-		req.ExperienceEntries = experienceEntries
-		
-		// Populate education entries
-		educationEntries, err := s.getExpertRequestEducationEntries(req.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get education entries for request %d: %w", req.ID, err)
-		}
-		req.EducationEntries = educationEntries
-		
-		requests = append(requests, &req)
+		req.Comments = comments
 	}
 	
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating user expert request rows: %w", err)
+	return requests, nil
+}
+
+// CountExpertRequests counts the expert requests matching the given filters (sorting is ignored)
+func (s *SQLiteStore) CountExpertRequests(filters map[string]interface{}) (int, error) {
+	query := "SELECT COUNT(*) FROM expert_requests"
+	
+	whereClause, args := s.buildWhereClauseForExpertRequestFilters(filters, time.Now())
+	if whereClause != "" {
+		query += " WHERE " + whereClause
 	}
 	
-	return requests, nil
+	var count int
+	if err := s.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expert requests: %w", err)
+	}
+	
+	return count, nil
 }
 
-// UpdateExpertRequestStatus updates the status of an expert request
-func (s *SQLiteStore) UpdateExpertRequestStatus(id int64, status, rejectionReason string, reviewedBy int64) error {
-	log := logger.Get()
-	log.Debug("Updating expert request status: ID=%d, status='%s'", id, status)
-	query := `
-		UPDATE expert_requests
-		SET status = ?, rejection_reason = ?, reviewed_at = ?, reviewed_by = ?
-		WHERE id = ?
-	`
+// buildWhereClauseForExpertRequestFilters builds the WHERE clause shared by ListExpertRequests and CountExpertRequests
+// SLA breaches are judged as of now
+func (s *SQLiteStore) buildWhereClauseForExpertRequestFilters(filters map[string]interface{}, now time.Time) (string, []interface{}) {
+	var conditions []string
+	var params []interface{}
+	
+	addCondition := func(condition string, conditionParams []interface{}) {
+		if condition != "" {
+			conditions = append(conditions, condition)
+			params = append(params, conditionParams...)
+		}
+	}
 	
-	now := time.Now()
+	// Multi-value filters with exact matching ("all" disables the status filter)
+	if val, ok := filters["status"].(string); ok && val != "" && val != "all" {
+		addCondition(buildInClause("status", parseMultiValue(val)))
+	}
+	if val, ok := filters["role"].(string); ok && val != "" {
+		addCondition(buildInClause("role", parseMultiValue(val)))
+	}
 	
-	// Execute the update
-	result, err := s.db.Exec(query, status, rejectionReason, now, reviewedBy, id)
-	if err != nil {
-		return fmt.Errorf("failed to update expert request status: %w", err)
+	// General area filter (integer values)
+	if val, ok := filters["general_area"].(string); ok && val != "" {
+		areaIDs := []string{}
+		for _, strVal := range parseMultiValue(val) {
+			if _, err := strconv.ParseInt(strVal, 10, 64); err == nil {
+				areaIDs = append(areaIDs, strVal)
+			}
+		}
+		addCondition(buildInClause("general_area", areaIDs))
 	}
 	
-	// Check if a row was affected
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	// Submitter
+	if val, ok := filters["created_by"].(int64); ok {
+		addCondition("created_by = ?", []interface{}{val})
 	}
 	
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
+	if val, ok := filters["is_bahraini"].(bool); ok {
+		addCondition("is_bahraini = ?", []interface{}{val})
 	}
 	
-	// Note: Expert creation for approvals is now handled by ApproveExpertRequestWithDocument method
-	// This method is only used for rejections and simple status updates
+	if val, ok := filters["is_self_nominated"].(bool); ok {
+		addCondition("is_self_nominated = ?", []interface{}{val})
+	}
 	
-	return nil
+	// Requests past (or within) the review target for their current status
+	if val, ok := filters["sla_breached"].(bool); ok {
+		condition, conditionParams := slaBreachCondition(s.requestSLA, now)
+		if !val {
+			condition = "NOT " + condition
+		}
+		addCondition(condition, conditionParams)
+	}
+	
+	// Submission date range, inclusive; compared on the stored date so the local day is used
+	if val, ok := filters["created_from"].(string); ok && val != "" {
+		addCondition("substr(created_at, 1, 10) >= ?", []interface{}{val})
+	}
+	if val, ok := filters["created_to"].(string); ok && val != "" {
+		addCondition("substr(created_at, 1, 10) <= ?", []interface{}{val})
+	}
+	
+	// Free-text search on name and affiliation
+	if val, ok := filters["search"].(string); ok && strings.TrimSpace(val) != "" {
+		term := "%" + strings.TrimSpace(val) + "%"
+		addCondition("(name LIKE ? OR affiliation LIKE ?)", []interface{}{term, term})
+	}
+	
+	return strings.Join(conditions, " AND "), params
 }
 
 // UpdateExpertRequest updates an expert request with new data
@@ -469,6 +603,58 @@ func (s *SQLiteStore) UpdateExpertRequest(req *domain.ExpertRequest) error {
 			return fmt.Errorf("cannot update expert request with empty core data")
 		}
 	}
+	result, err := s.updateExpertRequestRow(s.db, req, "")
+	
+	if err != nil {
+		return fmt.Errorf("failed to update expert request: %w", err)
+	}
+	
+	// Check if the update affected a row
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	
+	return nil
+}
+
+// UpdateAndResubmitExpertRequest stores the edited fields of a returned expert request and resubmits it
+// for review in one transaction. Nothing is saved unless the request is still in fromStatus
+func (s *SQLiteStore) UpdateAndResubmitExpertRequest(req *domain.ExpertRequest, fromStatus, note string, changedBy int64) error {
+	log := logger.Get()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The edit keeps the returned status; the transition below moves it on. If the request has left
+	// fromStatus the edit matches no row, and the transition reports why
+	edited := *req
+	edited.Status = fromStatus
+	if _, err := s.updateExpertRequestRow(tx, &edited, fromStatus); err != nil {
+		return fmt.Errorf("failed to update expert request: %w", err)
+	}
+	if err := transitionExpertRequestTx(tx, req.ID, fromStatus, domain.RequestStatusSubmitted, note, changedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expert request resubmission: %w", err)
+	}
+
+	log.Info("Expert request %d edited and resubmitted from '%s' by user %d", req.ID, fromStatus, changedBy)
+	return nil
+}
+
+// updateExpertRequestRow writes the editable fields of req to its row
+// A non-empty fromStatus restricts the update to a request still in that status
+func (s *SQLiteStore) updateExpertRequestRow(ex execer, req *domain.ExpertRequest, fromStatus string) (sql.Result, error) {
 	query := `
 		UPDATE expert_requests
 		SET name = ?, designation = ?, affiliation = ?, is_bahraini = ?,
@@ -479,7 +665,7 @@ func (s *SQLiteStore) UpdateExpertRequest(req *domain.ExpertRequest) error {
 			reviewed_at = ?, reviewed_by = ?, created_by = ?
 		WHERE id = ?
 	`
-	
+
 	// Handle nullable fields
 	var specializedArea, cvDocumentID, approvalDocumentID, rejectionReason interface{} = nil, nil, nil, nil
 	if req.SpecializedArea != "" {
@@ -494,60 +680,48 @@ func (s *SQLiteStore) UpdateExpertRequest(req *domain.ExpertRequest) error {
 	if req.RejectionReason != "" {
 		rejectionReason = req.RejectionReason
 	}
-	
+
 	var reviewedAt interface{} = nil
 	if !req.ReviewedAt.IsZero() {
 		reviewedAt = req.ReviewedAt
 	}
-	
+
 	var reviewedBy interface{} = nil
 	if req.ReviewedBy != 0 {
 		reviewedBy = req.ReviewedBy
 	}
-	
+
 	var createdBy interface{} = nil
 	if req.CreatedBy != 0 {
 		createdBy = req.CreatedBy
 	}
-	
-	// Serialize suggested areas to JSON
-	suggestedAreasJSON := s.serializeSuggestedAreas(req.SuggestedSpecializedAreas)
-	
-	// Execute update
-	result, err := s.db.Exec(
-		query,
+
+	args := []interface{}{
 		req.Name, req.Designation, req.Affiliation, req.IsBahraini,
 		req.IsAvailable, req.Role, req.EmploymentType,
 		req.GeneralArea, specializedArea, req.IsTrained,
 		cvDocumentID, approvalDocumentID, req.Phone, req.Email, req.IsPublished,
-		suggestedAreasJSON, req.Status, rejectionReason,
+		s.serializeSuggestedAreas(req.SuggestedSpecializedAreas), req.Status, rejectionReason,
 		reviewedAt, reviewedBy, createdBy,
 		req.ID,
-	)
-	
-	if err != nil {
-		return fmt.Errorf("failed to update expert request: %w", err)
-	}
-	
-	// Check if the update affected a row
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
+	if fromStatus != "" {
+		query += " AND status = ?"
+		args = append(args, fromStatus)
 	}
-	
-	return nil
+
+	return ex.Exec(query, args...)
 }
 
 
-// BatchApproveExpertRequestsWithFileMove approves multiple expert requests with file moving
-func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64, reviewedBy int64, documentService interface{}) ([]int64, []int64, map[int64]error) {
+// BatchApproveExpertRequestsWithFileMove records a reviewer's approval of multiple expert requests with file moving
+// Each request follows the approval quorum: experts are only created for requests whose quorum is met,
+// the others keep the vote and wait for further approvals
+func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64, reviewedBy int64, documentService interface{}) ([]*domain.ExpertRequestApprovalResult, map[int64]error) {
 	log := logger.Get()
 	log.Debug("Batch approving %d expert requests with file move", len(requestIDs))
 	
+	results := []*domain.ExpertRequestApprovalResult{}
 	successIDs := []int64{}
 	expertIDs := []int64{}
 	errors := make(map[int64]error)
@@ -561,7 +735,7 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 		for _, id := range requestIDs {
 			errors[id] = fmt.Errorf("failed to begin transaction: %w", err)
 		}
-		return successIDs, expertIDs, errors
+		return results, errors
 	}
 	defer tx.Rollback()
 	
@@ -574,15 +748,14 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 		
 		// Step 1: Get the request data
 		var req domain.ExpertRequest
-		var cvDocumentID, approvalDocumentID sql.NullInt64
+		var cvDocumentID, approvalDocumentID, createdBy sql.NullInt64 // Self-nominations have no creator
 		query := `
 			SELECT id, name, designation, affiliation, is_bahraini, 
 				is_available, role, employment_type, general_area, 
 				specialized_area, is_trained, cv_document_id, approval_document_id,
 				phone, email, is_published, status, created_by
 			FROM expert_requests
-			WHERE id = ? AND status = 'pending'
-		`
+			WHERE id = ? AND ` + approvableRequestCondition
 		
 		log.Debug("DEBUG: Executing query to get request data for ID: %d", requestID)
 		err = tx.QueryRow(query, requestID).Scan(
@@ -590,20 +763,22 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 			&req.IsBahraini, &req.IsAvailable, &req.Role, 
 			&req.EmploymentType, &req.GeneralArea, &req.SpecializedArea, 
 			&req.IsTrained, &cvDocumentID, &approvalDocumentID, &req.Phone, &req.Email, 
-			&req.IsPublished, &req.Status, &req.CreatedBy,
+			&req.IsPublished, &req.Status, &createdBy,
 		)
 		log.Debug("DEBUG: Request data scan completed - Name: '%s', Email: '%s', Phone: '%s', Designation: '%s', Affiliation: '%s'", 
 			req.Name, req.Email, req.Phone, req.Designation, req.Affiliation)
 		
 		if err != nil {
 			if err == sql.ErrNoRows {
-				errors[requestID] = fmt.Errorf("request not found or not pending")
+				errors[requestID] = fmt.Errorf("request not found or not awaiting review")
 			} else {
 				errors[requestID] = fmt.Errorf("failed to retrieve request data: %w", err)
 			}
 			continue
 		}
 		
+		req.CreatedBy = createdBy.Int64
+		
 		// Assign document IDs if valid
 		if cvDocumentID.Valid {
 			req.CVDocumentID = &cvDocumentID.Int64
@@ -612,6 +787,22 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 			req.ApprovalDocumentID = &approvalDocumentID.Int64
 		}
 		
+		// Record the vote; requests short of their quorum stay pending
+		approvals, quorumMet, err := s.castExpertRequestApprovalTx(tx, &req, reviewedBy)
+		if err != nil {
+			errors[requestID] = err
+			continue
+		}
+		outcome := &domain.ExpertRequestApprovalResult{
+			RequestID:         requestID,
+			Approvals:         approvals,
+			RequiredApprovals: s.approvalPolicy.RequiredApprovals,
+		}
+		if !quorumMet {
+			results = append(results, outcome)
+			continue
+		}
+		
 		// Step 2: Create expert record
 		log.Debug("DEBUG: Creating expert record from request data for ID: %d", requestID)
 		expert := &domain.Expert{
@@ -677,6 +868,14 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 			errors[requestID] = fmt.Errorf("failed to copy education entries: %w", err)
 			continue
 		}
+
+		// Carry declared training over as a legacy attendance record
+		if req.IsTrained {
+			if err = s.recordLegacyTrainingTx(tx, expertID); err != nil {
+				errors[requestID] = err
+				continue
+			}
+		}
 		
 		// Step 4: Update request status
 		log.Debug("DEBUG: Updating request status to approved for ID: %d", requestID)
@@ -684,28 +883,35 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 			UPDATE expert_requests
 			SET status = ?, reviewed_at = ?, reviewed_by = ?
 			WHERE id = ?
-		`, "approved", now, reviewedBy, requestID)
+		`, domain.RequestStatusApproved, now, reviewedBy, requestID)
 		log.Debug("DEBUG: Request status update completed with error: %v", err)
 		if err != nil {
 			errors[requestID] = fmt.Errorf("failed to update request status: %w", err)
 			continue
 		}
+		if err = recordExpertRequestTransition(tx, requestID, req.Status, domain.RequestStatusApproved, "", reviewedBy); err != nil {
+			errors[requestID] = err
+			continue
+		}
 		
 		// This request was successful
+		outcome.Approved = true
+		outcome.ExpertID = expertID
+		results = append(results, outcome)
 		successIDs = append(successIDs, requestID)
 		expertIDs = append(expertIDs, expertID)
 	}
 	
-	// Commit transaction if we have any successes
-	if len(successIDs) > 0 {
+	// Commit transaction if we have any successes or recorded votes
+	if len(results) > 0 {
 		log.Debug("DEBUG: Committing transaction for %d successful requests", len(successIDs))
 		if err := tx.Commit(); err != nil {
 			log.Debug("DEBUG: Transaction commit failed: %v", err)
 			// If commit fails, all operations fail
-			for _, id := range successIDs {
-				errors[id] = fmt.Errorf("failed to commit transaction: %w", err)
+			for _, outcome := range results {
+				errors[outcome.RequestID] = fmt.Errorf("failed to commit transaction: %w", err)
 			}
-			return []int64{}, []int64{}, errors
+			return []*domain.ExpertRequestApprovalResult{}, errors
 		}
 		log.Debug("DEBUG: Transaction committed successfully")
 		
@@ -739,11 +945,11 @@ func (s *SQLiteStore) BatchApproveExpertRequestsWithFileMove(requestIDs []int64,
 		log.Debug("Document transfer completed for %d successful approvals", len(successIDs))
 	} else {
 		// No successful operations
-		return []int64{}, []int64{}, errors
+		return []*domain.ExpertRequestApprovalResult{}, errors
 	}
 	
-	log.Debug("Batch approval completed: %d successes, %d errors", len(successIDs), len(errors))
-	return successIDs, expertIDs, errors
+	log.Debug("Batch approval completed: %d approved, %d pending, %d errors", len(successIDs), len(results)-len(successIDs), len(errors))
+	return results, errors
 }
 
 // UpdateExpertsApprovalPath updates the approval document path for multiple experts
@@ -803,14 +1009,15 @@ func (s *SQLiteStore) deserializeSuggestedAreas(jsonData string) []string {
 	return areas
 }
 
-// ApproveExpertRequestWithDocument approves a single expert request and creates expert with proper approval document naming
-func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int64, documentService interface{}) (int64, error) {
+// ApproveExpertRequestWithDocument records a reviewer's approval of a single expert request
+// The expert is created (with proper approval document naming) only once the approval quorum is met
+func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int64, documentService interface{}) (*domain.ExpertRequestApprovalResult, error) {
 	log := logger.Get()
 	
 	// Begin transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 	
@@ -818,31 +1025,32 @@ func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int
 	
 	// Step 1: Get the request data
 	var req domain.ExpertRequest
-	var cvDocumentID, approvalDocumentID sql.NullInt64
+	var cvDocumentID, approvalDocumentID, createdBy sql.NullInt64 // Self-nominations have no creator
 	query := `
 		SELECT id, name, designation, affiliation, is_bahraini, 
 			is_available, role, employment_type, general_area, 
 			specialized_area, is_trained, cv_document_id, approval_document_id, 
 			phone, email, is_published, status, created_by
 		FROM expert_requests
-		WHERE id = ? AND status = 'pending'
-	`
+		WHERE id = ? AND ` + approvableRequestCondition
 	
 	err = tx.QueryRow(query, requestID).Scan(
 		&req.ID, &req.Name, &req.Designation, &req.Affiliation, 
 		&req.IsBahraini, &req.IsAvailable, &req.Role, 
 		&req.EmploymentType, &req.GeneralArea, &req.SpecializedArea, 
 		&req.IsTrained, &cvDocumentID, &approvalDocumentID, 
-		&req.Phone, &req.Email, &req.IsPublished, &req.Status, &req.CreatedBy,
+		&req.Phone, &req.Email, &req.IsPublished, &req.Status, &createdBy,
 	)
 	
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("request not found or not pending")
+			return nil, fmt.Errorf("request not found or not awaiting review")
 		}
-		return 0, fmt.Errorf("failed to retrieve request data: %w", err)
+		return nil, fmt.Errorf("failed to retrieve request data: %w", err)
 	}
 	
+	req.CreatedBy = createdBy.Int64
+	
 	// Assign document IDs if valid
 	if cvDocumentID.Valid {
 		req.CVDocumentID = &cvDocumentID.Int64
@@ -851,6 +1059,24 @@ func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int
 		req.ApprovalDocumentID = &approvalDocumentID.Int64
 	}
 	
+	// Record the vote; the expert is only created once enough reviewers have approved
+	approvals, quorumMet, err := s.castExpertRequestApprovalTx(tx, &req, reviewedBy)
+	if err != nil {
+		return nil, err
+	}
+	outcome := &domain.ExpertRequestApprovalResult{
+		RequestID:         requestID,
+		Approvals:         approvals,
+		RequiredApprovals: s.approvalPolicy.RequiredApprovals,
+	}
+	if !quorumMet {
+		if err = tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		log.Info("Expert request %d has %d of %d approvals", requestID, approvals, outcome.RequiredApprovals)
+		return outcome, nil
+	}
+	
 	// Step 2: Create expert record
 	expert := &domain.Expert{
 		Name:            req.Name,
@@ -887,24 +1113,33 @@ func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int
 		expert.CreatedAt, expert.UpdatedAt, expert.OriginalRequestID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert expert: %w", err)
+		return nil, fmt.Errorf("failed to insert expert: %w", err)
 	}
 	
 	expertIDResult, err := result.LastInsertId()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get expert ID: %w", err)
+		return nil, fmt.Errorf("failed to get expert ID: %w", err)
 	}
 	expertID := expertIDResult
+	outcome.Approved = true
+	outcome.ExpertID = expertID
 	
 	// Step 3: Copy experience and education entries
 	err = s.copyExperienceEntries(tx, requestID, expertID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to copy experience entries: %w", err)
+		return nil, fmt.Errorf("failed to copy experience entries: %w", err)
 	}
 
 	err = s.copyEducationEntries(tx, requestID, expertID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to copy education entries: %w", err)
+		return nil, fmt.Errorf("failed to copy education entries: %w", err)
+	}
+
+	// Carry declared training over as a legacy attendance record
+	if req.IsTrained {
+		if err = s.recordLegacyTrainingTx(tx, expertID); err != nil {
+			return nil, err
+		}
 	}
 	
 	// Step 4: Update request status
@@ -912,14 +1147,17 @@ func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int
 		UPDATE expert_requests
 		SET status = ?, reviewed_at = ?, reviewed_by = ?
 		WHERE id = ?
-	`, "approved", now, reviewedBy, requestID)
+	`, domain.RequestStatusApproved, now, reviewedBy, requestID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to update request status: %w", err)
+		return nil, fmt.Errorf("failed to update request status: %w", err)
+	}
+	if err = recordExpertRequestTransition(tx, requestID, req.Status, domain.RequestStatusApproved, "", reviewedBy); err != nil {
+		return nil, err
 	}
 	
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	
 	// Step 5: Handle document movements (outside transaction)
@@ -954,7 +1192,7 @@ func (s *SQLiteStore) ApproveExpertRequestWithDocument(requestID, reviewedBy int
 		}
 	}
 	
-	return expertID, nil
+	return outcome, nil
 }
 
 // getExpertRequestExperienceEntries retrieves experience entries for an expert request
@@ -1032,29 +1270,23 @@ func (s *SQLiteStore) moveCVFileToExpertDirectory(oldPath string, expertID int64
 	log := logger.Get()
 	
 	// Check if the old file exists
-	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+	if _, err := s.blobs.Stat(oldPath); err != nil {
 		log.Warn("CV file does not exist at path: %s", oldPath)
-		return fmt.Errorf("CV file not found: %s", oldPath)
+		return fmt.Errorf("CV file not found: %s: %w", oldPath, err)
 	}
 	
 	// Extract file extension from the original path
-	ext := filepath.Ext(oldPath)
+	ext := path.Ext(oldPath)
 	
 	// Generate new filename with expert ID and timestamp
 	timestamp := time.Now().Format("20060102_150405")
 	newFileName := fmt.Sprintf("expert_%d_%s%s", expertID, timestamp, ext)
 	
-	// Construct new path in cvs directory
-	newPath := filepath.Join("data", "documents", "cvs", newFileName)
-	
-	// Ensure the cvs directory exists
-	cvsDir := filepath.Dir(newPath)
-	if err := os.MkdirAll(cvsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cvs directory: %w", err)
-	}
+	// Construct new key in cvs directory
+	newPath := path.Join("cvs", newFileName)
 	
 	// Move the file
-	if err := os.Rename(oldPath, newPath); err != nil {
+	if err := s.blobs.Move(oldPath, newPath); err != nil {
 		return fmt.Errorf("failed to move CV file from %s to %s: %w", oldPath, newPath, err)
 	}
 	
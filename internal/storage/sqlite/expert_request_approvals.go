@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// SetExpertRequestApprovalPolicy sets the quorum rules used when approving expert requests
+func (s *SQLiteStore) SetExpertRequestApprovalPolicy(policy domain.ExpertRequestApprovalPolicy) {
+	if policy.RequiredApprovals < 1 {
+		policy.RequiredApprovals = 1
+	}
+	s.approvalPolicy = policy
+}
+
+// ExpertRequestApprovalPolicy returns the quorum rules used when approving expert requests
+func (s *SQLiteStore) ExpertRequestApprovalPolicy() domain.ExpertRequestApprovalPolicy {
+	return s.approvalPolicy
+}
+
+// castExpertRequestApprovalTx records a reviewer's approval vote on a request awaiting review
+// It returns the number of standing votes and whether they meet the quorum. While the quorum
+// is not met, a submitted request is moved under review so the next reviewer can pick it up
+func (s *SQLiteStore) castExpertRequestApprovalTx(tx *sql.Tx, req *domain.ExpertRequest, approverID int64) (int, bool, error) {
+	if approverID == req.CreatedBy {
+		return 0, false, fmt.Errorf("%w: requesters cannot approve their own requests", domain.ErrValidation)
+	}
+
+	var approverRole string
+	if err := tx.QueryRow("SELECT role FROM users WHERE id = ?", approverID).Scan(&approverRole); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, domain.ErrNotFound
+		}
+		return 0, false, fmt.Errorf("failed to get approver role: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		SELECT approver_id, approver_role FROM expert_request_approvals
+		WHERE request_id = ? AND invalidated_at IS NULL
+	`, req.ID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := 0
+	for rows.Next() {
+		var voterID int64
+		var voterRole string
+		if err := rows.Scan(&voterID, &voterRole); err != nil {
+			return 0, false, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		if voterID == approverID {
+			return 0, false, fmt.Errorf("%w: you have already approved this request", domain.ErrValidation)
+		}
+		if s.approvalPolicy.RequireDistinctRoles && voterRole == approverRole {
+			return 0, false, fmt.Errorf("%w: this request already has an approval from the %s role; a reviewer with another role must approve it", domain.ErrValidation, approverRole)
+		}
+		approvals++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, false, fmt.Errorf("error iterating approvals: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO expert_request_approvals (request_id, approver_id, approver_role, created_at)
+		VALUES (?, ?, ?, ?)
+	`, req.ID, approverID, approverRole, time.Now())
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to record approval: %w", err)
+	}
+	approvals++
+
+	required := s.approvalPolicy.RequiredApprovals
+	if approvals >= required {
+		return approvals, true, nil
+	}
+
+	if req.Status == domain.RequestStatusSubmitted {
+		_, err = tx.Exec("UPDATE expert_requests SET status = ?, reviewed_by = ? WHERE id = ?",
+			domain.RequestStatusUnderReview, approverID, req.ID)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to update request status: %w", err)
+		}
+		note := fmt.Sprintf("Approval %d of %d recorded", approvals, required)
+		if err := recordExpertRequestTransition(tx, req.ID, req.Status, domain.RequestStatusUnderReview, note, approverID); err != nil {
+			return 0, false, err
+		}
+		req.Status = domain.RequestStatusUnderReview
+	}
+
+	return approvals, false, nil
+}
+
+// invalidateExpertRequestApprovalsTx withdraws the standing votes on a request that was sent back,
+// so the corrected request needs a fresh quorum
+func invalidateExpertRequestApprovalsTx(tx *sql.Tx, requestID int64) error {
+	_, err := tx.Exec(`
+		UPDATE expert_request_approvals SET invalidated_at = ?
+		WHERE request_id = ? AND invalidated_at IS NULL
+	`, time.Now(), requestID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate approvals: %w", err)
+	}
+	return nil
+}
+
+// ListExpertRequestApprovals retrieves every approval vote cast on an expert request, oldest first
+// Invalidated votes are included and carry InvalidatedAt
+func (s *SQLiteStore) ListExpertRequestApprovals(requestID int64) ([]*domain.ExpertRequestApproval, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.request_id, a.approver_id, COALESCE(u.name, ''), a.approver_role, a.created_at, a.invalidated_at
+		FROM expert_request_approvals a
+		LEFT JOIN users u ON u.id = a.approver_id
+		WHERE a.request_id = ?
+		ORDER BY a.created_at ASC, a.id ASC
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []*domain.ExpertRequestApproval{}
+	for rows.Next() {
+		var approval domain.ExpertRequestApproval
+		var invalidatedAt sql.NullTime
+		err := rows.Scan(&approval.ID, &approval.RequestID, &approval.ApproverID, &approval.ApproverName,
+			&approval.ApproverRole, &approval.CreatedAt, &invalidatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		if invalidatedAt.Valid {
+			approval.InvalidatedAt = &invalidatedAt.Time
+		}
+		approvals = append(approvals, &approval)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating approvals: %w", err)
+	}
+
+	return approvals, nil
+}
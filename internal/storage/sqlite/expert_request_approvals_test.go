@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestApprovalQuorum(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   domain.ExpertRequestApprovalPolicy
+		voters   []string // Roles of the reviewers voting in turn; "owner" is the requester
+		rejected []bool   // Whether each vote is refused
+		approved bool     // Whether the request ends approved
+	}{
+		{"single approval", domain.ExpertRequestApprovalPolicy{}, []string{"admin"}, []bool{false}, true},
+		{"quorum of two", domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2}, []string{"admin", "admin"}, []bool{false, false}, true},
+		{"one of two", domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2}, []string{"admin"}, []bool{false}, false},
+		{"requester cannot vote", domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2}, []string{"owner", "admin"}, []bool{true, false}, false},
+		{"same role twice", domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2, RequireDistinctRoles: true},
+			[]string{"admin", "admin", "super_user"}, []bool{false, true, false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			s.SetExpertRequestApprovalPolicy(tt.policy)
+			owner := createTestUser(t, s, "admin")
+			id := createTestExpertRequest(t, s, newTestExpertRequest("Quorum", createTestArea(t, s, "Engineering"), owner))
+
+			var result *domain.ExpertRequestApprovalResult
+			for i, role := range tt.voters {
+				voter := owner
+				if role != "owner" {
+					voter = createTestUser(t, s, role)
+				}
+				outcome, err := s.ApproveExpertRequestWithDocument(id, voter, nil)
+				if tt.rejected[i] {
+					if !errors.Is(err, domain.ErrValidation) {
+						t.Fatalf("vote %d by %s: error = %v, want a validation error", i+1, role, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("vote %d by %s: %v", i+1, role, err)
+				}
+				result = outcome
+			}
+
+			if result.Approved != tt.approved || (result.ExpertID != 0) != tt.approved {
+				t.Errorf("result = %+v, want approved %v", result, tt.approved)
+			}
+			want := domain.RequestStatusApproved
+			if !tt.approved {
+				want = domain.RequestStatusUnderReview
+			}
+			if status := requestStatus(t, s, id); status != want {
+				t.Errorf("status = %s, want %s", status, want)
+			}
+		})
+	}
+}
+
+func TestApprovalRepeatedVote(t *testing.T) {
+	s := newTestStore(t)
+	s.SetExpertRequestApprovalPolicy(domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2})
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	id := createTestExpertRequest(t, s, newTestExpertRequest("Repeat", createTestArea(t, s, "Engineering"), owner))
+
+	if _, err := s.ApproveExpertRequestWithDocument(id, reviewer, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ApproveExpertRequestWithDocument(id, reviewer, nil); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("second vote by the same reviewer: error = %v, want a validation error", err)
+	}
+	if approvals, _ := s.ListExpertRequestApprovals(id); len(approvals) != 1 {
+		t.Errorf("%d approvals recorded, want 1", len(approvals))
+	}
+}
+
+func TestApprovalsInvalidatedWhenSentBack(t *testing.T) {
+	s := newTestStore(t)
+	s.SetExpertRequestApprovalPolicy(domain.ExpertRequestApprovalPolicy{RequiredApprovals: 2})
+	owner := createTestUser(t, s, "user")
+	first := createTestUser(t, s, "admin")
+	second := createTestUser(t, s, "admin")
+	id := createTestExpertRequest(t, s, newTestExpertRequest("Sent Back", createTestArea(t, s, "Engineering"), owner))
+
+	if _, err := s.ApproveExpertRequestWithDocument(id, first, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TransitionExpertRequest(id, domain.RequestStatusUnderReview, domain.RequestStatusNeedsChanges, "Fix the CV", second); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TransitionExpertRequest(id, domain.RequestStatusNeedsChanges, domain.RequestStatusSubmitted, "", owner); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first vote was for the request before the changes, so one more is not enough
+	result, err := s.ApproveExpertRequestWithDocument(id, second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Approved || result.Approvals != 1 {
+		t.Errorf("result = %+v, want 1 standing approval", result)
+	}
+
+	approvals, err := s.ListExpertRequestApprovals(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 2 || approvals[0].InvalidatedAt == nil || approvals[1].InvalidatedAt != nil {
+		t.Fatalf("approvals = %+v, want the first invalidated and the second standing", approvals)
+	}
+
+	// The first reviewer may approve the corrected request again
+	if result, err = s.ApproveExpertRequestWithDocument(id, first, nil); err != nil || !result.Approved {
+		t.Errorf("re-approval = %+v, %v, want the request approved", result, err)
+	}
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// BatchUpdateExpertRequests applies a reject, withdraw or reassign action to several expert requests
+// Each request is checked against the workflow for the acting user, as for a single transition.
+// Returns the IDs that were updated and an error for each request that was not; in all-or-nothing
+// mode a single failure rolls back the whole batch and every request is reported as failed
+func (s *SQLiteStore) BatchUpdateExpertRequests(batch *domain.ExpertRequestBatch, userID int64, isReviewer bool) ([]int64, map[int64]error) {
+	log := logger.Get()
+
+	succeeded := []int64{}
+	errors := make(map[int64]error)
+
+	if batch.AllOrNothing {
+		tx, err := s.db.Begin()
+		if err != nil {
+			for _, item := range batch.Items {
+				errors[item.ID] = fmt.Errorf("failed to begin transaction: %w", err)
+			}
+			return succeeded, errors
+		}
+		defer tx.Rollback()
+
+		for _, item := range batch.Items {
+			if err := applyExpertRequestBatchItemTx(tx, batch, item, userID, isReviewer); err != nil {
+				errors[item.ID] = err
+				continue
+			}
+			succeeded = append(succeeded, item.ID)
+		}
+
+		if len(errors) == 0 {
+			if err := tx.Commit(); err != nil {
+				errors = make(map[int64]error)
+				for _, item := range batch.Items {
+					errors[item.ID] = fmt.Errorf("failed to commit batch: %w", err)
+				}
+				return []int64{}, errors
+			}
+			log.Info("Batch %s applied to %d expert requests by user %d", batch.Action, len(succeeded), userID)
+			return succeeded, errors
+		}
+
+		// Nothing was applied; report the requests that would have succeeded as rolled back
+		for _, id := range succeeded {
+			errors[id] = fmt.Errorf("not applied: the batch was rolled back because other requests failed")
+		}
+		log.Warn("Batch %s by user %d rolled back: %d of %d requests failed", batch.Action, userID, len(batch.Items)-len(succeeded), len(batch.Items))
+		return []int64{}, errors
+	}
+
+	for _, item := range batch.Items {
+		err := func() error {
+			tx, err := s.db.Begin()
+			if err != nil {
+				return fmt.Errorf("failed to begin transaction: %w", err)
+			}
+			defer tx.Rollback()
+
+			if err := applyExpertRequestBatchItemTx(tx, batch, item, userID, isReviewer); err != nil {
+				return err
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("failed to commit change: %w", err)
+			}
+			return nil
+		}()
+		if err != nil {
+			errors[item.ID] = err
+			continue
+		}
+		succeeded = append(succeeded, item.ID)
+	}
+
+	log.Info("Batch %s by user %d: %d of %d expert requests updated", batch.Action, userID, len(succeeded), len(batch.Items))
+	return succeeded, errors
+}
+
+// applyExpertRequestBatchItemTx checks and applies a batch action to one request within the given transaction
+func applyExpertRequestBatchItemTx(tx *sql.Tx, batch *domain.ExpertRequestBatch, item domain.ExpertRequestBatchItem, userID int64, isReviewer bool) error {
+	var status string
+	var createdBy, reviewedBy sql.NullInt64
+	err := tx.QueryRow("SELECT status, created_by, reviewed_by FROM expert_requests WHERE id = ?", item.ID).
+		Scan(&status, &createdBy, &reviewedBy)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get expert request: %w", err)
+	}
+	isOwner := createdBy.Valid && createdBy.Int64 == userID
+
+	switch batch.Action {
+	case domain.ExpertRequestBatchReject, domain.ExpertRequestBatchWithdraw:
+		toStatus := domain.RequestStatusRejected
+		if batch.Action == domain.ExpertRequestBatchWithdraw {
+			toStatus = domain.RequestStatusWithdrawn
+		}
+		if err := domain.ValidateExpertRequestTransition(status, toStatus, isOwner, isReviewer); err != nil {
+			return err
+		}
+		return transitionExpertRequestTx(tx, item.ID, status, toStatus, item.Reason, userID)
+
+	case domain.ExpertRequestBatchReassign:
+		if !isReviewer {
+			return domain.ErrForbidden
+		}
+		if status != domain.RequestStatusSubmitted && status != domain.RequestStatusUnderReview {
+			return fmt.Errorf("%w: only requests awaiting review can be reassigned, this one is '%s'", domain.ErrValidation, status)
+		}
+		_, err := tx.Exec("UPDATE expert_requests SET reviewed_by = ? WHERE id = ?", batch.ReviewerID, item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reassign expert request: %w", err)
+		}
+		return recordExpertRequestReassignment(tx, item.ID, status, reviewedBy.Int64, batch.ReviewerID, item.Reason, userID)
+
+	default:
+		return fmt.Errorf("%w: unknown batch action '%s'", domain.ErrValidation, batch.Action)
+	}
+}
+
+// recordExpertRequestReassignment adds a history entry for a request handed from one reviewer to another
+// The status is unchanged, so the request's aging clock keeps running
+func recordExpertRequestReassignment(ex execer, requestID int64, status string, fromReviewer, toReviewer int64, reason string, changedBy int64) error {
+	from := "unassigned"
+	if fromReviewer != 0 {
+		from = fmt.Sprintf("reviewer %d", fromReviewer)
+	}
+	note := fmt.Sprintf("Reassigned from %s to reviewer %d", from, toReviewer)
+	if reason != "" {
+		note += ": " + reason
+	}
+	return insertExpertRequestHistory(ex, requestID, status, status, note, changedBy, time.Now())
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+// requestStatusChangedAt returns when an expert request's aging clock last started, as stored
+func requestStatusChangedAt(t *testing.T, s *SQLiteStore, id int64) string {
+	t.Helper()
+	var changedAt string
+	if err := s.db.QueryRow("SELECT status_changed_at FROM expert_requests WHERE id = ?", id).Scan(&changedAt); err != nil {
+		t.Fatalf("status time of request %d: %v", id, err)
+	}
+	return changedAt
+}
+
+func TestBatchReassignRecordsHistory(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	admin := createTestUser(t, s, "admin")
+	first := createTestUser(t, s, "admin")
+	second := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	unassigned := createTestExpertRequest(t, s, newTestExpertRequest("Unassigned", area, owner))
+	picked := createTestExpertRequest(t, s, newTestExpertRequest("Picked up", area, owner))
+	if err := s.TransitionExpertRequest(picked, domain.RequestStatusSubmitted, domain.RequestStatusUnderReview, "", first); err != nil {
+		t.Fatal(err)
+	}
+	agingSince := requestStatusChangedAt(t, s, picked)
+
+	batch := &domain.ExpertRequestBatch{
+		Action:     domain.ExpertRequestBatchReassign,
+		Items:      []domain.ExpertRequestBatchItem{{ID: unassigned}, {ID: picked, Reason: "On leave"}},
+		ReviewerID: second,
+	}
+	succeeded, errs := s.BatchUpdateExpertRequests(batch, admin, true)
+	if len(errs) != 0 || len(succeeded) != 2 {
+		t.Fatalf("reassigned %v with errors %v", succeeded, errs)
+	}
+
+	tests := []struct {
+		id       int64
+		status   string
+		wantNote string
+	}{
+		{unassigned, domain.RequestStatusSubmitted, fmt.Sprintf("Reassigned from unassigned to reviewer %d", second)},
+		{picked, domain.RequestStatusUnderReview, fmt.Sprintf("Reassigned from reviewer %d to reviewer %d: On leave", first, second)},
+	}
+	for _, tt := range tests {
+		req, err := s.GetExpertRequest(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.ReviewedBy != second || req.Status != tt.status {
+			t.Errorf("request %d reviewed by %d in %s, want %d in %s", tt.id, req.ReviewedBy, req.Status, second, tt.status)
+		}
+		history, err := s.ListExpertRequestHistory(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := history[len(history)-1]
+		if last.FromStatus != tt.status || last.ToStatus != tt.status || last.ChangedBy != admin || last.Note != tt.wantNote {
+			t.Errorf("request %d history entry = %+v, want %q by user %d", tt.id, last, tt.wantNote, admin)
+		}
+	}
+	if got := requestStatusChangedAt(t, s, picked); got != agingSince {
+		t.Errorf("reassignment restarted the aging clock: %s, was %s", got, agingSince)
+	}
+}
+
+func TestBatchUpdateExpertRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        string
+		allOrNothing  bool
+		asOwner       bool
+		wantSucceeded int
+		wantStatus    string // Status of the open requests afterwards
+	}{
+		{"reject", domain.ExpertRequestBatchReject, false, false, 2, domain.RequestStatusRejected},
+		{"withdraw", domain.ExpertRequestBatchWithdraw, false, true, 2, domain.RequestStatusWithdrawn},
+		{"reject all or nothing", domain.ExpertRequestBatchReject, true, false, 0, domain.RequestStatusSubmitted},
+		{"reject by the owner", domain.ExpertRequestBatchReject, false, true, 0, domain.RequestStatusSubmitted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			owner := createTestUser(t, s, "user")
+			admin := createTestUser(t, s, "admin")
+			area := createTestArea(t, s, "Engineering")
+			open := []int64{
+				createTestExpertRequest(t, s, newTestExpertRequest("First", area, owner)),
+				createTestExpertRequest(t, s, newTestExpertRequest("Second", area, owner)),
+			}
+			// A withdrawn request can't be acted on, so it fails in every batch
+			closed := createTestExpertRequest(t, s, newTestExpertRequest("Closed", area, owner))
+			if err := s.TransitionExpertRequest(closed, domain.RequestStatusSubmitted, domain.RequestStatusWithdrawn, "", owner); err != nil {
+				t.Fatal(err)
+			}
+
+			batch := &domain.ExpertRequestBatch{Action: tt.action, AllOrNothing: tt.allOrNothing}
+			for _, id := range append(open, closed) {
+				batch.Items = append(batch.Items, domain.ExpertRequestBatchItem{ID: id, Reason: "Out of scope"})
+			}
+			actor, isReviewer := admin, true
+			if tt.asOwner {
+				actor, isReviewer = owner, false
+			}
+			succeeded, errs := s.BatchUpdateExpertRequests(batch, actor, isReviewer)
+
+			if len(succeeded) != tt.wantSucceeded {
+				t.Errorf("%d requests updated, want %d", len(succeeded), tt.wantSucceeded)
+			}
+			if len(errs) != len(batch.Items)-tt.wantSucceeded {
+				t.Errorf("%d errors, want %d: %v", len(errs), len(batch.Items)-tt.wantSucceeded, errs)
+			}
+			if !errors.Is(errs[closed], domain.ErrValidation) && !errors.Is(errs[closed], domain.ErrForbidden) {
+				t.Errorf("closed request error = %v, want it refused", errs[closed])
+			}
+			for _, id := range open {
+				if status := requestStatus(t, s, id); status != tt.wantStatus {
+					t.Errorf("request %d is %s, want %s", id, status, tt.wantStatus)
+				}
+			}
+		})
+	}
+}
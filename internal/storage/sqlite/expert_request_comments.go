@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// CreateExpertRequestComment adds a comment to an expert request
+// Replies must belong to the same request, and replies to internal notes are internal too
+func (s *SQLiteStore) CreateExpertRequestComment(comment *domain.ExpertRequestComment) (int64, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM expert_requests WHERE id = ?)", comment.RequestID).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("failed to check expert request: %w", err)
+	}
+	if !exists {
+		return 0, domain.ErrNotFound
+	}
+
+	if comment.ParentID != nil {
+		var parentRequestID int64
+		var parentInternal bool
+		err := s.db.QueryRow("SELECT request_id, is_internal FROM expert_request_comments WHERE id = ?", *comment.ParentID).
+			Scan(&parentRequestID, &parentInternal)
+		if err == sql.ErrNoRows || (err == nil && parentRequestID != comment.RequestID) {
+			return 0, fmt.Errorf("%w: comment %d is not on this request", domain.ErrValidation, *comment.ParentID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to check parent comment: %w", err)
+		}
+		if parentInternal {
+			comment.IsInternal = true
+		}
+	}
+
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO expert_request_comments (request_id, parent_id, author_id, body, is_internal, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, comment.RequestID, comment.ParentID, comment.AuthorID, comment.Body, comment.IsInternal, comment.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get comment ID: %w", err)
+	}
+	comment.ID = id
+
+	// Writing a comment implies the author has seen everything before it
+	if err := s.MarkExpertRequestCommentsRead(comment.RequestID, comment.AuthorID); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ListExpertRequestComments retrieves the comment threads of an expert request, oldest first
+// Internal notes (and replies to them) are only included when includeInternal is true
+func (s *SQLiteStore) ListExpertRequestComments(requestID int64, includeInternal bool) ([]*domain.ExpertRequestComment, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.request_id, c.parent_id, COALESCE(c.author_id, 0), COALESCE(u.name, ''), COALESCE(u.role, ''),
+		       c.body, c.is_internal, c.created_at
+		FROM expert_request_comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.request_id = ? AND (c.is_internal = 0 OR ?)
+		ORDER BY c.created_at ASC, c.id ASC
+	`, requestID, includeInternal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*domain.ExpertRequestComment
+	for rows.Next() {
+		var comment domain.ExpertRequestComment
+		var parentID sql.NullInt64
+		err := rows.Scan(&comment.ID, &comment.RequestID, &parentID, &comment.AuthorID, &comment.AuthorName,
+			&comment.AuthorRole, &comment.Body, &comment.IsInternal, &comment.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		if parentID.Valid {
+			comment.ParentID = &parentID.Int64
+		}
+		comments = append(comments, &comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return buildCommentThreads(comments), nil
+}
+
+// buildCommentThreads nests replies under their parent comments, keeping the input order
+func buildCommentThreads(comments []*domain.ExpertRequestComment) []*domain.ExpertRequestComment {
+	byID := make(map[int64]*domain.ExpertRequestComment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	threads := []*domain.ExpertRequestComment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := byID[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+		threads = append(threads, comment)
+	}
+
+	return threads
+}
+
+// MarkExpertRequestCommentsRead records that the user has read every comment on the request so far
+func (s *SQLiteStore) MarkExpertRequestCommentsRead(requestID, userID int64) error {
+	_, err := s.db.Exec(`
+		INSERT INTO expert_request_comment_reads (request_id, user_id, last_read_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (request_id, user_id) DO UPDATE SET last_read_at = excluded.last_read_at
+	`, requestID, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark comments as read: %w", err)
+	}
+	return nil
+}
+
+// CountUnreadExpertRequestComments counts comments by other users posted since the user last read the request
+func (s *SQLiteStore) CountUnreadExpertRequestComments(requestID, userID int64, includeInternal bool) (int, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM expert_request_comments c
+		LEFT JOIN expert_request_comment_reads r ON r.request_id = c.request_id AND r.user_id = ?
+		WHERE c.request_id = ?
+		  AND COALESCE(c.author_id, 0) != ?
+		  AND (c.is_internal = 0 OR ?)
+		  AND (r.last_read_at IS NULL OR c.created_at > r.last_read_at)
+	`, userID, requestID, userID, includeInternal).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread comments: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+func TestListExpertRequestsByUserUnreadComments(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	comment := func(requestID, author int64, internal bool, at time.Time) {
+		t.Helper()
+		_, err := s.CreateExpertRequestComment(&domain.ExpertRequestComment{
+			RequestID: requestID, AuthorID: author, Body: "Please check", IsInternal: internal, CreatedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("comment on request %d: %v", requestID, err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		comments func(requestID int64)
+		want     int
+	}{
+		{"no comments", func(int64) {}, 0},
+		{"two from the reviewer", func(id int64) {
+			comment(id, reviewer, false, past)
+			comment(id, reviewer, false, past.Add(time.Minute))
+		}, 2},
+		{"internal notes", func(id int64) { comment(id, reviewer, true, past) }, 0},
+		{"own comments", func(id int64) { comment(id, owner, false, past) }, 0},
+		{"read, then a new reply", func(id int64) {
+			comment(id, reviewer, false, past)
+			if err := s.MarkExpertRequestCommentsRead(id, owner); err != nil {
+				t.Fatal(err)
+			}
+			comment(id, reviewer, false, time.Now().Add(time.Minute))
+		}, 1},
+		{"answered by the owner", func(id int64) {
+			comment(id, reviewer, false, past)
+			comment(id, owner, false, time.Now())
+		}, 0},
+	}
+
+	want := map[int64]int{}
+	for _, tt := range tests {
+		id := createTestExpertRequest(t, s, newTestExpertRequest(tt.name, area, owner))
+		tt.comments(id)
+		want[id] = tt.want
+	}
+	// Another user's request is neither listed nor counted
+	createTestExpertRequest(t, s, newTestExpertRequest("Other", area, reviewer))
+
+	requests, err := s.ListExpertRequestsByUser(owner, map[string]interface{}{}, 20, 0)
+	if err != nil {
+		t.Fatalf("ListExpertRequestsByUser: %v", err)
+	}
+	if len(requests) != len(tests) {
+		t.Fatalf("%d requests listed, want %d", len(requests), len(tests))
+	}
+	for _, req := range requests {
+		if req.UnreadComments != want[req.ID] {
+			t.Errorf("%s: %d unread comments, want %d", req.Name, req.UnreadComments, want[req.ID])
+		}
+		single, err := s.CountUnreadExpertRequestComments(req.ID, owner, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if single != req.UnreadComments {
+			t.Errorf("%s: listing counts %d unread, CountUnreadExpertRequestComments %d", req.Name, req.UnreadComments, single)
+		}
+	}
+}
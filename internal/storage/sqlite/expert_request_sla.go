@@ -0,0 +1,197 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// SetExpertRequestSLA sets the review targets used for request aging and escalation
+func (s *SQLiteStore) SetExpertRequestSLA(sla domain.ExpertRequestSLA) {
+	s.requestSLA = sla
+}
+
+// ExpertRequestSLA returns the review targets used for request aging and escalation
+func (s *SQLiteStore) ExpertRequestSLA() domain.ExpertRequestSLA {
+	return s.requestSLA
+}
+
+// slaBreachCondition builds a condition matching requests that have spent longer than their
+// status's review target in that status, as of now
+func slaBreachCondition(sla domain.ExpertRequestSLA, now time.Time) (string, []interface{}) {
+	statuses := make([]string, 0, len(sla))
+	for status, target := range sla {
+		if target > 0 {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return "(0 = 1)", nil
+	}
+	sort.Strings(statuses)
+
+	conditions := make([]string, len(statuses))
+	params := make([]interface{}, 0, len(statuses)*2)
+	for i, status := range statuses {
+		conditions[i] = "(status = ? AND status_changed_at <= ?)"
+		params = append(params, status, now.Add(-sla[status]))
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", params
+}
+
+// GetExpertRequestAgingReport summarizes how long open requests have been in their current status,
+// bucketed by age and by the reviewer handling them
+func (s *SQLiteStore) GetExpertRequestAgingReport(now time.Time) (*domain.ExpertRequestAgingReport, error) {
+	statusCondition, params := buildInClause("r.status", domain.OpenExpertRequestStatuses)
+	rows, err := s.db.Query(`
+		SELECT r.id, r.status, r.created_at, r.status_changed_at, COALESCE(r.reviewed_by, 0), COALESCE(u.name, '')
+		FROM expert_requests r
+		LEFT JOIN users u ON u.id = r.reviewed_by
+		WHERE `+statusCondition, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open expert requests: %w", err)
+	}
+	defer rows.Close()
+
+	report := &domain.ExpertRequestAgingReport{
+		GeneratedAt: now,
+		SLAHours:    make(map[string]int, len(s.requestSLA)),
+		Buckets:     domain.NewExpertRequestAgingBuckets(),
+		ByReviewer:  []*domain.ExpertRequestReviewerAging{},
+	}
+	for status, target := range s.requestSLA {
+		report.SLAHours[status] = int(target.Hours())
+	}
+
+	reviewers := map[int64]*domain.ExpertRequestReviewerAging{}
+	for rows.Next() {
+		var req domain.ExpertRequest
+		var statusChangedAt sql.NullTime
+		var reviewerName string
+		if err := rows.Scan(&req.ID, &req.Status, &req.CreatedAt, &statusChangedAt, &req.ReviewedBy, &reviewerName); err != nil {
+			return nil, fmt.Errorf("failed to scan open expert request: %w", err)
+		}
+		if statusChangedAt.Valid {
+			req.StatusChangedAt = statusChangedAt.Time
+		}
+		req.ApplySLA(s.requestSLA, now)
+
+		reviewer, ok := reviewers[req.ReviewedBy]
+		if !ok {
+			reviewer = &domain.ExpertRequestReviewerAging{
+				ReviewerID:   req.ReviewedBy,
+				ReviewerName: reviewerName,
+				Buckets:      domain.NewExpertRequestAgingBuckets(),
+			}
+			if req.ReviewedBy == 0 {
+				reviewer.ReviewerName = "Unassigned"
+			}
+			reviewers[req.ReviewedBy] = reviewer
+			report.ByReviewer = append(report.ByReviewer, reviewer)
+		}
+
+		report.TotalOpen++
+		reviewer.Open++
+		reviewer.OldestAgeHours = max(reviewer.OldestAgeHours, req.AgeHours)
+		if req.SLABreached {
+			report.TotalBreached++
+			reviewer.Breached++
+		}
+		addToAgingBucket(report.Buckets, &req)
+		addToAgingBucket(reviewer.Buckets, &req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating open expert requests: %w", err)
+	}
+
+	sort.Slice(report.ByReviewer, func(i, j int) bool {
+		a, b := report.ByReviewer[i], report.ByReviewer[j]
+		if a.Breached != b.Breached {
+			return a.Breached > b.Breached
+		}
+		return a.OldestAgeHours > b.OldestAgeHours
+	})
+
+	return report, nil
+}
+
+// addToAgingBucket counts a request in the bucket covering its age in days
+func addToAgingBucket(buckets []domain.ExpertRequestAgingBucket, req *domain.ExpertRequest) {
+	days := req.AgeHours / 24
+	for i := range buckets {
+		if days >= buckets[i].MinDays && (buckets[i].MaxDays == nil || days <= *buckets[i].MaxDays) {
+			buckets[i].Count++
+			if req.SLABreached {
+				buckets[i].Breached++
+			}
+			return
+		}
+	}
+}
+
+// EscalateBreachedExpertRequests raises an admin notification for each request past its review target
+// A request is escalated once each time it enters a status; returns the number of new notifications
+func (s *SQLiteStore) EscalateBreachedExpertRequests(now time.Time) (int, error) {
+	log := logger.Get()
+
+	condition, params := slaBreachCondition(s.requestSLA, now)
+	rows, err := s.db.Query("SELECT id, name, status, status_changed_at FROM expert_requests WHERE "+condition, params...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query breached expert requests: %w", err)
+	}
+
+	type breach struct {
+		id              int64
+		name            string
+		status          string
+		statusChangedAt time.Time
+	}
+	var breaches []breach
+	for rows.Next() {
+		var b breach
+		if err := rows.Scan(&b.id, &b.name, &b.status, &b.statusChangedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan breached expert request: %w", err)
+		}
+		breaches = append(breaches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating breached expert requests: %w", err)
+	}
+
+	escalated := 0
+	for _, b := range breaches {
+		message := fmt.Sprintf("Expert request #%d (%s) has been '%s' for %s, past its review target of %s",
+			b.id, b.name, b.status, formatAge(now.Sub(b.statusChangedAt)), formatAge(s.requestSLA[b.status]))
+
+		result, err := s.db.Exec(`
+			INSERT OR IGNORE INTO admin_notifications (kind, request_id, status, status_changed_at, message, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, domain.AdminNotificationSLABreach, b.id, b.status, b.statusChangedAt, message, now)
+		if err != nil {
+			return escalated, fmt.Errorf("failed to escalate expert request %d: %w", b.id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			log.Info("Escalated expert request %d: %s", b.id, message)
+			escalated++
+		}
+	}
+
+	return escalated, nil
+}
+
+// formatAge describes a duration in whole days, or hours when under two days
+func formatAge(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours < 48 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d days", hours/24)
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// ageRequest backdates the time a request entered its current status
+func ageRequest(t *testing.T, s *SQLiteStore, id int64, age time.Duration) {
+	t.Helper()
+	if _, err := s.db.Exec("UPDATE expert_requests SET status_changed_at = ? WHERE id = ?", time.Now().Add(-age), id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpertRequestAgingReport(t *testing.T) {
+	s := newTestStore(t)
+	s.SetExpertRequestSLA(domain.ExpertRequestSLA{
+		domain.RequestStatusSubmitted:   72 * time.Hour,
+		domain.RequestStatusUnderReview: 168 * time.Hour,
+	})
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	fresh := createTestExpertRequest(t, s, newTestExpertRequest("Fresh", area, owner))
+	waiting := createTestExpertRequest(t, s, newTestExpertRequest("Waiting", area, owner))
+	reviewing := createTestExpertRequest(t, s, newTestExpertRequest("Reviewing", area, owner))
+	decided := createTestExpertRequest(t, s, newTestExpertRequest("Decided", area, owner))
+	if err := s.TransitionExpertRequest(reviewing, domain.RequestStatusSubmitted, domain.RequestStatusUnderReview, "", reviewer); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TransitionExpertRequest(decided, domain.RequestStatusSubmitted, domain.RequestStatusRejected, "Out of scope", reviewer); err != nil {
+		t.Fatal(err)
+	}
+	ageRequest(t, s, fresh, 24*time.Hour)
+	ageRequest(t, s, waiting, 5*24*time.Hour)
+	ageRequest(t, s, reviewing, 10*24*time.Hour)
+	ageRequest(t, s, decided, 40*24*time.Hour)
+
+	report, err := s.GetExpertRequestAgingReport(time.Now())
+	if err != nil {
+		t.Fatalf("GetExpertRequestAgingReport: %v", err)
+	}
+	if report.TotalOpen != 3 || report.TotalBreached != 2 {
+		t.Errorf("open %d, breached %d; want 3 and 2", report.TotalOpen, report.TotalBreached)
+	}
+	wantBuckets := []struct{ count, breached int }{{1, 0}, {1, 1}, {1, 1}, {0, 0}, {0, 0}}
+	for i, want := range wantBuckets {
+		if b := report.Buckets[i]; b.Count != want.count || b.Breached != want.breached {
+			t.Errorf("bucket %s = %d (%d breached), want %d (%d)", b.Label, b.Count, b.Breached, want.count, want.breached)
+		}
+	}
+
+	// Both reviewers have one breach, so the one with the older request comes first
+	tests := []struct {
+		reviewerID     int64
+		open, breached int
+		oldestDays     int
+	}{
+		{reviewer, 1, 1, 10},
+		{0, 2, 1, 5},
+	}
+	if len(report.ByReviewer) != len(tests) {
+		t.Fatalf("%d reviewers in the report, want %d", len(report.ByReviewer), len(tests))
+	}
+	for i, tt := range tests {
+		got := report.ByReviewer[i]
+		if got.ReviewerID != tt.reviewerID || got.Open != tt.open || got.Breached != tt.breached || got.OldestAgeHours/24 != tt.oldestDays {
+			t.Errorf("reviewer %d = %+v, want reviewer %d with %d open, %d breached, oldest %d days",
+				i, got, tt.reviewerID, tt.open, tt.breached, tt.oldestDays)
+		}
+	}
+	if report.ByReviewer[1].ReviewerName != "Unassigned" {
+		t.Errorf("reviewer name = %q, want Unassigned", report.ByReviewer[1].ReviewerName)
+	}
+}
+
+func TestEscalateBreachedExpertRequests(t *testing.T) {
+	s := newTestStore(t)
+	s.SetExpertRequestSLA(domain.ExpertRequestSLA{
+		domain.RequestStatusSubmitted:   72 * time.Hour,
+		domain.RequestStatusUnderReview: 168 * time.Hour,
+	})
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	late := createTestExpertRequest(t, s, newTestExpertRequest("Late", area, owner))
+	onTime := createTestExpertRequest(t, s, newTestExpertRequest("On Time", area, owner))
+	ageRequest(t, s, late, 4*24*time.Hour)
+	ageRequest(t, s, onTime, 2*24*time.Hour)
+
+	steps := []struct {
+		name  string
+		setup func()
+		want  int
+	}{
+		{"first run", func() {}, 1},
+		{"already escalated", func() {}, 0},
+		{"picked up", func() {
+			if err := s.TransitionExpertRequest(late, domain.RequestStatusSubmitted, domain.RequestStatusUnderReview, "", reviewer); err != nil {
+				t.Fatal(err)
+			}
+		}, 0},
+		{"late again in the new status", func() { ageRequest(t, s, late, 8*24*time.Hour) }, 1},
+	}
+	for _, step := range steps {
+		step.setup()
+		escalated, err := s.EscalateBreachedExpertRequests(time.Now())
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if escalated != step.want {
+			t.Errorf("%s: escalated %d, want %d", step.name, escalated, step.want)
+		}
+	}
+
+	notifications, err := s.ListAdminNotifications(true, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("%d notifications, want 2", len(notifications))
+	}
+	for _, n := range notifications {
+		if n.Kind != domain.AdminNotificationSLABreach || n.RequestID == nil || *n.RequestID != late {
+			t.Errorf("notification = %+v, want an SLA breach of request %d", n, late)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestListExpertRequestsFilters(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	other := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	engineering := createTestArea(t, s, "Engineering")
+	medicine := createTestArea(t, s, "Medicine")
+
+	add := func(name, affiliation, role string, area, createdBy int64, isBahraini bool) int64 {
+		req := newTestExpertRequest(name, area, createdBy)
+		req.Affiliation = affiliation
+		req.Role = role
+		req.IsBahraini = isBahraini
+		return createTestExpertRequest(t, s, req)
+	}
+	amal := add("Amal Hasan", "University of Bahrain", "evaluator", engineering, owner, true)
+	badr := add("Badr Saleh", "Bapco", "validator", engineering, owner, false)
+	carla := add("Carla Diaz", "Polytechnic", "evaluator", medicine, other, false)
+	if err := s.TransitionExpertRequest(badr, domain.RequestStatusSubmitted, domain.RequestStatusRejected, "Out of scope", reviewer); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		want    []int64 // In listing order
+	}{
+		{"all, newest first", map[string]interface{}{}, []int64{carla, badr, amal}},
+		{"oldest first", map[string]interface{}{"sort_order": "asc"}, []int64{amal, badr, carla}},
+		{"by name", map[string]interface{}{"sort_by": "name", "sort_order": "desc"}, []int64{carla, badr, amal}},
+		{"unknown sort column", map[string]interface{}{"sort_by": "name; DROP TABLE users"}, []int64{carla, badr, amal}},
+		{"status", map[string]interface{}{"status": "rejected"}, []int64{badr}},
+		{"several statuses", map[string]interface{}{"status": "submitted,rejected"}, []int64{carla, badr, amal}},
+		{"role", map[string]interface{}{"role": "validator"}, []int64{badr}},
+		{"general area", map[string]interface{}{"general_area": fmt.Sprint(medicine)}, []int64{carla}},
+		{"creator", map[string]interface{}{"created_by": owner}, []int64{badr, amal}},
+		{"nationality", map[string]interface{}{"is_bahraini": true}, []int64{amal}},
+		{"search name", map[string]interface{}{"search": "saleh"}, []int64{badr}},
+		{"search affiliation", map[string]interface{}{"search": " Polytechnic "}, []int64{carla}},
+		{"combined", map[string]interface{}{"role": "evaluator", "general_area": fmt.Sprint(engineering)}, []int64{amal}},
+		{"no match", map[string]interface{}{"search": "nobody"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := s.ListExpertRequests(tt.filters, 10, 0)
+			if err != nil {
+				t.Fatalf("ListExpertRequests: %v", err)
+			}
+			var got []int64
+			for _, req := range requests {
+				got = append(got, req.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ListExpertRequests = %v, want %v", got, tt.want)
+			}
+			total, err := s.CountExpertRequests(tt.filters)
+			if err != nil {
+				t.Fatalf("CountExpertRequests: %v", err)
+			}
+			if total != len(tt.want) {
+				t.Errorf("CountExpertRequests = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestListExpertRequestsPagination(t *testing.T) {
+	s := newTestStore(t)
+	area := createTestArea(t, s, "Engineering")
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		ids = append(ids, createTestExpertRequest(t, s, newTestExpertRequest(fmt.Sprintf("Expert %d", i), area, 0)))
+	}
+
+	page, err := s.ListExpertRequests(map[string]interface{}{"sort_order": "asc"}, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].ID != ids[2] || page[1].ID != ids[3] {
+		t.Errorf("second page = %v, want requests %d and %d", page, ids[2], ids[3])
+	}
+}
+
+func TestCreateExpertRequestWithDocument(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	area := createTestArea(t, s, "Engineering")
+	count := func(table string) int {
+		t.Helper()
+		var n int
+		if err := s.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	missingInvite := int64(1 << 40)
+
+	tests := []struct {
+		name     string
+		docType  string
+		invite   *int64 // An invite that doesn't exist fails the request before the file is written
+		writeErr error
+		wantErr  bool
+		written  bool // Whether writeFile is called
+	}{
+		{"CV", domain.DocumentTypeCV, nil, nil, false, true},
+		{"approval", domain.DocumentTypeApproval, nil, nil, false, true},
+		{"file not written", domain.DocumentTypeCV, nil, errors.New("disk full"), true, true},
+		{"request fails", domain.DocumentTypeCV, &missingInvite, nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestsBefore, documentsBefore := count("expert_requests"), count("expert_documents")
+			req := newTestExpertRequest("With Document", area, owner)
+			req.NominationInviteID = tt.invite
+			doc := &domain.Document{DocumentType: tt.docType, Filename: "cv.pdf", ContentType: "application/pdf", FileSize: 9}
+			written := false
+
+			id, err := s.CreateExpertRequestWithDocument(req, doc, func(requestID int64) (string, error) {
+				written = true
+				return fmt.Sprintf("expert_requests/expert_request_%d.pdf", requestID), tt.writeErr
+			})
+			if (err != nil) != tt.wantErr || written != tt.written {
+				t.Fatalf("error = %v, file written %v; want error %v, written %v", err, written, tt.wantErr, tt.written)
+			}
+			if tt.wantErr {
+				if count("expert_requests") != requestsBefore || count("expert_documents") != documentsBefore {
+					t.Error("rows kept after a failed creation")
+				}
+				return
+			}
+
+			stored, err := s.GetExpertRequest(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			linked := stored.CVDocumentID
+			if tt.docType == domain.DocumentTypeApproval {
+				linked = stored.ApprovalDocumentID
+			}
+			if linked == nil || *linked != doc.ID {
+				t.Fatalf("request links document %v, want %d", linked, doc.ID)
+			}
+			saved, err := s.GetDocument(doc.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.ExpertID != id || saved.FilePath != fmt.Sprintf("expert_requests/expert_request_%d.pdf", id) {
+				t.Errorf("document = request %d at %s, want request %d", saved.ExpertID, saved.FilePath, id)
+			}
+		})
+	}
+}
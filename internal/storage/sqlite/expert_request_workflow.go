@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+// approvableRequestCondition matches expert requests that are waiting for a reviewer's decision
+const approvableRequestCondition = "status IN ('submitted', 'under_review')"
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// recordExpertRequestTransition appends an entry to an expert request's status history
+// fromStatus is empty for the initial status; ex is either the database or an open transaction
+func recordExpertRequestTransition(ex execer, requestID int64, fromStatus, toStatus, note string, changedBy int64) error {
+	now := time.Now()
+	if err := insertExpertRequestHistory(ex, requestID, fromStatus, toStatus, note, changedBy, now); err != nil {
+		return err
+	}
+
+	// Restart the request's aging clock for the new status
+	if _, err := ex.Exec("UPDATE expert_requests SET status_changed_at = ? WHERE id = ?", now, requestID); err != nil {
+		return fmt.Errorf("failed to update expert request status time: %w", err)
+	}
+
+	return nil
+}
+
+// insertExpertRequestHistory appends an entry to an expert request's status history
+func insertExpertRequestHistory(ex execer, requestID int64, fromStatus, toStatus, note string, changedBy int64, at time.Time) error {
+	var from, noteValue, changedByValue interface{}
+	if fromStatus != "" {
+		from = fromStatus
+	}
+	if note != "" {
+		noteValue = note
+	}
+	if changedBy != 0 {
+		changedByValue = changedBy
+	}
+
+	_, err := ex.Exec(`
+		INSERT INTO expert_request_status_history (request_id, from_status, to_status, note, changed_by, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, requestID, from, toStatus, noteValue, changedByValue, at)
+	if err != nil {
+		return fmt.Errorf("failed to record expert request status change: %w", err)
+	}
+	return nil
+}
+
+// TransitionExpertRequest moves an expert request from one workflow status to another and records the change
+// The update only applies if the request is still in fromStatus, so concurrent changes are not overwritten
+// Approval is not handled here because it creates the expert; use ApproveExpertRequestWithDocument
+func (s *SQLiteStore) TransitionExpertRequest(id int64, fromStatus, toStatus, note string, changedBy int64) error {
+	log := logger.Get()
+
+	if toStatus == domain.RequestStatusApproved {
+		return fmt.Errorf("approval must go through ApproveExpertRequestWithDocument")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := transitionExpertRequestTx(tx, id, fromStatus, toStatus, note, changedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	log.Info("Expert request %d moved from '%s' to '%s' by user %d", id, fromStatus, toStatus, changedBy)
+	return nil
+}
+
+// transitionExpertRequestTx applies a status change within the given transaction
+// See TransitionExpertRequest; approval is not handled here
+func transitionExpertRequestTx(tx *sql.Tx, id int64, fromStatus, toStatus, note string, changedBy int64) error {
+	var result sql.Result
+	var err error
+	now := time.Now()
+	switch toStatus {
+	case domain.RequestStatusRejected, domain.RequestStatusNeedsChanges:
+		// Decisions record the reviewer and the reason shown to the requester
+		result, err = tx.Exec(`
+			UPDATE expert_requests
+			SET status = ?, rejection_reason = ?, reviewed_at = ?, reviewed_by = ?
+			WHERE id = ? AND status = ?
+		`, toStatus, note, now, changedBy, id, fromStatus)
+	case domain.RequestStatusSubmitted:
+		// (Re)submission clears the previous decision
+		result, err = tx.Exec(`
+			UPDATE expert_requests
+			SET status = ?, rejection_reason = NULL, reviewed_at = NULL, reviewed_by = NULL
+			WHERE id = ? AND status = ?
+		`, toStatus, id, fromStatus)
+	case domain.RequestStatusUnderReview:
+		result, err = tx.Exec(`
+			UPDATE expert_requests
+			SET status = ?, reviewed_by = ?
+			WHERE id = ? AND status = ?
+		`, toStatus, changedBy, id, fromStatus)
+	default:
+		result, err = tx.Exec("UPDATE expert_requests SET status = ? WHERE id = ? AND status = ?", toStatus, id, fromStatus)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update expert request status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM expert_requests WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check expert request: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("%w: request %d is no longer '%s'", domain.ErrValidation, id, fromStatus)
+	}
+
+	if err := recordExpertRequestTransition(tx, id, fromStatus, toStatus, note, changedBy); err != nil {
+		return err
+	}
+
+	// Approvals were given for the request as it stood; once it is sent back they no longer count
+	switch toStatus {
+	case domain.RequestStatusNeedsChanges, domain.RequestStatusRejected, domain.RequestStatusWithdrawn:
+		if err := invalidateExpertRequestApprovalsTx(tx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListExpertRequestHistory retrieves the status changes of an expert request, oldest first
+func (s *SQLiteStore) ListExpertRequestHistory(requestID int64) ([]*domain.ExpertRequestStatusChange, error) {
+	rows, err := s.db.Query(`
+		SELECT h.id, h.request_id, h.from_status, h.to_status, h.note, h.changed_by, u.name, h.changed_at
+		FROM expert_request_status_history h
+		LEFT JOIN users u ON u.id = h.changed_by
+		WHERE h.request_id = ?
+		ORDER BY h.changed_at ASC, h.id ASC
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expert request history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*domain.ExpertRequestStatusChange{}
+	for rows.Next() {
+		var change domain.ExpertRequestStatusChange
+		var fromStatus, note, changedByName sql.NullString
+		var changedBy sql.NullInt64
+
+		err := rows.Scan(&change.ID, &change.RequestID, &fromStatus, &change.ToStatus, &note,
+			&changedBy, &changedByName, &change.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expert request history: %w", err)
+		}
+
+		change.FromStatus = fromStatus.String
+		change.Note = note.String
+		change.ChangedBy = changedBy.Int64
+		change.ChangedByName = changedByName.String
+
+		history = append(history, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expert request history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+// createReturnedRequest stores a request by owner that a reviewer has sent back with toStatus
+func createReturnedRequest(t *testing.T, s *SQLiteStore, owner, reviewer int64, toStatus string) int64 {
+	t.Helper()
+	id := createTestExpertRequest(t, s, newTestExpertRequest("Returned", createTestArea(t, s, "Engineering"), owner))
+	if err := s.TransitionExpertRequest(id, domain.RequestStatusSubmitted, toStatus, "Fix the CV", reviewer); err != nil {
+		t.Fatalf("return request: %v", err)
+	}
+	return id
+}
+
+func TestUpdateAndResubmitExpertRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		fromStatus string
+		moveOn     string // status the request is moved to before the edit is saved, if any
+		wantErr    error
+	}{
+		{"needs changes", domain.RequestStatusNeedsChanges, "", nil},
+		{"rejected", domain.RequestStatusRejected, "", nil},
+		{"withdrawn meanwhile", domain.RequestStatusNeedsChanges, domain.RequestStatusWithdrawn, domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			owner := createTestUser(t, s, "user")
+			reviewer := createTestUser(t, s, "admin")
+			id := createReturnedRequest(t, s, owner, reviewer, tt.fromStatus)
+			if tt.moveOn != "" {
+				if err := s.TransitionExpertRequest(id, tt.fromStatus, tt.moveOn, "", owner); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			edited, err := s.GetExpertRequest(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			edited.Designation = "Associate Professor"
+			err = s.UpdateAndResubmitExpertRequest(edited, tt.fromStatus, "Resubmitted after changes", owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateAndResubmitExpertRequest error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := s.GetExpertRequest(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			history, err := s.ListExpertRequestHistory(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := history[len(history)-1]
+			if tt.wantErr != nil {
+				// Neither the edit nor a resubmission may be saved
+				if stored.Designation != "Professor" || stored.Status != tt.moveOn || last.ToStatus != tt.moveOn {
+					t.Errorf("request saved as %s in status %s after a failed resubmission", stored.Designation, stored.Status)
+				}
+				return
+			}
+			if stored.Designation != "Associate Professor" || stored.Status != domain.RequestStatusSubmitted {
+				t.Errorf("request = %s in status %s, want the edit resubmitted", stored.Designation, stored.Status)
+			}
+			if stored.RejectionReason != "" || stored.ReviewedBy != 0 {
+				t.Errorf("resubmitted request keeps the decision: reason %q by %d", stored.RejectionReason, stored.ReviewedBy)
+			}
+			if last.FromStatus != tt.fromStatus || last.ToStatus != domain.RequestStatusSubmitted || last.ChangedBy != owner {
+				t.Errorf("last history entry = %+v, want the resubmission by the owner", last)
+			}
+		})
+	}
+}
+
+func TestTransitionExpertRequest(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	reviewer := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+
+	tests := []struct {
+		name       string
+		from, to   string
+		id         int64 // 0 for a fresh submitted request
+		wantErr    error
+		wantReason string
+	}{
+		{"pick up", domain.RequestStatusSubmitted, domain.RequestStatusUnderReview, 0, nil, ""},
+		{"send back", domain.RequestStatusSubmitted, domain.RequestStatusNeedsChanges, 0, nil, "Fix the CV"},
+		{"reject", domain.RequestStatusSubmitted, domain.RequestStatusRejected, 0, nil, "Fix the CV"},
+		{"stale status", domain.RequestStatusUnderReview, domain.RequestStatusRejected, 0, domain.ErrValidation, ""},
+		{"missing request", domain.RequestStatusSubmitted, domain.RequestStatusRejected, 1 << 40, domain.ErrNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.id
+			if id == 0 {
+				id = createTestExpertRequest(t, s, newTestExpertRequest("Transition", area, owner))
+			}
+			err := s.TransitionExpertRequest(id, tt.from, tt.to, "Fix the CV", reviewer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransitionExpertRequest error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.id != 0 {
+				return
+			}
+
+			stored, err := s.GetExpertRequest(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			history, err := s.ListExpertRequestHistory(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := history[len(history)-1]
+			if tt.wantErr != nil {
+				if stored.Status != domain.RequestStatusSubmitted || last.ToStatus != domain.RequestStatusSubmitted {
+					t.Errorf("request moved to %s by a stale transition", stored.Status)
+				}
+				return
+			}
+			if stored.Status != tt.to || stored.ReviewedBy != reviewer || stored.RejectionReason != tt.wantReason {
+				t.Errorf("request = %s reviewed by %d with reason %q, want %s by %d with %q",
+					stored.Status, stored.ReviewedBy, stored.RejectionReason, tt.to, reviewer, tt.wantReason)
+			}
+			if last.FromStatus != tt.from || last.ToStatus != tt.to || last.ChangedBy != reviewer || last.Note != "Fix the CV" {
+				t.Errorf("last history entry = %+v, want %s to %s by %d", last, tt.from, tt.to, reviewer)
+			}
+		})
+	}
+
+	// Approval creates the expert and must go through the approval path
+	id := createTestExpertRequest(t, s, newTestExpertRequest("Approve", area, owner))
+	if err := s.TransitionExpertRequest(id, domain.RequestStatusSubmitted, domain.RequestStatusApproved, "", reviewer); err == nil {
+		t.Error("TransitionExpertRequest approved a request")
+	}
+}
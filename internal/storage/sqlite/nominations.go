@@ -0,0 +1,165 @@
+package sqlite
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// hashNominationToken returns the stored form of an invite token
+func hashNominationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateNominationInvite stores a nomination invite; only a hash of the token is kept
+func (s *SQLiteStore) CreateNominationInvite(invite *domain.NominationInvite, token string) (int64, error) {
+	if invite.MaxUses <= 0 {
+		invite.MaxUses = 1
+	}
+	if invite.CreatedAt.IsZero() {
+		invite.CreatedAt = time.Now()
+	}
+
+	var email, note, createdBy interface{}
+	if invite.Email != "" {
+		email = invite.Email
+	}
+	if invite.Note != "" {
+		note = invite.Note
+	}
+	if invite.CreatedBy != 0 {
+		createdBy = invite.CreatedBy
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO nomination_invites (token_hash, email, note, max_uses, use_count, expires_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?)
+	`, hashNominationToken(token), email, note, invite.MaxUses, invite.ExpiresAt, createdBy, invite.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create nomination invite: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nomination invite ID: %w", err)
+	}
+	invite.ID = id
+
+	return id, nil
+}
+
+const nominationInviteColumns = `id, email, note, max_uses, use_count, expires_at, revoked_at, created_by, created_at`
+
+// scanNominationInvite reads a nomination invite row selected with nominationInviteColumns
+func scanNominationInvite(row interface{ Scan(...interface{}) error }) (*domain.NominationInvite, error) {
+	var invite domain.NominationInvite
+	var email, note sql.NullString
+	var revokedAt sql.NullTime
+	var createdBy sql.NullInt64
+
+	if err := row.Scan(&invite.ID, &email, &note, &invite.MaxUses, &invite.UseCount,
+		&invite.ExpiresAt, &revokedAt, &createdBy, &invite.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	invite.Email = email.String
+	invite.Note = note.String
+	invite.CreatedBy = createdBy.Int64
+	if revokedAt.Valid {
+		invite.RevokedAt = &revokedAt.Time
+	}
+
+	return &invite, nil
+}
+
+// GetNominationInviteByToken looks up a nomination invite by its token
+// Returns domain.ErrNotFound for unknown tokens; callers check Usable for expiry and revocation
+func (s *SQLiteStore) GetNominationInviteByToken(token string) (*domain.NominationInvite, error) {
+	row := s.db.QueryRow("SELECT "+nominationInviteColumns+" FROM nomination_invites WHERE token_hash = ?",
+		hashNominationToken(token))
+
+	invite, err := scanNominationInvite(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nomination invite: %w", err)
+	}
+
+	return invite, nil
+}
+
+// ListNominationInvites returns all nomination invites, newest first
+func (s *SQLiteStore) ListNominationInvites() ([]*domain.NominationInvite, error) {
+	rows, err := s.db.Query("SELECT " + nominationInviteColumns + " FROM nomination_invites ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nomination invites: %w", err)
+	}
+	defer rows.Close()
+
+	invites := []*domain.NominationInvite{}
+	for rows.Next() {
+		invite, err := scanNominationInvite(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan nomination invite: %w", err)
+		}
+		invites = append(invites, invite)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating nomination invites: %w", err)
+	}
+
+	return invites, nil
+}
+
+// RevokeNominationInvite stops an invite from accepting further nominations
+func (s *SQLiteStore) RevokeNominationInvite(id int64) error {
+	result, err := s.db.Exec("UPDATE nomination_invites SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke nomination invite: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM nomination_invites WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check nomination invite: %w", err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// consumeNominationInviteTx uses up one nomination on an invite
+// The conditional update keeps concurrent submissions from exceeding the invite's limit
+func consumeNominationInviteTx(ex execer, inviteID int64) error {
+	result, err := ex.Exec(`
+		UPDATE nomination_invites SET use_count = use_count + 1
+		WHERE id = ? AND revoked_at IS NULL AND use_count < max_uses AND expires_at > ?
+	`, inviteID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to use nomination invite: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: invitation is no longer valid", domain.ErrValidation)
+	}
+
+	return nil
+}
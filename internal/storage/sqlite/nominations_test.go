@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// createTestNomination stores a self-nomination made through a new single-use invite
+func createTestNomination(t *testing.T, s *SQLiteStore, generalArea, admin int64) (requestID, inviteID int64) {
+	t.Helper()
+	inviteID, err := s.CreateNominationInvite(&domain.NominationInvite{
+		MaxUses:   1,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedBy: admin,
+	}, "token-"+time.Now().Format(time.RFC3339Nano))
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+
+	req := newTestExpertRequest("Nominee", generalArea, 0)
+	req.IsSelfNominated = true
+	req.NominationInviteID = &inviteID
+	return createTestExpertRequest(t, s, req), inviteID
+}
+
+func TestApproveSelfNomination(t *testing.T) {
+	tests := []struct {
+		name    string
+		approve func(s *SQLiteStore, requestID, reviewer int64) (*domain.ExpertRequestApprovalResult, error)
+	}{
+		{"single", func(s *SQLiteStore, requestID, reviewer int64) (*domain.ExpertRequestApprovalResult, error) {
+			return s.ApproveExpertRequestWithDocument(requestID, reviewer, nil)
+		}},
+		{"batch", func(s *SQLiteStore, requestID, reviewer int64) (*domain.ExpertRequestApprovalResult, error) {
+			results, errs := s.BatchApproveExpertRequestsWithFileMove([]int64{requestID}, reviewer, nil)
+			if err := errs[requestID]; err != nil {
+				return nil, err
+			}
+			if len(results) != 1 {
+				return nil, errors.New("no approval result")
+			}
+			return results[0], nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			admin := createTestUser(t, s, "admin")
+			requestID, _ := createTestNomination(t, s, createTestArea(t, s, "Engineering"), admin)
+
+			result, err := tt.approve(s, requestID, admin)
+			if err != nil {
+				t.Fatalf("approve self-nomination: %v", err)
+			}
+			if !result.Approved || result.ExpertID == 0 {
+				t.Fatalf("result = %+v, want an approved request with an expert", result)
+			}
+			if status := requestStatus(t, s, requestID); status != domain.RequestStatusApproved {
+				t.Errorf("request status = %s, want approved", status)
+			}
+			var name string
+			var originalRequestID int64
+			err = s.db.QueryRow("SELECT name, original_request_id FROM experts WHERE id = ?", result.ExpertID).
+				Scan(&name, &originalRequestID)
+			if err != nil {
+				t.Fatalf("get expert: %v", err)
+			}
+			if name != "Nominee" || originalRequestID != requestID {
+				t.Errorf("expert = %s from request %d, want Nominee from request %d", name, originalRequestID, requestID)
+			}
+		})
+	}
+}
+
+func TestNominationUsesInvite(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Engineering")
+	requestID, inviteID := createTestNomination(t, s, area, admin)
+
+	req, err := s.GetExpertRequest(requestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.IsSelfNominated || req.CreatedBy != 0 || req.Status != domain.RequestStatusSubmitted {
+		t.Errorf("nomination stored as self-nominated %v, created by %d, status %s", req.IsSelfNominated, req.CreatedBy, req.Status)
+	}
+
+	// The invite allowed a single nomination, which has been made
+	again := newTestExpertRequest("Second nominee", area, 0)
+	again.IsSelfNominated = true
+	again.NominationInviteID = &inviteID
+	if _, err := s.CreateExpertRequest(again); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("second nomination with a used invite: %v, want a validation error", err)
+	}
+	if n, _ := s.CountExpertRequests(map[string]interface{}{"is_self_nominated": true}); n != 1 {
+		t.Errorf("%d self-nominations stored, want 1", n)
+	}
+}
@@ -887,7 +887,7 @@ func (s *SQLiteStore) UpdatePhaseApplicationStatus(id int64, status, rejectionNo
 				EngagementType: engagementType,
 				StartDate:      now,
 				ProjectName:    fmt.Sprintf("%s - %s", app.InstitutionName, app.QualificationName),
-				Status:         "active",
+				Status:         domain.EngagementStatusActive,
 				Notes:          fmt.Sprintf("Automatically created from phase application ID %d", app.ID),
 				CreatedAt:      now,
 			}
@@ -920,7 +920,7 @@ func (s *SQLiteStore) UpdatePhaseApplicationStatus(id int64, status, rejectionNo
 				EngagementType: engagementType,
 				StartDate:      now,
 				ProjectName:    fmt.Sprintf("%s - %s", app.InstitutionName, app.QualificationName),
-				Status:         "active",
+				Status:         domain.EngagementStatusActive,
 				Notes:          fmt.Sprintf("Automatically created from phase application ID %d", app.ID),
 				CreatedAt:      now,
 			}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// CreateQuarantinedUpload records a file flagged by the malware scanner
+func (s *SQLiteStore) CreateQuarantinedUpload(upload *domain.QuarantinedUpload) (int64, error) {
+	if upload.QuarantinedAt.IsZero() {
+		upload.QuarantinedAt = time.Now()
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO quarantined_uploads (
+			filename, file_path, content_type, file_size, sha256,
+			scanner, signature, document_type, source, quarantined_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		upload.Filename, upload.FilePath, upload.ContentType, upload.FileSize, upload.SHA256,
+		upload.Scanner, upload.Signature, upload.DocumentType, upload.Source, upload.QuarantinedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record quarantined upload: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quarantined upload ID: %w", err)
+	}
+	upload.ID = id
+	return id, nil
+}
+
+// ListQuarantinedUploads returns every quarantined upload, most recent first
+func (s *SQLiteStore) ListQuarantinedUploads() ([]*domain.QuarantinedUpload, error) {
+	rows, err := s.db.Query(`
+		SELECT id, filename, file_path, content_type, file_size, sha256,
+		       scanner, signature, document_type, source, quarantined_at
+		FROM quarantined_uploads
+		ORDER BY quarantined_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined uploads: %w", err)
+	}
+	defer rows.Close()
+
+	uploads := []*domain.QuarantinedUpload{}
+	for rows.Next() {
+		upload, err := scanQuarantinedUpload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined upload: %w", err)
+		}
+		uploads = append(uploads, upload)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quarantined uploads: %w", err)
+	}
+
+	return uploads, nil
+}
+
+// GetQuarantinedUpload returns one quarantined upload
+func (s *SQLiteStore) GetQuarantinedUpload(id int64) (*domain.QuarantinedUpload, error) {
+	upload, err := scanQuarantinedUpload(s.db.QueryRow(`
+		SELECT id, filename, file_path, content_type, file_size, sha256,
+		       scanner, signature, document_type, source, quarantined_at
+		FROM quarantined_uploads
+		WHERE id = ?
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get quarantined upload: %w", err)
+	}
+	return upload, nil
+}
+
+// DeleteQuarantinedUpload removes the record of a quarantined upload
+func (s *SQLiteStore) DeleteQuarantinedUpload(id int64) error {
+	result, err := s.db.Exec("DELETE FROM quarantined_uploads WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete quarantined upload: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// scanQuarantinedUpload scans a quarantined_uploads row
+func scanQuarantinedUpload(scanner interface{ Scan(...interface{}) error }) (*domain.QuarantinedUpload, error) {
+	var u domain.QuarantinedUpload
+	err := scanner.Scan(
+		&u.ID, &u.Filename, &u.FilePath, &u.ContentType, &u.FileSize, &u.SHA256,
+		&u.Scanner, &u.Signature, &u.DocumentType, &u.Source, &u.QuarantinedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
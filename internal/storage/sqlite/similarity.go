@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"strings"
+	"unicode"
+)
+
+// normalizeName lowercases a name, drops punctuation and collapses whitespace so that
+// "Banking & Finance" and "banking  finance" compare equal
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// nameSimilarity scores two names between 0 and 1 after normalization
+// The score is the better of the edit-distance ratio and the word overlap, so both
+// typos ("Acounting") and reordered words ("Finance and Banking") are caught
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeName(a), normalizeName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	maxLen := max(len([]rune(a)), len([]rune(b)))
+	editScore := 1 - float64(levenshtein(a, b))/float64(maxLen)
+
+	wordsA := make(map[string]bool)
+	for _, w := range strings.Fields(a) {
+		wordsA[w] = true
+	}
+	wordsB := make(map[string]bool)
+	for _, w := range strings.Fields(b) {
+		wordsB[w] = true
+	}
+	shared := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	union := len(wordsA) + len(wordsB) - shared
+	wordScore := float64(shared) / float64(union)
+
+	return max(editScore, wordScore)
+}
@@ -0,0 +1,365 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+	"expertdb/internal/logger"
+)
+
+const (
+	// suggestionMatchThreshold is the minimum similarity for an existing area to be reported as a near-duplicate
+	suggestionMatchThreshold = 0.6
+	// suggestionMaxMatches caps the near-duplicates reported per suggestion
+	suggestionMaxMatches = 5
+)
+
+// suggestionRequest is an expert request carrying suggested specialized areas
+type suggestionRequest struct {
+	id              int64
+	specializedArea string
+	suggestions     []string
+	createdAt       time.Time
+}
+
+// querySuggestionRequests loads every expert request with at least one suggested specialized area
+// q is either the database or an open transaction
+func (s *SQLiteStore) querySuggestionRequests(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}) ([]suggestionRequest, error) {
+	rows, err := q.Query(`
+		SELECT id, COALESCE(specialized_area, ''), suggested_specialized_areas, created_at
+		FROM expert_requests
+		WHERE suggested_specialized_areas IS NOT NULL AND suggested_specialized_areas NOT IN ('', '[]', 'null')
+		ORDER BY created_at ASC, id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suggested specialized areas: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []suggestionRequest
+	for rows.Next() {
+		var req suggestionRequest
+		var suggestionsJSON string
+		if err := rows.Scan(&req.id, &req.specializedArea, &suggestionsJSON, &req.createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan suggested specialized areas: %w", err)
+		}
+		req.suggestions = s.deserializeSuggestedAreas(suggestionsJSON)
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suggested specialized areas: %w", err)
+	}
+
+	return requests, nil
+}
+
+// ListSpecializedAreaSuggestions groups every unresolved suggestion across expert requests,
+// most requested first, with near-duplicate matches against active specialized areas
+func (s *SQLiteStore) ListSpecializedAreaSuggestions() ([]*domain.SpecializedAreaSuggestion, error) {
+	requests, err := s.querySuggestionRequests(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*domain.SpecializedAreaSuggestion)
+	var suggestions []*domain.SpecializedAreaSuggestion
+	for _, req := range requests {
+		seen := make(map[string]bool)
+		for _, name := range req.suggestions {
+			key := normalizeName(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			suggestion, ok := byKey[key]
+			if !ok {
+				suggestion = &domain.SpecializedAreaSuggestion{
+					Name:             strings.TrimSpace(name),
+					FirstSuggestedAt: req.createdAt,
+					RequestIDs:       []int64{},
+					Matches:          []domain.SpecializedAreaMatch{},
+				}
+				byKey[key] = suggestion
+				suggestions = append(suggestions, suggestion)
+			}
+			suggestion.RequestIDs = append(suggestion.RequestIDs, req.id)
+			suggestion.RequestCount++
+		}
+	}
+
+	areas, err := s.ListSpecializedAreas()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suggestion := range suggestions {
+		for _, area := range areas {
+			if area.RetiredAt != nil {
+				continue
+			}
+			if score := nameSimilarity(suggestion.Name, area.Name); score >= suggestionMatchThreshold {
+				suggestion.Matches = append(suggestion.Matches, domain.SpecializedAreaMatch{
+					ID:    area.ID,
+					Name:  area.Name,
+					Score: float64(int(score*100)) / 100,
+				})
+			}
+		}
+		sort.SliceStable(suggestion.Matches, func(i, j int) bool {
+			return suggestion.Matches[i].Score > suggestion.Matches[j].Score
+		})
+		if len(suggestion.Matches) > suggestionMaxMatches {
+			suggestion.Matches = suggestion.Matches[:suggestionMaxMatches]
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].RequestCount > suggestions[j].RequestCount
+	})
+
+	return suggestions, nil
+}
+
+// AcceptSpecializedAreaSuggestion creates a specialized area from a suggestion and moves every
+// request (and expert created from one) carrying the suggestion onto the new area
+// The area is created in the same transaction, so it is not left behind when the resolution fails
+func (s *SQLiteStore) AcceptSpecializedAreaSuggestion(name string, generalAreaID *int64, resolvedBy int64) (*domain.SuggestionResolution, error) {
+	if err := s.ensureSuggestionPending(name); err != nil {
+		return nil, err
+	}
+
+	area := &domain.SpecializedArea{
+		Name:          strings.TrimSpace(name),
+		GeneralAreaID: generalAreaID,
+	}
+	return s.resolveSpecializedAreaSuggestion(name, area, "accepted", resolvedBy)
+}
+
+// MapSpecializedAreaSuggestion resolves a suggestion onto an existing specialized area
+func (s *SQLiteStore) MapSpecializedAreaSuggestion(name string, specializedAreaID, resolvedBy int64) (*domain.SuggestionResolution, error) {
+	area, err := s.GetSpecializedArea(specializedAreaID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, fmt.Errorf("specialized area %d does not exist", specializedAreaID)
+		}
+		return nil, err
+	}
+	if area.RetiredAt != nil {
+		return nil, fmt.Errorf("cannot map to retired specialized area '%s'", area.Name)
+	}
+
+	if err := s.ensureSuggestionPending(name); err != nil {
+		return nil, err
+	}
+
+	return s.resolveSpecializedAreaSuggestion(name, area, "mapped", resolvedBy)
+}
+
+// ensureSuggestionPending returns domain.ErrNotFound if no expert request carries the suggestion
+func (s *SQLiteStore) ensureSuggestionPending(name string) error {
+	key := normalizeName(name)
+	if key == "" {
+		return fmt.Errorf("suggestion name cannot be empty")
+	}
+
+	requests, err := s.querySuggestionRequests(s.db)
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		for _, suggestion := range req.suggestions {
+			if normalizeName(suggestion) == key {
+				return nil
+			}
+		}
+	}
+
+	return domain.ErrNotFound
+}
+
+// resolveSpecializedAreaSuggestion removes a suggestion from every expert request carrying it, adds the
+// specialized area in its place (also on experts created from those requests) and records the resolution
+// An area without an ID is created as part of the resolution
+func (s *SQLiteStore) resolveSpecializedAreaSuggestion(name string, area *domain.SpecializedArea, resolution string, resolvedBy int64) (*domain.SuggestionResolution, error) {
+	log := logger.Get()
+	key := normalizeName(name)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if area.ID == 0 {
+		if area.ID, err = createSpecializedArea(tx, area); err != nil {
+			return nil, err
+		}
+	}
+	specializedAreaID := area.ID
+
+	requests, err := s.querySuggestionRequests(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.SuggestionResolution{
+		SuggestedName:       strings.TrimSpace(name),
+		Resolution:          resolution,
+		SpecializedAreaID:   specializedAreaID,
+		SpecializedAreaName: area.Name,
+		ResolvedBy:          resolvedBy,
+		ResolvedAt:          time.Now(),
+	}
+	reason := fmt.Sprintf("Suggested specialized area '%s' %s as '%s'", result.SuggestedName, resolution, area.Name)
+
+	for _, req := range requests {
+		remaining := []string{}
+		for _, suggestion := range req.suggestions {
+			if normalizeName(suggestion) != key {
+				remaining = append(remaining, suggestion)
+			}
+		}
+		if len(remaining) == len(req.suggestions) {
+			continue
+		}
+
+		_, err := tx.Exec("UPDATE expert_requests SET specialized_area = ?, suggested_specialized_areas = ? WHERE id = ?",
+			appendSpecializedAreaID(req.specializedArea, specializedAreaID), s.serializeSuggestedAreas(remaining), req.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update expert request %d: %w", req.id, err)
+		}
+		result.RequestsUpdated++
+
+		updated, err := s.addSpecializedAreaToRequestExpertsTx(tx, req.id, specializedAreaID, resolvedBy, reason)
+		if err != nil {
+			return nil, err
+		}
+		result.ExpertsUpdated += updated
+	}
+
+	if result.RequestsUpdated == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO specialized_area_suggestion_resolutions (
+			suggested_name, resolution, specialized_area_id, requests_updated, experts_updated, resolved_by, resolved_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, result.SuggestedName, resolution, specializedAreaID, result.RequestsUpdated, result.ExpertsUpdated, resolvedBy, result.ResolvedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record suggestion resolution: %w", err)
+	}
+	result.ID, _ = res.LastInsertId()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit suggestion resolution: %w", err)
+	}
+
+	log.Info("Suggested specialized area '%s' %s as area %d: %d requests, %d experts updated",
+		result.SuggestedName, resolution, specializedAreaID, result.RequestsUpdated, result.ExpertsUpdated)
+	return result, nil
+}
+
+// addSpecializedAreaToRequestExpertsTx adds a specialized area to every expert created from the given request
+func (s *SQLiteStore) addSpecializedAreaToRequestExpertsTx(tx *sql.Tx, requestID, specializedAreaID, editedBy int64, reason string) (int, error) {
+	rows, err := tx.Query("SELECT id, COALESCE(specialized_area, '') FROM experts WHERE original_request_id = ?", requestID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find experts for request %d: %w", requestID, err)
+	}
+	type expertArea struct {
+		id              int64
+		specializedArea string
+	}
+	var experts []expertArea
+	for rows.Next() {
+		var e expertArea
+		if err := rows.Scan(&e.id, &e.specializedArea); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan expert specialized areas: %w", err)
+		}
+		experts = append(experts, e)
+	}
+	rows.Close()
+
+	updated := 0
+	now := time.Now()
+	for _, e := range experts {
+		newValue := appendSpecializedAreaID(e.specializedArea, specializedAreaID)
+		if newValue == e.specializedArea {
+			continue
+		}
+
+		_, err := tx.Exec("UPDATE experts SET specialized_area = ?, updated_at = ?, last_edited_by = ?, last_edited_at = ? WHERE id = ?",
+			newValue, now, editedBy, now, e.id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update expert %d: %w", e.id, err)
+		}
+
+		err = s.createExpertEditHistoryWithReasonTx(tx, e.id, editedBy, []string{"specializedArea"},
+			map[string]interface{}{"specializedArea": e.specializedArea}, map[string]interface{}{"specializedArea": newValue}, reason)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create audit history: %w", err)
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// ListSuggestionResolutions retrieves moderated suggestions, newest first
+func (s *SQLiteStore) ListSuggestionResolutions() ([]*domain.SuggestionResolution, error) {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.suggested_name, r.resolution, r.specialized_area_id, sa.name,
+		       r.requests_updated, r.experts_updated, COALESCE(r.resolved_by, 0), u.name, r.resolved_at
+		FROM specialized_area_suggestion_resolutions r
+		JOIN specialized_areas sa ON sa.id = r.specialized_area_id
+		LEFT JOIN users u ON u.id = r.resolved_by
+		ORDER BY r.resolved_at DESC, r.id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suggestion resolutions: %w", err)
+	}
+	defer rows.Close()
+
+	var resolutions []*domain.SuggestionResolution
+	for rows.Next() {
+		var r domain.SuggestionResolution
+		var resolverName sql.NullString
+		err := rows.Scan(&r.ID, &r.SuggestedName, &r.Resolution, &r.SpecializedAreaID, &r.SpecializedAreaName,
+			&r.RequestsUpdated, &r.ExpertsUpdated, &r.ResolvedBy, &resolverName, &r.ResolvedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion resolution: %w", err)
+		}
+		if resolverName.Valid {
+			r.ResolverName = resolverName.String
+		}
+		resolutions = append(resolutions, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suggestion resolutions: %w", err)
+	}
+
+	return resolutions, nil
+}
+
+// appendSpecializedAreaID adds an ID to a comma-separated specialized area list unless already present
+func appendSpecializedAreaID(list string, id int64) string {
+	value := strconv.FormatInt(id, 10)
+	values := parseMultiValue(list)
+	for _, existing := range values {
+		if existing == value {
+			return list
+		}
+	}
+	return strings.Join(append(values, value), ",")
+}
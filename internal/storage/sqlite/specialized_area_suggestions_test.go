@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"expertdb/internal/domain"
+)
+
+func TestAppendSpecializedAreaID(t *testing.T) {
+	tests := []struct {
+		list string
+		id   int64
+		want string
+	}{
+		{"", 4, "4"},
+		{"1,2", 4, "1,2,4"},
+		{"1, 4", 4, "1, 4"},
+		{" 1 ,2 ", 3, "1,2,3"},
+	}
+	for _, tt := range tests {
+		if got := appendSpecializedAreaID(tt.list, tt.id); got != tt.want {
+			t.Errorf("appendSpecializedAreaID(%q, %d) = %q, want %q", tt.list, tt.id, got, tt.want)
+		}
+	}
+}
+
+// createSuggestingRequest stores a request carrying suggested specialized areas on top of specializedArea
+func createSuggestingRequest(t *testing.T, s *SQLiteStore, area, owner int64, specializedArea string, suggestions ...string) int64 {
+	t.Helper()
+	req := newTestExpertRequest("Suggesting", area, owner)
+	req.SpecializedArea = specializedArea
+	req.SuggestedSpecializedAreas = suggestions
+	return createTestExpertRequest(t, s, req)
+}
+
+func TestListSpecializedAreaSuggestions(t *testing.T) {
+	s := newTestStore(t)
+	owner := createTestUser(t, s, "user")
+	area := createTestArea(t, s, "Science")
+	marine := fmt.Sprintf("Marine Ecology %d", fixtureSeq.Add(1))
+	existing, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: marine})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := createSuggestingRequest(t, s, area, owner, "", "Coral Reefs", "Drones")
+	// The same suggestion differently cased and repeated on one request is counted once
+	second := createSuggestingRequest(t, s, area, owner, "", "coral reefs ", "Coral Reefs", "  "+marine)
+	createTestExpertRequest(t, s, newTestExpertRequest("Plain", area, owner))
+
+	suggestions, err := s.ListSpecializedAreaSuggestions()
+	if err != nil {
+		t.Fatalf("ListSpecializedAreaSuggestions: %v", err)
+	}
+	byName := make(map[string]*domain.SpecializedAreaSuggestion)
+	for _, suggestion := range suggestions {
+		byName[suggestion.Name] = suggestion
+	}
+	if len(suggestions) != 3 || suggestions[0].Name != "Coral Reefs" {
+		t.Fatalf("suggestions = %+v, want Coral Reefs first of 3", suggestions)
+	}
+
+	tests := []struct {
+		name        string
+		requestIDs  []int64
+		wantMatchID int64 // 0 for no near-duplicate
+	}{
+		{"Coral Reefs", []int64{first, second}, 0},
+		{"Drones", []int64{first}, 0},
+		{marine, []int64{second}, existing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion := byName[tt.name]
+			if suggestion == nil {
+				t.Fatalf("suggestion %q not listed", tt.name)
+			}
+			if suggestion.RequestCount != len(tt.requestIDs) || fmt.Sprint(suggestion.RequestIDs) != fmt.Sprint(tt.requestIDs) {
+				t.Errorf("requests = %v (%d), want %v", suggestion.RequestIDs, suggestion.RequestCount, tt.requestIDs)
+			}
+			if tt.wantMatchID == 0 {
+				for _, match := range suggestion.Matches {
+					if match.Score == 1 {
+						t.Errorf("unexpected exact match %+v", match)
+					}
+				}
+				return
+			}
+			if len(suggestion.Matches) == 0 || suggestion.Matches[0].ID != tt.wantMatchID || suggestion.Matches[0].Score != 1 {
+				t.Errorf("matches = %+v, want area %d first with score 1", suggestion.Matches, tt.wantMatchID)
+			}
+		})
+	}
+}
+
+func TestResolveSpecializedAreaSuggestion(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolution string
+	}{
+		{"accept", "accepted"},
+		{"map", "mapped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			admin := createTestUser(t, s, "admin")
+			owner := createTestUser(t, s, "user")
+			area := createTestArea(t, s, "Science")
+			other, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: fmt.Sprintf("Hydrology %d", fixtureSeq.Add(1))})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			withExpert := createSuggestingRequest(t, s, area, owner, fmt.Sprint(other), "Coral Reefs", "Drones")
+			alone := createSuggestingRequest(t, s, area, owner, "", "coral reefs")
+			untouched := createSuggestingRequest(t, s, area, owner, "", "Drones")
+			expert := createTestExpert(t, s, "From Request", "from.request@example.com", area)
+			if _, err := s.db.Exec("UPDATE experts SET specialized_area = ?, original_request_id = ? WHERE id = ?", fmt.Sprint(other), withExpert, expert); err != nil {
+				t.Fatal(err)
+			}
+
+			var result *domain.SuggestionResolution
+			var target int64
+			if tt.resolution == "accepted" {
+				result, err = s.AcceptSpecializedAreaSuggestion("Coral Reefs", &area, admin)
+				if result != nil {
+					target = result.SpecializedAreaID
+				}
+			} else {
+				target, err = s.CreateSpecializedArea(&domain.SpecializedArea{Name: fmt.Sprintf("Reef Ecology %d", fixtureSeq.Add(1))})
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err = s.MapSpecializedAreaSuggestion("Coral Reefs", target, admin)
+			}
+			if err != nil {
+				t.Fatalf("resolve suggestion: %v", err)
+			}
+			if result.Resolution != tt.resolution || result.RequestsUpdated != 2 || result.ExpertsUpdated != 1 {
+				t.Errorf("result = %+v, want 2 requests and 1 expert %s", result, tt.resolution)
+			}
+			if created, err := s.GetSpecializedArea(target); err != nil {
+				t.Fatalf("specialized area %d: %v", target, err)
+			} else if tt.resolution == "accepted" && (created.Name != "Coral Reefs" || created.GeneralAreaID == nil || *created.GeneralAreaID != area) {
+				t.Errorf("accepted area = %+v, want Coral Reefs under area %d", created, area)
+			}
+
+			wantRequests := []struct {
+				id          int64
+				areas       string
+				suggestions string
+			}{
+				{withExpert, fmt.Sprintf("%d,%d", other, target), "[Drones]"},
+				{alone, fmt.Sprint(target), "[]"},
+				{untouched, "", "[Drones]"},
+			}
+			for _, want := range wantRequests {
+				req, err := s.GetExpertRequest(want.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if req.SpecializedArea != want.areas || fmt.Sprint(req.SuggestedSpecializedAreas) != want.suggestions {
+					t.Errorf("request %d = %q with suggestions %v, want %q with %s",
+						want.id, req.SpecializedArea, req.SuggestedSpecializedAreas, want.areas, want.suggestions)
+				}
+			}
+			if updated, _ := s.GetExpert(expert); updated.SpecializedArea != fmt.Sprintf("%d,%d", other, target) {
+				t.Errorf("expert specialized areas = %q, want the resolved area added", updated.SpecializedArea)
+			}
+
+			resolutions, err := s.ListSuggestionResolutions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resolutions) != 1 || resolutions[0].SpecializedAreaID != target || resolutions[0].ResolvedBy != admin {
+				t.Errorf("resolutions = %+v, want one onto area %d by %d", resolutions, target, admin)
+			}
+
+			// Once resolved, the suggestion is gone
+			if _, err := s.MapSpecializedAreaSuggestion("Coral Reefs", target, admin); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("resolving twice: error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestMapSpecializedAreaSuggestionRejects(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	area := createTestArea(t, s, "Science")
+	createSuggestingRequest(t, s, area, admin, "", "Coral Reefs")
+	active, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: fmt.Sprintf("Reefs %d", fixtureSeq.Add(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retired, err := s.CreateSpecializedArea(&domain.SpecializedArea{Name: fmt.Sprintf("Old Reefs %d", fixtureSeq.Add(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetSpecializedAreaRetired(retired, true, "Superseded", admin); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		suggestion string
+		areaID     int64
+		notFound   bool
+	}{
+		{"retired area", "Coral Reefs", retired, false},
+		{"missing area", "Coral Reefs", retired + 1000, false},
+		{"unknown suggestion", "Kelp Forests", active, true},
+		{"empty suggestion", "  ", active, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.MapSpecializedAreaSuggestion(tt.suggestion, tt.areaID, admin)
+			if err == nil || errors.Is(err, domain.ErrNotFound) != tt.notFound {
+				t.Errorf("error = %v, want not found %v", err, tt.notFound)
+			}
+		})
+	}
+
+	if suggestions, _ := s.ListSpecializedAreaSuggestions(); len(suggestions) != 1 {
+		t.Errorf("%d suggestions pending after rejected mappings, want 1", len(suggestions))
+	}
+}
@@ -1,15 +1,19 @@
 package sqlite
 
 import (
+	"database/sql"
+	"errors"
 	"expertdb/internal/domain"
+	"expertdb/internal/logger"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ListSpecializedAreas retrieves all specialized areas
 func (s *SQLiteStore) ListSpecializedAreas() ([]*domain.SpecializedArea, error) {
 	query := `
-		SELECT id, name, created_at
+		SELECT id, name, general_area_id, retired_at, merged_into_id, created_at
 		FROM specialized_areas
 		ORDER BY name ASC
 	`
@@ -22,11 +26,11 @@ func (s *SQLiteStore) ListSpecializedAreas() ([]*domain.SpecializedArea, error)
 	
 	var areas []*domain.SpecializedArea
 	for rows.Next() {
-		var area domain.SpecializedArea
-		if err := rows.Scan(&area.ID, &area.Name, &area.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan specialized area: %w", err)
+		area, err := scanSpecializedArea(rows)
+		if err != nil {
+			return nil, err
 		}
-		areas = append(areas, &area)
+		areas = append(areas, area)
 	}
 	
 	if err := rows.Err(); err != nil {
@@ -36,6 +40,25 @@ func (s *SQLiteStore) ListSpecializedAreas() ([]*domain.SpecializedArea, error)
 	return areas, nil
 }
 
+// GetSpecializedArea retrieves a single specialized area by ID
+func (s *SQLiteStore) GetSpecializedArea(id int64) (*domain.SpecializedArea, error) {
+	row := s.db.QueryRow(`
+		SELECT id, name, general_area_id, retired_at, merged_into_id, created_at
+		FROM specialized_areas
+		WHERE id = ?
+	`, id)
+	
+	area, err := scanSpecializedArea(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	
+	return area, nil
+}
+
 // GetSpecializedAreasByIds retrieves specialized areas by their IDs
 func (s *SQLiteStore) GetSpecializedAreasByIds(ids []int64) ([]*domain.SpecializedArea, error) {
 	if len(ids) == 0 {
@@ -51,7 +74,7 @@ func (s *SQLiteStore) GetSpecializedAreasByIds(ids []int64) ([]*domain.Specializ
 	}
 	
 	query := fmt.Sprintf(`
-		SELECT id, name, created_at
+		SELECT id, name, general_area_id, retired_at, merged_into_id, created_at
 		FROM specialized_areas
 		WHERE id IN (%s)
 		ORDER BY name ASC
@@ -65,11 +88,11 @@ func (s *SQLiteStore) GetSpecializedAreasByIds(ids []int64) ([]*domain.Specializ
 	
 	var areas []*domain.SpecializedArea
 	for rows.Next() {
-		var area domain.SpecializedArea
-		if err := rows.Scan(&area.ID, &area.Name, &area.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan specialized area: %w", err)
+		area, err := scanSpecializedArea(rows)
+		if err != nil {
+			return nil, err
 		}
-		areas = append(areas, &area)
+		areas = append(areas, area)
 	}
 	
 	if err := rows.Err(); err != nil {
@@ -81,12 +104,17 @@ func (s *SQLiteStore) GetSpecializedAreasByIds(ids []int64) ([]*domain.Specializ
 
 // CreateSpecializedArea creates a new specialized area
 func (s *SQLiteStore) CreateSpecializedArea(area *domain.SpecializedArea) (int64, error) {
+	return createSpecializedArea(s.db, area)
+}
+
+// createSpecializedArea inserts a specialized area; ex is either the database or an open transaction
+func createSpecializedArea(ex execer, area *domain.SpecializedArea) (int64, error) {
 	query := `
-		INSERT INTO specialized_areas (name, created_at)
-		VALUES (?, CURRENT_TIMESTAMP)
+		INSERT INTO specialized_areas (name, general_area_id, created_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
 	`
 	
-	result, err := s.db.Exec(query, area.Name)
+	result, err := ex.Exec(query, area.Name, area.GeneralAreaID)
 	if err != nil {
 		// Check for unique constraint violation
 		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
@@ -101,4 +129,227 @@ func (s *SQLiteStore) CreateSpecializedArea(area *domain.SpecializedArea) (int64
 	}
 	
 	return id, nil
-}
\ No newline at end of file
+}
+
+// UpdateSpecializedAreaGeneralArea maps a specialized area to a general area; nil clears the mapping
+func (s *SQLiteStore) UpdateSpecializedAreaGeneralArea(id int64, generalAreaID *int64) error {
+	if generalAreaID != nil {
+		if _, err := s.GetArea(*generalAreaID); err != nil {
+			if err == domain.ErrNotFound {
+				return fmt.Errorf("general area %d does not exist", *generalAreaID)
+			}
+			return err
+		}
+	}
+	
+	result, err := s.db.Exec("UPDATE specialized_areas SET general_area_id = ? WHERE id = ?", generalAreaID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update specialized area mapping: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	
+	return nil
+}
+
+// scanSpecializedArea scans a specialized area row selected as
+// id, name, general_area_id, retired_at, merged_into_id, created_at
+func scanSpecializedArea(scanner interface{ Scan(...interface{}) error }) (*domain.SpecializedArea, error) {
+	var area domain.SpecializedArea
+	var generalAreaID, mergedIntoID sql.NullInt64
+	var retiredAt sql.NullTime
+	if err := scanner.Scan(&area.ID, &area.Name, &generalAreaID, &retiredAt, &mergedIntoID, &area.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan specialized area: %w", err)
+	}
+	if generalAreaID.Valid {
+		area.GeneralAreaID = &generalAreaID.Int64
+	}
+	if retiredAt.Valid {
+		area.RetiredAt = &retiredAt.Time
+	}
+	if mergedIntoID.Valid {
+		area.MergedIntoID = &mergedIntoID.Int64
+	}
+	return &area, nil
+}
+
+// MergeSpecializedAreas replaces the source specialized area with the target in every expert and
+// expert request in a single transaction, then retires the source
+func (s *SQLiteStore) MergeSpecializedAreas(sourceID, targetID, performedBy int64) (*domain.AreaMergeResult, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("a specialized area cannot be merged into itself")
+	}
+	
+	source, err := s.GetSpecializedArea(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.MergedIntoID != nil {
+		return nil, fmt.Errorf("specialized area '%s' has already been merged", source.Name)
+	}
+	
+	target, err := s.GetSpecializedArea(targetID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, fmt.Errorf("target specialized area %d does not exist", targetID)
+		}
+		return nil, err
+	}
+	if target.RetiredAt != nil {
+		return nil, fmt.Errorf("cannot merge into retired specialized area '%s'", target.Name)
+	}
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result := &domain.AreaMergeResult{SourceID: sourceID, TargetID: targetID}
+	now := time.Now()
+	reason := fmt.Sprintf("Specialized area '%s' merged into '%s'", source.Name, target.Name)
+	
+	// Rewrite expert specialized area lists, recording each change in the expert's history
+	type listUpdate struct {
+		id       int64
+		oldValue string
+		newValue string
+	}
+	var expertUpdates []listUpdate
+	rows, err := tx.Query("SELECT id, specialized_area FROM experts WHERE "+containsSpecializedAreaCondition("specialized_area"), sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find experts with specialized area: %w", err)
+	}
+	for rows.Next() {
+		var u listUpdate
+		if err := rows.Scan(&u.id, &u.oldValue); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expert specialized areas: %w", err)
+		}
+		u.newValue = replaceSpecializedAreaID(u.oldValue, sourceID, targetID)
+		expertUpdates = append(expertUpdates, u)
+	}
+	rows.Close()
+	
+	for _, u := range expertUpdates {
+		_, err = tx.Exec("UPDATE experts SET specialized_area = ?, updated_at = ?, last_edited_by = ?, last_edited_at = ? WHERE id = ?",
+			u.newValue, now, performedBy, now, u.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update expert %d: %w", u.id, err)
+		}
+		
+		err = s.createExpertEditHistoryWithReasonTx(tx, u.id, performedBy, []string{"specializedArea"},
+			map[string]interface{}{"specializedArea": u.oldValue}, map[string]interface{}{"specializedArea": u.newValue}, reason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit history: %w", err)
+		}
+	}
+	result.ExpertsUpdated = len(expertUpdates)
+	
+	// Rewrite expert request specialized area lists
+	var requestUpdates []listUpdate
+	rows, err = tx.Query("SELECT id, specialized_area FROM expert_requests WHERE "+containsSpecializedAreaCondition("specialized_area"), sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expert requests with specialized area: %w", err)
+	}
+	for rows.Next() {
+		var u listUpdate
+		if err := rows.Scan(&u.id, &u.oldValue); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expert request specialized areas: %w", err)
+		}
+		u.newValue = replaceSpecializedAreaID(u.oldValue, sourceID, targetID)
+		requestUpdates = append(requestUpdates, u)
+	}
+	rows.Close()
+	
+	for _, u := range requestUpdates {
+		if _, err := tx.Exec("UPDATE expert_requests SET specialized_area = ? WHERE id = ?", u.newValue, u.id); err != nil {
+			return nil, fmt.Errorf("failed to update expert request %d: %w", u.id, err)
+		}
+	}
+	result.RequestsUpdated = len(requestUpdates)
+	
+	_, err = tx.Exec("UPDATE specialized_areas SET retired_at = COALESCE(retired_at, ?), merged_into_id = ? WHERE id = ?", now, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire merged specialized area: %w", err)
+	}
+	
+	err = s.writeAreaAuditTx(tx, "specialized", sourceID, "merge", &targetID, map[string]interface{}{
+		"sourceName":      source.Name,
+		"targetName":      target.Name,
+		"expertsUpdated":  result.ExpertsUpdated,
+		"requestsUpdated": result.RequestsUpdated,
+	}, performedBy)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit specialized area merge: %w", err)
+	}
+	
+	logger.Get().Info("Merged specialized area %d into %d: %d experts, %d requests updated", sourceID, targetID, result.ExpertsUpdated, result.RequestsUpdated)
+	return result, nil
+}
+
+// SetSpecializedAreaRetired retires a specialized area (hiding it from new selections) or restores it
+// Existing experts and requests keep their reference either way
+func (s *SQLiteStore) SetSpecializedAreaRetired(id int64, retired bool, reason string, performedBy int64) error {
+	area, err := s.GetSpecializedArea(id)
+	if err != nil {
+		return err
+	}
+	
+	if retired == (area.RetiredAt != nil) {
+		return nil // Already in the requested state
+	}
+	if !retired && area.MergedIntoID != nil {
+		return fmt.Errorf("specialized area '%s' was merged and cannot be restored", area.Name)
+	}
+	
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	action := "restore"
+	var retiredAt interface{}
+	if retired {
+		action = "retire"
+		retiredAt = time.Now()
+	}
+	
+	if _, err := tx.Exec("UPDATE specialized_areas SET retired_at = ? WHERE id = ?", retiredAt, id); err != nil {
+		return fmt.Errorf("failed to update specialized area: %w", err)
+	}
+	
+	var expertCount int
+	err = tx.QueryRow("SELECT COUNT(*) FROM experts WHERE "+containsSpecializedAreaCondition("specialized_area"), id).Scan(&expertCount)
+	if err != nil {
+		return fmt.Errorf("failed to count experts with specialized area: %w", err)
+	}
+	
+	err = s.writeAreaAuditTx(tx, "specialized", id, action, nil, map[string]interface{}{
+		"name":          area.Name,
+		"reason":        reason,
+		"expertsInArea": expertCount,
+	}, performedBy)
+	if err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit specialized area %s: %w", action, err)
+	}
+	
+	logger.Get().Info("Specialized area %d %sd by user %d", id, action, performedBy)
+	return nil
+}
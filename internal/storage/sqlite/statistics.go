@@ -71,9 +71,10 @@ func (s *SQLiteStore) GetStatistics(years int) (*domain.Statistics, error) {
 	
 	// Get top areas
 	rows, err := s.db.Query(`
-		SELECT ea.name as area_name, COUNT(*) as count
+		SELECT COALESCE(eap.path, ea.name) as area_name, COUNT(*) as count
 		FROM experts e
 		JOIN expert_areas ea ON e.general_area = ea.id
+		LEFT JOIN expert_area_paths eap ON eap.id = ea.id
 		GROUP BY e.general_area
 		ORDER BY count DESC
 		LIMIT 10
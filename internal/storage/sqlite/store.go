@@ -8,13 +8,18 @@ import (
 	"path/filepath"
 	
 	_ "github.com/mattn/go-sqlite3"
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
 	"expertdb/internal/logger"
 	"expertdb/internal/storage"
 )
 
 // SQLiteStore implements the Storage interface with SQLite backend
 type SQLiteStore struct {
-	db *sql.DB
+	db             *sql.DB
+	approvalPolicy domain.ExpertRequestApprovalPolicy // Quorum rules for approving expert requests
+	requestSLA     domain.ExpertRequestSLA            // Review targets for expert request statuses
+	blobs          blobstore.Store                    // Where document files are kept
 }
 
 // Verify that SQLiteStore implements the Storage interface at compile time
@@ -40,13 +45,23 @@ func New(dbPath string) (*SQLiteStore, error) {
 	}
 	
 	// Create the store
+	// A single approval is enough until SetExpertRequestApprovalPolicy says otherwise
+	// Document files live in the default upload directory until SetBlobStore says otherwise
 	store := &SQLiteStore{
-		db: db,
+		db:             db,
+		approvalPolicy: domain.ExpertRequestApprovalPolicy{RequiredApprovals: 1},
+		requestSLA:     domain.DefaultExpertRequestSLA,
+		blobs:          blobstore.NewFileSystem(filepath.Join("data", "documents")),
 	}
 	
 	return store, nil
 }
 
+// SetBlobStore sets where document files are kept, for operations that remove or move them
+func (s *SQLiteStore) SetBlobStore(blobs blobstore.Store) {
+	s.blobs = blobs
+}
+
 // Close closes the database connection
 func (s *SQLiteStore) Close() error {
 	return s.db.Close()
@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"expertdb/internal/blobstore"
+	"expertdb/internal/domain"
+)
+
+// migrationsDir holds the goose migrations the schema is built from
+const migrationsDir = "../../../db/migrations/sqlite"
+
+// newTestStore returns a store on a fresh database with every migration applied
+// Document files are kept in a temporary directory. The database skips syncing to disk, which
+// tests don't need and which makes building the schema slow
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s, err := New(filepath.Join(t.TempDir(), "test.db") + "?_sync=OFF&_journal_mode=MEMORY")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	s.SetBlobStore(blobstore.NewFileSystem(t.TempDir()))
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations found in %s: %v", migrationsDir, err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up := strings.SplitN(string(data), "-- +goose Down", 2)[0]
+		up = strings.NewReplacer("-- +goose StatementBegin", "", "-- +goose StatementEnd", "").Replace(up)
+		if _, err := s.db.Exec(up); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(file), err)
+		}
+	}
+	return s
+}
+
+var fixtureSeq atomic.Int64
+
+// createTestUser adds an active user with the given role
+func createTestUser(t *testing.T, s *SQLiteStore, role string) int64 {
+	t.Helper()
+	n := fixtureSeq.Add(1)
+	id, err := s.CreateUser(&domain.User{
+		Name:         fmt.Sprintf("User %d", n),
+		Email:        fmt.Sprintf("user%d@example.com", n),
+		PasswordHash: "hash",
+		Role:         role,
+		IsActive:     true,
+	})
+	if err != nil {
+		t.Fatalf("create %s user: %v", role, err)
+	}
+	return id
+}
+
+// createTestArea adds a top-level general area; the migrations already seed areas, so names are numbered
+func createTestArea(t *testing.T, s *SQLiteStore, name string) int64 {
+	t.Helper()
+	name = fmt.Sprintf("%s %d", name, fixtureSeq.Add(1))
+	id, err := s.CreateArea(name, nil)
+	if err != nil {
+		t.Fatalf("create area %s: %v", name, err)
+	}
+	return id
+}
+
+// newTestExpertRequest returns a complete expert request in generalArea, created by createdBy
+// (0 for a self-nomination)
+func newTestExpertRequest(name string, generalArea, createdBy int64) *domain.ExpertRequest {
+	n := fixtureSeq.Add(1)
+	return &domain.ExpertRequest{
+		Name:           name,
+		Designation:    "Professor",
+		Affiliation:    "University of Bahrain",
+		Phone:          fmt.Sprintf("+973 3900 %04d", n),
+		Email:          fmt.Sprintf("expert%d@example.com", n),
+		IsBahraini:     true,
+		IsAvailable:    true,
+		Role:           "evaluator",
+		EmploymentType: "academic",
+		GeneralArea:    generalArea,
+		CreatedAt:      time.Now(),
+		CreatedBy:      createdBy,
+	}
+}
+
+// createTestExpertRequest stores req and returns its ID
+func createTestExpertRequest(t *testing.T, s *SQLiteStore, req *domain.ExpertRequest) int64 {
+	t.Helper()
+	id, err := s.CreateExpertRequest(req)
+	if err != nil {
+		t.Fatalf("create expert request %s: %v", req.Name, err)
+	}
+	return id
+}
+
+// createTestExpert adds an expert in generalArea
+func createTestExpert(t *testing.T, s *SQLiteStore, name, email string, generalArea int64) int64 {
+	t.Helper()
+	id, err := s.CreateExpert(&domain.Expert{
+		Name:           name,
+		Email:          email,
+		Phone:          fmt.Sprintf("+973 3800 %04d", fixtureSeq.Add(1)),
+		Designation:    "Engineer",
+		Affiliation:    "Bapco",
+		Role:           "evaluator",
+		EmploymentType: "employer",
+		GeneralArea:    generalArea,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("create expert %s: %v", name, err)
+	}
+	return id
+}
+
+// requestStatus returns the stored status of an expert request
+func requestStatus(t *testing.T, s *SQLiteStore, id int64) string {
+	t.Helper()
+	var status string
+	if err := s.db.QueryRow("SELECT status FROM expert_requests WHERE id = ?", id).Scan(&status); err != nil {
+		t.Fatalf("status of request %d: %v", id, err)
+	}
+	return status
+}
@@ -0,0 +1,491 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// legacyTrainingProgramName is the program that holds training recorded through the former is_trained flag
+const legacyTrainingProgramName = "Legacy Training"
+
+// trainingDateFormat is the storage format for training DATE columns so they compare against date('now')
+const trainingDateFormat = "2006-01-02"
+
+// validAttendanceCondition matches attendance rows (aliased ta) that currently count as training
+const validAttendanceCondition = `ta.status = 'attended' AND (ta.expires_at IS NULL OR ta.expires_at >= date('now'))`
+
+// expertIsTrainedExpr derives an expert's (aliased e) trained status from valid attendance records
+const expertIsTrainedExpr = `EXISTS (SELECT 1 FROM training_attendance ta WHERE ta.expert_id = e.id AND ` + validAttendanceCondition + `)`
+
+// ListTrainingPrograms retrieves all training programs
+func (s *SQLiteStore) ListTrainingPrograms() ([]*domain.TrainingProgram, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, description, validity_months, is_active, created_at
+		FROM training_programs
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list training programs: %w", err)
+	}
+	defer rows.Close()
+
+	var programs []*domain.TrainingProgram
+	for rows.Next() {
+		var program domain.TrainingProgram
+		var description sql.NullString
+		var createdAt sql.NullTime
+
+		if err := rows.Scan(&program.ID, &program.Name, &description, &program.ValidityMonths, &program.IsActive, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan training program: %w", err)
+		}
+
+		if description.Valid {
+			program.Description = description.String
+		}
+		if createdAt.Valid {
+			program.CreatedAt = createdAt.Time
+		}
+
+		programs = append(programs, &program)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating training program rows: %w", err)
+	}
+
+	return programs, nil
+}
+
+// GetTrainingProgram retrieves a training program by ID
+func (s *SQLiteStore) GetTrainingProgram(id int64) (*domain.TrainingProgram, error) {
+	var program domain.TrainingProgram
+	var description sql.NullString
+	var createdAt sql.NullTime
+
+	err := s.db.QueryRow(`
+		SELECT id, name, description, validity_months, is_active, created_at
+		FROM training_programs
+		WHERE id = ?
+	`, id).Scan(&program.ID, &program.Name, &description, &program.ValidityMonths, &program.IsActive, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get training program: %w", err)
+	}
+
+	if description.Valid {
+		program.Description = description.String
+	}
+	if createdAt.Valid {
+		program.CreatedAt = createdAt.Time
+	}
+
+	return &program, nil
+}
+
+// CreateTrainingProgram creates a new training program
+func (s *SQLiteStore) CreateTrainingProgram(program *domain.TrainingProgram) (int64, error) {
+	if strings.TrimSpace(program.Name) == "" {
+		return 0, fmt.Errorf("training program name cannot be empty")
+	}
+	if program.ValidityMonths < 0 {
+		return 0, fmt.Errorf("validity months cannot be negative")
+	}
+
+	// Check for a duplicate name (case-insensitive)
+	var exists bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM training_programs WHERE LOWER(name) = LOWER(?))", program.Name).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check for existing training program: %w", err)
+	}
+	if exists {
+		return 0, fmt.Errorf("training program with name '%s' already exists", program.Name)
+	}
+
+	program.CreatedAt = time.Now()
+	result, err := s.db.Exec(`
+		INSERT INTO training_programs (name, description, validity_months, is_active, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, program.Name, program.Description, program.ValidityMonths, program.IsActive, program.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create training program: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get training program ID: %w", err)
+	}
+
+	program.ID = id
+	return id, nil
+}
+
+// UpdateTrainingProgram updates an existing training program
+// Changing the validity period does not rewrite the expiry of existing attendance records
+func (s *SQLiteStore) UpdateTrainingProgram(program *domain.TrainingProgram) error {
+	current, err := s.GetTrainingProgram(program.ID)
+	if err != nil {
+		return err
+	}
+
+	if program.Name == "" {
+		program.Name = current.Name
+	}
+	if program.ValidityMonths < 0 {
+		return fmt.Errorf("validity months cannot be negative")
+	}
+
+	// Check the new name does not collide with another program
+	var exists bool
+	err = s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM training_programs WHERE LOWER(name) = LOWER(?) AND id != ?)", program.Name, program.ID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing training program: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("training program with name '%s' already exists", program.Name)
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE training_programs
+		SET name = ?, description = ?, validity_months = ?, is_active = ?
+		WHERE id = ?
+	`, program.Name, program.Description, program.ValidityMonths, program.IsActive, program.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update training program: %w", err)
+	}
+
+	return nil
+}
+
+// ListTrainingSessions retrieves the sessions of a training program, newest first
+// If programID is 0, it returns sessions for all programs
+func (s *SQLiteStore) ListTrainingSessions(programID int64) ([]*domain.TrainingSession, error) {
+	query := `
+		SELECT ts.id, ts.program_id, tp.name, ts.session_date, ts.location, ts.trainer, ts.notes, ts.created_at
+		FROM training_sessions ts
+		JOIN training_programs tp ON tp.id = ts.program_id
+	`
+	var params []interface{}
+	if programID > 0 {
+		query += " WHERE ts.program_id = ?"
+		params = append(params, programID)
+	}
+	query += " ORDER BY ts.session_date DESC, ts.id DESC"
+
+	rows, err := s.db.Query(query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list training sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.TrainingSession
+	for rows.Next() {
+		session, err := scanTrainingSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating training session rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetTrainingSession retrieves a training session by ID together with its attendance
+func (s *SQLiteStore) GetTrainingSession(id int64) (*domain.TrainingSession, error) {
+	row := s.db.QueryRow(`
+		SELECT ts.id, ts.program_id, tp.name, ts.session_date, ts.location, ts.trainer, ts.notes, ts.created_at
+		FROM training_sessions ts
+		JOIN training_programs tp ON tp.id = ts.program_id
+		WHERE ts.id = ?
+	`, id)
+
+	session, err := scanTrainingSession(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	attendance, err := s.queryTrainingAttendance("ta.session_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range attendance {
+		session.Attendance = append(session.Attendance, *record)
+	}
+
+	return session, nil
+}
+
+// CreateTrainingSession creates a new session for an active training program
+func (s *SQLiteStore) CreateTrainingSession(session *domain.TrainingSession) (int64, error) {
+	if session.SessionDate.IsZero() {
+		return 0, fmt.Errorf("session date is required")
+	}
+
+	program, err := s.GetTrainingProgram(session.ProgramID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return 0, fmt.Errorf("training program %d does not exist", session.ProgramID)
+		}
+		return 0, err
+	}
+	if !program.IsActive {
+		return 0, fmt.Errorf("training program '%s' is inactive", program.Name)
+	}
+
+	session.CreatedAt = time.Now()
+	result, err := s.db.Exec(`
+		INSERT INTO training_sessions (program_id, session_date, location, trainer, notes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, session.ProgramID, session.SessionDate.Format(trainingDateFormat), session.Location, session.Trainer, session.Notes, session.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create training session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get training session ID: %w", err)
+	}
+
+	session.ID = id
+	session.ProgramName = program.Name
+	return id, nil
+}
+
+// GetTrainingAttendance retrieves a single attendance record by ID
+func (s *SQLiteStore) GetTrainingAttendance(id int64) (*domain.TrainingAttendance, error) {
+	records, err := s.queryTrainingAttendance("ta.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return records[0], nil
+}
+
+// RecordTrainingAttendance records (or replaces) an expert's attendance at a session
+// When the attendance is marked attended without an explicit expiry, the expiry is derived
+// from the completion date and the program's validity period
+func (s *SQLiteStore) RecordTrainingAttendance(attendance *domain.TrainingAttendance) (int64, error) {
+	if attendance.Status == "" {
+		attendance.Status = "attended"
+	}
+	validStatuses := []string{"registered", "attended", "absent"}
+	if !containsString(validStatuses, attendance.Status) {
+		return 0, fmt.Errorf("invalid attendance status: %s", attendance.Status)
+	}
+
+	session, err := s.GetTrainingSession(attendance.SessionID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return 0, fmt.Errorf("training session %d does not exist", attendance.SessionID)
+		}
+		return 0, err
+	}
+
+	var expertExists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM experts WHERE id = ?)", attendance.ExpertID).Scan(&expertExists); err != nil {
+		return 0, fmt.Errorf("failed to check expert: %w", err)
+	}
+	if !expertExists {
+		return 0, fmt.Errorf("expert %d does not exist", attendance.ExpertID)
+	}
+
+	var completedAt, expiresAt interface{}
+	if attendance.Status == "attended" {
+		if attendance.CompletedAt == nil {
+			completed := session.SessionDate
+			attendance.CompletedAt = &completed
+		}
+		if attendance.ExpiresAt == nil {
+			program, err := s.GetTrainingProgram(session.ProgramID)
+			if err != nil {
+				return 0, err
+			}
+			if program.ValidityMonths > 0 {
+				expires := attendance.CompletedAt.AddDate(0, program.ValidityMonths, 0)
+				attendance.ExpiresAt = &expires
+			}
+		}
+		completedAt = attendance.CompletedAt.Format(trainingDateFormat)
+		if attendance.ExpiresAt != nil {
+			expiresAt = attendance.ExpiresAt.Format(trainingDateFormat)
+		}
+	} else {
+		// Only completed training carries completion and expiry dates
+		attendance.CompletedAt = nil
+		attendance.ExpiresAt = nil
+	}
+
+	attendance.CreatedAt = time.Now()
+	_, err = s.db.Exec(`
+		INSERT INTO training_attendance (
+			session_id, expert_id, status, completed_at, expires_at, certificate_document_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, expert_id) DO UPDATE SET
+			status = excluded.status,
+			completed_at = excluded.completed_at,
+			expires_at = excluded.expires_at,
+			certificate_document_id = COALESCE(excluded.certificate_document_id, training_attendance.certificate_document_id)
+	`, attendance.SessionID, attendance.ExpertID, attendance.Status, completedAt, expiresAt,
+		attendance.CertificateDocumentID, attendance.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record training attendance: %w", err)
+	}
+
+	// LastInsertId is not reliable for upserts, so look the row up by its natural key
+	var id int64
+	err = s.db.QueryRow("SELECT id FROM training_attendance WHERE session_id = ? AND expert_id = ?",
+		attendance.SessionID, attendance.ExpertID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get training attendance ID: %w", err)
+	}
+
+	attendance.ID = id
+	return id, nil
+}
+
+// DeleteTrainingAttendance deletes an attendance record
+func (s *SQLiteStore) DeleteTrainingAttendance(id int64) error {
+	result, err := s.db.Exec("DELETE FROM training_attendance WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete training attendance: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListExpertTrainingRecords retrieves all attendance records of an expert, newest first
+func (s *SQLiteStore) ListExpertTrainingRecords(expertID int64) ([]*domain.TrainingAttendance, error) {
+	return s.queryTrainingAttendance("ta.expert_id = ?", expertID)
+}
+
+// recordLegacyTrainingTx records attendance of the legacy program for an expert whose
+// training was only declared as a flag (e.g. on an approved expert request)
+func (s *SQLiteStore) recordLegacyTrainingTx(tx *sql.Tx, expertID int64) error {
+	_, err := tx.Exec(`
+		INSERT OR IGNORE INTO training_attendance (session_id, expert_id, status, completed_at, created_at)
+		SELECT ts.id, ?, 'attended', ?, ?
+		FROM training_sessions ts
+		JOIN training_programs tp ON tp.id = ts.program_id
+		WHERE tp.name = ?
+		ORDER BY ts.id
+		LIMIT 1
+	`, expertID, time.Now().Format(trainingDateFormat), time.Now(), legacyTrainingProgramName)
+	if err != nil {
+		return fmt.Errorf("failed to record legacy training: %w", err)
+	}
+	return nil
+}
+
+// queryTrainingAttendance runs the shared attendance query with the given condition
+func (s *SQLiteStore) queryTrainingAttendance(condition string, args ...interface{}) ([]*domain.TrainingAttendance, error) {
+	query := `
+		SELECT ta.id, ta.session_id, ta.expert_id, e.name, ts.program_id, tp.name, ts.session_date,
+		       ta.status, ta.completed_at, ta.expires_at, ta.certificate_document_id,
+		       (` + validAttendanceCondition + `) AS is_valid, ta.created_at
+		FROM training_attendance ta
+		JOIN training_sessions ts ON ts.id = ta.session_id
+		JOIN training_programs tp ON tp.id = ts.program_id
+		JOIN experts e ON e.id = ta.expert_id
+		WHERE ` + condition + `
+		ORDER BY ts.session_date DESC, ta.id DESC
+	`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query training attendance: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*domain.TrainingAttendance
+	for rows.Next() {
+		var record domain.TrainingAttendance
+		var completedAt, expiresAt, createdAt sql.NullTime
+		var certificateDocumentID sql.NullInt64
+
+		err := rows.Scan(
+			&record.ID, &record.SessionID, &record.ExpertID, &record.ExpertName,
+			&record.ProgramID, &record.ProgramName, &record.SessionDate,
+			&record.Status, &completedAt, &expiresAt, &certificateDocumentID,
+			&record.IsValid, &createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan training attendance: %w", err)
+		}
+
+		if completedAt.Valid {
+			record.CompletedAt = &completedAt.Time
+		}
+		if expiresAt.Valid {
+			record.ExpiresAt = &expiresAt.Time
+		}
+		if certificateDocumentID.Valid {
+			record.CertificateDocumentID = &certificateDocumentID.Int64
+		}
+		if createdAt.Valid {
+			record.CreatedAt = createdAt.Time
+		}
+
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating training attendance rows: %w", err)
+	}
+
+	return records, nil
+}
+
+// scanTrainingSession scans a training session row from either *sql.Row or *sql.Rows
+func scanTrainingSession(scanner interface{ Scan(...interface{}) error }) (*domain.TrainingSession, error) {
+	var session domain.TrainingSession
+	var location, trainer, notes sql.NullString
+	var createdAt sql.NullTime
+
+	err := scanner.Scan(
+		&session.ID, &session.ProgramID, &session.ProgramName, &session.SessionDate,
+		&location, &trainer, &notes, &createdAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan training session: %w", err)
+	}
+
+	if location.Valid {
+		session.Location = location.String
+	}
+	if trainer.Valid {
+		session.Trainer = trainer.String
+	}
+	if notes.Valid {
+		session.Notes = notes.String
+	}
+	if createdAt.Valid {
+		session.CreatedAt = createdAt.Time
+	}
+
+	return &session, nil
+}
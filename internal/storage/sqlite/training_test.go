@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+func TestExpertTrainedFromAttendance(t *testing.T) {
+	today := time.Now()
+	tests := []struct {
+		name           string
+		validityMonths int
+		sessionDate    time.Time
+		status         string
+		wantTrained    bool
+	}{
+		{"attended recently", 12, today.AddDate(0, -1, 0), "attended", true},
+		{"attended, expired", 12, today.AddDate(-2, 0, 0), "attended", false},
+		{"attended, never expires", 0, today.AddDate(-5, 0, 0), "attended", true},
+		{"registered", 12, today.AddDate(0, 0, 7), "registered", false},
+		{"absent", 12, today.AddDate(0, -1, 0), "absent", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			n := fixtureSeq.Add(1)
+			expertID := createTestExpert(t, s, "Trainee", fmt.Sprintf("trainee%d@example.com", n), createTestArea(t, s, "Engineering"))
+			programID, err := s.CreateTrainingProgram(&domain.TrainingProgram{
+				Name: fmt.Sprintf("Evaluator training %d", n), ValidityMonths: tt.validityMonths, IsActive: true,
+			})
+			if err != nil {
+				t.Fatalf("create program: %v", err)
+			}
+			sessionID, err := s.CreateTrainingSession(&domain.TrainingSession{ProgramID: programID, SessionDate: tt.sessionDate})
+			if err != nil {
+				t.Fatalf("create session: %v", err)
+			}
+
+			attendance := &domain.TrainingAttendance{SessionID: sessionID, ExpertID: expertID, Status: tt.status}
+			if _, err := s.RecordTrainingAttendance(attendance); err != nil {
+				t.Fatalf("RecordTrainingAttendance: %v", err)
+			}
+			if tt.status == "attended" {
+				wantExpiry := tt.validityMonths > 0
+				if attendance.CompletedAt == nil || (attendance.ExpiresAt != nil) != wantExpiry {
+					t.Errorf("completed %v, expires %v; want completion on the session date and expiry %v",
+						attendance.CompletedAt, attendance.ExpiresAt, wantExpiry)
+				}
+			} else if attendance.CompletedAt != nil || attendance.ExpiresAt != nil {
+				t.Errorf("%s attendance carries completion dates", tt.status)
+			}
+
+			expert, err := s.GetExpert(expertID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expert.IsTrained != tt.wantTrained {
+				t.Errorf("expert trained = %v, want %v", expert.IsTrained, tt.wantTrained)
+			}
+			records, err := s.ListExpertTrainingRecords(expertID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 || records[0].IsValid != tt.wantTrained {
+				t.Errorf("training records = %+v, want one with valid %v", records, tt.wantTrained)
+			}
+		})
+	}
+}
+
+func TestRecordTrainingAttendanceReplaces(t *testing.T) {
+	s := newTestStore(t)
+	expertID := createTestExpert(t, s, "Trainee", "replaced@example.com", createTestArea(t, s, "Engineering"))
+	programID, err := s.CreateTrainingProgram(&domain.TrainingProgram{Name: "Validator training", ValidityMonths: 24, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID, err := s.CreateTrainingSession(&domain.TrainingSession{ProgramID: programID, SessionDate: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.RecordTrainingAttendance(&domain.TrainingAttendance{SessionID: sessionID, ExpertID: expertID, Status: "registered"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.RecordTrainingAttendance(&domain.TrainingAttendance{SessionID: sessionID, ExpertID: expertID, Status: "attended"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("attendance recorded as %d then %d, want the record replaced", first, second)
+	}
+	if expert, _ := s.GetExpert(expertID); !expert.IsTrained {
+		t.Error("expert not trained after attending")
+	}
+}
+
+func TestApprovedTrainedRequestRecordsLegacyTraining(t *testing.T) {
+	s := newTestStore(t)
+	admin := createTestUser(t, s, "admin")
+	req := newTestExpertRequest("Declared trained", createTestArea(t, s, "Engineering"), createTestUser(t, s, "user"))
+	req.IsTrained = true
+	id := createTestExpertRequest(t, s, req)
+
+	result, err := s.ApproveExpertRequestWithDocument(id, admin, nil)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	records, err := s.ListExpertTrainingRecords(result.ExpertID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ProgramName != legacyTrainingProgramName || !records[0].IsValid {
+		t.Errorf("training records = %+v, want valid legacy training", records)
+	}
+}
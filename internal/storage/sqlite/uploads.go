@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+const uploadSessionColumns = `id, document_type, filename, content_type, file_size, sha256, received, status,
+	created_by, created_at, updated_at, expires_at`
+
+// scanUploadSession reads an upload session row selected with uploadSessionColumns
+func scanUploadSession(row interface{ Scan(...interface{}) error }) (*domain.UploadSession, error) {
+	var upload domain.UploadSession
+	if err := row.Scan(&upload.ID, &upload.DocumentType, &upload.Filename, &upload.ContentType, &upload.FileSize,
+		&upload.SHA256, &upload.Received, &upload.Status, &upload.CreatedBy, &upload.CreatedAt, &upload.UpdatedAt,
+		&upload.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// CreateUploadSession starts a resumable upload
+func (s *SQLiteStore) CreateUploadSession(upload *domain.UploadSession) (int64, error) {
+	now := time.Now()
+	upload.CreatedAt = now
+	upload.UpdatedAt = now
+	upload.Received = 0
+	upload.Status = domain.UploadStatusUploading
+
+	result, err := s.db.Exec(`
+		INSERT INTO upload_sessions (document_type, filename, content_type, file_size, sha256, received, status,
+			created_by, created_at, updated_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)
+	`, upload.DocumentType, upload.Filename, upload.ContentType, upload.FileSize, upload.SHA256, upload.Status,
+		upload.CreatedBy, upload.CreatedAt, upload.UpdatedAt, upload.ExpiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create upload session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get upload session ID: %w", err)
+	}
+	upload.ID = id
+
+	return id, nil
+}
+
+// GetUploadSession retrieves an upload session by ID
+func (s *SQLiteStore) GetUploadSession(id int64) (*domain.UploadSession, error) {
+	row := s.db.QueryRow("SELECT "+uploadSessionColumns+" FROM upload_sessions WHERE id = ?", id)
+
+	upload, err := scanUploadSession(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	return upload, nil
+}
+
+// AddUploadChunk records a received chunk and advances its upload past it
+// It reports false without recording anything when the upload is no longer receiving chunks or has
+// already received bytes beyond the chunk's offset, e.g. from a retried request
+func (s *SQLiteStore) AddUploadChunk(chunk *domain.UploadChunk) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE upload_sessions SET received = received + ?, updated_at = ?
+		WHERE id = ? AND received = ? AND status = ?
+	`, chunk.Size, time.Now(), chunk.UploadID, chunk.Offset, domain.UploadStatusUploading)
+	if err != nil {
+		return false, fmt.Errorf("failed to update upload session: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("failed to check upload session update: %w", err)
+	} else if rows == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO upload_chunks (upload_id, chunk_offset, size, file_path) VALUES (?, ?, ?, ?)
+	`, chunk.UploadID, chunk.Offset, chunk.Size, chunk.FilePath); err != nil {
+		return false, fmt.Errorf("failed to record upload chunk: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit upload chunk: %w", err)
+	}
+	return true, nil
+}
+
+// listUploadChunks returns the chunks matching a WHERE clause, in upload and offset order
+func (s *SQLiteStore) listUploadChunks(where string, args ...interface{}) ([]*domain.UploadChunk, error) {
+	rows, err := s.db.Query("SELECT upload_id, chunk_offset, size, file_path FROM upload_chunks "+where+
+		" ORDER BY upload_id, chunk_offset", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upload chunks: %w", err)
+	}
+	defer rows.Close()
+
+	chunks := []*domain.UploadChunk{}
+	for rows.Next() {
+		var chunk domain.UploadChunk
+		if err := rows.Scan(&chunk.UploadID, &chunk.Offset, &chunk.Size, &chunk.FilePath); err != nil {
+			return nil, fmt.Errorf("failed to scan upload chunk: %w", err)
+		}
+		chunks = append(chunks, &chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// ListUploadChunks returns the chunks of an upload in file order
+func (s *SQLiteStore) ListUploadChunks(uploadID int64) ([]*domain.UploadChunk, error) {
+	return s.listUploadChunks("WHERE upload_id = ?", uploadID)
+}
+
+// ListAllUploadChunks returns the chunks of every upload session
+func (s *SQLiteStore) ListAllUploadChunks() ([]*domain.UploadChunk, error) {
+	return s.listUploadChunks("")
+}
+
+// CompleteUploadSession marks an upload whose content has been verified as complete
+func (s *SQLiteStore) CompleteUploadSession(id int64) error {
+	result, err := s.db.Exec(`
+		UPDATE upload_sessions SET status = ?, updated_at = ? WHERE id = ?
+	`, domain.UploadStatusComplete, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to complete upload session: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check upload session update: %w", err)
+	} else if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteUploadSession removes an upload session and the records of its chunks
+// The chunk files are removed by the caller
+func (s *SQLiteStore) DeleteUploadSession(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM upload_chunks WHERE upload_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete upload chunks: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM upload_sessions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check upload session deletion: %w", err)
+	} else if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit upload session deletion: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredUploadSessions returns the upload sessions that expired before now
+func (s *SQLiteStore) ListExpiredUploadSessions(now time.Time) ([]*domain.UploadSession, error) {
+	rows, err := s.db.Query("SELECT "+uploadSessionColumns+" FROM upload_sessions WHERE expires_at < ? ORDER BY id", now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired upload sessions: %w", err)
+	}
+	defer rows.Close()
+
+	uploads := []*domain.UploadSession{}
+	for rows.Next() {
+		upload, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan upload session: %w", err)
+		}
+		uploads = append(uploads, upload)
+	}
+	return uploads, rows.Err()
+}
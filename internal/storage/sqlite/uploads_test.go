@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"expertdb/internal/domain"
+)
+
+// createTestUploadSession starts an upload of size bytes expiring at expires
+func createTestUploadSession(t *testing.T, s *SQLiteStore, createdBy, size int64, expires time.Time) int64 {
+	t.Helper()
+	id, err := s.CreateUploadSession(&domain.UploadSession{
+		DocumentType: domain.DocumentTypeCV,
+		Filename:     "cv.pdf",
+		ContentType:  "application/pdf",
+		FileSize:     size,
+		SHA256:       "aaaa",
+		CreatedBy:    createdBy,
+		ExpiresAt:    expires,
+	})
+	if err != nil {
+		t.Fatalf("create upload session: %v", err)
+	}
+	return id
+}
+
+func TestAddUploadChunkOffsets(t *testing.T) {
+	s := newTestStore(t)
+	id := createTestUploadSession(t, s, createTestUser(t, s, "user"), 10, time.Now().Add(time.Hour))
+
+	steps := []struct {
+		name     string
+		offset   int64
+		size     int64
+		added    bool
+		received int64
+	}{
+		{"first chunk", 0, 4, true, 4},
+		{"retried chunk", 0, 4, false, 4},
+		{"gap", 6, 4, false, 4},
+		{"last chunk", 4, 6, true, 10},
+	}
+	for _, step := range steps {
+		added, err := s.AddUploadChunk(&domain.UploadChunk{
+			UploadID: id, Offset: step.offset, Size: step.size, FilePath: fmt.Sprintf("uploads/%d/%012d", id, step.offset),
+		})
+		if err != nil || added != step.added {
+			t.Errorf("%s: AddUploadChunk = %v, %v, want %v", step.name, added, err, step.added)
+		}
+		if upload, _ := s.GetUploadSession(id); upload.Received != step.received {
+			t.Errorf("%s: received %d, want %d", step.name, upload.Received, step.received)
+		}
+	}
+
+	chunks, err := s.ListUploadChunks(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 2 || chunks[0].Offset != 0 || chunks[1].Offset != 4 || chunks[1].Size != 6 {
+		t.Errorf("chunks = %+v, want offsets 0 and 4", chunks)
+	}
+
+	// A complete upload accepts no more chunks
+	if err := s.CompleteUploadSession(id); err != nil {
+		t.Fatal(err)
+	}
+	if upload, _ := s.GetUploadSession(id); upload.Status != domain.UploadStatusComplete {
+		t.Errorf("status = %s, want complete", upload.Status)
+	}
+	if added, err := s.AddUploadChunk(&domain.UploadChunk{UploadID: id, Offset: 10, Size: 1, FilePath: "uploads/late"}); err != nil || added {
+		t.Errorf("chunk after completion: AddUploadChunk = %v, %v, want false", added, err)
+	}
+	if err := s.CompleteUploadSession(id + 1000); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("completing a missing upload: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteUploadSession(t *testing.T) {
+	s := newTestStore(t)
+	user := createTestUser(t, s, "user")
+	expired := createTestUploadSession(t, s, user, 10, time.Now().Add(-time.Minute))
+	active := createTestUploadSession(t, s, user, 10, time.Now().Add(time.Hour))
+	for _, id := range []int64{expired, active} {
+		if _, err := s.AddUploadChunk(&domain.UploadChunk{UploadID: id, Size: 5, FilePath: fmt.Sprintf("uploads/%d/0", id)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uploads, err := s.ListExpiredUploadSessions(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].ID != expired {
+		t.Fatalf("expired uploads = %+v, want upload %d", uploads, expired)
+	}
+
+	if err := s.DeleteUploadSession(expired); err != nil {
+		t.Fatalf("DeleteUploadSession: %v", err)
+	}
+	if err := s.DeleteUploadSession(expired); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("deleting twice: error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetUploadSession(expired); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("deleted upload: error = %v, want ErrNotFound", err)
+	}
+	chunks, err := s.ListAllUploadChunks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].UploadID != active {
+		t.Errorf("chunks = %+v, want only those of upload %d", chunks, active)
+	}
+}
@@ -0,0 +1,72 @@
+package textextract
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// extractDOCX reads the text of the main document part of a Word file
+// Paragraphs and line breaks become new lines, tabs become spaces
+func extractDOCX(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to open Word document: %w", err)
+	}
+
+	var part *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			part = f
+			break
+		}
+	}
+	if part == nil {
+		return "", fmt.Errorf("Word document has no word/document.xml")
+	}
+
+	rc, err := part.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read Word document: %w", err)
+	}
+	defer rc.Close()
+
+	var text strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse Word document: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
@@ -0,0 +1,458 @@
+package textextract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxPDFSize bounds the PDFs read into memory for extraction
+const maxPDFSize = 64 << 20
+
+// Decompressed data is cut off at maxStreamSize per stream and maxDecodedSize per document,
+// well above the text that is kept but small enough that compression bombs cannot exhaust memory
+const (
+	maxStreamSize  = 16 << 20
+	maxDecodedSize = 64 << 20
+)
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// pdfDoc holds the objects of a parsed PDF file
+// Only what text extraction needs is supported: plain and compressed object streams, Flate and
+// ASCII85 filters, ToUnicode maps and simple font encodings. Encrypted files are rejected
+type pdfDoc struct {
+	objects  map[int]interface{}
+	trailers []pdfDict
+	fonts    map[pdfRef]*pdfFont
+	decoded  int // Bytes produced by decompression so far
+}
+
+// extractPDF returns the text of every page of a PDF in page order
+func extractPDF(r io.ReaderAt, size int64) (string, error) {
+	if size > maxPDFSize {
+		return "", fmt.Errorf("PDF is too large for text extraction")
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read PDF: %w", err)
+	}
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return "", fmt.Errorf("not a PDF file")
+	}
+
+	doc := parsePDF(data)
+	for _, trailer := range doc.trailers {
+		if _, encrypted := trailer["Encrypt"]; encrypted {
+			return "", fmt.Errorf("PDF is encrypted")
+		}
+	}
+
+	var text strings.Builder
+	for _, page := range doc.pages() {
+		doc.pageText(page, &text)
+		text.WriteByte('\n')
+	}
+	return text.String(), nil
+}
+
+// parsePDF collects every object in data
+// Objects are located by their "n g obj" headers rather than the cross-reference table, which also
+// copes with damaged files; later definitions replace earlier ones, as incremental updates do
+func parsePDF(data []byte) *pdfDoc {
+	doc := &pdfDoc{objects: map[int]interface{}{}, fonts: map[pdfRef]*pdfFont{}}
+
+	cursor := 0
+	for _, m := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		if m[0] < cursor {
+			continue // Inside the data of the previous stream
+		}
+		num := atoi(data[m[2]:m[3]])
+		l := &pdfLexer{data: data, pos: m[1], refs: true}
+		value, ok := l.object()
+		if !ok {
+			break
+		}
+		cursor = l.pos
+
+		if dict, isDict := value.(pdfDict); isDict {
+			l.skipSpace()
+			if bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+				stream := &pdfStream{dict: dict}
+				stream.raw, cursor = streamData(data, l.pos+len("stream"), dict)
+				value = stream
+				if dict["Type"] == pdfName("XRef") {
+					doc.trailers = append(doc.trailers, dict)
+				}
+			}
+		}
+		doc.objects[num] = value
+	}
+
+	// Classic trailers follow the cross-reference table
+	for _, i := range regexp.MustCompile(`trailer\s*<<`).FindAllIndex(data, -1) {
+		l := &pdfLexer{data: data, pos: i[0] + len("trailer"), refs: true}
+		if dict, ok := l.object(); ok {
+			if d, isDict := dict.(pdfDict); isDict {
+				doc.trailers = append(doc.trailers, d)
+			}
+		}
+	}
+
+	doc.loadObjectStreams()
+	return doc
+}
+
+// streamData returns the raw data of a stream starting at start and the offset just past it
+func streamData(data []byte, start int, dict pdfDict) ([]byte, int) {
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	// Trust a direct Length when "endstream" follows it; otherwise search for the end
+	// The length is compared as a float so that huge values cannot overflow int
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && n <= float64(len(data)-start) {
+		end := start + int(n)
+		rest := bytes.TrimLeft(data[end:], "\x00\t\r\n\f ")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return data[start:end], end
+		}
+	}
+	i := bytes.Index(data[start:], []byte("endstream"))
+	if i < 0 {
+		return data[start:], len(data)
+	}
+	end := start + i
+	return bytes.TrimRight(data[start:end], "\r\n"), end
+}
+
+// loadObjectStreams adds objects stored inside compressed object streams
+func (d *pdfDoc) loadObjectStreams() {
+	for _, value := range d.objects {
+		stream, ok := value.(*pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := d.decode(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := stream.dict["N"].(float64)
+		first, _ := stream.dict["First"].(float64)
+
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(n); i++ {
+			num, ok1 := header.token()
+			offset, ok2 := header.token()
+			objNum, isNum := num.(float64)
+			objOffset, isOffset := offset.(float64)
+			if !ok1 || !ok2 || !isNum || !isOffset {
+				break
+			}
+			if _, defined := d.objects[int(objNum)]; defined {
+				continue
+			}
+			start := first + objOffset
+			if start < 0 || start >= float64(len(data)) {
+				continue
+			}
+			l := &pdfLexer{data: data, pos: int(start), refs: true}
+			if obj, ok := l.object(); ok {
+				d.objects[int(objNum)] = obj
+			}
+		}
+	}
+}
+
+// resolve follows references to the object they point at
+func (d *pdfDoc) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+// dict resolves v and returns it as a dictionary, using a stream's dictionary for streams
+func (d *pdfDoc) dict(v interface{}) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.dict
+	}
+	return nil
+}
+
+// decode applies a stream's filters to its data
+func (d *pdfDoc) decode(stream *pdfStream) ([]byte, error) {
+	var filters []pdfName
+	switch f := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []pdfName{f}
+	case pdfArray:
+		for _, name := range f {
+			if n, ok := d.resolve(name).(pdfName); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+
+	data := stream.raw
+	for _, filter := range filters {
+		switch filter {
+		case "FlateDecode", "Fl":
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			limit := maxStreamSize
+			if remaining := maxDecodedSize - d.decoded; remaining < limit {
+				limit = remaining
+			}
+			decoded, err := io.ReadAll(io.LimitReader(zr, int64(limit)))
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && len(decoded) == 0 {
+				return nil, err
+			}
+			d.decoded += len(decoded)
+			data = decoded
+		case "ASCII85Decode", "A85":
+			trimmed := bytes.TrimSuffix(bytes.TrimSpace(data), []byte("~>"))
+			decoded := make([]byte, len(trimmed))
+			n, _, err := ascii85.Decode(decoded, bytes.TrimPrefix(trimmed, []byte("<~")), true)
+			if err != nil {
+				return nil, err
+			}
+			data = decoded[:n]
+		default:
+			return nil, fmt.Errorf("unsupported PDF filter %s", filter)
+		}
+	}
+	return data, nil
+}
+
+// pdfPage is a page dictionary with the resources it inherits
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the document's pages in order
+// Files without a usable page tree fall back to every page object in object number order
+func (d *pdfDoc) pages() []pdfPage {
+	var root pdfDict
+	for _, trailer := range d.trailers {
+		if root = d.dict(trailer["Root"]); root != nil {
+			break
+		}
+	}
+	if root == nil {
+		for _, value := range d.objects {
+			if dict, ok := value.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				root = dict
+				break
+			}
+		}
+	}
+
+	var pages []pdfPage
+	if root != nil {
+		d.walkPages(root["Pages"], nil, map[int]bool{}, &pages)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	var nums []int
+	for num, value := range d.objects {
+		if dict, ok := value.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict := d.objects[num].(pdfDict)
+		pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+	}
+	return pages
+}
+
+// walkPages appends the pages below node, passing inherited resources down the tree
+func (d *pdfDoc) walkPages(node interface{}, inherited pdfDict, seen map[int]bool, pages *[]pdfPage) {
+	if ref, ok := node.(pdfRef); ok {
+		if seen[ref.num] {
+			return
+		}
+		seen[ref.num] = true
+	}
+	dict := d.dict(node)
+	if dict == nil {
+		return
+	}
+
+	resources := inherited
+	if own := d.dict(dict["Resources"]); own != nil {
+		resources = own
+	}
+
+	if kids, ok := d.resolve(dict["Kids"]).(pdfArray); ok {
+		for _, kid := range kids {
+			d.walkPages(kid, resources, seen, pages)
+		}
+		return
+	}
+	*pages = append(*pages, pdfPage{dict: dict, resources: resources})
+}
+
+// pageText appends the text shown by a page's content streams
+func (d *pdfDoc) pageText(page pdfPage, out *strings.Builder) {
+	var content []byte
+	var streams []interface{}
+	switch c := d.resolve(page.dict["Contents"]).(type) {
+	case *pdfStream:
+		streams = append(streams, c)
+	case pdfArray:
+		streams = c
+	}
+	for _, s := range streams {
+		stream, ok := d.resolve(s).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decode(stream)
+		if err != nil {
+			continue
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+	}
+
+	fonts := map[pdfName]*pdfFont{}
+	if fontDict := d.dict(page.resources["Font"]); fontDict != nil {
+		for name, ref := range fontDict {
+			fonts[name] = d.font(ref)
+		}
+	}
+
+	showText(content, fonts, out)
+}
+
+// showText interprets the text operators of a content stream
+// Line breaks are inferred from vertical moves and word gaps from large TJ adjustments
+func showText(content []byte, fonts map[pdfName]*pdfFont, out *strings.Builder) {
+	l := &pdfLexer{data: content}
+	var operands []interface{}
+	var font *pdfFont
+	var lastY float64
+	hasY := false
+
+	for {
+		tok, ok := l.token()
+		if !ok {
+			return
+		}
+		op, isOp := tok.(pdfKeyword)
+		if !isOp || op == "[" || op == "<<" {
+			operands = append(operands, l.build(tok))
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					font = fonts[name]
+				}
+			}
+		case "Tj":
+			if s, ok := lastString(operands); ok {
+				out.WriteString(font.decode(s))
+			}
+		case "'", "\"":
+			out.WriteByte('\n')
+			if s, ok := lastString(operands); ok {
+				out.WriteString(font.decode(s))
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				if arr, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, el := range arr {
+						switch v := el.(type) {
+						case []byte:
+							out.WriteString(font.decode(v))
+						case float64:
+							if v < -180 {
+								out.WriteByte(' ')
+							}
+						}
+					}
+				}
+			}
+		case "T*":
+			out.WriteByte('\n')
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[len(operands)-1].(float64); ok && ty != 0 {
+					out.WriteByte('\n')
+				} else {
+					out.WriteByte(' ')
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[len(operands)-1].(float64); ok {
+					if hasY && y != lastY {
+						out.WriteByte('\n')
+					} else if hasY {
+						out.WriteByte(' ')
+					}
+					lastY, hasY = y, true
+				}
+			}
+		case "ID":
+			skipInlineImage(l)
+		}
+		operands = operands[:0]
+	}
+}
+
+// lastString returns the final operand when it is a string
+func lastString(operands []interface{}) ([]byte, bool) {
+	if len(operands) == 0 {
+		return nil, false
+	}
+	s, ok := operands[len(operands)-1].([]byte)
+	return s, ok
+}
+
+// skipInlineImage moves past the binary data of an inline image, which ends at an "EI" operator
+func skipInlineImage(l *pdfLexer) {
+	for i := l.pos; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && i > 0 && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+func atoi(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
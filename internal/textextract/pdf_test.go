@@ -0,0 +1,242 @@
+package textextract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a PDF from numbered object bodies, with object 1 as the catalog
+// Stream bodies are built with streamObject
+func buildPDF(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, body := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\n%%%%EOF\n", len(objects)+1)
+	return b.Bytes()
+}
+
+// streamObject returns a stream object body with a correct Length
+func streamObject(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+// onePagePDF returns a document whose single page shows content with the font /F1
+func onePagePDF(content []byte, font string) []byte {
+	return buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", content),
+		font,
+	)
+}
+
+const helvetica = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+
+func extract(t *testing.T, pdf []byte) string {
+	t.Helper()
+	text, err := Extract(bytes.NewReader(pdf), int64(len(pdf)), ContentTypePDF)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	return text
+}
+
+func TestExtractPDFText(t *testing.T) {
+	content := []byte(`BT /F1 12 Tf 72 720 Td (Jane Doe) Tj 0 -14 Td [(Senior)-250(Engin)10(eer)] TJ
+T* (caf\351 \(Bahrain\)) Tj ET`)
+	got := extract(t, onePagePDF(content, helvetica))
+	want := "Jane Doe\nSenior Engineer\ncafé (Bahrain)"
+	if got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
+
+func TestExtractPDFPagesInOrder(t *testing.T) {
+	page := func(text string) string {
+		return streamObject("", []byte("BT /F1 12 Tf ("+text+") Tj ET"))
+	}
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R 3 0 R] /Count 2 /Resources << /Font << /F1 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		page("first"),
+		page("second"),
+		helvetica,
+	)
+	if got := extract(t, pdf); got != "first\nsecond" {
+		t.Errorf("Extract = %q, want the pages in page tree order", got)
+	}
+}
+
+func TestExtractPDFCompressed(t *testing.T) {
+	// The page and font live in a compressed object stream; the content stream is Flate encoded
+	objects := []string{
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 6 0 R >> >> >>",
+		helvetica,
+	}
+	var header, body bytes.Buffer
+	for i, obj := range []int{3, 6} {
+		fmt.Fprintf(&header, "%d %d ", obj, body.Len())
+		body.WriteString(objects[i] + "\n")
+	}
+	objStm := append(header.Bytes(), body.Bytes()...)
+
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"null",
+		streamObject("/Filter /FlateDecode", deflate([]byte("BT /F1 12 Tf (compressed text) Tj ET"))),
+		streamObject(fmt.Sprintf("/Type /ObjStm /N 2 /First %d /Filter [/FlateDecode]", header.Len()), deflate(objStm)),
+	)
+	// Object 3 is defined as null in the file body, so it must come from the object stream
+	pdf = bytes.Replace(pdf, []byte("3 0 obj\nnull\nendobj\n"), nil, 1)
+
+	if got := extract(t, pdf); got != "compressed text" {
+		t.Errorf("Extract = %q, want %q", got, "compressed text")
+	}
+}
+
+func TestExtractPDFToUnicode(t *testing.T) {
+	cmap := []byte(`/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0001> <0645> <0002> <0020> endbfchar
+1 beginbfrange <0010> <0012> <0041> endbfrange
+1 beginbfrange <0020> <0021> [<00DF> <0066006C>] endbfrange
+endcmap`)
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		streamObject("", []byte("BT /F1 12 Tf <0001000200100011001200020020 0021> Tj ET")),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Arial /ToUnicode 6 0 R >>",
+		streamObject("", cmap),
+	)
+	if got, want := extract(t, pdf), "م ABC ßfl"; got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
+
+func TestExtractPDFDifferences(t *testing.T) {
+	font := "<< /Type /Font /Subtype /Type1 /Encoding << /Differences [65 /eacute /space 97 /A] >> >>"
+	got := extract(t, onePagePDF([]byte("BT /F1 12 Tf (ABaz) Tj ET"), font))
+	if want := "é Az"; got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
+
+func TestExtractPDFRejects(t *testing.T) {
+	encrypted := bytes.Replace(onePagePDF([]byte("BT (x) Tj ET"), helvetica),
+		[]byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt 9 0 R"), 1)
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not a PDF", []byte("GIF89a"), "not a PDF"},
+		{"encrypted", encrypted, "encrypted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(bytes.NewReader(tt.data), int64(len(tt.data)), ContentTypePDF)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Extract error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamDataLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		length string
+	}{
+		{"correct", "5"},
+		{"too short", "3"},
+		{"past the end", "500"},
+		{"negative", "-5"},
+		{"overflows int", "99999999999999999999"},
+		{"huge float", "1e308"},
+		{"infinite", "Inf"},
+		{"not a number", "NaN"},
+		{"reference", "9 0 R"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte("1 0 obj\n<< /Length " + tt.length + " >>\nstream\nhello\nendstream\nendobj\n")
+			doc := parsePDF(data)
+			stream, ok := doc.objects[1].(*pdfStream)
+			if !ok {
+				t.Fatalf("object 1 = %#v, want a stream", doc.objects[1])
+			}
+			if string(stream.raw) != "hello" {
+				t.Errorf("stream data = %q, want %q", stream.raw, "hello")
+			}
+		})
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	bomb := &pdfStream{
+		dict: pdfDict{"Filter": pdfName("FlateDecode")},
+		raw:  deflate(make([]byte, maxStreamSize+1024)),
+	}
+
+	doc := &pdfDoc{objects: map[int]interface{}{}}
+	data, err := doc.decode(bomb)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(data) != maxStreamSize {
+		t.Errorf("stream decoded to %d bytes, want it cut at %d", len(data), maxStreamSize)
+	}
+
+	doc.decoded = maxDecodedSize - 10
+	if data, _ := doc.decode(bomb); len(data) != 10 {
+		t.Errorf("stream decoded to %d bytes with 10 left for the document", len(data))
+	}
+	if data, _ := doc.decode(bomb); len(data) != 0 {
+		t.Errorf("stream decoded to %d bytes after the document limit was reached", len(data))
+	}
+}
+
+func TestExtractPDFDeepNesting(t *testing.T) {
+	deep := strings.Repeat("[", 1<<20) + strings.Repeat("<<", 1<<20)
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Nested "+deep+" >>",
+		streamObject("", []byte("BT /F1 12 Tf (still here) Tj "+deep+" ET")),
+		helvetica,
+	)
+	// Only completing without exhausting the stack matters; the page itself is lost in the nesting
+	Extract(bytes.NewReader(pdf), int64(len(pdf)), ContentTypePDF)
+}
+
+func FuzzExtractPDF(f *testing.F) {
+	f.Add(onePagePDF([]byte("BT /F1 12 Tf 72 720 Td (Jane Doe) Tj [(a)-300(b)] TJ ET"), helvetica))
+	f.Add(onePagePDF(deflate([]byte("BT (x) Tj ET")), helvetica))
+	f.Add(buildPDF("<< /Type /ObjStm /N 1 /First 4 /Length 8 >>\nstream\n2 0 [1]\nendstream"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Length 99999999999999999999 >>\nstream\nx\nendstream\nendobj\n"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Filter /ASCII85Decode /Length 9 >>\nstream\n<~87cURD]i,\"Ebo80~>\nendstream\nendobj\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// extractPDF is called directly, as Extract would turn a panic into an error
+		extractPDF(bytes.NewReader(data), int64(len(data)))
+	})
+}
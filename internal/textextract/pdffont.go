@@ -0,0 +1,252 @@
+package textextract
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfFont maps the character codes of a font to text
+type pdfFont struct {
+	codeBytes   int               // Bytes per character code
+	toUnicode   map[uint32]string // From the font's ToUnicode CMap, when present
+	differences map[byte]string   // From a simple font's /Encoding /Differences
+}
+
+// font loads the font referenced by v, caching fonts shared between pages
+func (d *pdfDoc) font(v interface{}) *pdfFont {
+	ref, isRef := v.(pdfRef)
+	if isRef {
+		if f, ok := d.fonts[ref]; ok {
+			return f
+		}
+	}
+
+	f := &pdfFont{codeBytes: 1}
+	dict := d.dict(v)
+	if dict != nil {
+		if dict["Subtype"] == pdfName("Type0") {
+			f.codeBytes = 2
+		}
+		if stream, ok := d.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+			if data, err := d.decode(stream); err == nil {
+				var codeBytes int
+				f.toUnicode, codeBytes = parseCMap(data)
+				if codeBytes > 0 {
+					f.codeBytes = codeBytes
+				}
+			}
+		}
+		if encoding := d.dict(dict["Encoding"]); encoding != nil {
+			f.differences = d.differences(encoding["Differences"])
+		}
+	}
+
+	if isRef {
+		d.fonts[ref] = f
+	}
+	return f
+}
+
+// differences reads an encoding's Differences array, such as [32 /space 65 /A /B]
+func (d *pdfDoc) differences(v interface{}) map[byte]string {
+	arr, ok := d.resolve(v).(pdfArray)
+	if !ok {
+		return nil
+	}
+	m := map[byte]string{}
+	code := 0
+	for _, el := range arr {
+		switch t := el.(type) {
+		case float64:
+			code = int(t)
+		case pdfName:
+			if code >= 0 && code < 256 {
+				if s, ok := glyphText(string(t)); ok {
+					m[byte(code)] = s
+				}
+			}
+			code++
+		}
+	}
+	return m
+}
+
+// decode turns a shown string into text
+// Fonts without a ToUnicode map fall back to their Differences and then to WinAnsi; multi-byte
+// fonts without one use glyph IDs that cannot be mapped, so their text is dropped
+func (f *pdfFont) decode(s []byte) string {
+	if f == nil {
+		f = &pdfFont{codeBytes: 1}
+	}
+
+	var out strings.Builder
+	n := f.codeBytes
+	for i := 0; i+n <= len(s); i += n {
+		var code uint32
+		for _, b := range s[i : i+n] {
+			code = code<<8 | uint32(b)
+		}
+		if text, ok := f.toUnicode[code]; ok {
+			out.WriteString(text)
+			continue
+		}
+		if n != 1 {
+			continue
+		}
+		if text, ok := f.differences[byte(code)]; ok {
+			out.WriteString(text)
+			continue
+		}
+		if r := winAnsi(byte(code)); r != 0 {
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// parseCMap reads the bfchar and bfrange mappings of a ToUnicode CMap along with its code length
+func parseCMap(data []byte) (map[uint32]string, int) {
+	m := map[uint32]string{}
+	codeBytes := 0
+	l := &pdfLexer{data: data}
+
+	for {
+		tok, ok := l.token()
+		if !ok {
+			return m, codeBytes
+		}
+		switch tok {
+		case pdfKeyword("begincodespacerange"):
+			if lo, ok := l.token(); ok {
+				if b, isStr := lo.([]byte); isStr && codeBytes == 0 {
+					codeBytes = len(b)
+				}
+			}
+		case pdfKeyword("beginbfchar"):
+			for {
+				src, ok := l.object()
+				if !ok || src == pdfKeyword("endbfchar") {
+					break
+				}
+				dst, _ := l.object()
+				srcBytes, ok1 := src.([]byte)
+				dstBytes, ok2 := dst.([]byte)
+				if ok1 && ok2 {
+					m[codeOf(srcBytes)] = utf16BE(dstBytes)
+				}
+			}
+		case pdfKeyword("beginbfrange"):
+			for {
+				lo, ok := l.object()
+				if !ok || lo == pdfKeyword("endbfrange") {
+					break
+				}
+				hi, _ := l.object()
+				dst, _ := l.object()
+				loBytes, ok1 := lo.([]byte)
+				hiBytes, ok2 := hi.([]byte)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeOf(loBytes), codeOf(hiBytes)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+				switch d := dst.(type) {
+				case []byte:
+					// Consecutive codes map to consecutive values of the destination's last unit
+					units := utf16Units(d)
+					if len(units) == 0 {
+						continue
+					}
+					for code := start; code <= end; code++ {
+						shifted := append([]uint16(nil), units...)
+						shifted[len(shifted)-1] += uint16(code - start)
+						m[code] = string(utf16.Decode(shifted))
+					}
+				case pdfArray:
+					for i, el := range d {
+						if b, ok := el.([]byte); ok && start+uint32(i) <= end {
+							m[start+uint32(i)] = utf16BE(b)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func codeOf(b []byte) uint32 {
+	var code uint32
+	for _, c := range b {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+func utf16Units(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+func utf16BE(b []byte) string {
+	return string(utf16.Decode(utf16Units(b)))
+}
+
+// winAnsiHigh maps the WinAnsi codes 0x80-0x9F that differ from Latin-1
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+	0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// winAnsi decodes a byte in the WinAnsi encoding, returning 0 for control codes
+func winAnsi(b byte) rune {
+	switch {
+	case b == '\t' || b == '\n' || b == '\r':
+		return ' '
+	case b >= 0x20 && b < 0x7F:
+		return rune(b)
+	case b >= 0xA0:
+		return rune(b)
+	}
+	return winAnsiHigh[b]
+}
+
+// glyphNames maps common glyph names that are not a single character
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$", "percent": "%",
+	"ampersand": "&", "quoteright": "’", "quotesingle": "'", "parenleft": "(", "parenright": ")",
+	"asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".", "slash": "/",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5", "six": "6",
+	"seven": "7", "eight": "8", "nine": "9", "colon": ":", "semicolon": ";", "less": "<",
+	"equal": "=", "greater": ">", "question": "?", "at": "@", "bracketleft": "[", "backslash": "\\",
+	"bracketright": "]", "asciicircum": "^", "underscore": "_", "quoteleft": "‘", "grave": "`",
+	"braceleft": "{", "bar": "|", "braceright": "}", "asciitilde": "~", "endash": "–", "emdash": "—",
+	"quotedblleft": "“", "quotedblright": "”", "bullet": "•", "ellipsis": "…", "fi": "fi", "fl": "fl",
+	"ff": "ff", "ffi": "ffi", "ffl": "ffl", "dotlessi": "ı", "germandbls": "ß", "copyright": "©",
+	"registered": "®", "trademark": "™", "degree": "°", "minus": "−", "eacute": "é", "egrave": "è",
+	"aacute": "á", "agrave": "à", "oacute": "ó", "uacute": "ú", "iacute": "í", "ntilde": "ñ",
+	"ccedilla": "ç", "udieresis": "ü", "odieresis": "ö", "adieresis": "ä",
+}
+
+// glyphText returns the text of a glyph name such as "A", "comma" or "uni00E9"
+func glyphText(name string) (string, bool) {
+	if len(name) == 1 {
+		return name, true
+	}
+	if s, ok := glyphNames[name]; ok {
+		return s, true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if v, err := strconv.ParseUint(name[3:], 16, 16); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	return "", false
+}
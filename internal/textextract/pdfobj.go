@@ -0,0 +1,293 @@
+package textextract
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PDF object values produced by pdfLexer
+// Strings are []byte, numbers float64, booleans bool and null nil
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfArray   []interface{}
+	pdfDict    map[pdfName]interface{}
+	pdfRef     struct{ num, gen int }
+)
+
+// pdfStream is a stream object: its dictionary and the still-encoded data
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+// pdfLexer reads PDF tokens and objects from data
+// With refs set, "n g R" sequences are read as references, which content streams never contain
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	refs  bool
+	depth int // Arrays and dictionaries currently open
+}
+
+// maxPDFNesting bounds how deeply arrays and dictionaries are read, so that crafted files cannot
+// exhaust the stack; deeper values are left as their opening keyword
+const maxPDFNesting = 64
+
+func isPDFSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// token returns the next scalar value or keyword; ok is false at the end of the data
+// Array and dictionary delimiters are returned as the keywords "[", "]", "<<" and ">>"
+func (l *pdfLexer) token() (interface{}, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	c := l.data[l.pos]
+	switch c {
+	case '/':
+		l.pos++
+		return pdfName(l.regular(true)), true
+	case '(':
+		l.pos++
+		return l.literalString(), true
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), true
+		}
+		l.pos++
+		return l.hexString(), true
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>"), true
+		}
+		l.pos++
+		return pdfKeyword(">"), true
+	case '[', ']', '{', '}', ')':
+		l.pos++
+		return pdfKeyword(string(c)), true
+	}
+
+	word := l.regular(false)
+	switch word {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return n, true
+	}
+	return pdfKeyword(word), true
+}
+
+// regular reads a run of regular characters, decoding #xx escapes in names
+func (l *pdfLexer) regular(name bool) string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := l.data[start:l.pos]
+	if !name || bytes.IndexByte(word, '#') < 0 {
+		return string(word)
+	}
+
+	var decoded []byte
+	for i := 0; i < len(word); i++ {
+		if word[i] == '#' && i+2 < len(word) {
+			if v, err := strconv.ParseUint(string(word[i+1:i+3]), 16, 8); err == nil {
+				decoded = append(decoded, byte(v))
+				i += 2
+				continue
+			}
+		}
+		decoded = append(decoded, word[i])
+	}
+	return string(decoded)
+}
+
+// literalString reads a (string) after its opening parenthesis
+func (l *pdfLexer) literalString() []byte {
+	var s []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return s
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return s
+}
+
+// hexString reads a <hex string> after its opening bracket
+func (l *pdfLexer) hexString() []byte {
+	var s []byte
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		if v, ok := hexValue(c); ok {
+			digits = append(digits, v)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, 0)
+	}
+	for i := 0; i < len(digits); i += 2 {
+		s = append(s, digits[i]<<4|digits[i+1])
+	}
+	return s
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// object reads a complete value, including arrays, dictionaries and references
+func (l *pdfLexer) object() (interface{}, bool) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, false
+	}
+	return l.build(tok), true
+}
+
+// build completes the value that starts with tok
+func (l *pdfLexer) build(tok interface{}) interface{} {
+	switch t := tok.(type) {
+	case pdfKeyword:
+		if t != "[" && t != "<<" {
+			break
+		}
+		if l.depth >= maxPDFNesting {
+			return tok
+		}
+		l.depth++
+		defer func() { l.depth-- }()
+		switch t {
+		case "[":
+			arr := pdfArray{}
+			for {
+				next, ok := l.token()
+				if !ok || next == pdfKeyword("]") {
+					return arr
+				}
+				arr = append(arr, l.build(next))
+			}
+		case "<<":
+			dict := pdfDict{}
+			for {
+				key, ok := l.token()
+				if !ok || key == pdfKeyword(">>") {
+					return dict
+				}
+				value, ok := l.object()
+				if !ok {
+					return dict
+				}
+				if name, isName := key.(pdfName); isName {
+					dict[name] = value
+				}
+			}
+		}
+	case float64:
+		if l.refs {
+			save := l.pos
+			gen, ok := l.token()
+			if g, isNum := gen.(float64); ok && isNum {
+				if r, ok := l.token(); ok && r == pdfKeyword("R") {
+					return pdfRef{num: int(t), gen: int(g)}
+				}
+			}
+			l.pos = save
+		}
+	}
+	return tok
+}
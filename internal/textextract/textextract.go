@@ -0,0 +1,71 @@
+// Package textextract pulls plain text out of uploaded PDF and Word documents so they can be searched
+package textextract
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ErrUnsupported is returned for content types text cannot be extracted from
+var ErrUnsupported = errors.New("text extraction is not supported for this file type")
+
+// Content types text can be extracted from
+const (
+	ContentTypePDF  = "application/pdf"
+	ContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// Supports reports whether text can be extracted from files of contentType
+func Supports(contentType string) bool {
+	return contentType == ContentTypePDF || contentType == ContentTypeDOCX
+}
+
+// Extract returns the plain text of a PDF or DOCX file
+// Lines are kept, runs of spaces are collapsed and blank lines are dropped
+// A panic while parsing a malformed file is returned as an error
+func Extract(r io.ReaderAt, size int64, contentType string) (text string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			text, err = "", fmt.Errorf("failed to parse file: %v", p)
+		}
+	}()
+
+	switch contentType {
+	case ContentTypePDF:
+		text, err = extractPDF(r, size)
+	case ContentTypeDOCX:
+		text, err = extractDOCX(r, size)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+	return normalize(text), nil
+}
+
+// normalize collapses whitespace within lines and drops empty lines and control characters
+func normalize(text string) string {
+	var out strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Map(func(r rune) rune {
+			if r == '\t' || r == '\r' || r == ' ' {
+				return ' '
+			}
+			if unicode.IsControl(r) || r == unicode.ReplacementChar {
+				return -1
+			}
+			return r
+		}, line)
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}